@@ -0,0 +1,263 @@
+package certfix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// agentStatus is the state reported by the agent's health endpoints
+type agentStatus struct {
+	mu sync.RWMutex
+
+	Config       string    `json:"config"`
+	Interval     string    `json:"interval"`
+	Runs         int       `json:"runs"`
+	LastRun      time.Time `json:"last_run"`
+	LastSuccess  time.Time `json:"last_success"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastCreated  int       `json:"last_created"`
+	TotalCreated int       `json:"total_created"`
+}
+
+func (s *agentStatus) record(created int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Runs++
+	s.LastRun = time.Now()
+	s.LastCreated = created
+	s.TotalCreated += created
+	if err != nil {
+		s.LastError = err.Error()
+		return
+	}
+	s.LastError = ""
+	s.LastSuccess = s.LastRun
+}
+
+// healthy reports whether the last run succeeded
+func (s *agentStatus) healthy() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Runs > 0 && s.LastError == ""
+}
+
+func (s *agentStatus) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	type plain agentStatus
+	return json.Marshal((*plain)(s))
+}
+
+// agentLockPath is the lock file that keeps a single agent running per user
+func agentLockPath() string {
+	return filepath.Join(config.GetStateDir(), "agent.lock")
+}
+
+// acquireAgentLock creates the lock file, failing when another agent holds it.
+// A lock left behind by an agent that is no longer running is taken over. The
+// returned function removes it again.
+func acquireAgentLock() (func(), error) {
+	path := agentLockPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if errors.Is(err, os.ErrExist) {
+		owner := "unknown"
+		if data, err := os.ReadFile(path); err == nil {
+			owner = strings.TrimSpace(string(data))
+		}
+		pid, pidErr := strconv.Atoi(owner)
+		if pidErr != nil || processRunning(pid) {
+			return nil, fmt.Errorf("another agent is already running (pid %s); if it is not, remove %s", owner, path)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: taking over the lock of agent %d, which is no longer running\n", pid)
+		os.Remove(path)
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lock file: %w", err)
+	}
+	fmt.Fprintln(f, strconv.Itoa(os.Getpid()))
+	f.Close()
+
+	return func() { os.Remove(path) }, nil
+}
+
+// processRunning reports whether a process with the given PID exists. On
+// Windows, finding the process fails once it has exited; elsewhere signal 0
+// checks for it without affecting it.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// reconcileOnce applies the configuration, skipping resources that already
+// exist. Unlike apply, nothing is rolled back on error: the next run picks up
+// where this one stopped.
+func reconcileOnce(configFile string) (int, error) {
+	certfixConfig, err := loadApplyConfig(configFile)
+	if err != nil {
+		return 0, err
+	}
+
+	token, err := auth.GetToken()
+	if err != nil {
+		return 0, err
+	}
+
+	// Create API client
+	apiClient := newAPIClient()
+
+	var createdResources []models.CreatedResource
+	err = applyConfiguration(&certfixConfig, apiClient, token, &createdResources, skipAllExisting)
+	return len(createdResources), err
+}
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Continuously reconcile a configuration file",
+	Long: `Run apply in a loop: every --interval the configuration file is read again and
+any resource it declares that is missing on the server (for example because it
+was deleted by hand) is created again. Resources that already exist are left
+untouched, and nothing is rolled back on error; the next run retries.
+
+Only one agent runs per user at a time. With --listen, /healthz returns 200
+while the last run succeeded and /status reports the agent state as JSON.
+SIGINT and SIGTERM stop the agent after the current run finishes.`,
+	Example: `  certfix agent --config manifest.yaml --interval 10m
+  certfix agent --config manifest.yaml --listen :8787`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile, _ := cmd.Flags().GetString("config")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		listen, _ := cmd.Flags().GetString("listen")
+
+		if interval < 30*time.Second {
+			return fmt.Errorf("--interval must be at least 30s")
+		}
+
+		// Validate before starting so a broken file fails immediately
+		if _, err := loadApplyConfig(configFile); err != nil {
+			return err
+		}
+		if _, err := auth.GetToken(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		release, err := acquireAgentLock()
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		status := &agentStatus{Config: configFile, Interval: interval.String()}
+
+		var server *http.Server
+		serverErr := make(chan error, 1)
+		if listen != "" {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+				if !status.healthy() {
+					http.Error(w, "unhealthy", http.StatusServiceUnavailable)
+					return
+				}
+				fmt.Fprintln(w, "ok")
+			})
+			mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				data, _ := json.MarshalIndent(status, "", "  ")
+				w.Write(append(data, '\n'))
+			})
+			server = &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+			go func() {
+				if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					serverErr <- err
+				}
+			}()
+		}
+
+		fmt.Printf("Reconciling %s every %s against %s\n", configFile, interval, config.GetAPIEndpoint())
+		if listen != "" {
+			fmt.Printf("Health endpoint: %s/healthz\n", listen)
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(interrupt)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		run := func() {
+			start := time.Now()
+			created, err := reconcileOnce(configFile)
+			status.record(created, err)
+			timestamp := start.Format("2006-01-02 15:04:05")
+			if err != nil {
+				fmt.Printf("%s  ✗ Reconcile failed after creating %d resource(s): %v\n", timestamp, created, err)
+				return
+			}
+			if created > 0 {
+				fmt.Printf("%s  ✓ Drift corrected: %d resource(s) created (%s)\n", timestamp, created, time.Since(start).Round(time.Millisecond))
+			} else {
+				fmt.Printf("%s  ✓ In sync (%s)\n", timestamp, time.Since(start).Round(time.Millisecond))
+			}
+		}
+
+		run()
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case err := <-serverErr:
+				return fmt.Errorf("failed to serve health endpoint: %w", err)
+			case <-interrupt:
+				fmt.Println("\nShutting down...")
+				if server != nil {
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+					server.Shutdown(ctx)
+				}
+				return nil
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+
+	// Agent command flags
+	agentCmd.Flags().StringP("config", "c", "", "Configuration file to reconcile (required)")
+	agentCmd.Flags().Duration("interval", 10*time.Minute, "Time between reconcile runs")
+	agentCmd.Flags().String("listen", "", "Address for the /healthz and /status endpoints (e.g. :8787)")
+	agentCmd.MarkFlagRequired("config")
+}
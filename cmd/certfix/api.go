@@ -0,0 +1,133 @@
+package certfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// apiMethods are the HTTP methods accepted by the api command
+var apiMethods = map[string]bool{
+	"GET":    true,
+	"POST":   true,
+	"PUT":    true,
+	"PATCH":  true,
+	"DELETE": true,
+}
+
+// readAPIBody returns the request body from --data or --data-file ("-" reads
+// standard input). The body must be valid JSON.
+func readAPIBody(data, dataFile string) ([]byte, error) {
+	if data != "" && dataFile != "" {
+		return nil, fmt.Errorf("--data and --data-file cannot be used together")
+	}
+
+	var body []byte
+	switch {
+	case data != "":
+		body = []byte(data)
+	case dataFile == "-":
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body from stdin: %w", err)
+		}
+		body = content
+	case dataFile != "":
+		content, err := os.ReadFile(dataFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body file: %w", err)
+		}
+		body = content
+	default:
+		return nil, nil
+	}
+
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("request body is not valid JSON")
+	}
+	return body, nil
+}
+
+var apiCmd = &cobra.Command{
+	Use:   "api <method> <path>",
+	Short: "Make an authenticated API request",
+	Long: `Send an arbitrary request to the Certfix API using the configured endpoint and
+your login session, and print the response body as returned.
+
+Use it to reach endpoints that do not have a dedicated command yet.`,
+	Example: `  certfix api GET /services/abc123/keys
+  certfix api POST /events --data '{"name": "deploy"}'
+  certfix api PUT /services/abc123 --data-file service.json
+  cat body.json | certfix api PATCH /policies/p1 --data-file -`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method := strings.ToUpper(args[0])
+		path := args[1]
+		data, _ := cmd.Flags().GetString("data")
+		dataFile, _ := cmd.Flags().GetString("data-file")
+		include, _ := cmd.Flags().GetBool("include")
+
+		if !apiMethods[method] {
+			return fmt.Errorf("unsupported method %q (use GET, POST, PUT, PATCH or DELETE)", args[0])
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		body, err := readAPIBody(data, dataFile)
+		if err != nil {
+			return err
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		status, response, err := apiClient.RawWithAuth(method, path, body, token)
+		cmd.SilenceUsage = true
+		if err != nil {
+			return err
+		}
+
+		if include {
+			fmt.Printf("HTTP %d\n\n", status)
+		}
+
+		// Pretty-print JSON responses, pass anything else through untouched
+		var indented bytes.Buffer
+		if json.Indent(&indented, response, "", "  ") == nil {
+			fmt.Println(indented.String())
+		} else if len(response) > 0 {
+			os.Stdout.Write(response)
+			if !bytes.HasSuffix(response, []byte("\n")) {
+				fmt.Println()
+			}
+		}
+
+		if status < 200 || status >= 300 {
+			return fmt.Errorf("request failed with status %d", status)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+
+	// API command flags
+	apiCmd.Flags().String("data", "", "JSON request body")
+	apiCmd.Flags().String("data-file", "", "File containing the JSON request body (- for stdin)")
+	apiCmd.Flags().BoolP("include", "i", false, "Print the HTTP status before the body")
+}
@@ -3,13 +3,17 @@ package certfix
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/internal/cron"
+	"github.com/certfix/certfix-cli/internal/tracing"
 	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/certfix/certfix-cli/pkg/logger"
 	"github.com/certfix/certfix-cli/pkg/models"
+	"github.com/certfix/certfix-cli/pkg/notify"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/yaml.v3"
 )
 
@@ -34,17 +38,16 @@ resources will be rolled back automatically.`,
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		skipExisting, _ := cmd.Flags().GetBool("skip-existing")
 
-		// Read YAML file
-		fmt.Printf("Reading configuration from: %s\n", configFile)
-		data, err := os.ReadFile(configFile)
+		notifiers, err := notifiersFromFlags(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to read config file: %w", err)
+			return err
 		}
 
-		// Parse YAML
-		var certfixConfig models.CertfixConfig
-		if err := yaml.Unmarshal(data, &certfixConfig); err != nil {
-			return fmt.Errorf("failed to parse YAML: %w", err)
+		// Read YAML file
+		fmt.Printf("Reading configuration from: %s\n", configFile)
+		certfixConfig, err := loadApplyConfig(configFile)
+		if err != nil {
+			return err
 		}
 
 		fmt.Println("Configuration loaded successfully")
@@ -56,78 +59,7 @@ resources will be rolled back automatically.`,
 		if dryRun {
 			fmt.Println("\n=== DRY RUN MODE - No changes will be made ===")
 
-			// Show what would be created
-			if len(certfixConfig.Events) > 0 {
-				fmt.Println("Events to create:")
-				for _, e := range certfixConfig.Events {
-					fmt.Printf("  ✓ %s (severity: %s, enabled: %v)\n", e.Name, e.Severity, e.Enabled)
-				}
-				fmt.Println()
-			}
-
-			if len(certfixConfig.Policies) > 0 {
-				fmt.Println("Policies to create:")
-				for _, p := range certfixConfig.Policies {
-					fmt.Printf("  ✓ %s (strategy: %s, enabled: %v)\n", p.Name, p.Strategy, p.Enabled)
-					if len(p.CronConfig) > 0 {
-						fmt.Printf("      Cron: %v\n", p.CronConfig)
-					}
-					if len(p.EventConfig) > 0 {
-						fmt.Printf("      Event Config: %v\n", p.EventConfig)
-					}
-				}
-				fmt.Println()
-			}
-
-			if len(certfixConfig.ServiceGroups) > 0 {
-				fmt.Println("Service Groups to create:")
-				for _, g := range certfixConfig.ServiceGroups {
-					desc := g.Description
-					if desc == "" {
-						desc = "(no description)"
-					}
-					fmt.Printf("  ✓ %s - %s (enabled: %v)\n", g.Name, desc, g.Enabled)
-				}
-				fmt.Println()
-			}
-
-			if len(certfixConfig.Services) > 0 {
-				fmt.Println("Services to create:")
-				for _, s := range certfixConfig.Services {
-					fmt.Printf("  ✓ %s (hash: %s)\n", s.Name, s.Hash)
-					if s.GroupName != "" {
-						fmt.Printf("      Group: %s\n", s.GroupName)
-					}
-					if s.PolicyName != "" {
-						fmt.Printf("      Policy: %s\n", s.PolicyName)
-					}
-					if s.WebhookURL != "" {
-						fmt.Printf("      Webhook: %s\n", s.WebhookURL)
-					}
-					if len(s.DNSNames) > 0 {
-						fmt.Printf("      DNS Names: %v\n", s.DNSNames)
-					}
-					if s.ReloadService != "" {
-						fmt.Printf("      Reload Service: %s\n", s.ReloadService)
-					}
-					if len(s.Keys) > 0 {
-						fmt.Printf("      Keys: %d\n", len(s.Keys))
-						for _, k := range s.Keys {
-							fmt.Printf("        - %s (expiration: %d days)\n", k.Name, k.ExpirationDays)
-						}
-					}
-					if len(s.Relations) > 0 {
-						fmt.Printf("      Relations: %d\n", len(s.Relations))
-						for _, r := range s.Relations {
-							fmt.Printf("        - %s (type: %s)\n", r.TargetHash, r.Type)
-						}
-					}
-					fmt.Println()
-				}
-			}
-
-			total := len(certfixConfig.Events) + len(certfixConfig.Policies) + len(certfixConfig.ServiceGroups) + len(certfixConfig.Services)
-			fmt.Printf("Total resources: %d\n", total)
+			printApplyPlan(&certfixConfig)
 			return nil
 		}
 
@@ -138,8 +70,7 @@ resources will be rolled back automatically.`,
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Track created resources for rollback
 		var createdResources []models.CreatedResource
@@ -154,103 +85,376 @@ resources will be rolled back automatically.`,
 		}()
 
 		// Apply configuration
-		err = applyConfiguration(&certfixConfig, apiClient, token, &createdResources, skipExisting)
+		existing := failOnExisting
+		if skipExisting {
+			existing = skipExistingServices
+		}
+		err = applyConfiguration(&certfixConfig, apiClient, token, &createdResources, existing)
 		if err != nil {
 			log.Errorf("Error during apply: %v", err)
 			log.Infof("Rolling back created resources...")
 			rollbackResources(apiClient, token, createdResources)
+			sendNotification(notifiers, notify.Message{
+				Title:    fmt.Sprintf("Apply of %s failed", configFile),
+				Severity: notify.SeverityError,
+				Lines: []string{
+					err.Error(),
+					fmt.Sprintf("%d created resource(s) were rolled back.", len(createdResources)),
+				},
+			})
 			return err
 		}
 
 		log.Infof("✓ Configuration applied successfully!")
 		log.Infof("Total resources created: %d", len(createdResources))
+		sendNotification(notifiers, notify.Message{
+			Title:    fmt.Sprintf("Applied %s", configFile),
+			Severity: notify.SeverityInfo,
+			Lines:    []string{fmt.Sprintf("%d resource(s) created.", len(createdResources))},
+		})
 
 		return nil
 	},
 }
 
-func applyConfiguration(config *models.CertfixConfig, apiClient *client.HTTPClient, token string, createdResources *[]models.CreatedResource, skipExisting bool) error {
+// printApplyPlan prints the resources applying cfg would create
+func printApplyPlan(cfg *models.CertfixConfig) {
+	if len(cfg.Events) > 0 {
+		fmt.Println("Events to create:")
+		for _, e := range cfg.Events {
+			fmt.Printf("  ✓ %s (severity: %s, enabled: %v)\n", e.Name, e.Severity, e.Enabled)
+		}
+		fmt.Println()
+	}
+
+	if len(cfg.Policies) > 0 {
+		fmt.Println("Policies to create:")
+		for _, p := range cfg.Policies {
+			fmt.Printf("  ✓ %s (strategy: %s, enabled: %v)\n", p.Name, p.Strategy, p.Enabled)
+			if cronConfig, _ := policyCronConfig(p); len(cronConfig) > 0 {
+				fmt.Printf("      Cron: %v\n", cronConfig)
+			}
+			if len(p.EventConfig) > 0 {
+				fmt.Printf("      Event Config: %v\n", p.EventConfig)
+			}
+			if p.GradualConfig != nil {
+				fmt.Printf("      Gradual: batch size %d, interval %s\n", p.GradualConfig.BatchSize, orNone(p.GradualConfig.Interval))
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(cfg.ServiceGroups) > 0 {
+		fmt.Println("Service Groups to create:")
+		for _, g := range cfg.ServiceGroups {
+			desc := g.Description
+			if desc == "" {
+				desc = "(no description)"
+			}
+			fmt.Printf("  ✓ %s - %s (enabled: %v)\n", g.Name, desc, g.Enabled)
+		}
+		fmt.Println()
+	}
+
+	if len(cfg.Services) > 0 {
+		fmt.Println("Services to create:")
+		for _, s := range cfg.Services {
+			fmt.Printf("  ✓ %s (hash: %s)\n", s.Name, s.Hash)
+			if s.GroupName != "" {
+				fmt.Printf("      Group: %s\n", s.GroupName)
+			}
+			if s.PolicyName != "" {
+				fmt.Printf("      Policy: %s\n", s.PolicyName)
+			}
+			if s.WebhookURL != "" {
+				fmt.Printf("      Webhook: %s\n", s.WebhookURL)
+			}
+			if len(s.DNSNames) > 0 {
+				fmt.Printf("      DNS Names: %v\n", s.DNSNames)
+			}
+			if len(s.Labels) > 0 {
+				fmt.Printf("      Labels: %s\n", formatLabels(s.Labels))
+			}
+			if s.ReloadService != "" {
+				fmt.Printf("      Reload Service: %s\n", s.ReloadService)
+			}
+			if len(s.Keys) > 0 {
+				fmt.Printf("      Keys: %d\n", len(s.Keys))
+				for _, k := range s.Keys {
+					fmt.Printf("        - %s (expiration: %d days)\n", k.Name, k.ExpirationDays)
+				}
+			}
+			if len(s.Relations) > 0 {
+				fmt.Printf("      Relations: %d\n", len(s.Relations))
+				for _, r := range s.Relations {
+					fmt.Printf("        - %s (type: %s)\n", r.TargetHash, r.Type)
+				}
+			}
+			fmt.Println()
+		}
+	}
+
+	total := len(cfg.Events) + len(cfg.Policies) + len(cfg.ServiceGroups) + len(cfg.Services)
+	fmt.Printf("Total resources: %d\n", total)
+}
+
+// loadApplyConfig reads a YAML configuration and validates strategies and cron
+// expressions before anything is created
+func loadApplyConfig(path string) (models.CertfixConfig, error) {
+	var certfixConfig models.CertfixConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return certfixConfig, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &certfixConfig); err != nil {
+		return certfixConfig, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for _, p := range certfixConfig.Policies {
+		if _, err := parseStrategy(p.Strategy); err != nil {
+			return certfixConfig, fmt.Errorf("policy '%s': %w", p.Name, err)
+		}
+		if _, err := policyCronConfig(p); err != nil {
+			return certfixConfig, err
+		}
+		if _, err := policyGradualConfig(p); err != nil {
+			return certfixConfig, err
+		}
+	}
+	return certfixConfig, nil
+}
+
+// existingResources says which resources applyConfiguration leaves alone when
+// they already exist, instead of failing
+type existingResources int
+
+const (
+	// failOnExisting fails on a service that already exists
+	failOnExisting existingResources = iota
+	// skipExistingServices skips services that already exist, as
+	// apply --skip-existing does
+	skipExistingServices
+	// skipAllExisting also skips events, policies and service groups with the
+	// same name, keys with the same name and relations to the same target, so
+	// the agent and migrate can run against a server that has some of them
+	skipAllExisting
+)
+
+func applyConfiguration(config *models.CertfixConfig, apiClient client.APIClient, token string, createdResources *[]models.CreatedResource, existing existingResources) (err error) {
 	log := logger.GetLogger()
+	skipExisting := existing == skipAllExisting
+
+	endSpan := tracing.Span("apply", attribute.Bool("certfix.apply.skip_existing", existing != failOnExisting))
+	defer func() { endSpan(err) }()
 
 	// 1. Create Events
 	log.Infof("\n=== Creating Events ===")
-	for i, event := range config.Events {
-		log.Infof("[%d/%d] Creating event: %s", i+1, len(config.Events), event.Name)
+	err = applyStage("events", len(config.Events), func() error {
+		for i, event := range config.Events {
+			log.Infof("[%d/%d] Creating event: %s", i+1, len(config.Events), event.Name)
 
-		if err := createEvent(apiClient, token, event, createdResources, skipExisting); err != nil {
-			return fmt.Errorf("failed to create event '%s': %w", event.Name, err)
+			if err := createEvent(apiClient, token, event, createdResources, skipExisting); err != nil {
+				return fmt.Errorf("failed to create event '%s': %w", event.Name, err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// 2. Create Policies
 	log.Infof("\n=== Creating Policies ===")
-	for i, policy := range config.Policies {
-		log.Infof("[%d/%d] Creating policy: %s", i+1, len(config.Policies), policy.Name)
+	err = applyStage("policies", len(config.Policies), func() error {
+		for i, policy := range config.Policies {
+			log.Infof("[%d/%d] Creating policy: %s", i+1, len(config.Policies), policy.Name)
 
-		if err := createPolicy(apiClient, token, policy, createdResources, skipExisting); err != nil {
-			return fmt.Errorf("failed to create policy '%s': %w", policy.Name, err)
+			if err := createPolicy(apiClient, token, policy, createdResources, skipExisting); err != nil {
+				return fmt.Errorf("failed to create policy '%s': %w", policy.Name, err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// 3. Create Service Groups
 	log.Infof("\n=== Creating Service Groups ===")
-	for i, group := range config.ServiceGroups {
-		log.Infof("[%d/%d] Creating service group: %s", i+1, len(config.ServiceGroups), group.Name)
+	err = applyStage("service_groups", len(config.ServiceGroups), func() error {
+		for i, group := range config.ServiceGroups {
+			log.Infof("[%d/%d] Creating service group: %s", i+1, len(config.ServiceGroups), group.Name)
 
-		if err := createServiceGroup(apiClient, token, group, createdResources, skipExisting); err != nil {
-			return fmt.Errorf("failed to create service group '%s': %w", group.Name, err)
+			if err := createServiceGroup(apiClient, token, group, createdResources, skipExisting); err != nil {
+				return fmt.Errorf("failed to create service group '%s': %w", group.Name, err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// 4. Create Services (without keys and relations)
 	log.Infof("\n=== Creating Services ===")
-	for i, service := range config.Services {
-		log.Infof("[%d/%d] Creating service: %s (%s)", i+1, len(config.Services), service.Name, service.Hash)
+	err = applyStage("services", len(config.Services), func() error {
+		for i, service := range config.Services {
+			log.Infof("[%d/%d] Creating service: %s (%s)", i+1, len(config.Services), service.Name, service.Hash)
 
-		if err := createService(apiClient, token, service, createdResources, skipExisting); err != nil {
-			return fmt.Errorf("failed to create service '%s': %w", service.Hash, err)
+			if err := createService(apiClient, token, service, createdResources, existing != failOnExisting); err != nil {
+				return fmt.Errorf("failed to create service '%s': %w", service.Hash, err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	// Keys and relations are the bulk of large manifests, so they are sent
+	// in batches where the server supports it
+	batcher := client.NewBatcher(apiClient, token)
+
 	// 5. Create Service Keys
 	log.Infof("\n=== Creating Service Keys ===")
+	keyCount := 0
 	for _, service := range config.Services {
-		if len(service.Keys) > 0 {
-			log.Infof("Creating %d keys for service: %s", len(service.Keys), service.Hash)
+		keyCount += len(service.Keys)
+	}
+	err = applyStage("keys", keyCount, func() error {
+		var keys batchedCreates
+		for _, service := range config.Services {
+			if len(service.Keys) > 0 {
+				log.Infof("Preparing %d keys for service: %s", len(service.Keys), service.Hash)
 
-			for i, key := range service.Keys {
-				log.Infof("  [%d/%d] Creating key: %s", i+1, len(service.Keys), key.Name)
+				for i, key := range service.Keys {
+					log.Infof("  [%d/%d] Key: %s", i+1, len(service.Keys), key.Name)
 
-				if err := createServiceKey(apiClient, token, service.Hash, key, createdResources); err != nil {
-					return fmt.Errorf("failed to create key '%s' for service '%s': %w", key.Name, service.Hash, err)
+					op, err := serviceKeyOperation(apiClient, token, service.Hash, key, skipExisting)
+					if err != nil {
+						return fmt.Errorf("failed to create key '%s' for service '%s': %w", key.Name, service.Hash, err)
+					}
+					if op != nil {
+						keys.add(*op, models.CreatedResource{Type: "key", Hash: service.Hash},
+							fmt.Sprintf("key '%s' for service '%s'", key.Name, service.Hash))
+					}
 				}
 			}
 		}
+		return keys.run(batcher, "key_id", createdResources)
+	})
+	if err != nil {
+		return err
 	}
 
 	// 6. Create Service Relations
 	log.Infof("\n=== Creating Service Relations ===")
+	relationCount := 0
 	for _, service := range config.Services {
-		if len(service.Relations) > 0 {
-			log.Infof("Creating %d relations for service: %s", len(service.Relations), service.Hash)
+		relationCount += len(service.Relations)
+	}
+	return applyStage("relations", relationCount, func() error {
+		var relations batchedCreates
+		for _, service := range config.Services {
+			if len(service.Relations) > 0 {
+				log.Infof("Preparing %d relations for service: %s", len(service.Relations), service.Hash)
 
-			for i, relation := range service.Relations {
-				log.Infof("  [%d/%d] Creating relation: %s -> %s", i+1, len(service.Relations), service.Hash, relation.TargetHash)
+				for i, relation := range service.Relations {
+					log.Infof("  [%d/%d] Relation: %s -> %s", i+1, len(service.Relations), service.Hash, relation.TargetHash)
 
-				if err := createServiceRelation(apiClient, token, service.Hash, relation, createdResources); err != nil {
-					return fmt.Errorf("failed to create relation from '%s' to '%s': %w", service.Hash, relation.TargetHash, err)
+					op, err := serviceRelationOperation(apiClient, token, service.Hash, relation, skipExisting)
+					if err != nil {
+						return fmt.Errorf("failed to create relation from '%s' to '%s': %w", service.Hash, relation.TargetHash, err)
+					}
+					if op != nil {
+						relations.add(*op, models.CreatedResource{Type: "relation", Hash: service.Hash, ID: relation.TargetHash},
+							fmt.Sprintf("relation from '%s' to '%s'", service.Hash, relation.TargetHash))
+					}
 				}
 			}
 		}
+		return relations.run(batcher, "", createdResources)
+	})
+}
+
+// applyStage runs one stage of an apply, creating count resources of a kind,
+// in a tracing span of its own
+func applyStage(kind string, count int, run func() error) (err error) {
+	endSpan := tracing.Span("apply "+kind, attribute.Int("certfix.apply.resources", count))
+	defer func() { endSpan(err) }()
+	return run()
+}
+
+// batchedCreates collects create requests of one kind to send as a batch
+type batchedCreates struct {
+	ops       []client.Operation
+	resources []models.CreatedResource
+	labels    []string
+}
+
+// add queues a create request with the resource it creates and a description
+// for errors
+func (b *batchedCreates) add(op client.Operation, resource models.CreatedResource, label string) {
+	b.ops = append(b.ops, op)
+	b.resources = append(b.resources, resource)
+	b.labels = append(b.labels, label)
+}
+
+// run sends the requests and records every resource created, taking its ID
+// from idField of the response when set, so a rollback finds them all. It
+// returns the first failure.
+func (b *batchedCreates) run(batcher *client.Batcher, idField string, createdResources *[]models.CreatedResource) error {
+	log := logger.GetLogger()
+
+	var firstErr error
+	for i, result := range batcher.Do(b.ops) {
+		if result.Err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to create %s: %w", b.labels[i], result.Err)
+			}
+			continue
+		}
+		resource := b.resources[i]
+		if idField != "" {
+			resource.ID = stringField(result.Response, idField)
+		}
+		*createdResources = append(*createdResources, resource)
+		log.Infof("    ✓ Created %s", b.labels[i])
 	}
+	return firstErr
+}
 
-	return nil
+// existsInList reports whether the array returned by endpoint has an item whose
+// field equals value. Used by skipAllExisting for resources that cannot be
+// fetched by name directly.
+func existsInList(apiClient client.APIClient, token, endpoint, field, value string) (bool, error) {
+	response, err := apiClient.GetWithAuth(endpoint, token)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for existing resources: %w", err)
+	}
+	for _, item := range parseArrayResponse(response) {
+		if fmt.Sprintf("%v", item[field]) == value {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-func createEvent(apiClient *client.HTTPClient, token string, event models.EventConfig, createdResources *[]models.CreatedResource, skipExisting bool) error {
+func createEvent(apiClient client.APIClient, token string, event models.EventConfig, createdResources *[]models.CreatedResource, skipExisting bool) error {
 	log := logger.GetLogger()
 
-	// Note: Skip existence check for now - events API doesn't support hash-based lookup
+	if skipExisting {
+		exists, err := existsInList(apiClient, token, "/events", "name", event.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			log.Infof("  ⊙ Event already exists, skipping")
+			return nil
+		}
+	}
 
 	payload := map[string]interface{}{
 		"name":     event.Name,
@@ -277,20 +481,38 @@ func createEvent(apiClient *client.HTTPClient, token string, event models.EventC
 	return nil
 }
 
-func createPolicy(apiClient *client.HTTPClient, token string, policy models.PolicyConfig, createdResources *[]models.CreatedResource, skipExisting bool) error {
+func createPolicy(apiClient client.APIClient, token string, policy models.PolicyConfig, createdResources *[]models.CreatedResource, skipExisting bool) error {
 	log := logger.GetLogger()
 
-	// Check if exists (skip for now, will check by list)
+	if skipExisting {
+		exists, err := existsInList(apiClient, token, "/policies", "name", policy.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			log.Infof("  ⊙ Policy already exists, skipping")
+			return nil
+		}
+	}
+
+	strategy, err := parseStrategy(policy.Strategy)
+	if err != nil {
+		return err
+	}
 
 	payload := map[string]interface{}{
 		"name":     policy.Name,
-		"strategy": policy.Strategy,
+		"strategy": strategy,
 		"enabled":  policy.Enabled,
 	}
 
 	// Add optional cron config
-	if len(policy.CronConfig) > 0 {
-		payload["cron_config"] = policy.CronConfig
+	cronConfig, err := policyCronConfig(policy)
+	if err != nil {
+		return err
+	}
+	if len(cronConfig) > 0 {
+		payload["cron_config"] = cronConfig
 	}
 
 	// Add optional event config
@@ -298,6 +520,15 @@ func createPolicy(apiClient *client.HTTPClient, token string, policy models.Poli
 		payload["event_config"] = policy.EventConfig
 	}
 
+	// Add optional gradual config
+	gradualConfig, err := policyGradualConfig(policy)
+	if err != nil {
+		return err
+	}
+	if len(gradualConfig) > 0 {
+		payload["gradual_config"] = gradualConfig
+	}
+
 	resp, err := apiClient.PostWithAuth("/policies", payload, token)
 	if err != nil {
 		return err
@@ -317,10 +548,60 @@ func createPolicy(apiClient *client.HTTPClient, token string, policy models.Poli
 	return nil
 }
 
-func createServiceGroup(apiClient *client.HTTPClient, token string, group models.ServiceGroupConfig, createdResources *[]models.CreatedResource, skipExisting bool) error {
+// policyCronConfig returns the cron_config of a policy, expanding the cron
+// expression form when it is used instead of the per-field map
+func policyCronConfig(policy models.PolicyConfig) (map[string]string, error) {
+	if policy.Cron == "" {
+		return policy.CronConfig, nil
+	}
+	if len(policy.CronConfig) > 0 {
+		return nil, fmt.Errorf("policy '%s' sets both cron and cron_config; use one of them", policy.Name)
+	}
+	fields, err := cron.Split(policy.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("policy '%s' has an invalid cron expression: %w", policy.Name, err)
+	}
+	return map[string]string{
+		"minute":  fields[0],
+		"hour":    fields[1],
+		"day":     fields[2],
+		"month":   fields[3],
+		"weekday": fields[4],
+	}, nil
+}
+
+// policyGradualConfig converts a policy's gradual_config into the API payload
+func policyGradualConfig(policy models.PolicyConfig) (map[string]interface{}, error) {
+	if policy.GradualConfig == nil {
+		return nil, nil
+	}
+	var interval time.Duration
+	if policy.GradualConfig.Interval != "" {
+		var err error
+		if interval, err = time.ParseDuration(policy.GradualConfig.Interval); err != nil {
+			return nil, fmt.Errorf("policy '%s' has an invalid gradual interval '%s'", policy.Name, policy.GradualConfig.Interval)
+		}
+	}
+	gradualConfig, err := gradualConfigPayload(policy.GradualConfig.BatchSize, interval)
+	if err != nil {
+		return nil, fmt.Errorf("policy '%s': %w", policy.Name, err)
+	}
+	return gradualConfig, nil
+}
+
+func createServiceGroup(apiClient client.APIClient, token string, group models.ServiceGroupConfig, createdResources *[]models.CreatedResource, skipExisting bool) error {
 	log := logger.GetLogger()
 
-	// Check if exists (skip for now, will check by list)
+	if skipExisting {
+		exists, err := existsInList(apiClient, token, "/service-groups", "name", group.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			log.Infof("  ⊙ Service group already exists, skipping")
+			return nil
+		}
+	}
 
 	payload := map[string]interface{}{
 		"name":        group.Name,
@@ -347,7 +628,7 @@ func createServiceGroup(apiClient *client.HTTPClient, token string, group models
 	return nil
 }
 
-func createService(apiClient *client.HTTPClient, token string, service models.ServiceConfig, createdResources *[]models.CreatedResource, skipExisting bool) error {
+func createService(apiClient client.APIClient, token string, service models.ServiceConfig, createdResources *[]models.CreatedResource, skipExisting bool) error {
 	log := logger.GetLogger()
 
 	// Check if exists
@@ -375,6 +656,10 @@ func createService(apiClient *client.HTTPClient, token string, service models.Se
 		payload["reload_service"] = service.ReloadService
 	}
 
+	if len(service.Labels) > 0 {
+		payload["labels"] = service.Labels
+	}
+
 	// Look up service group ID by name
 	if service.GroupName != "" {
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/service-groups/name/%s", service.GroupName), token)
@@ -423,11 +708,24 @@ func createService(apiClient *client.HTTPClient, token string, service models.Se
 	return nil
 }
 
-func createServiceKey(apiClient *client.HTTPClient, token string, serviceHash string, key models.ServiceKeyConfig, createdResources *[]models.CreatedResource) error {
+// serviceKeyOperation returns the request creating a key, or nil when
+// skipExisting finds the key already there
+func serviceKeyOperation(apiClient client.APIClient, token string, serviceHash string, key models.ServiceKeyConfig, skipExisting bool) (*client.Operation, error) {
 	log := logger.GetLogger()
 
 	if key.ExpirationDays <= 0 {
-		return fmt.Errorf("expiration_days must be a positive integer (got %d); use e.g. 365 for 1 year or 36500 for ~100 years", key.ExpirationDays)
+		return nil, fmt.Errorf("expiration_days must be a positive integer (got %d); use e.g. 365 for 1 year or 36500 for ~100 years", key.ExpirationDays)
+	}
+
+	if skipExisting {
+		exists, err := existsInList(apiClient, token, fmt.Sprintf("/services/%s/keys/list", serviceHash), "key_name", key.Name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			log.Infof("    ⊙ Key already exists, skipping")
+			return nil, nil
+		}
 	}
 
 	payload := map[string]interface{}{
@@ -436,29 +734,25 @@ func createServiceKey(apiClient *client.HTTPClient, token string, serviceHash st
 		"expiration_days": key.ExpirationDays,
 	}
 
-	response, err := apiClient.PostWithAuth(fmt.Sprintf("/services/%s/keys", serviceHash), payload, token)
-	if err != nil {
-		return err
-	}
-
-	keyID := ""
-	if id, ok := response["key_id"].(string); ok {
-		keyID = id
-	}
-
-	*createdResources = append(*createdResources, models.CreatedResource{
-		Type: "key",
-		Hash: serviceHash,
-		ID:   keyID,
-	})
-
-	log.Infof("    ✓ Key created")
-	return nil
+	return &client.Operation{Method: "POST", Endpoint: fmt.Sprintf("/services/%s/keys", serviceHash), Payload: payload}, nil
 }
 
-func createServiceRelation(apiClient *client.HTTPClient, token string, sourceHash string, relation models.ServiceRelationConfig, createdResources *[]models.CreatedResource) error {
+// serviceRelationOperation returns the request creating a relation, or nil
+// when skipExisting finds the relation already there
+func serviceRelationOperation(apiClient client.APIClient, token string, sourceHash string, relation models.ServiceRelationConfig, skipExisting bool) (*client.Operation, error) {
 	log := logger.GetLogger()
 
+	if skipExisting {
+		exists, err := existsInList(apiClient, token, fmt.Sprintf("/services/%s/matrix/relations", sourceHash), "related_service_hash", relation.TargetHash)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			log.Infof("    ⊙ Relation already exists, skipping")
+			return nil, nil
+		}
+	}
+
 	payload := map[string]interface{}{
 		"related_service_hash": relation.TargetHash,
 	}
@@ -466,29 +760,26 @@ func createServiceRelation(apiClient *client.HTTPClient, token string, sourceHas
 	if relation.Type != "" {
 		payload["relation_type"] = relation.Type
 	}
-
-	_, err := apiClient.PostWithAuth(fmt.Sprintf("/services/%s/matrix", sourceHash), payload, token)
-	if err != nil {
-		return err
+	if relation.Description != "" {
+		payload["description"] = relation.Description
+	}
+	if relation.Direction != "" {
+		payload["direction"] = relation.Direction
 	}
 
-	*createdResources = append(*createdResources, models.CreatedResource{
-		Type: "relation",
-		Hash: sourceHash,
-		ID:   relation.TargetHash,
-	})
-
-	log.Infof("    ✓ Relation created")
-	return nil
+	return &client.Operation{Method: "POST", Endpoint: fmt.Sprintf("/services/%s/matrix", sourceHash), Payload: payload}, nil
 }
 
-func rollbackResources(apiClient *client.HTTPClient, token string, resources []models.CreatedResource) {
+func rollbackResources(apiClient client.APIClient, token string, resources []models.CreatedResource) {
 	log := logger.GetLogger()
 
 	if len(resources) == 0 {
 		return
 	}
 
+	endSpan := tracing.Span("apply rollback", attribute.Int("certfix.apply.resources", len(resources)))
+	defer endSpan(nil)
+
 	log.Infof("\n=== Rolling Back Resources ===")
 	log.Infof("Deleting %d resources in reverse order...", len(resources))
 
@@ -561,4 +852,5 @@ func init() {
 
 	applyCmd.Flags().Bool("dry-run", false, "Show what would be created without making changes")
 	applyCmd.Flags().Bool("skip-existing", false, "Skip resources that already exist instead of failing")
+	enableNotify(applyCmd)
 }
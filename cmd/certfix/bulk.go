@@ -0,0 +1,285 @@
+package certfix
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/picker"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// collectServiceHashes gathers the service hashes a bulk command should act on.
+// Hashes may be given as a comma-separated argument, as "-" to read them from
+// stdin, through the --from-file flag (one hash per line), picked with
+// --interactive or selected by label with --selector. Service names are accepted in place of hashes. Blank lines and
+// lines starting with '#' are ignored, and duplicates are removed.
+func collectServiceHashes(cmd *cobra.Command, args []string) ([]string, error) {
+	var hashes []string
+
+	if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+		picked, err := pickServices(true)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return nil, err
+		}
+		hashes = append(hashes, picked...)
+	}
+
+	if len(args) > 0 {
+		if args[0] == "-" {
+			fromStdin, err := readHashList(os.Stdin)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read hashes from stdin: %w", err)
+			}
+			hashes = append(hashes, fromStdin...)
+		} else {
+			hashes = append(hashes, strings.Split(args[0], ",")...)
+		}
+	}
+
+	if fromFile, _ := cmd.Flags().GetString("from-file"); fromFile != "" {
+		f, err := os.Open(fromFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open hash file: %w", err)
+		}
+		defer f.Close()
+
+		fileHashes, err := readHashList(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read hash file: %w", err)
+		}
+		hashes = append(hashes, fileHashes...)
+	}
+
+	if selector, _ := cmd.Flags().GetString("selector"); selector != "" {
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return nil, err
+		}
+		selected, err := selectedServiceHashes(newAPIClient(), token, selector)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return nil, err
+		}
+		hashes = append(hashes, selected...)
+	}
+
+	// Exact names are accepted in place of hashes, but not partial ones since
+	// these commands change or delete what they select. Unknown entries are
+	// kept as given so that each command reports them the way it reports
+	// missing services.
+	var resolver *api.Resolver
+	if !resolverFlagUsed(cmd) {
+		resolver = newAPI().NewResolver()
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, hash := range hashes {
+		hash = strings.TrimSpace(hash)
+		if hash == "" {
+			continue
+		}
+		if resolver != nil {
+			match, err := resolver.ResolveExact(api.KindService, hash)
+			var notFound *api.NotFoundError
+			switch {
+			case errors.As(err, &notFound):
+			case err != nil:
+				cmd.SilenceUsage = true
+				return nil, err
+			default:
+				hash = match.ID
+			}
+		}
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		result = append(result, hash)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no service hashes provided (pass a comma-separated list, '-' for stdin, --from-file, --selector, or --interactive)")
+	}
+
+	return result, nil
+}
+
+func readHashList(r io.Reader) ([]string, error) {
+	var hashes []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hashes = append(hashes, line)
+	}
+	return hashes, scanner.Err()
+}
+
+// pickServices fetches all services and lets the user choose one or more of
+// them with the interactive fuzzy picker.
+func pickServices(multi bool) ([]string, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient := newAPIClient()
+
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	var items []picker.Item
+	for _, svc := range parseArrayResponse(response) {
+		status := "inactive"
+		if active, _ := svc["active"].(bool); active {
+			status = "active"
+		}
+		label := fmt.Sprintf("%v  %v  (%s, group: %s)", svc["service_name"], svc["service_hash"], status, stringOrNA(svc, "service_group_name"))
+		items = append(items, picker.Item{Label: label, Value: fmt.Sprintf("%v", svc["service_hash"])})
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no services found")
+	}
+
+	return picker.Pick(items, "Select services", multi)
+}
+
+// runServiceBulk applies fn to every hash, printing one progress line per
+// service and a final summary. It returns an error listing the failed hashes
+// so the command exits non-zero when any operation failed.
+func runServiceBulk(hashes []string, action string, fn func(hash string) error) error {
+	var failed []string
+	for i, hash := range hashes {
+		fmt.Printf("[%d/%d] %s %s... ", i+1, len(hashes), action, hash)
+		if err := fn(hash); err != nil {
+			fmt.Printf("Failed: %v\n", err)
+			failed = append(failed, hash)
+		} else {
+			fmt.Printf("OK\n")
+		}
+	}
+
+	fmt.Printf("\nSummary: %d succeeded, %d failed (total %d)\n", len(hashes)-len(failed), len(failed), len(hashes))
+	if len(failed) > 0 {
+		return fmt.Errorf("operation failed for: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// serviceMembership describes a resource services belong to through a field on
+// the service, such as a policy (policy_id) or a service group
+// (service_group_id).
+type serviceMembership struct {
+	field string // service field holding the owner's ID
+	kind  string // owner name used in messages
+	path  string // API path of the owner, with a %s for its ID
+}
+
+// members returns the services whose membership field equals id
+func (m serviceMembership) members(apiClient client.APIClient, token, id string) ([]map[string]interface{}, error) {
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	var services []map[string]interface{}
+	for _, svc := range parseArrayResponse(response) {
+		if stringOrNA(svc, m.field) == id {
+			services = append(services, svc)
+		}
+	}
+	return services, nil
+}
+
+// setServicesMembership adds the selected services to the owner in args[0], or
+// removes them from it, by updating each service. Services are selected as
+// for collectServiceHashes, and services already in the requested state are
+// skipped.
+func setServicesMembership(cmd *cobra.Command, args []string, m serviceMembership, add bool) error {
+	ownerID := args[0]
+
+	hashes, err := collectServiceHashes(cmd, args[1:])
+	if err != nil {
+		return err
+	}
+
+	// Get authentication token
+	token, err := auth.GetToken()
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	// Create API client
+	apiClient := newAPIClient()
+
+	owner, err := apiClient.GetWithAuth(fmt.Sprintf(m.path, ownerID), token)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to get %s: %w", m.kind, err)
+	}
+
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	current := make(map[string]string)
+	for _, svc := range parseArrayResponse(response) {
+		current[fmt.Sprintf("%v", svc["service_hash"])] = stringOrNA(svc, m.field)
+	}
+
+	var targets []string
+	for _, hash := range hashes {
+		ownedBy, found := current[hash]
+		switch {
+		case !found:
+			fmt.Printf("Skipping %s: service not found\n", hash)
+		case add && ownedBy == ownerID:
+			fmt.Printf("Skipping %s: already in this %s\n", hash, m.kind)
+		case !add && ownedBy != ownerID:
+			fmt.Printf("Skipping %s: not in this %s\n", hash, m.kind)
+		default:
+			targets = append(targets, hash)
+		}
+	}
+
+	if len(targets) == 0 {
+		fmt.Println("Nothing to do.")
+		return nil
+	}
+
+	action, preposition := "Removing", "from"
+	payload := map[string]interface{}{
+		m.field: nil,
+	}
+	if add {
+		action, preposition = "Adding", "to"
+		payload[m.field] = ownerID
+	}
+
+	fmt.Printf("%s %d service(s) %s %s %v\n\n", action, len(targets), preposition, m.kind, owner["name"])
+
+	err = runServiceBulk(targets, action, func(hash string) error {
+		_, err := apiClient.PutWithAuth(fmt.Sprintf("/services/%s", hash), payload, token)
+		return err
+	})
+	if err != nil {
+		cmd.SilenceUsage = true
+	}
+	return err
+}
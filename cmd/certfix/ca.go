@@ -1,12 +1,13 @@
 package certfix
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -29,8 +30,7 @@ var caInfoCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/ca/info", token)
 		if err != nil {
@@ -65,8 +65,7 @@ var caDetailsCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/ca/details", token)
 		if err != nil {
@@ -103,8 +102,7 @@ var caCRLInfoCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/ca/crl/info", token)
 		if err != nil {
@@ -137,8 +135,7 @@ var caCRLContentCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/ca/crl/content", token)
 		if err != nil {
@@ -159,15 +156,113 @@ var caCRLContentCmd = &cobra.Command{
 	},
 }
 
+var caBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the CA and download the backup",
+	Long: `Create a backup of the Certificate Authority and download it to --out, or
+download an existing backup with --id. The SHA-256 checksum the server
+provides is verified before anything is written, and with --verify-key so
+is the server's detached signature; on a mismatch the command fails and no
+file is written. The file is only readable by its owner, since it holds the
+CA's private key.
+
+With --id and --checksum-only nothing is downloaded: the existing file at
+--out is verified against the server's checksum (and signature) instead.`,
+	Example: `  certfix ca backup --out ca-backup.bin
+  certfix ca backup --id 42 --out ca-backup.bin --checksum-only`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backupID, _ := cmd.Flags().GetString("id")
+		outFile, _ := cmd.Flags().GetString("out")
+		verifyKey, _ := cmd.Flags().GetString("verify-key")
+		checksumOnly, _ := cmd.Flags().GetBool("checksum-only")
+
+		if checksumOnly && (backupID == "" || outFile == "") {
+			return fmt.Errorf("--checksum-only needs the --id of the backup and the file to verify in --out")
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		var response map[string]interface{}
+		if backupID != "" {
+			response, err = newAPIClient().GetWithAuth(fmt.Sprintf("/ca/backups/%s", backupID), token)
+		} else {
+			response, err = newAPI().CreateBackup()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get CA backup: %w", err)
+		}
+		if backupID == "" {
+			backupID = stringField(response, "backup_id")
+		}
+
+		content, err := base64.StdEncoding.DecodeString(stringField(response, "data"))
+		if err != nil {
+			return fmt.Errorf("the server returned malformed backup data: %w", err)
+		}
+		if len(content) == 0 && !checksumOnly {
+			fmt.Printf("✓ Backup %s created; the server did not return its content\n", orNone(backupID))
+			return nil
+		}
+		d, err := newDownload(response, content)
+		if err != nil {
+			return err
+		}
+		if outFile == "" && stringField(response, "filename") != "" {
+			// Never let the server pick a directory
+			outFile = filepath.Base(stringField(response, "filename"))
+		}
+		if outFile == "" {
+			outFile = fmt.Sprintf("certfix-ca-backup-%s.bin", backupID)
+		}
+
+		var warning string
+		if checksumOnly {
+			warning, err = verifyLocalFile(outFile, d, verifyKey)
+		} else {
+			warning, err = writeVerified(outFile, d, verifyKey, 0600)
+		}
+		if err != nil {
+			return err
+		}
+		if warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+
+		if checksumOnly {
+			fmt.Printf("✓ %s matches backup %s\n", outFile, backupID)
+		} else {
+			fmt.Printf("✓ Backup %s written to %s\n", orNone(backupID), outFile)
+		}
+		if d.Checksum != "" {
+			fmt.Printf("SHA-256:   %s\n", d.Checksum)
+		}
+		if verifyKey != "" {
+			fmt.Printf("Signature: verified with %s\n", verifyKey)
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(caCmd)
 	caCmd.AddCommand(caInfoCmd)
 	caCmd.AddCommand(caDetailsCmd)
 	caCmd.AddCommand(caCRLInfoCmd)
 	caCmd.AddCommand(caCRLContentCmd)
+	caCmd.AddCommand(caBackupCmd)
 
 	caInfoCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 	caDetailsCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 	caCRLInfoCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 	caCRLContentCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	caBackupCmd.Flags().String("id", "", "Download this existing backup instead of creating one")
+	caBackupCmd.Flags().String("out", "", "File to write (default: the name the server suggests)")
+	caBackupCmd.Flags().String("verify-key", "", "Public key or certificate (PEM) to verify the server's signature with")
+	caBackupCmd.Flags().Bool("checksum-only", false, "Verify the existing file at --out instead of downloading")
 }
@@ -0,0 +1,358 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/cache"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// cachedResource is a resource type 'certfix cache sync' stores. When itemPath
+// is set each item of the list is also stored under its own path so get
+// commands work offline.
+type cachedResource struct {
+	name     string
+	listPath string
+	itemPath string
+	idField  string
+}
+
+var cachedResources = []cachedResource{
+	{name: "services", listPath: "/services", itemPath: "/services/%s", idField: "service_hash"},
+	{name: "policies", listPath: "/policies", itemPath: "/policies/%s", idField: "policy_id"},
+	{name: "events", listPath: "/events", itemPath: "/events/%s", idField: "event_id"},
+	{name: "service groups", listPath: "/service-groups", itemPath: "/service-groups/%s", idField: "service_group_id"},
+	{name: "certificates", listPath: "/certificates"},
+}
+
+// cachedServicePaths are stored for every service. Incremental syncs only
+// fetch them again for services whose updated_at changed.
+var cachedServicePaths = []struct {
+	name string
+	path string
+}{
+	{name: "keys", path: "/services/%s/keys/list"},
+	{name: "service certificates", path: "/services/%s/certificates"},
+	{name: "relations", path: "/services/%s/matrix/relations"},
+}
+
+// cacheSyncConcurrency limits parallel per-service requests during a sync
+const cacheSyncConcurrency = 8
+
+// loadCache returns the local snapshot when it was synced from the configured
+// endpoint
+func loadCache() (*cache.Snapshot, error) {
+	snapshot, err := cache.Load()
+	if err != nil {
+		return nil, err
+	}
+	if endpoint := config.GetAPIEndpoint(); snapshot.Endpoint != endpoint {
+		return nil, fmt.Errorf("local cache was synced from %s, not %s: run 'certfix cache sync' while online", snapshot.Endpoint, endpoint)
+	}
+	return snapshot, nil
+}
+
+// newCacheClient returns the client commands use with --offline
+func newCacheClient() client.APIClient {
+	snapshot, err := loadCache()
+	if err != nil {
+		return cache.NewFailingClient(err)
+	}
+	return cache.NewClient(snapshot, func(s *cache.Snapshot) {
+		// Commands may create several clients; label the data only once
+		offlineNotice.Do(func() {
+			fmt.Fprintf(os.Stderr, "Offline: showing cached data from %s (%s old)\n", s.SyncedAt.Local().Format("2006-01-02 15:04"), formatAge(s.Age()))
+		})
+	})
+}
+
+// offlineNotice prints the age of the cached data once per run
+var offlineNotice sync.Once
+
+// freshCacheAPI returns an internal/api client reading the local cache when
+// it is younger than the cache_ttl setting, for lookups that can fall back to
+// the API. It returns nil when there is no such cache.
+func freshCacheAPI() *api.Client {
+	if offline {
+		return nil
+	}
+	ttl := config.GetCacheTTL()
+	if ttl == 0 {
+		return nil
+	}
+	snapshot, err := loadCache()
+	if err != nil || snapshot.Age() > ttl {
+		return nil
+	}
+	return api.New(cache.NewClient(snapshot, nil))
+}
+
+// serviceVersion returns the updated_at of a cached service, or "" when it is
+// unknown
+func serviceVersion(snapshot *cache.Snapshot, hash string) string {
+	body, ok := snapshot.Lookup(fmt.Sprintf("/services/%s", hash))
+	if !ok {
+		return ""
+	}
+	var service map[string]interface{}
+	if err := json.Unmarshal(body, &service); err != nil || service["updated_at"] == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", service["updated_at"])
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local cache used by --offline",
+	Long: `Manage the local snapshot of services, policies, events, service groups,
+certificates, and each service's keys, certificates and relations.
+
+With --offline, list and get commands read this snapshot instead of the API, so
+inventory queries keep working during API outages or from hosts without access
+to it. Every offline command says how old the data is. Name lookups also read a
+snapshot younger than the cache_ttl setting (default 1h) before asking the API.`,
+}
+
+var cacheSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Refresh the local cache from the API",
+	Long: `Refresh the local cache from the API.
+
+Lists are fetched in full on every sync. Per-service data (keys, certificates
+and relations) is only fetched again for services whose updated_at changed
+since the last sync, unless --full is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		full, _ := cmd.Flags().GetBool("full")
+
+		if offline {
+			return fmt.Errorf("cannot sync the cache in offline mode")
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := api.NewHTTPClient()
+
+		var previous *cache.Snapshot
+		if !full {
+			previous, _ = loadCache()
+		}
+
+		snapshot := cache.New(config.GetAPIEndpoint())
+		snapshot.SyncedAt = time.Now()
+		var services []map[string]interface{}
+		for _, resource := range cachedResources {
+			status, body, err := apiClient.RawWithAuth("GET", resource.listPath, nil, token)
+			var response map[string]interface{}
+			if err == nil {
+				response, err = client.ParseResponse(status, body)
+			}
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to sync %s: %w", resource.name, err)
+			}
+			snapshot.Put(resource.listPath, body)
+
+			items := parseArrayResponse(response)
+			snapshot.Counts[resource.name] = len(items)
+			if resource.listPath == "/services" {
+				services = items
+			}
+			if resource.itemPath == "" {
+				continue
+			}
+			for _, item := range items {
+				if item[resource.idField] == nil {
+					continue
+				}
+				data, _ := json.Marshal(item)
+				snapshot.Put(fmt.Sprintf(resource.itemPath, fmt.Sprintf("%v", item[resource.idField])), data)
+			}
+		}
+
+		// Per-service data, reused from the previous sync for unchanged services
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			sem      = make(chan struct{}, cacheSyncConcurrency)
+			failures []string
+			reused   int
+		)
+		for _, svc := range services {
+			hash := stringOrNA(svc, "service_hash")
+			if hash == "N/A" {
+				continue
+			}
+			if previous != nil && reuseServiceData(previous, snapshot, hash) {
+				reused++
+				continue
+			}
+
+			wg.Add(1)
+			go func(hash string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				for _, p := range cachedServicePaths {
+					path := fmt.Sprintf(p.path, hash)
+					status, body, err := apiClient.RawWithAuth("GET", path, nil, token)
+					if err == nil {
+						_, err = client.ParseResponse(status, body)
+					}
+					mu.Lock()
+					if err != nil {
+						failures = append(failures, fmt.Sprintf("%s of %s: %v", p.name, hash, err))
+					} else {
+						snapshot.Put(path, body)
+					}
+					mu.Unlock()
+				}
+			}(hash)
+		}
+		wg.Wait()
+
+		if err := snapshot.Save(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		sort.Strings(failures)
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "Warning: failed to sync %s\n", failure)
+		}
+		printCacheCounts(snapshot)
+		if previous != nil {
+			fmt.Printf("Per-service data refreshed for %d of %d service(s)\n", len(services)-reused, len(services))
+		}
+		fmt.Printf("✓ Cache synced to %s\n", cache.GetPath())
+		return nil
+	},
+}
+
+// reuseServiceData copies a service's per-service data from the previous
+// snapshot when the service is unchanged and all of it was cached. It reports
+// whether the data was reused.
+func reuseServiceData(previous, snapshot *cache.Snapshot, hash string) bool {
+	version := serviceVersion(previous, hash)
+	if version == "" || version != serviceVersion(snapshot, hash) {
+		return false
+	}
+	bodies := make(map[string][]byte, len(cachedServicePaths))
+	for _, p := range cachedServicePaths {
+		path := fmt.Sprintf(p.path, hash)
+		body, ok := previous.Lookup(path)
+		if !ok {
+			return false
+		}
+		bodies[path] = body
+	}
+	for path, body := range bodies {
+		snapshot.Put(path, body)
+	}
+	return true
+}
+
+// printCacheCounts prints the number of cached items of each resource type
+func printCacheCounts(snapshot *cache.Snapshot) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	for _, resource := range cachedResources {
+		fmt.Fprintf(w, "  %s\t%d\n", resource.name, snapshot.Counts[resource.name])
+	}
+	w.Flush()
+}
+
+var cacheStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show what the local cache holds and how old it is",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		snapshot, err := cache.Load()
+		if err == cache.ErrNoCache {
+			fmt.Println("No local cache. Run 'certfix cache sync' to create one.")
+			return nil
+		}
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		var size int64
+		if info, err := os.Stat(cache.GetPath()); err == nil {
+			size = info.Size()
+		}
+		current := snapshot.Endpoint == config.GetAPIEndpoint()
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"path":        cache.GetPath(),
+				"endpoint":    snapshot.Endpoint,
+				"current":     current,
+				"synced_at":   snapshot.SyncedAt,
+				"age_seconds": int(snapshot.Age().Seconds()),
+				"size_bytes":  size,
+				"counts":      snapshot.Counts,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Path:      %s (%.1f KB)\n", cache.GetPath(), float64(size)/1024)
+		fmt.Printf("Endpoint:  %s\n", snapshot.Endpoint)
+		fmt.Printf("Synced At: %s (%s ago)\n", snapshot.SyncedAt.Local().Format("2006-01-02 15:04"), formatAge(snapshot.Age()))
+		printCacheCounts(snapshot)
+		if !current {
+			fmt.Fprintf(os.Stderr, "Warning: the cache was synced from another endpoint than %s and is not used\n", config.GetAPIEndpoint())
+		}
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete the local cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := cache.Clear()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if !removed {
+			fmt.Println("No local cache to clear.")
+			return nil
+		}
+		fmt.Println("✓ Cache cleared")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheSyncCmd)
+	cacheCmd.AddCommand(cacheStatusCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+
+	// Sync command flags
+	cacheSyncCmd.Flags().Bool("full", false, "Fetch all per-service data instead of only what changed")
+
+	// Status command flags
+	cacheStatusCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
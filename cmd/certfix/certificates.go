@@ -3,15 +3,22 @@ package certfix
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"text/tabwriter"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/certfix/certfix-cli/internal/api"
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/spf13/cobra"
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/term"
 )
 
 var certsCmd = &cobra.Command{
@@ -24,7 +31,13 @@ var certsCmd = &cobra.Command{
 var certsListCmd = &cobra.Command{
 	Use:   "list <service-hash>",
 	Short: "List all certificates for a service",
-	Args:  cobra.ExactArgs(1),
+	Long: `List the certificates of a service, 50 per page by default. --page selects
+another page, --limit changes the page size and --all lists every
+certificate. JSON output lists every certificate unless --limit or --page
+is given.`,
+	Example: `  certfix certs list a1b2c3 --page 2
+  certfix certs list a1b2c3 --sort-by expires_at --limit 10`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceHash := args[0]
 		outputFormat, _ := cmd.Flags().GetString("output")
@@ -35,8 +48,7 @@ var certsListCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates", serviceHash), token)
 		if err != nil {
@@ -55,16 +67,32 @@ var certsListCmd = &cobra.Command{
 			}
 		}
 
+		certs, err = filterByTime(cmd, certs)
+		if err != nil {
+			return err
+		}
+		if err := sortItems(cmd, certSortColumns, certs); err != nil {
+			return err
+		}
+		certs, page, err := paginateItems(cmd, certs, outputFormat != "json")
+		if err != nil {
+			return err
+		}
+
 		if outputFormat == "json" {
 			data, _ := json.MarshalIndent(certs, "", "  ")
 			fmt.Println(string(data))
 			return nil
 		}
 
-		if len(certs) == 0 {
+		if page.Total == 0 {
 			fmt.Println("No certificates found.")
 			return nil
 		}
+		if len(certs) == 0 {
+			printPageFooter(page, "certificates")
+			return nil
+		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 		fmt.Fprintln(w, "UNIQUE ID\tTYPE\tSTATUS\tSERIAL\tCOMMON NAME\tEXPIRES AT")
@@ -88,6 +116,7 @@ var certsListCmd = &cobra.Command{
 			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", uniqueID, certType, status, serial, cn, expiresAt)
 		}
 		w.Flush()
+		printPageFooter(page, "certificates")
 
 		return nil
 	},
@@ -107,8 +136,7 @@ var certsGetCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/certificates/%s/details", uniqueID), token)
 		if err != nil {
@@ -147,6 +175,434 @@ var certsGetCmd = &cobra.Command{
 	},
 }
 
+var certsCreateCmd = &cobra.Command{
+	Use:   "create <common-name>",
+	Short: "Issue a certificate directly from the CA",
+	Long: `Issue a server or client certificate directly from the CA.
+
+Keys are RSA by default. --key-type ecdsa issues an ECDSA key on the curve
+given by --curve (P-256 or P-384, default P-256), and --key-type ed25519 an
+Ed25519 key. --key-size only applies to RSA keys (2048, 3072 or 4096).
+
+Subject alternative names are given with --dns, --ip and --uri, each
+repeatable. They are validated before anything is sent.
+
+--template pre-populates the type, validity, key parameters and key usages
+from a template such as web-server, mtls-client or code-signing; flags given
+explicitly override it. See 'certfix certs templates' for the templates
+available, including those defined on the server and in the config file.
+
+Before anything is sent the subject and the final set of SANs are shown,
+and in a terminal you are asked to confirm (skip with --force or --yes).
+Internationalized names are converted to punycode. Wildcard names require
+--allow-wildcard, and names that are public suffixes, such as co.uk, are
+warned about. --dry-run stops after the preview.`,
+	Example: `  certfix certs create api.example.com --days 90 --dns api.example.com --dns www.example.com
+  certfix certs create mesh-a --ip 10.0.0.1 --uri spiffe://example.org/ns/prod/sa/mesh-a
+  certfix certs create api.example.com --key-type ecdsa --curve P-384
+  certfix certs create worker-1 --type client --client-id worker-1 --key-type ed25519
+  certfix certs create api.example.com --template web-server --days 30 --dns api.example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		certType, _ := cmd.Flags().GetString("type")
+		clientID, _ := cmd.Flags().GetString("client-id")
+		description, _ := cmd.Flags().GetString("description")
+		days, _ := cmd.Flags().GetInt("days")
+		keyType, _ := cmd.Flags().GetString("key-type")
+		keySize, _ := cmd.Flags().GetInt("key-size")
+		curve, _ := cmd.Flags().GetString("curve")
+		templateName, _ := cmd.Flags().GetString("template")
+		allowWildcard, _ := cmd.Flags().GetBool("allow-wildcard")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		req := api.CertificateRequest{
+			CommonName:  args[0],
+			Type:        certType,
+			ClientID:    clientID,
+			Description: description,
+			Days:        days,
+			KeyType:     keyType,
+			KeySize:     keySize,
+			Curve:       curve,
+		}
+		if templateName != "" {
+			token, err := auth.GetToken()
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			template, err := findCertTemplate(newAPIClient(), token, templateName)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			applyCertTemplate(cmd, &req, template)
+		}
+
+		if req.Type != "server" && req.Type != "client" {
+			return fmt.Errorf("invalid type '%s' (valid: server, client)", req.Type)
+		}
+		var err error
+		req.KeyType, req.Curve, err = validateKeyParams(req.KeyType, req.KeySize, req.Curve)
+		if err != nil {
+			return err
+		}
+		req.CommonName, err = validateCommonName(req.CommonName, req.Type)
+		if err != nil {
+			return err
+		}
+		req.SAN, err = subjectAltNames(cmd)
+		if err != nil {
+			return err
+		}
+		warnings, err := checkIssuanceNames(req, allowWildcard)
+		if err != nil {
+			return err
+		}
+
+		printCertificatePreview(req, templateName, warnings)
+		if dryRun {
+			fmt.Fprintln(os.Stderr, "Dry run: no certificate was issued.")
+			return nil
+		}
+		// Scripts are not asked; the preview is enough for their logs
+		if !skipConfirmation(cmd) && term.IsTerminal(int(os.Stdin.Fd())) {
+			confirmed, err := confirm(cmd, "Issue this certificate?")
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Println("Creation cancelled.")
+				return nil
+			}
+		}
+
+		response, err := newAPI().CreateCertificate(req)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to create certificate: %w", err)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(response, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✓ Certificate created successfully\n")
+		fmt.Printf("Unique ID:    %s\n", stringOrNA(response, "unique_id"))
+		fmt.Printf("Common Name:  %s\n", stringOrNA(response, "common_name"))
+		fmt.Printf("Serial:       %s\n", stringOrNA(response, "serial_number"))
+		fmt.Printf("Key:          %s\n", describeKey(req.KeyType, req.KeySize, req.Curve))
+		if templateName != "" {
+			fmt.Printf("Template:     %s\n", templateName)
+		}
+		if response["expires_at"] != nil {
+			fmt.Printf("Expires At:   %v\n", response["expires_at"])
+		}
+
+		return nil
+	},
+}
+
+// subjectAltNames assembles the SAN string sent to the CA, e.g.
+// "DNS:a.example.com,IP:10.0.0.1", from --dns, --ip, --uri and the deprecated
+// --san, validating and deduplicating every entry
+func subjectAltNames(cmd *cobra.Command) (string, error) {
+	dnsNames, _ := cmd.Flags().GetStringArray("dns")
+	ips, _ := cmd.Flags().GetStringArray("ip")
+	uris, _ := cmd.Flags().GetStringArray("uri")
+	legacy, _ := cmd.Flags().GetString("san")
+
+	type entry struct{ kind, value string }
+	var entries []entry
+	for _, name := range dnsNames {
+		entries = append(entries, entry{"DNS", name})
+	}
+	for _, ip := range ips {
+		entries = append(entries, entry{"IP", ip})
+	}
+	for _, uri := range uris {
+		entries = append(entries, entry{"URI", uri})
+	}
+	if legacy != "" {
+		for _, part := range strings.Split(legacy, ",") {
+			kind, value, ok := strings.Cut(strings.TrimSpace(part), ":")
+			if !ok {
+				return "", fmt.Errorf("invalid SAN entry '%s' in --san (use DNS:, IP: or URI: prefixes, or --dns, --ip and --uri)", part)
+			}
+			entries = append(entries, entry{strings.ToUpper(kind), value})
+		}
+	}
+
+	var sans []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		value, err := validateSAN(e.kind, e.value)
+		if err != nil {
+			return "", err
+		}
+		san := e.kind + ":" + value
+		if !seen[san] {
+			seen[san] = true
+			sans = append(sans, san)
+		}
+	}
+	return strings.Join(sans, ","), nil
+}
+
+// validateSAN checks a subject alternative name of the given kind and
+// returns it normalized
+func validateSAN(kind, value string) (string, error) {
+	switch kind {
+	case "DNS":
+		name, err := normalizeDNSName(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid DNS name '%s': %w", value, err)
+		}
+		return name, nil
+	case "IP":
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", fmt.Errorf("invalid IP address '%s'", value)
+		}
+		return ip.String(), nil
+	case "URI":
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			return "", fmt.Errorf("invalid URI '%s' (must be absolute, e.g. spiffe://example.org/service)", value)
+		}
+		if strings.ContainsAny(value, ", \t") {
+			return "", fmt.Errorf("invalid URI '%s': must not contain spaces or commas", value)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("unsupported SAN type '%s' (valid: DNS, IP, URI)", kind)
+}
+
+// normalizeDNSName lowercases a host name, drops its trailing dot, converts
+// internationalized names to their ASCII (punycode) form and checks the
+// result
+func normalizeDNSName(value string) (string, error) {
+	name := strings.ToLower(strings.TrimSuffix(value, "."))
+	if !isASCII(name) {
+		wildcard := strings.HasPrefix(name, "*.")
+		ascii, err := idna.Lookup.ToASCII(strings.TrimPrefix(name, "*."))
+		if err != nil {
+			return "", fmt.Errorf("not a valid internationalized name: %w", err)
+		}
+		name = ascii
+		if wildcard {
+			name = "*." + ascii
+		}
+	}
+	if err := validateDNSName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// isASCII reports whether s only contains ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// validateCommonName checks the common name of a certificate of the given
+// type and returns it normalized. Server certificates are named after a host
+// or an IP address; client certificates may use any name without spaces.
+func validateCommonName(cn, certType string) (string, error) {
+	if cn == "" {
+		return "", fmt.Errorf("the common name must not be empty")
+	}
+	if strings.IndexFunc(cn, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return "", fmt.Errorf("invalid common name '%s': must not contain spaces", cn)
+	}
+	if certType != "server" {
+		return cn, nil
+	}
+	if ip := net.ParseIP(cn); ip != nil {
+		return ip.String(), nil
+	}
+	name, err := normalizeDNSName(cn)
+	if err != nil {
+		return "", fmt.Errorf("invalid common name '%s' for a server certificate: %w", cn, err)
+	}
+	return name, nil
+}
+
+// certificateDNSNames returns the host names a certificate request covers:
+// the common name of a server certificate and the DNS subject alternative
+// names
+func certificateDNSNames(req api.CertificateRequest) []string {
+	var names []string
+	if req.Type == "server" && net.ParseIP(req.CommonName) == nil {
+		names = append(names, req.CommonName)
+	}
+	for _, san := range strings.Split(req.SAN, ",") {
+		if name, ok := strings.CutPrefix(san, "DNS:"); ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// checkIssuanceNames rejects wildcard names unless allowWildcard is set, and
+// returns warnings for names that are public suffixes, such as co.uk, which a
+// certificate should not cover
+func checkIssuanceNames(req api.CertificateRequest, allowWildcard bool) ([]string, error) {
+	var warnings []string
+	for _, name := range certificateDNSNames(req) {
+		base, wildcard := strings.CutPrefix(name, "*.")
+		if wildcard && !allowWildcard {
+			return nil, fmt.Errorf("'%s' is a wildcard name; pass --allow-wildcard to issue a wildcard certificate", name)
+		}
+		suffix, icann := publicsuffix.PublicSuffix(base)
+		if suffix != base || (!icann && !strings.Contains(base, ".")) {
+			continue
+		}
+		if wildcard {
+			warnings = append(warnings, fmt.Sprintf("'%s' covers every domain under the public suffix %s", name, base))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("'%s' is a public suffix, not a domain someone owns", name))
+		}
+	}
+	return warnings, nil
+}
+
+// printCertificatePreview shows on stderr what certs create is about to
+// request, so it stays out of the command's output
+func printCertificatePreview(req api.CertificateRequest, templateName string, warnings []string) {
+	fmt.Fprintln(os.Stderr, "Certificate to issue:")
+	fmt.Fprintf(os.Stderr, "  Subject:   CN=%s\n", req.CommonName)
+	fmt.Fprintf(os.Stderr, "  Type:      %s\n", req.Type)
+	fmt.Fprintf(os.Stderr, "  Key:       %s\n", describeKey(req.KeyType, req.KeySize, req.Curve))
+	if req.Days > 0 {
+		fmt.Fprintf(os.Stderr, "  Validity:  %d days\n", req.Days)
+	} else {
+		fmt.Fprintln(os.Stderr, "  Validity:  the CA's default")
+	}
+	if templateName != "" {
+		fmt.Fprintf(os.Stderr, "  Template:  %s\n", templateName)
+	}
+	if req.SAN == "" {
+		fmt.Fprintln(os.Stderr, "  SANs:      (none)")
+	} else {
+		for i, san := range strings.Split(req.SAN, ",") {
+			label := ""
+			if i == 0 {
+				label = "SANs:"
+			}
+			fmt.Fprintf(os.Stderr, "  %-10s %s\n", label, san)
+		}
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+// validateDNSName checks the syntax of a host name. A wildcard is only
+// accepted as the whole leftmost label.
+func validateDNSName(name string) error {
+	if name == "" {
+		return fmt.Errorf("empty name")
+	}
+	if len(name) > 253 {
+		return fmt.Errorf("longer than 253 characters")
+	}
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if label == "*" && i == 0 && len(labels) > 2 {
+			continue
+		}
+		if label == "" {
+			return fmt.Errorf("empty label")
+		}
+		if len(label) > 63 {
+			return fmt.Errorf("label '%s' is longer than 63 characters", label)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("label '%s' starts or ends with a hyphen", label)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return fmt.Errorf("label '%s' contains '%c' (only letters, digits and hyphens are allowed)", label, r)
+			}
+		}
+	}
+	return nil
+}
+
+// rsaKeySizes are the RSA key sizes the CA issues
+var rsaKeySizes = []int{2048, 3072, 4096}
+
+// ecdsaCurves maps the accepted spellings of the ECDSA curves the CA issues
+// to their names
+var ecdsaCurves = map[string]string{
+	"p-256":      "P-256",
+	"p256":       "P-256",
+	"prime256v1": "P-256",
+	"secp256r1":  "P-256",
+	"p-384":      "P-384",
+	"p384":       "P-384",
+	"secp384r1":  "P-384",
+}
+
+// validateKeyParams checks that the key size and curve fit the key type and
+// returns the key type and curve normalized. The ECDSA curve defaults to P-256.
+func validateKeyParams(keyType string, keySize int, curve string) (string, string, error) {
+	keyType = strings.ToLower(keyType)
+	switch keyType {
+	case "", "rsa":
+		if curve != "" {
+			return "", "", fmt.Errorf("--curve only applies to ECDSA keys (use --key-type ecdsa)")
+		}
+		if keySize != 0 && !slices.Contains(rsaKeySizes, keySize) {
+			return "", "", fmt.Errorf("invalid RSA key size %d (valid: 2048, 3072, 4096)", keySize)
+		}
+		return "rsa", "", nil
+	case "ecdsa":
+		if keySize != 0 {
+			return "", "", fmt.Errorf("--key-size only applies to RSA keys; ECDSA keys take --curve")
+		}
+		if curve == "" {
+			return keyType, "P-256", nil
+		}
+		name, ok := ecdsaCurves[strings.ToLower(curve)]
+		if !ok {
+			return "", "", fmt.Errorf("invalid curve '%s' (valid: P-256, P-384)", curve)
+		}
+		return keyType, name, nil
+	case "ed25519":
+		if keySize != 0 || curve != "" {
+			return "", "", fmt.Errorf("--key-size and --curve do not apply to Ed25519 keys")
+		}
+		return keyType, "", nil
+	}
+	return "", "", fmt.Errorf("invalid key type '%s' (valid: rsa, ecdsa, ed25519)", keyType)
+}
+
+// describeKey names a key for output, e.g. "RSA 4096", "ECDSA P-384" or
+// "Ed25519"
+func describeKey(keyType string, keySize int, curve string) string {
+	switch keyType {
+	case "ecdsa":
+		return "ECDSA " + curve
+	case "ed25519":
+		return "Ed25519"
+	}
+	if keySize == 0 {
+		return "RSA (server default size)"
+	}
+	return fmt.Sprintf("RSA %d", keySize)
+}
+
 var certsRevokeCmd = &cobra.Command{
 	Use:   "revoke <unique-id>",
 	Short: "Revoke a certificate",
@@ -154,17 +610,19 @@ var certsRevokeCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		uniqueID := args[0]
 		reason, _ := cmd.Flags().GetString("reason")
-		force, _ := cmd.Flags().GetBool("force")
 		outputFormat, _ := cmd.Flags().GetString("output")
 
-		if !force {
-			fmt.Printf("Are you sure you want to revoke certificate %s? (y/N): ", uniqueID)
-			var ans string
-			fmt.Scanln(&ans)
-			if strings.ToLower(ans) != "y" && strings.ToLower(ans) != "yes" {
-				fmt.Println("Revocation cancelled.")
-				return nil
-			}
+		if err := requirePermission(cmd, "cert:revoke"); err != nil {
+			return err
+		}
+
+		confirmed, err := confirmTyped(cmd, fmt.Sprintf("Revoking certificate %s cannot be undone.", uniqueID), uniqueID)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Revocation cancelled.")
+			return nil
 		}
 
 		token, err := auth.GetToken()
@@ -173,8 +631,7 @@ var certsRevokeCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		payload := map[string]interface{}{}
 		if reason != "" {
@@ -203,16 +660,50 @@ var certsRevokeCmd = &cobra.Command{
 	},
 }
 
+// certSortColumns are the --sort-by columns of 'certs list'
+var certSortColumns = sortColumns{
+	"unique_id":   "unique_id",
+	"type":        "certificate_type",
+	"status":      "status",
+	"serial":      "serial_number",
+	"common_name": "common_name",
+	"expires_at":  "expires_at",
+}
+
 func init() {
 	rootCmd.AddCommand(certsCmd)
 	certsCmd.AddCommand(certsListCmd)
 	certsCmd.AddCommand(certsGetCmd)
+	certsCmd.AddCommand(certsCreateCmd)
 	certsCmd.AddCommand(certsRevokeCmd)
 
 	certsListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	enableSorting(certSortColumns, certsListCmd)
+	enableTimeFilters(true, certsListCmd)
+	enablePagination(50, certsListCmd)
 	certsGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 
+	certsCreateCmd.Flags().StringP("type", "t", "server", "Certificate type (server, client)")
+	certsCreateCmd.Flags().String("client-id", "", "Client ID embedded in client certificates")
+	certsCreateCmd.Flags().StringP("description", "d", "", "Description of the certificate")
+	certsCreateCmd.Flags().Int("days", 0, "Validity in days (default: the CA's default)")
+	certsCreateCmd.Flags().String("key-type", "rsa", "Key type (rsa, ecdsa, ed25519)")
+	certsCreateCmd.Flags().Int("key-size", 0, "RSA key size (2048, 3072, 4096; default: the CA's default)")
+	certsCreateCmd.Flags().String("curve", "", "ECDSA curve (P-256, P-384; default P-256)")
+	certsCreateCmd.Flags().StringArray("dns", nil, "DNS subject alternative name (repeatable)")
+	certsCreateCmd.Flags().StringArray("ip", nil, "IP address subject alternative name (repeatable)")
+	certsCreateCmd.Flags().StringArray("uri", nil, "URI subject alternative name, e.g. a SPIFFE ID (repeatable)")
+	certsCreateCmd.Flags().Bool("allow-wildcard", false, "Allow wildcard names such as *.example.com")
+	certsCreateCmd.Flags().Bool("dry-run", false, "Show the certificate that would be requested without issuing it")
+	certsCreateCmd.Flags().BoolP("force", "f", false, "Skip the confirmation prompt")
+	certsCreateCmd.Flags().String("template", "", "Certificate template to start from (see certs templates)")
+	certsCreateCmd.Flags().String("san", "", "Subject alternative names (e.g. \"DNS:www.example.com,IP:10.0.0.1\")")
+	certsCreateCmd.Flags().MarkDeprecated("san", "use --dns, --ip and --uri instead")
+	certsCreateCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
 	certsRevokeCmd.Flags().StringP("reason", "r", "", "Revocation reason (e.g. cessationOfOperation, superseded, keyCompromise)")
 	certsRevokeCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 	certsRevokeCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	enableIdentifierResolver(api.KindService, certsListCmd)
 }
@@ -0,0 +1,294 @@
+package certfix
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// auditSeverities ranks the severities of audit findings
+var auditSeverities = map[string]int{
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// auditFinding is one problem certs audit found in a certificate
+type auditFinding struct {
+	Severity   string `json:"severity"`
+	Check      string `json:"check"`
+	UniqueID   string `json:"unique_id"`
+	CommonName string `json:"common_name"`
+	Detail     string `json:"detail"`
+}
+
+// auditReport is what certs audit reports
+type auditReport struct {
+	CertificatesScanned int            `json:"certificates_scanned"`
+	Counts              map[string]int `json:"counts"`
+	Findings            []auditFinding `json:"findings"`
+}
+
+// certFacts are the properties of a certificate the audit checks. Unknown
+// values are left zero and their checks are skipped.
+type certFacts struct {
+	KeyType            string
+	KeySize            int
+	Curve              string
+	SignatureAlgorithm string
+	NotBefore          time.Time
+	NotAfter           time.Time
+	SANs               []string
+}
+
+// parseCertificatePEM returns the X.509 certificate embedded in a
+// certificate record, nil when the record has none
+func parseCertificatePEM(cert map[string]interface{}) *x509.Certificate {
+	for _, key := range []string{"certificate_pem", "certificate", "pem"} {
+		text, ok := cert[key].(string)
+		if !ok {
+			continue
+		}
+		block, _ := pem.Decode([]byte(text))
+		if block == nil || block.Type != "CERTIFICATE" {
+			continue
+		}
+		if parsed, err := x509.ParseCertificate(block.Bytes); err == nil {
+			return parsed
+		}
+	}
+	return nil
+}
+
+// certificateFacts reads the facts of a certificate record from its PEM when
+// it has one, and from the record's fields otherwise
+func certificateFacts(cert map[string]interface{}) certFacts {
+	if parsed := parseCertificatePEM(cert); parsed != nil {
+		facts := certFacts{
+			SignatureAlgorithm: parsed.SignatureAlgorithm.String(),
+			NotBefore:          parsed.NotBefore,
+			NotAfter:           parsed.NotAfter,
+		}
+		switch key := parsed.PublicKey.(type) {
+		case *rsa.PublicKey:
+			facts.KeyType, facts.KeySize = "rsa", key.N.BitLen()
+		case *ecdsa.PublicKey:
+			facts.KeyType, facts.Curve = "ecdsa", key.Curve.Params().Name
+		case ed25519.PublicKey:
+			facts.KeyType = "ed25519"
+		}
+		for _, name := range parsed.DNSNames {
+			facts.SANs = append(facts.SANs, "DNS:"+name)
+		}
+		for _, ip := range parsed.IPAddresses {
+			facts.SANs = append(facts.SANs, "IP:"+ip.String())
+		}
+		for _, uri := range parsed.URIs {
+			facts.SANs = append(facts.SANs, "URI:"+uri.String())
+		}
+		return facts
+	}
+
+	facts := certFacts{
+		KeyType:            strings.ToLower(stringField(cert, "key_type")),
+		KeySize:            int(toFloat(cert["key_size"])),
+		Curve:              stringField(cert, "curve"),
+		SignatureAlgorithm: stringField(cert, "signature_algorithm"),
+	}
+	facts.NotBefore, _ = time.Parse(time.RFC3339, firstField(cert, "issued_at", "created_at"))
+	facts.NotAfter, _ = time.Parse(time.RFC3339, stringField(cert, "expires_at"))
+	if san := stringField(cert, "san"); san != "" {
+		for _, name := range strings.Split(san, ",") {
+			facts.SANs = append(facts.SANs, strings.TrimSpace(name))
+		}
+	}
+	return facts
+}
+
+// auditCertificate returns the findings for one certificate. Validity longer
+// than maxValidity is reported.
+func auditCertificate(cert map[string]interface{}, maxValidity time.Duration) []auditFinding {
+	facts := certificateFacts(cert)
+	certType := stringField(cert, "certificate_type")
+
+	var findings []auditFinding
+	add := func(severity, check, format string, args ...interface{}) {
+		findings = append(findings, auditFinding{
+			Severity:   severity,
+			Check:      check,
+			UniqueID:   stringOrNA(cert, "unique_id"),
+			CommonName: stringOrNA(cert, "common_name"),
+			Detail:     fmt.Sprintf(format, args...),
+		})
+	}
+
+	switch {
+	case facts.KeyType == "rsa" && facts.KeySize > 0 && facts.KeySize < 2048:
+		add("critical", "weak-key", "%d-bit RSA key (at least 2048 bits expected)", facts.KeySize)
+	case facts.KeyType == "ecdsa" && (facts.Curve == "P-224" || facts.Curve == "P-192"):
+		add("high", "weak-key", "ECDSA key on %s (P-256 or stronger expected)", facts.Curve)
+	}
+
+	signature := strings.ToUpper(strings.ReplaceAll(facts.SignatureAlgorithm, "-", ""))
+	switch {
+	case strings.Contains(signature, "MD5") || strings.Contains(signature, "MD2"):
+		add("critical", "weak-signature", "signed with %s", facts.SignatureAlgorithm)
+	case strings.Contains(signature, "SHA1"):
+		add("high", "weak-signature", "signed with %s (SHA-256 or stronger expected)", facts.SignatureAlgorithm)
+	}
+
+	if !facts.NotBefore.IsZero() && !facts.NotAfter.IsZero() {
+		if validity := facts.NotAfter.Sub(facts.NotBefore); validity > maxValidity {
+			severity := "low"
+			if certType == "server" {
+				severity = "medium"
+			}
+			add(severity, "long-validity", "valid for %d days (at most %d expected)", int(validity.Hours()/24), int(maxValidity.Hours()/24))
+		}
+	}
+
+	if certType == "server" && len(facts.SANs) == 0 {
+		add("medium", "missing-san", "no subject alternative names; TLS clients ignore the common name")
+	}
+	return findings
+}
+
+var certsAuditCmd = &cobra.Command{
+	Use:   "audit [service-hash]",
+	Short: "Scan issued certificates for weak cryptography",
+	Long: `Scan the issued certificates, or those of one service, for:
+
+  weak-key         RSA keys under 2048 bits (critical), ECDSA keys on curves
+                   weaker than P-256 (high)
+  weak-signature   MD5 (critical) or SHA-1 (high) signatures
+  long-validity    validity longer than --max-validity (medium for server
+                   certificates, low for client certificates)
+  missing-san      server certificates without subject alternative names
+                   (medium)
+
+The certificate itself is inspected when the server returns its PEM, and the
+certificate's metadata otherwise; checks whose data is unknown are skipped.
+With --fail-on the command exits non-zero when a finding of that severity or
+higher is found, for use in CI.`,
+	Example: `  certfix certs audit
+  certfix certs audit a1b2c3 --max-validity 90d
+  certfix certs audit --fail-on high -o json > audit.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+		maxValidityFlag, _ := cmd.Flags().GetString("max-validity")
+		minSeverity, _ := cmd.Flags().GetString("min-severity")
+		failOn, _ := cmd.Flags().GetString("fail-on")
+
+		maxValidity, err := parseHumanDuration(maxValidityFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --max-validity: %w", err)
+		}
+		for _, severity := range []string{minSeverity, failOn} {
+			if _, ok := auditSeverities[severity]; !ok && severity != "" {
+				return fmt.Errorf("invalid severity '%s' (valid: low, medium, high, critical)", severity)
+			}
+		}
+
+		var certs []map[string]interface{}
+		if len(args) == 1 {
+			token, err := auth.GetToken()
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			response, err := newAPIClient().GetWithAuth(fmt.Sprintf("/services/%s/certificates", args[0]), token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to list certificates: %w", err)
+			}
+			for _, cert := range parseArrayResponse(response) {
+				if !strings.EqualFold(stringField(cert, "status"), "revoked") {
+					certs = append(certs, cert)
+				}
+			}
+		} else {
+			certs, err = newAPI().ListValidCertificates()
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to list certificates: %w", err)
+			}
+		}
+
+		report := &auditReport{
+			CertificatesScanned: len(certs),
+			Counts:              make(map[string]int),
+			Findings:            []auditFinding{},
+		}
+		for _, cert := range certs {
+			for _, finding := range auditCertificate(cert, maxValidity) {
+				if auditSeverities[finding.Severity] < auditSeverities[minSeverity] {
+					continue
+				}
+				report.Findings = append(report.Findings, finding)
+				report.Counts[finding.Severity]++
+			}
+		}
+		sort.SliceStable(report.Findings, func(i, j int) bool {
+			return auditSeverities[report.Findings[i].Severity] > auditSeverities[report.Findings[j].Severity]
+		})
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else if len(report.Findings) == 0 {
+			fmt.Printf("✓ No findings in %d certificates\n", report.CertificatesScanned)
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "SEVERITY\tCHECK\tUNIQUE ID\tCOMMON NAME\tDETAIL")
+			fmt.Fprintln(w, "--------\t-----\t---------\t-----------\t------")
+			for _, f := range report.Findings {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", strings.ToUpper(f.Severity), f.Check, f.UniqueID, f.CommonName, f.Detail)
+			}
+			w.Flush()
+
+			var counts []string
+			for _, severity := range []string{"critical", "high", "medium", "low"} {
+				if n := report.Counts[severity]; n > 0 {
+					counts = append(counts, fmt.Sprintf("%d %s", n, severity))
+				}
+			}
+			fmt.Printf("\n%d findings in %d certificates (%s)\n", len(report.Findings), report.CertificatesScanned, strings.Join(counts, ", "))
+		}
+
+		if failOn != "" {
+			failing := 0
+			for _, f := range report.Findings {
+				if auditSeverities[f.Severity] >= auditSeverities[failOn] {
+					failing++
+				}
+			}
+			if failing > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d findings of severity %s or higher", failing, failOn)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	certsCmd.AddCommand(certsAuditCmd)
+	certsAuditCmd.Flags().String("max-validity", "398d", "Longest acceptable validity (e.g. 398d, 90d)")
+	certsAuditCmd.Flags().String("min-severity", "low", "Hide findings below this severity (low, medium, high, critical)")
+	certsAuditCmd.Flags().String("fail-on", "", "Exit non-zero on findings of this severity or higher")
+	certsAuditCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
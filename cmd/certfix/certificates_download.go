@@ -0,0 +1,90 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+var certsDownloadCmd = &cobra.Command{
+	Use:   "download <unique-id>",
+	Short: "Download a certificate's PEM after verifying its checksum",
+	Long: `Download the PEM of a certificate to --out. The SHA-256 checksum the server
+provides is verified before anything is written, and with --verify-key so
+is the server's detached signature; on a mismatch the command fails and no
+file is written.
+
+With --checksum-only nothing is downloaded: the existing file at --out is
+verified against the server's checksum (and signature) instead.`,
+	Example: `  certfix certs download 7c9e6679 --out api.pem
+  certfix certs download 7c9e6679 --out api.pem --verify-key ca-signing.pub
+  certfix certs download 7c9e6679 --out /etc/nginx/tls/api.pem --checksum-only`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		uniqueID := args[0]
+		outFile, _ := cmd.Flags().GetString("out")
+		verifyKey, _ := cmd.Flags().GetString("verify-key")
+		checksumOnly, _ := cmd.Flags().GetBool("checksum-only")
+
+		if outFile == "" {
+			outFile = uniqueID + ".pem"
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+		cmd.SilenceUsage = true
+
+		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/certificates/%s/details", uniqueID), token)
+		if err != nil {
+			return fmt.Errorf("failed to get certificate: %w", err)
+		}
+		pemData := firstField(response, "certificate_pem", "certificate", "pem")
+		if pemData == "" && !checksumOnly {
+			return fmt.Errorf("the server returned no PEM for certificate %s", uniqueID)
+		}
+		d, err := newDownload(response, []byte(pemData))
+		if err != nil {
+			return err
+		}
+
+		var warning string
+		if checksumOnly {
+			warning, err = verifyLocalFile(outFile, d, verifyKey)
+		} else {
+			warning, err = writeVerified(outFile, d, verifyKey, 0644)
+		}
+		if err != nil {
+			return err
+		}
+		if warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+
+		if checksumOnly {
+			fmt.Printf("✓ %s matches certificate %s\n", outFile, uniqueID)
+		} else {
+			fmt.Printf("✓ Certificate %s written to %s\n", uniqueID, outFile)
+		}
+		if d.Checksum != "" {
+			fmt.Printf("SHA-256:   %s\n", d.Checksum)
+		}
+		if verifyKey != "" {
+			fmt.Printf("Signature: verified with %s\n", verifyKey)
+		}
+		return nil
+	},
+}
+
+func init() {
+	certsCmd.AddCommand(certsDownloadCmd)
+	certsDownloadCmd.Flags().String("out", "", "File to write (default: <unique-id>.pem)")
+	certsDownloadCmd.Flags().String("verify-key", "", "Public key or certificate (PEM) to verify the server's signature with")
+	certsDownloadCmd.Flags().Bool("checksum-only", false, "Verify the existing file at --out instead of downloading")
+}
@@ -0,0 +1,241 @@
+package certfix
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// certMatch is a certificate found by certs find, with its owners
+type certMatch struct {
+	UniqueID    string             `json:"unique_id"`
+	CommonName  string             `json:"common_name"`
+	Serial      string             `json:"serial_number"`
+	Fingerprint string             `json:"fingerprint_sha256,omitempty"`
+	Type        string             `json:"type"`
+	Status      string             `json:"status"`
+	ExpiresAt   string             `json:"expires_at,omitempty"`
+	Services    []inventoryService `json:"services"`
+}
+
+var certsFindCmd = &cobra.Command{
+	Use:   "find",
+	Short: "Find the record of a certificate by serial number or fingerprint",
+	Long: `Resolve a certificate seen on the wire or on a host back to its record and
+the services that own it. Give its serial number in hex (--serial), its
+SHA-256 fingerprint (--fingerprint), or the PEM file itself (--file).
+Colons, spaces and letter case are ignored, so the values can be pasted from
+openssl output.
+
+Fingerprints are matched against the fingerprint the server reports, or
+computed from the certificate's PEM when the server returns it.`,
+	Example: `  certfix certs find --serial 4F:1A:09:C2
+  certfix certs find --fingerprint sha256:9c1e...
+  certfix certs find --file /etc/nginx/tls/unknown.pem`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serial, _ := cmd.Flags().GetString("serial")
+		fingerprint, _ := cmd.Flags().GetString("fingerprint")
+		file, _ := cmd.Flags().GetString("file")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		given := 0
+		for _, v := range []string{serial, fingerprint, file} {
+			if v != "" {
+				given++
+			}
+		}
+		if given != 1 {
+			return fmt.Errorf("give exactly one of --serial, --fingerprint or --file")
+		}
+		if file != "" {
+			var err error
+			serial, fingerprint, err = identifyPEMFile(file)
+			if err != nil {
+				return err
+			}
+		}
+		if serial != "" {
+			serial = normalizeSerial(serial)
+		}
+		fingerprint = normalizeFingerprint(fingerprint)
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		certs, err := listAllCertificates()
+		if err != nil {
+			return fmt.Errorf("failed to list certificates: %w", err)
+		}
+
+		var found map[string]interface{}
+		for _, cert := range certs {
+			if fingerprint != "" && certificateFingerprint(cert) == fingerprint {
+				found = cert
+				break
+			}
+			if serial != "" && normalizeSerial(stringField(cert, "serial_number")) == serial {
+				found = cert
+				break
+			}
+		}
+		if found == nil {
+			if fingerprint != "" {
+				return fmt.Errorf("no certificate with fingerprint %s was issued by this CertFix instance", fingerprint)
+			}
+			return fmt.Errorf("no certificate with serial number %s was issued by this CertFix instance", serial)
+		}
+
+		match := certMatch{
+			UniqueID:    stringField(found, "unique_id"),
+			CommonName:  stringField(found, "common_name"),
+			Serial:      stringField(found, "serial_number"),
+			Fingerprint: certificateFingerprint(found),
+			Type:        stringField(found, "certificate_type"),
+			Status:      stringField(found, "status"),
+			ExpiresAt:   stringField(found, "expires_at"),
+		}
+		match.Services, err = certificateOwners(token, found)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(match, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Unique ID:    %s\n", match.UniqueID)
+		fmt.Printf("Common Name:  %s\n", orNone(match.CommonName))
+		fmt.Printf("Type:         %s\n", orNone(match.Type))
+		fmt.Printf("Status:       %s\n", orNone(match.Status))
+		fmt.Printf("Serial:       %s\n", orNone(match.Serial))
+		if match.Fingerprint != "" {
+			fmt.Printf("SHA-256:      %s\n", match.Fingerprint)
+		}
+		fmt.Printf("Expires At:   %s\n", formatTimestamp(match.ExpiresAt, "2006-01-02 15:04", "N/A"))
+		if len(match.Services) == 0 {
+			fmt.Println("Services:     (none; issued directly from the CA)")
+		}
+		for i, svc := range match.Services {
+			label := ""
+			if i == 0 {
+				label = "Services:"
+			}
+			fmt.Printf("%-13s %s (%s)\n", label, svc.Hash, orNone(svc.Name))
+		}
+		return nil
+	},
+}
+
+// identifyPEMFile returns the serial number and SHA-256 fingerprint of the
+// first certificate in a PEM file
+func identifyPEMFile(path string) (string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read certificate file: %w", err)
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return "", "", fmt.Errorf("no PEM certificate found in %s", path)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse the certificate in %s: %w", path, err)
+		}
+		sum := sha256.Sum256(cert.Raw)
+		return cert.SerialNumber.Text(16), hex.EncodeToString(sum[:]), nil
+	}
+}
+
+// certificateFingerprint returns the SHA-256 fingerprint of a certificate
+// record, from its PEM when it has one and as the server reports it
+// otherwise
+func certificateFingerprint(cert map[string]interface{}) string {
+	if parsed := parseCertificatePEM(cert); parsed != nil {
+		sum := sha256.Sum256(parsed.Raw)
+		return hex.EncodeToString(sum[:])
+	}
+	return normalizeFingerprint(firstField(cert, "fingerprint_sha256", "fingerprint"))
+}
+
+// normalizeSerial puts a hex serial number in a comparable form: lowercase,
+// without separators, a 0x prefix or leading zeros
+func normalizeSerial(serial string) string {
+	serial = strings.ToLower(strings.NewReplacer(":", "", " ", "", "-", "").Replace(serial))
+	serial = strings.TrimPrefix(serial, "serial=")
+	serial = strings.TrimPrefix(serial, "0x")
+	serial = strings.TrimLeft(serial, "0")
+	if serial == "" {
+		return "0"
+	}
+	return serial
+}
+
+// normalizeFingerprint puts a SHA-256 fingerprint in a comparable form:
+// lowercase hex without separators or a sha256 prefix
+func normalizeFingerprint(fingerprint string) string {
+	fingerprint = strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fingerprint))
+	for _, prefix := range []string{"sha256fingerprint=", "sha256=", "sha256:"} {
+		fingerprint = strings.TrimPrefix(fingerprint, prefix)
+	}
+	return fingerprint
+}
+
+// certificateOwners returns the services that own a certificate, from the
+// record itself when it names its service, and by looking through the
+// certificates of every service otherwise
+func certificateOwners(token string, cert map[string]interface{}) ([]inventoryService, error) {
+	if hash := stringField(cert, "service_hash"); hash != "" {
+		return []inventoryService{{Hash: hash, Name: stringField(cert, "service_name")}}, nil
+	}
+
+	apiClient := newAPIClient()
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	id := stringField(cert, "unique_id")
+	owners := []inventoryService{}
+	for _, svc := range parseArrayResponse(response) {
+		hash := stringField(svc, "service_hash")
+		owned, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates", hash), token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping the certificates of service %s: %v\n", hash, err)
+			continue
+		}
+		for _, c := range parseArrayResponse(owned) {
+			if stringField(c, "unique_id") == id {
+				owners = append(owners, inventoryService{Hash: hash, Name: stringField(svc, "service_name")})
+				break
+			}
+		}
+	}
+	return owners, nil
+}
+
+func init() {
+	certsCmd.AddCommand(certsFindCmd)
+	certsFindCmd.Flags().String("serial", "", "Serial number in hex, e.g. 4F:1A:09:C2")
+	certsFindCmd.Flags().String("fingerprint", "", "SHA-256 fingerprint in hex")
+	certsFindCmd.Flags().String("file", "", "PEM file holding the certificate to look up")
+	certsFindCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
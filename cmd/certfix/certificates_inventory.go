@@ -0,0 +1,336 @@
+package certfix
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// inventoryService is a service that owns a certificate
+type inventoryService struct {
+	Hash string `json:"hash"`
+	Name string `json:"name,omitempty"`
+}
+
+// inventoryCertificate is one certificate of the inventory
+type inventoryCertificate struct {
+	UniqueID           string             `json:"unique_id"`
+	Subject            string             `json:"subject"`
+	Issuer             string             `json:"issuer,omitempty"`
+	Serial             string             `json:"serial_number,omitempty"`
+	Type               string             `json:"type,omitempty"`
+	Status             string             `json:"status,omitempty"`
+	KeyAlgorithm       string             `json:"key_algorithm,omitempty"`
+	SignatureAlgorithm string             `json:"signature_algorithm,omitempty"`
+	SANs               []string           `json:"sans,omitempty"`
+	NotBefore          string             `json:"not_before,omitempty"`
+	NotAfter           string             `json:"not_after,omitempty"`
+	Services           []inventoryService `json:"services,omitempty"`
+}
+
+// certInventory is the inventory in the CLI's own JSON format
+type certInventory struct {
+	GeneratedAt  time.Time              `json:"generated_at"`
+	Endpoint     string                 `json:"endpoint"`
+	Certificates []inventoryCertificate `json:"certificates"`
+}
+
+var certsInventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Export the certificate inventory for security tooling",
+	Long: `Export every certificate with its subject, issuer, serial number, key and
+signature algorithms, validity and the services that own it.
+
+--format cyclonedx writes a CycloneDX 1.6 BOM in which each certificate is a
+cryptographic-asset component and each service an application component that
+depends on its certificates, for tools that track cryptographic assets.
+--format json writes the CLI's own, flatter format. Revoked certificates are
+left out unless --include-revoked is given.`,
+	Example: `  certfix certs inventory --format cyclonedx --out certs.cdx.json
+  certfix certs inventory --format json --include-revoked`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		outFile, _ := cmd.Flags().GetString("out")
+		includeRevoked, _ := cmd.Flags().GetBool("include-revoked")
+
+		if format != "cyclonedx" && format != "json" {
+			return fmt.Errorf("invalid format '%s' (valid: cyclonedx, json)", format)
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		inventory, err := buildCertInventory(token, includeRevoked)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		var document interface{} = inventory
+		if format == "cyclonedx" {
+			document = cycloneDXInventory(inventory)
+		}
+		data, _ := json.MarshalIndent(document, "", "  ")
+
+		out := io.Writer(os.Stdout)
+		if outFile != "" {
+			f, err := os.Create(outFile)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+		fmt.Fprintln(out, string(data))
+
+		if outFile != "" {
+			fmt.Printf("✓ Exported %d certificates to %s\n", len(inventory.Certificates), outFile)
+		}
+		return nil
+	},
+}
+
+// buildCertInventory lists the certificates and finds the services that own
+// them. A service whose certificates cannot be listed is skipped with a
+// warning.
+func buildCertInventory(token string, includeRevoked bool) (*certInventory, error) {
+	var certs []map[string]interface{}
+	var err error
+	if includeRevoked {
+		certs, err = listAllCertificates()
+	} else {
+		certs, err = newAPI().ListValidCertificates()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+
+	apiClient := newAPIClient()
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	byID := make(map[string]*inventoryCertificate)
+	var order []string
+	add := func(cert map[string]interface{}) *inventoryCertificate {
+		id := stringField(cert, "unique_id")
+		if entry, ok := byID[id]; ok {
+			return entry
+		}
+		entry := newInventoryCertificate(cert)
+		byID[id] = &entry
+		order = append(order, id)
+		return &entry
+	}
+	for _, cert := range certs {
+		add(cert)
+	}
+
+	for _, svc := range parseArrayResponse(response) {
+		hash := stringField(svc, "service_hash")
+		owned, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates", hash), token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping the certificates of service %s: %v\n", hash, err)
+			continue
+		}
+		for _, cert := range parseArrayResponse(owned) {
+			if !includeRevoked && strings.EqualFold(stringField(cert, "status"), "revoked") {
+				continue
+			}
+			entry := add(cert)
+			entry.Services = append(entry.Services, inventoryService{Hash: hash, Name: stringField(svc, "service_name")})
+		}
+	}
+
+	inventory := &certInventory{
+		GeneratedAt:  time.Now().UTC(),
+		Endpoint:     config.GetAPIEndpoint(),
+		Certificates: make([]inventoryCertificate, 0, len(order)),
+	}
+	for _, id := range order {
+		inventory.Certificates = append(inventory.Certificates, *byID[id])
+	}
+	sort.SliceStable(inventory.Certificates, func(i, j int) bool {
+		return inventory.Certificates[i].NotAfter < inventory.Certificates[j].NotAfter
+	})
+	return inventory, nil
+}
+
+// newInventoryCertificate picks the inventory fields out of a certificate as
+// the API returns it
+func newInventoryCertificate(cert map[string]interface{}) inventoryCertificate {
+	entry := inventoryCertificate{
+		UniqueID:           stringField(cert, "unique_id"),
+		Subject:            "CN=" + stringField(cert, "common_name"),
+		Issuer:             stringField(cert, "issuer"),
+		Serial:             stringField(cert, "serial_number"),
+		Type:               stringField(cert, "certificate_type"),
+		Status:             stringField(cert, "status"),
+		KeyAlgorithm:       stringField(cert, "key_algorithm"),
+		SignatureAlgorithm: stringField(cert, "signature_algorithm"),
+		NotBefore:          firstField(cert, "issued_at", "created_at"),
+		NotAfter:           stringField(cert, "expires_at"),
+	}
+	if keyType := stringField(cert, "key_type"); keyType != "" {
+		entry.KeyAlgorithm = describeKey(keyType, int(toFloat(cert["key_size"])), stringField(cert, "curve"))
+	}
+	if san := stringField(cert, "san"); san != "" {
+		for _, name := range strings.Split(san, ",") {
+			entry.SANs = append(entry.SANs, strings.TrimSpace(name))
+		}
+	}
+	return entry
+}
+
+// firstField returns the first of keys that is set in m
+func firstField(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v := stringField(m, key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// cdxProperty is a CycloneDX name/value property
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cycloneDXInventory converts the inventory to a CycloneDX 1.6 BOM. The
+// fields CycloneDX has no place for, such as the serial number, are
+// recorded as certfix: properties.
+func cycloneDXInventory(inventory *certInventory) map[string]interface{} {
+	components := []interface{}{}
+	owned := make(map[string][]string)
+	services := make(map[string]inventoryService)
+
+	for _, cert := range inventory.Certificates {
+		ref := "certificate:" + cert.UniqueID
+		certificateProperties := map[string]interface{}{
+			"subjectName":       cert.Subject,
+			"certificateFormat": "X.509",
+		}
+		if cert.Issuer != "" {
+			certificateProperties["issuerName"] = cert.Issuer
+		}
+		if cert.NotBefore != "" {
+			certificateProperties["notValidBefore"] = cert.NotBefore
+		}
+		if cert.NotAfter != "" {
+			certificateProperties["notValidAfter"] = cert.NotAfter
+		}
+
+		var properties []cdxProperty
+		addProperty := func(name, value string) {
+			if value != "" {
+				properties = append(properties, cdxProperty{Name: "certfix:" + name, Value: value})
+			}
+		}
+		addProperty("unique_id", cert.UniqueID)
+		addProperty("serial_number", cert.Serial)
+		addProperty("type", cert.Type)
+		addProperty("status", cert.Status)
+		addProperty("key_algorithm", cert.KeyAlgorithm)
+		addProperty("signature_algorithm", cert.SignatureAlgorithm)
+		for _, san := range cert.SANs {
+			addProperty("san", san)
+		}
+
+		component := map[string]interface{}{
+			"type":    "cryptographic-asset",
+			"bom-ref": ref,
+			"name":    strings.TrimPrefix(cert.Subject, "CN="),
+			"cryptoProperties": map[string]interface{}{
+				"assetType":             "certificate",
+				"certificateProperties": certificateProperties,
+			},
+		}
+		if len(properties) > 0 {
+			component["properties"] = properties
+		}
+		components = append(components, component)
+
+		for _, svc := range cert.Services {
+			services[svc.Hash] = svc
+			owned[svc.Hash] = append(owned[svc.Hash], ref)
+		}
+	}
+
+	hashes := make([]string, 0, len(services))
+	for hash := range services {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	dependencies := []interface{}{}
+	for _, hash := range hashes {
+		svc := services[hash]
+		name := svc.Name
+		if name == "" {
+			name = hash
+		}
+		ref := "service:" + hash
+		components = append(components, map[string]interface{}{
+			"type":       "application",
+			"bom-ref":    ref,
+			"name":       name,
+			"properties": []cdxProperty{{Name: "certfix:service_hash", Value: hash}},
+		})
+		dependencies = append(dependencies, map[string]interface{}{
+			"ref":       ref,
+			"dependsOn": owned[hash],
+		})
+	}
+
+	return map[string]interface{}{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.6",
+		"serialNumber": "urn:uuid:" + newUUID(),
+		"version":      1,
+		"metadata": map[string]interface{}{
+			"timestamp": inventory.GeneratedAt.Format(time.RFC3339),
+			"tools": map[string]interface{}{
+				"components": []interface{}{map[string]interface{}{
+					"type":    "application",
+					"name":    "certfix-cli",
+					"version": strings.TrimPrefix(Version, "v"),
+				}},
+			},
+			"properties": []cdxProperty{{Name: "certfix:endpoint", Value: inventory.Endpoint}},
+		},
+		"components":   components,
+		"dependencies": dependencies,
+	}
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func init() {
+	certsCmd.AddCommand(certsInventoryCmd)
+	certsInventoryCmd.Flags().StringP("format", "f", "cyclonedx", "Inventory format (cyclonedx, json)")
+	certsInventoryCmd.Flags().String("out", "", "Write to a file instead of stdout")
+	certsInventoryCmd.Flags().Bool("include-revoked", false, "Include revoked certificates")
+}
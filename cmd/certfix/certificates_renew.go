@@ -0,0 +1,230 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses of certs auto-renew besides 0 (nothing due, or everything
+// due was renewed) and 1 (the command itself failed)
+const (
+	autoRenewExitFailed  = 2 // at least one renewal failed
+	autoRenewExitPending = 3 // renewals are due but --apply was not given
+)
+
+// renewalCandidate is a service whose current certificate is due for renewal
+type renewalCandidate struct {
+	ServiceHash string `json:"service_hash"`
+	ServiceName string `json:"service_name"`
+	UniqueID    string `json:"unique_id"`
+	CommonName  string `json:"common_name"`
+	ExpiresAt   string `json:"expires_at"`
+	DaysLeft    int    `json:"days_left"`
+	Result      string `json:"result"` // pending, renewed or failed
+	Error       string `json:"error,omitempty"`
+}
+
+// autoRenewSummary is the machine-readable result of certs auto-renew
+type autoRenewSummary struct {
+	Threshold       string             `json:"threshold"`
+	ServiceGroup    string             `json:"service_group,omitempty"`
+	Applied         bool               `json:"applied"`
+	ServicesChecked int                `json:"services_checked"`
+	Due             []renewalCandidate `json:"due"`
+	Renewed         int                `json:"renewed"`
+	Failed          int                `json:"failed"`
+	Warnings        []string           `json:"warnings,omitempty"`
+}
+
+var certsAutoRenewCmd = &cobra.Command{
+	Use:   "auto-renew",
+	Short: "Renew certificates of managed services that expire soon",
+	Long: `Find the active services whose current certificate expires within --threshold
+and rotate their certificates. Without --apply nothing is rotated and the
+services that are due are only listed. The command is meant to run from cron:
+-o json prints a summary for log collection, and the exit status is
+
+  0  nothing is due, or every due certificate was renewed
+  1  the command failed, e.g. the services could not be listed
+  2  at least one renewal failed
+  3  renewals are due but --apply was not given`,
+	Example: `  certfix certs auto-renew --threshold 21d
+  certfix certs auto-renew --threshold 21d --service-group payments --apply -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		thresholdFlag, _ := cmd.Flags().GetString("threshold")
+		group, _ := cmd.Flags().GetString("service-group")
+		apply, _ := cmd.Flags().GetBool("apply")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		threshold, err := parseHumanDuration(thresholdFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --threshold: %w", err)
+		}
+
+		if apply {
+			if err := requirePermission(cmd, "service:rotate"); err != nil {
+				return err
+			}
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		apiClient := newAPIClient()
+		cmd.SilenceUsage = true
+
+		summary, err := findRenewals(apiClient, token, threshold, group)
+		if err != nil {
+			return err
+		}
+		summary.Threshold = thresholdFlag
+		summary.Applied = apply
+
+		if apply {
+			for i := range summary.Due {
+				candidate := &summary.Due[i]
+				_, err := apiClient.PostWithAuth("/services/"+candidate.ServiceHash+"/certificates/rotate", map[string]interface{}{}, token)
+				if err != nil {
+					candidate.Result = "failed"
+					candidate.Error = err.Error()
+					summary.Failed++
+					continue
+				}
+				candidate.Result = "renewed"
+				summary.Renewed++
+			}
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(summary, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printAutoRenewSummary(summary)
+		}
+
+		switch {
+		case summary.Failed > 0:
+			return &exitError{code: autoRenewExitFailed, err: fmt.Errorf("%d of %d renewals failed", summary.Failed, len(summary.Due))}
+		case !apply && len(summary.Due) > 0:
+			return &exitError{code: autoRenewExitPending, err: fmt.Errorf("%d certificates are due for renewal; rerun with --apply to renew them", len(summary.Due))}
+		}
+		return nil
+	},
+}
+
+// findRenewals lists the active services, of group when it is set, whose
+// current certificate expires within threshold. Services whose certificates
+// cannot be listed are recorded as warnings.
+func findRenewals(apiClient client.APIClient, token string, threshold time.Duration, group string) (*autoRenewSummary, error) {
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	summary := &autoRenewSummary{ServiceGroup: group, Due: []renewalCandidate{}}
+	for _, svc := range parseArrayResponse(response) {
+		if active, ok := svc["active"].(bool); ok && !active {
+			continue
+		}
+		if group != "" && stringField(svc, "service_group_id") != group && stringField(svc, "service_group_name") != group {
+			continue
+		}
+		summary.ServicesChecked++
+
+		hash := stringField(svc, "service_hash")
+		certsResponse, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates", hash), token)
+		if err != nil {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("failed to list the certificates of service %s: %v", hash, err))
+			continue
+		}
+		current, expiresAt := currentCertificate(parseArrayResponse(certsResponse))
+		if current == nil {
+			continue
+		}
+		left := time.Until(expiresAt)
+		if left > threshold {
+			continue
+		}
+		summary.Due = append(summary.Due, renewalCandidate{
+			ServiceHash: hash,
+			ServiceName: stringField(svc, "service_name"),
+			UniqueID:    stringField(current, "unique_id"),
+			CommonName:  stringField(current, "common_name"),
+			ExpiresAt:   expiresAt.UTC().Format(time.RFC3339),
+			DaysLeft:    int(left.Hours() / 24),
+			Result:      "pending",
+		})
+	}
+	sort.SliceStable(summary.Due, func(i, j int) bool { return summary.Due[i].ExpiresAt < summary.Due[j].ExpiresAt })
+	return summary, nil
+}
+
+// currentCertificate returns the certificate of a service that is not
+// revoked and expires last, with its expiry
+func currentCertificate(certs []map[string]interface{}) (map[string]interface{}, time.Time) {
+	var current map[string]interface{}
+	var latest time.Time
+	for _, cert := range certs {
+		if strings.EqualFold(stringField(cert, "status"), "revoked") {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, stringField(cert, "expires_at"))
+		if err != nil {
+			continue
+		}
+		if current == nil || expiresAt.After(latest) {
+			current, latest = cert, expiresAt
+		}
+	}
+	return current, latest
+}
+
+// printAutoRenewSummary prints the result of certs auto-renew for humans
+func printAutoRenewSummary(summary *autoRenewSummary) {
+	if len(summary.Due) == 0 {
+		fmt.Printf("✓ No certificates expire within %s (%d services checked)\n", summary.Threshold, summary.ServicesChecked)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tNAME\tCOMMON NAME\tEXPIRES AT\tDAYS LEFT\tRESULT")
+		fmt.Fprintln(w, "-------\t----\t-----------\t----------\t---------\t------")
+		for _, c := range summary.Due {
+			result := c.Result
+			if c.Error != "" {
+				result += ": " + c.Error
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", c.ServiceHash, orNone(c.ServiceName), orNone(c.CommonName),
+				formatTimestamp(c.ExpiresAt, "2006-01-02 15:04", "N/A"), c.DaysLeft, result)
+		}
+		w.Flush()
+
+		fmt.Println()
+		if summary.Applied {
+			fmt.Printf("%d renewed, %d failed, %d services checked\n", summary.Renewed, summary.Failed, summary.ServicesChecked)
+		} else {
+			fmt.Printf("%d due for renewal, %d services checked (dry run)\n", len(summary.Due), summary.ServicesChecked)
+		}
+	}
+	for _, warning := range summary.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
+
+func init() {
+	certsCmd.AddCommand(certsAutoRenewCmd)
+	certsAutoRenewCmd.Flags().String("threshold", "21d", "Renew certificates that expire within this long (e.g. 21d, 72h)")
+	certsAutoRenewCmd.Flags().String("service-group", "", "Only renew the services of this service group (ID or name)")
+	certsAutoRenewCmd.Flags().Bool("apply", false, "Rotate the certificates that are due instead of only listing them")
+	certsAutoRenewCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
@@ -0,0 +1,207 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// expiryBuckets are the upper bounds, in days, of the expiry histogram of
+// certs stats
+var expiryBuckets = []int{7, 30, 90}
+
+// expiryBucket counts the certificates expiring in a range of days
+type expiryBucket struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// certStats is what certs stats reports
+type certStats struct {
+	Total    int            `json:"total"`
+	ByType   map[string]int `json:"by_type"`
+	ByStatus map[string]int `json:"by_status"`
+	Expiry   []expiryBucket `json:"expiry"`
+}
+
+var certsStatsCmd = &cobra.Command{
+	Use:   "stats [service-hash]",
+	Short: "Show certificate totals and an expiry histogram",
+	Long: `Show how many certificates there are by type and by status, and a histogram
+of when the certificates that are not revoked expire: already expired,
+within 7, 30 or 90 days, or later.
+
+Without a service hash every certificate is counted, valid and revoked.`,
+	Example: `  certfix certs stats
+  certfix certs stats a1b2c3 -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		var certs []map[string]interface{}
+		if len(args) == 1 {
+			token, err := auth.GetToken()
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			response, err := newAPIClient().GetWithAuth(fmt.Sprintf("/services/%s/certificates", args[0]), token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to list certificates: %w", err)
+			}
+			certs = parseArrayResponse(response)
+		} else {
+			var err error
+			certs, err = listAllCertificates()
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to list certificates: %w", err)
+			}
+		}
+
+		stats := computeCertStats(certs, time.Now())
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(stats, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Total certificates: %d\n", stats.Total)
+		if stats.Total == 0 {
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "\nTYPE\tCOUNT")
+		fmt.Fprintln(w, "----\t-----")
+		for _, key := range sortedKeys(stats.ByType) {
+			fmt.Fprintf(w, "%s\t%d\n", key, stats.ByType[key])
+		}
+		fmt.Fprintln(w, "\nSTATUS\tCOUNT")
+		fmt.Fprintln(w, "------\t-----")
+		for _, key := range sortedKeys(stats.ByStatus) {
+			fmt.Fprintf(w, "%s\t%d\n", key, stats.ByStatus[key])
+		}
+		w.Flush()
+
+		highest := 0
+		for _, bucket := range stats.Expiry {
+			highest = max(highest, bucket.Count)
+		}
+		fmt.Println("\nExpiry:")
+		for _, bucket := range stats.Expiry {
+			bar := ""
+			if highest > 0 {
+				bar = strings.Repeat("#", (bucket.Count*30+highest-1)/highest)
+			}
+			fmt.Printf("  %-16s %5d  %s\n", bucket.Label, bucket.Count, bar)
+		}
+
+		return nil
+	},
+}
+
+// listAllCertificates lists the valid and the revoked certificates. Revoked
+// certificates without a status are given the status "revoked".
+func listAllCertificates() ([]map[string]interface{}, error) {
+	valid, err := newAPI().ListValidCertificates()
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := newAPI().ListRevokedCertificates()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	certs := make([]map[string]interface{}, 0, len(valid)+len(revoked))
+	for _, cert := range valid {
+		seen[stringField(cert, "unique_id")] = true
+		certs = append(certs, cert)
+	}
+	for _, cert := range revoked {
+		if id := stringField(cert, "unique_id"); id != "" && seen[id] {
+			continue
+		}
+		if cert["status"] == nil {
+			cert["status"] = "revoked"
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// computeCertStats counts certs by type and status, and the certificates that
+// are not revoked by when they expire relative to now
+func computeCertStats(certs []map[string]interface{}, now time.Time) *certStats {
+	stats := &certStats{
+		Total:    len(certs),
+		ByType:   make(map[string]int),
+		ByStatus: make(map[string]int),
+	}
+
+	labels := []string{"expired"}
+	previous := 0
+	for _, days := range expiryBuckets {
+		labels = append(labels, fmt.Sprintf("%d-%d days", previous, days))
+		previous = days + 1
+	}
+	labels = append(labels, fmt.Sprintf("over %d days", expiryBuckets[len(expiryBuckets)-1]))
+	counts := make([]int, len(labels))
+
+	for _, cert := range certs {
+		stats.ByType[stringOrNA(cert, "certificate_type")]++
+		status := stringOrNA(cert, "status")
+		stats.ByStatus[status]++
+		if strings.EqualFold(status, "revoked") {
+			continue
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, stringField(cert, "expires_at"))
+		if err != nil {
+			continue
+		}
+		left := expiresAt.Sub(now)
+		if left <= 0 {
+			counts[0]++
+			continue
+		}
+		bucket := len(labels) - 1
+		for i, days := range expiryBuckets {
+			if left <= time.Duration(days)*24*time.Hour {
+				bucket = i + 1
+				break
+			}
+		}
+		counts[bucket]++
+	}
+
+	for i, label := range labels {
+		stats.Expiry = append(stats.Expiry, expiryBucket{Label: label, Count: counts[i]})
+	}
+	return stats
+}
+
+// sortedKeys returns the keys of counts in alphabetical order
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	certsCmd.AddCommand(certsStatsCmd)
+	certsStatsCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
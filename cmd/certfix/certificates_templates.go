@@ -0,0 +1,229 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+// certTemplatesPath lists the certificate templates defined on the server.
+// Servers that predate templates answer 404.
+const certTemplatesPath = "/certificates/templates"
+
+// Sources of certificate templates, from the lowest precedence to the highest
+const (
+	templateBuiltIn = "built-in"
+	templateServer  = "server"
+	templateLocal   = "config"
+)
+
+// certTemplate pre-populates the parameters of certs create. Zero values
+// leave the flag defaults in place.
+type certTemplate struct {
+	Name        string   `json:"name"`
+	Source      string   `json:"source"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Days        int      `json:"days,omitempty"`
+	KeyType     string   `json:"key_type,omitempty"`
+	KeySize     int      `json:"key_size,omitempty"`
+	Curve       string   `json:"curve,omitempty"`
+	KeyUsage    []string `json:"key_usage,omitempty"`
+	ExtKeyUsage []string `json:"ext_key_usage,omitempty"`
+}
+
+// builtInCertTemplates are available on every server
+var builtInCertTemplates = []certTemplate{
+	{
+		Name:        "web-server",
+		Description: "TLS server certificate for web and API endpoints",
+		Type:        "server",
+		Days:        90,
+		KeyType:     "ecdsa",
+		Curve:       "P-256",
+		KeyUsage:    []string{"digitalSignature", "keyEncipherment"},
+		ExtKeyUsage: []string{"serverAuth"},
+	},
+	{
+		Name:        "mtls-client",
+		Description: "Client certificate for mutual TLS between services",
+		Type:        "client",
+		Days:        30,
+		KeyType:     "ecdsa",
+		Curve:       "P-256",
+		KeyUsage:    []string{"digitalSignature"},
+		ExtKeyUsage: []string{"clientAuth"},
+	},
+	{
+		Name:        "code-signing",
+		Description: "Certificate for signing build artifacts",
+		Type:        "client",
+		Days:        365,
+		KeyType:     "rsa",
+		KeySize:     3072,
+		KeyUsage:    []string{"digitalSignature"},
+		ExtKeyUsage: []string{"codeSigning"},
+	},
+}
+
+// loadCertTemplates returns every available template by name. Templates in
+// the config file override server-defined ones, which override the built-in
+// ones of the same name.
+func loadCertTemplates(apiClient client.APIClient, token string) (map[string]certTemplate, error) {
+	templates := make(map[string]certTemplate)
+	for _, t := range builtInCertTemplates {
+		t.Source = templateBuiltIn
+		templates[t.Name] = t
+	}
+
+	serverTemplates, err := fetchServerCertTemplates(apiClient, token)
+	if err != nil {
+		logger.GetLogger().Debugf("Failed to list server certificate templates: %v", err)
+	}
+	for _, t := range serverTemplates {
+		t.Source = templateServer
+		templates[t.Name] = t
+	}
+
+	for name, value := range config.GetCertTemplates() {
+		var t certTemplate
+		data, _ := json.Marshal(value)
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("invalid certificate template '%s' in the config file: %w", name, err)
+		}
+		t.Name = name
+		t.Source = templateLocal
+		templates[name] = t
+	}
+	return templates, nil
+}
+
+// fetchServerCertTemplates lists the templates defined on the server, none
+// when the server has no templates endpoint
+func fetchServerCertTemplates(apiClient client.APIClient, token string) ([]certTemplate, error) {
+	status, body, err := apiClient.RawWithAuth(http.MethodGet, certTemplatesPath, nil, token)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("request failed with status %d", status)
+	}
+	var templates []certTemplate
+	if err := json.Unmarshal(body, &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse certificate templates: %w", err)
+	}
+	return templates, nil
+}
+
+// findCertTemplate returns the template called name
+func findCertTemplate(apiClient client.APIClient, token, name string) (*certTemplate, error) {
+	templates, err := loadCertTemplates(apiClient, token)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := templates[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(templates))
+		for n := range templates {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown certificate template '%s' (available: %s)", name, strings.Join(names, ", "))
+	}
+	return &t, nil
+}
+
+// applyCertTemplate fills in req from t, except for the parameters given on
+// the command line
+func applyCertTemplate(cmd *cobra.Command, req *api.CertificateRequest, t *certTemplate) {
+	changed := cmd.Flags().Changed
+	if t.Type != "" && !changed("type") {
+		req.Type = t.Type
+	}
+	if t.Days != 0 && !changed("days") {
+		req.Days = t.Days
+	}
+	// The key parameters go together, so any key flag replaces all of them
+	if t.KeyType != "" && !changed("key-type") && !changed("key-size") && !changed("curve") {
+		req.KeyType = t.KeyType
+		req.KeySize = t.KeySize
+		req.Curve = t.Curve
+	}
+	req.KeyUsage = t.KeyUsage
+	req.ExtKeyUsage = t.ExtKeyUsage
+}
+
+var certsTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the certificate templates available to certs create",
+	Long: `List the templates 'certs create --template' accepts. Templates come from
+the CLI itself (built-in), the server, and the cert_templates section of the
+config file, which take precedence in that order when names clash.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		templates, err := loadCertTemplates(apiClient, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		list := make([]certTemplate, 0, len(templates))
+		for _, t := range templates {
+			list = append(list, t)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(list, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSOURCE\tTYPE\tKEY\tDAYS\tEXTENDED KEY USAGE")
+		fmt.Fprintln(w, "----\t------\t----\t---\t----\t------------------")
+		for _, t := range list {
+			key := "N/A"
+			if t.KeyType != "" {
+				key = describeKey(t.KeyType, t.KeySize, t.Curve)
+			}
+			days := "N/A"
+			if t.Days > 0 {
+				days = fmt.Sprintf("%d", t.Days)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", t.Name, t.Source, orNone(t.Type), key, days, orNone(strings.Join(t.ExtKeyUsage, ", ")))
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+func init() {
+	certsCmd.AddCommand(certsTemplatesCmd)
+	certsTemplatesCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
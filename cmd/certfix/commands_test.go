@@ -0,0 +1,1756 @@
+package certfix
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/cache"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/internal/history"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/models"
+)
+
+// fixtureServices is the /services response shared by the service tests
+var fixtureServices = []map[string]interface{}{
+	{
+		"service_hash":       "a1b2c3",
+		"service_name":       "payments-api",
+		"active":             true,
+		"service_group_name": "payments",
+		"policy_name":        "nightly",
+		"created_at":         "2026-01-15T10:30:00Z",
+	},
+	{
+		"service_hash": "d4e5f6",
+		"service_name": "legacy-billing",
+		"active":       false,
+		"created_at":   "2025-11-02T08:00:00Z",
+	},
+}
+
+func TestCommandOutput(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		setup func(fake *client.FakeClient)
+	}{
+		{
+			name: "services_list",
+			args: []string{"services", "list"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/services", fixtureServices)
+			},
+		},
+		{
+			name: "services_list_json",
+			args: []string{"services", "list", "-o", "json"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/services", fixtureServices)
+			},
+		},
+		{
+			name: "services_list_inactive",
+			args: []string{"services", "list", "--inactive"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/services?active=false", fixtureServices)
+			},
+		},
+		{
+			name: "services_list_empty",
+			args: []string{"services", "list"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/services", []interface{}{})
+			},
+		},
+		{
+			name: "services_list_tree",
+			args: []string{"services", "list", "--tree"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/services", []map[string]interface{}{
+					{"service_hash": "a1b2c3", "service_name": "payments-api", "active": true, "service_group_id": "g1", "service_group_name": "payments"},
+					fixtureServices[1],
+				})
+				fake.HandleJSON("GET", "/services/a1b2c3/keys/list", []map[string]interface{}{
+					{"key_id": "k2", "key_name": "deploy", "enabled": false, "expires_at": "2026-06-01T00:00:00Z"},
+					{"key_id": "k1", "key_name": "ci", "enabled": true},
+				})
+				fake.HandleJSON("GET", "/services/a1b2c3/matrix/relations", []map[string]interface{}{
+					{"relation_id": 1, "related_service_hash": "d4e5f6", "related_service_name": "legacy-billing", "relation_type": "depends_on", "enabled": false},
+				})
+				fake.HandleJSON("GET", "/services/d4e5f6/keys/list", []interface{}{})
+				fake.HandleJSON("GET", "/services/d4e5f6/matrix/relations", []interface{}{})
+			},
+		},
+		{
+			name: "services_list_selector",
+			args: []string{"services", "list", "--selector", "team=payments,env!=prod"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/services?selector=team%3Dpayments%2Cenv%21%3Dprod", []map[string]interface{}{
+					{"service_hash": "a1b2c3", "service_name": "payments-api", "active": true, "labels": map[string]interface{}{"team": "payments", "env": "staging"}},
+					{"service_hash": "d4e5f6", "service_name": "payments-prod", "active": true, "labels": map[string]interface{}{"team": "payments", "env": "prod"}},
+					{"service_hash": "0a0b0c", "service_name": "checkout", "active": true},
+				})
+			},
+		},
+		{
+			name: "services_list_created_since",
+			args: []string{"services", "list", "--created-since", "2026-01-01"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/services", fixtureServices)
+			},
+		},
+		{
+			name: "policy_list",
+			args: []string{"policy", "list"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/policies", []map[string]interface{}{
+					{"policy_id": 1, "name": "nightly", "strategy": "maintenance_window", "enabled": true, "created_at": "2026-01-10T00:00:00Z"},
+					{"policy_id": 2, "name": "on-incident", "strategy": "events", "enabled": false, "created_at": "2026-02-01T12:00:00Z"},
+				})
+			},
+		},
+		{
+			name: "policy_list_sorted",
+			args: []string{"policy", "list", "--sort-by", "created_at", "--reverse"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/policies", []map[string]interface{}{
+					{"policy_id": 1, "name": "nightly", "strategy": "maintenance_window", "enabled": true, "created_at": "2026-01-10T00:00:00Z"},
+					{"policy_id": 3, "name": "manual", "strategy": "manual", "enabled": true},
+					{"policy_id": 2, "name": "on-incident", "strategy": "events", "enabled": false, "created_at": "2026-02-01T12:00:00Z"},
+				})
+			},
+		},
+		{
+			name: "services_get_include",
+			args: []string{"services", "get", "payments-api", "--include", "keys,relations,certificates,rotation"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/services", fixtureServices)
+				fake.HandleJSON("GET", "/services/a1b2c3", fixtureServices[0])
+				fake.HandleJSON("GET", "/services/a1b2c3/keys/list", []map[string]interface{}{
+					{"key_id": "k1", "key_name": "deploy", "enabled": true, "expires_at": "2026-12-31T00:00:00Z"},
+				})
+				fake.HandleJSON("GET", "/services/a1b2c3/matrix/relations", []map[string]interface{}{
+					{"relation_id": 4, "related_service_hash": "d4e5f6", "related_service_name": "legacy-billing", "relation_type": "depends_on", "direction": "outgoing", "enabled": true},
+				})
+				fake.HandleJSON("GET", "/services/a1b2c3/certificates", []map[string]interface{}{})
+			},
+		},
+		{
+			name: "events_list",
+			args: []string{"events", "list"},
+			setup: func(fake *client.FakeClient) {
+				fake.HandleJSON("GET", "/events", []map[string]interface{}{
+					{"event_id": 7, "name": "key-leak", "severity": "critical", "enabled": true, "counter": 2, "created_at": "2026-03-01T09:00:00Z"},
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := client.NewFakeClient()
+			tt.setup(fake)
+
+			output, err := runCommand(t, fake, tt.args...)
+			if err != nil {
+				t.Fatalf("%s: %v", strings.Join(tt.args, " "), err)
+			}
+			assertGolden(t, tt.name, output)
+		})
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	const eventID = "0b7e3c1a-5d2f-4e8a-9c61-2f4d8e9a7b10"
+	fake := client.NewFakeClient()
+	fake.Handle("GET", "/events/"+eventID, 404, `{"message":"Event not found"}`)
+
+	_, err := runCommand(t, fake, "events", "get", eventID)
+	if err == nil || !strings.Contains(err.Error(), "Event not found") {
+		t.Fatalf("expected the server message in the error, got %v", err)
+	}
+}
+
+func TestConfirmation(t *testing.T) {
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("DELETE", "/services/a1b2c3", map[string]interface{}{})
+
+	// Tests run without a terminal on stdin, so prompting must fail
+	_, err := runCommand(t, fake, "services", "delete", "a1b2c3")
+	if err == nil || !strings.Contains(err.Error(), "stdin is not a terminal") {
+		t.Fatalf("expected a non-interactive error, got %v", err)
+	}
+	for _, request := range fake.Requests() {
+		if request.Method == "DELETE" {
+			t.Fatalf("expected no deletion without confirmation, got %+v", request)
+		}
+	}
+
+	output, err := runCommand(t, fake, "services", "delete", "a1b2c3", "--yes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Service deleted successfully") {
+		t.Errorf("expected --yes to skip the prompt, got %q", output)
+	}
+
+	// The generic delete confirms the hash a name resolved to
+	fake = client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("DELETE", "/services/a1b2c3", map[string]interface{}{})
+	if _, err := runCommand(t, fake, "delete", "service", "payments-api"); err == nil || !strings.Contains(err.Error(), "stdin is not a terminal") {
+		t.Fatalf("expected a non-interactive error, got %v", err)
+	}
+	if _, err := runCommand(t, fake, "delete", "service", "payments-api", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	requests := fake.Requests()
+	if last := requests[len(requests)-1]; last.Method != "DELETE" || last.Endpoint != "/services/a1b2c3" {
+		t.Errorf("expected the resolved service to be deleted, got %+v", last)
+	}
+
+	if _, err := runCommand(t, fake, "delete", "service", "payments", "--yes"); err == nil || !strings.Contains(err.Error(), "no service found") {
+		t.Errorf("expected a partial name to be refused, got %v", err)
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("GET", "/me/permissions", map[string]interface{}{
+		"role":        "viewer",
+		"permissions": []string{"service:read", "cert:*"},
+	})
+
+	// The check fails before the prompt, which would fail without a terminal
+	_, err := runCommand(t, fake, "services", "delete", "a1b2c3")
+	if err == nil || err.Error() != "your role (viewer) lacks service:delete" {
+		t.Fatalf("expected the missing permission, got %v", err)
+	}
+
+	output, err := runCommand(t, fake, "auth", "can-i", "revoke", "certs")
+	if err != nil || output != "yes\n" {
+		t.Errorf("expected cert:* to allow cert:revoke, got %q, %v", output, err)
+	}
+	output, err = runCommand(t, fake, "auth", "can-i", "delete", "services")
+	if err == nil || output != "no\n" {
+		t.Errorf("expected no for service:delete, got %q, %v", output, err)
+	}
+
+	// Servers without the endpoint leave the decision to the API
+	fake = client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("DELETE", "/services/a1b2c3", map[string]interface{}{})
+	if _, err := runCommand(t, fake, "services", "delete", "a1b2c3", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runCertCreate runs 'certs create' with args against a fake server that
+// issues the certificate and has a db-client template. It returns the payload
+// of the certificate request, and fails the test when a command that errors
+// has sent one.
+func runCertCreate(t *testing.T, args []string) (map[string]interface{}, error) {
+	t.Helper()
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/certificates/templates", []map[string]interface{}{
+		{"name": "db-client", "type": "client", "days": 7, "key_type": "ed25519", "ext_key_usage": []string{"clientAuth"}},
+	})
+	fake.HandleJSON("POST", "/certificates", map[string]interface{}{"unique_id": "c1", "common_name": "api.example.com"})
+
+	_, err := runCommand(t, fake, append([]string{"certs", "create"}, args...)...)
+	var payload map[string]interface{}
+	for _, request := range fake.Requests() {
+		if request.Method == "POST" {
+			payload, _ = request.Payload.(map[string]interface{})
+		}
+	}
+	if err != nil && payload != nil {
+		t.Errorf("expected no certificate request when the command fails, got %v", payload)
+	}
+	return payload, err
+}
+
+// checkCertError reports whether the test case expected an error, failing
+// the test when err does not match it
+func checkCertError(t *testing.T, err error, want string) bool {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return false
+	}
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+	return true
+}
+
+// checkPayload compares fields of a certificate request in their printed
+// form, e.g. "[serverAuth]" for a list, since the payload is decoded JSON
+func checkPayload(t *testing.T, payload, want map[string]interface{}) {
+	t.Helper()
+	for key, value := range want {
+		if fmt.Sprint(payload[key]) != fmt.Sprint(value) {
+			t.Errorf("expected %s=%v in the payload, got %v", key, value, payload)
+		}
+	}
+}
+
+func TestCertificateKeyTypes(t *testing.T) {
+	tests := []struct {
+		args    []string
+		payload map[string]interface{}
+		err     string
+	}{
+		{args: []string{"--key-size", "4096"}, payload: map[string]interface{}{"keyType": "rsa", "keySize": 4096}},
+		{args: []string{"--key-type", "ecdsa"}, payload: map[string]interface{}{"keyType": "ecdsa", "curve": "P-256"}},
+		{args: []string{"--key-type", "ECDSA", "--curve", "p384"}, payload: map[string]interface{}{"keyType": "ecdsa", "curve": "P-384"}},
+		{args: []string{"--key-type", "ed25519"}, payload: map[string]interface{}{"keyType": "ed25519"}},
+		{args: []string{"--key-type", "ecdsa", "--key-size", "2048"}, err: "--key-size only applies to RSA keys"},
+		{args: []string{"--curve", "P-256"}, err: "--curve only applies to ECDSA keys"},
+		{args: []string{"--key-type", "ed25519", "--curve", "P-256"}, err: "do not apply to Ed25519 keys"},
+		{args: []string{"--key-type", "ecdsa", "--curve", "P-521"}, err: "invalid curve"},
+		{args: []string{"--key-size", "1024"}, err: "invalid RSA key size"},
+		{args: []string{"--key-type", "dsa"}, err: "invalid key type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			payload, err := runCertCreate(t, append([]string{"api.example.com"}, tt.args...))
+			if !checkCertError(t, err, tt.err) {
+				checkPayload(t, payload, tt.payload)
+			}
+		})
+	}
+}
+
+func TestCertificateSANs(t *testing.T) {
+	tests := []struct {
+		args []string
+		san  string
+		err  string
+	}{
+		{
+			args: []string{"--dns", "API.example.com", "--dns", "www.example.com.", "--ip", "10.0.0.1", "--uri", "spiffe://example.org/ns/prod", "--dns", "api.example.com"},
+			san:  "DNS:api.example.com,DNS:www.example.com,IP:10.0.0.1,URI:spiffe://example.org/ns/prod",
+		},
+		{args: []string{"--san", "DNS:a.example.com, IP:::1", "--dns", "a.example.com"}, san: "DNS:a.example.com,IP:::1"},
+		{args: []string{"--dns", "bad name.example.com"}, err: "invalid DNS name"},
+		{args: []string{"--dns", "-a.example.com"}, err: "starts or ends with a hyphen"},
+		{args: []string{"--ip", "10.0.0.300"}, err: "invalid IP address"},
+		{args: []string{"--uri", "/relative/path"}, err: "must be absolute"},
+		{args: []string{"--san", "a.example.com"}, err: "invalid SAN entry"},
+		{args: []string{"--san", "email:a@example.com"}, err: "unsupported SAN type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			payload, err := runCertCreate(t, append([]string{"api.example.com"}, tt.args...))
+			if !checkCertError(t, err, tt.err) {
+				checkPayload(t, payload, map[string]interface{}{"san": tt.san})
+			}
+		})
+	}
+}
+
+func TestCertificateTemplates(t *testing.T) {
+	tests := []struct {
+		args    []string
+		payload map[string]interface{}
+		err     string
+	}{
+		{
+			args:    []string{"--template", "web-server"},
+			payload: map[string]interface{}{"type": "server", "days": 90, "keyType": "ecdsa", "curve": "P-256", "extKeyUsage": "[serverAuth]"},
+		},
+		{
+			args:    []string{"--template", "web-server", "--days", "30", "--key-type", "rsa", "--key-size", "4096"},
+			payload: map[string]interface{}{"days": 30, "keyType": "rsa", "keySize": 4096, "curve": nil, "extKeyUsage": "[serverAuth]"},
+		},
+		{
+			args:    []string{"--template", "code-signing"},
+			payload: map[string]interface{}{"type": "client", "keyType": "rsa", "keySize": 3072, "extKeyUsage": "[codeSigning]"},
+		},
+		{
+			args:    []string{"--template", "db-client"},
+			payload: map[string]interface{}{"type": "client", "days": 7, "keyType": "ed25519", "extKeyUsage": "[clientAuth]"},
+		},
+		{args: []string{"--template", "nope"}, err: "unknown certificate template 'nope'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			payload, err := runCertCreate(t, append([]string{"api.example.com"}, tt.args...))
+			if !checkCertError(t, err, tt.err) {
+				checkPayload(t, payload, tt.payload)
+			}
+		})
+	}
+}
+
+func TestCertificateNameSafeguards(t *testing.T) {
+	tests := []struct {
+		args []string
+		cn   string
+		san  string
+		err  string
+	}{
+		{args: []string{"*.example.com"}, err: "pass --allow-wildcard"},
+		{args: []string{"api.example.com", "--dns", "*.api.example.com"}, err: "pass --allow-wildcard"},
+		{args: []string{"*.example.com", "--allow-wildcard", "--dns", "*.example.com"}, cn: "*.example.com", san: "DNS:*.example.com"},
+		{args: []string{"Bücher.example", "--dns", "www.bücher.example"}, cn: "xn--bcher-kva.example", san: "DNS:www.xn--bcher-kva.example"},
+		{args: []string{"api example.com"}, err: "must not contain spaces"},
+		{args: []string{"worker 1", "--type", "client"}, err: "must not contain spaces"},
+		{args: []string{"api_internal.example.com"}, err: "invalid common name"},
+		{args: []string{"10.0.0.1"}, cn: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			payload, err := runCertCreate(t, tt.args)
+			if checkCertError(t, err, tt.err) {
+				return
+			}
+			want := map[string]interface{}{"commonName": tt.cn}
+			if tt.san != "" {
+				want["san"] = tt.san
+			}
+			checkPayload(t, payload, want)
+		})
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		fake := client.NewFakeClient()
+		if _, err := runCommand(t, fake, "certs", "create", "api.example.com", "--dns", "api.example.com", "--dry-run"); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.Requests()) != 0 {
+			t.Errorf("expected no request on a dry run, got %+v", fake.Requests())
+		}
+	})
+}
+
+func TestIssuanceNameWarnings(t *testing.T) {
+	warnings, err := checkIssuanceNames(api.CertificateRequest{CommonName: "co.uk", Type: "server", SAN: "DNS:*.co.uk,DNS:api.example.co.uk"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "is a public suffix") || !strings.Contains(warnings[1], "every domain under") {
+		t.Errorf("expected a warning for co.uk and *.co.uk, got %q", warnings)
+	}
+}
+
+func TestCertificateListPagination(t *testing.T) {
+	var certs []map[string]interface{}
+	for i := 1; i <= 5; i++ {
+		certs = append(certs, map[string]interface{}{"unique_id": fmt.Sprintf("c%d", i), "status": "active"})
+	}
+	tests := []struct {
+		args []string
+		ids  []string
+		err  string
+	}{
+		{args: []string{"--limit", "2"}, ids: []string{"c1", "c2"}},
+		{args: []string{"--limit", "2", "--page", "3"}, ids: []string{"c5"}},
+		{args: []string{"--limit", "2", "--page", "4"}},
+		{args: []string{"--all"}, ids: []string{"c1", "c2", "c3", "c4", "c5"}},
+		{args: []string{"--all", "--limit", "2"}, err: "--all cannot be combined"},
+		{args: []string{"--page", "0"}, err: "--page must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			fake := client.NewFakeClient()
+			fake.HandleJSON("GET", "/services", fixtureServices)
+			fake.HandleJSON("GET", "/services/a1b2c3/certificates", certs)
+
+			out, err := runCommand(t, fake, append([]string{"certs", "list", "a1b2c3", "-o", "json"}, tt.args...)...)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var listed []map[string]interface{}
+			if err := json.Unmarshal([]byte(out), &listed); err != nil {
+				t.Fatalf("invalid JSON output: %v\n%s", err, out)
+			}
+			var ids []string
+			for _, cert := range listed {
+				ids = append(ids, fmt.Sprint(cert["unique_id"]))
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.ids, ",") {
+				t.Errorf("expected %v, got %v", tt.ids, ids)
+			}
+		})
+	}
+
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("GET", "/services/a1b2c3/certificates", certs)
+	out, err := runCommand(t, fake, "certs", "list", "a1b2c3", "--limit", "2", "--page", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Showing 3-4 of 5 certificates (page 2 of 3). Use --page 3") {
+		t.Errorf("expected a page footer, got:\n%s", out)
+	}
+}
+
+func TestCertificateStats(t *testing.T) {
+	now := time.Now()
+	expires := func(d time.Duration) string { return now.Add(d).UTC().Format(time.RFC3339) }
+	day := 24 * time.Hour
+
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/certificates", []map[string]interface{}{
+		{"unique_id": "c1", "certificate_type": "server", "status": "active", "expires_at": expires(3 * day)},
+		{"unique_id": "c2", "certificate_type": "server", "status": "active", "expires_at": expires(20 * day)},
+		{"unique_id": "c3", "certificate_type": "client", "status": "active", "expires_at": expires(60 * day)},
+		{"unique_id": "c4", "certificate_type": "client", "status": "active", "expires_at": expires(200 * day)},
+		{"unique_id": "c5", "certificate_type": "server", "status": "expired", "expires_at": expires(-day)},
+	})
+	fake.HandleJSON("GET", "/certificates/revoked", []map[string]interface{}{
+		{"unique_id": "c6", "certificate_type": "server", "expires_at": expires(2 * day)},
+	})
+
+	out, err := runCommand(t, fake, "certs", "stats", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats certStats
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if stats.Total != 6 || stats.ByType["server"] != 4 || stats.ByStatus["revoked"] != 1 || stats.ByStatus["active"] != 4 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	want := "expired=1 0-7 days=1 8-30 days=1 31-90 days=1 over 90 days=1"
+	var got []string
+	for _, bucket := range stats.Expiry {
+		got = append(got, fmt.Sprintf("%s=%d", bucket.Label, bucket.Count))
+	}
+	if strings.Join(got, " ") != want {
+		t.Errorf("expected histogram %q, got %q", want, strings.Join(got, " "))
+	}
+}
+
+func TestCertificateInventory(t *testing.T) {
+	newFake := func() *client.FakeClient {
+		fake := client.NewFakeClient()
+		fake.HandleJSON("GET", "/certificates", []map[string]interface{}{
+			{"unique_id": "c1", "common_name": "api.example.com", "serial_number": "0a1b", "status": "active",
+				"key_type": "ecdsa", "curve": "P-256", "expires_at": "2027-01-01T00:00:00Z", "san": "DNS:api.example.com"},
+			{"unique_id": "c2", "common_name": "ca-direct", "status": "active", "expires_at": "2026-12-01T00:00:00Z"},
+		})
+		fake.HandleJSON("GET", "/services", fixtureServices)
+		fake.HandleJSON("GET", "/services/a1b2c3/certificates", []map[string]interface{}{
+			{"unique_id": "c1", "common_name": "api.example.com", "status": "active"},
+			{"unique_id": "c0", "common_name": "old.example.com", "status": "revoked"},
+		})
+		return fake
+	}
+
+	out, err := runCommand(t, newFake(), "certs", "inventory", "--format", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inventory certInventory
+	if err := json.Unmarshal([]byte(out), &inventory); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(inventory.Certificates) != 2 || inventory.Certificates[0].UniqueID != "c2" {
+		t.Fatalf("expected c2 and c1 by expiry without the revoked c0, got %+v", inventory.Certificates)
+	}
+	c1 := inventory.Certificates[1]
+	if c1.Subject != "CN=api.example.com" || c1.KeyAlgorithm != "ECDSA P-256" || len(c1.Services) != 1 || c1.Services[0].Name != "payments-api" {
+		t.Errorf("unexpected inventory entry: %+v", c1)
+	}
+
+	out, err = runCommand(t, newFake(), "certs", "inventory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bom struct {
+		BOMFormat    string `json:"bomFormat"`
+		SpecVersion  string `json:"specVersion"`
+		SerialNumber string `json:"serialNumber"`
+		Components   []struct {
+			Type   string `json:"type"`
+			BOMRef string `json:"bom-ref"`
+		} `json:"components"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal([]byte(out), &bom); err != nil {
+		t.Fatalf("invalid CycloneDX output: %v\n%s", err, out)
+	}
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != "1.6" || !strings.HasPrefix(bom.SerialNumber, "urn:uuid:") {
+		t.Errorf("unexpected BOM header: %+v", bom)
+	}
+	if len(bom.Components) != 3 || bom.Components[0].Type != "cryptographic-asset" || bom.Components[2].BOMRef != "service:a1b2c3" {
+		t.Errorf("expected 2 certificates and 1 service, got %+v", bom.Components)
+	}
+	if len(bom.Dependencies) != 1 || strings.Join(bom.Dependencies[0].DependsOn, ",") != "certificate:c1" {
+		t.Errorf("expected service a1b2c3 to depend on c1, got %+v", bom.Dependencies)
+	}
+}
+
+func TestCertificateAudit(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "legacy.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(800 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/certificates", []map[string]interface{}{
+		{"unique_id": "c1", "common_name": "legacy.example.com", "certificate_type": "server", "certificate": legacyPEM},
+		{"unique_id": "c2", "common_name": "old-client", "certificate_type": "client", "key_type": "rsa", "key_size": 2048,
+			"signature_algorithm": "SHA1-RSA", "created_at": "2026-01-01T00:00:00Z", "expires_at": "2026-03-01T00:00:00Z"},
+		{"unique_id": "c3", "common_name": "api.example.com", "certificate_type": "server", "key_type": "ecdsa", "curve": "P-256",
+			"signature_algorithm": "ECDSA-SHA256", "san": "DNS:api.example.com", "created_at": "2026-01-01T00:00:00Z", "expires_at": "2026-04-01T00:00:00Z"},
+	})
+
+	out, err := runCommand(t, fake, "certs", "audit", "-o", "json", "--fail-on", "critical")
+	if err == nil || !strings.Contains(err.Error(), "1 findings of severity critical or higher") {
+		t.Fatalf("expected --fail-on critical to fail, got %v", err)
+	}
+	var report auditReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	var got []string
+	for _, f := range report.Findings {
+		got = append(got, f.Severity+" "+f.Check+" "+f.UniqueID)
+	}
+	want := "critical weak-key c1,high weak-signature c2,medium long-validity c1,medium missing-san c1"
+	if strings.Join(got, ",") != want {
+		t.Errorf("expected findings %q, got %q", want, strings.Join(got, ","))
+	}
+
+	out, err = runCommand(t, fake, "certs", "audit", "--min-severity", "high", "--max-validity", "1000d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "2 findings in 3 certificates (1 critical, 1 high)") {
+		t.Errorf("expected a summary of 2 findings, got:\n%s", out)
+	}
+}
+
+func TestCertificateAutoRenew(t *testing.T) {
+	expires := func(days int) string {
+		return time.Now().Add(time.Duration(days) * 24 * time.Hour).UTC().Format(time.RFC3339)
+	}
+	newFake := func() *client.FakeClient {
+		fake := client.NewFakeClient()
+		fake.HandleJSON("GET", "/services", append(fixtureServices, map[string]interface{}{
+			"service_hash": "g7h8i9", "service_name": "web", "active": true, "service_group_name": "frontend",
+		}))
+		fake.HandleJSON("GET", "/services/a1b2c3/certificates", []map[string]interface{}{
+			{"unique_id": "old", "status": "revoked", "expires_at": expires(2)},
+			{"unique_id": "c1", "status": "active", "common_name": "payments.example.com", "expires_at": expires(5)},
+		})
+		fake.HandleJSON("GET", "/services/g7h8i9/certificates", []map[string]interface{}{
+			{"unique_id": "c2", "status": "active", "expires_at": expires(60)},
+		})
+		return fake
+	}
+	exitCode := func(err error) int {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			return exitErr.code
+		}
+		if err != nil {
+			return 1
+		}
+		return 0
+	}
+
+	fake := newFake()
+	out, err := runCommand(t, fake, "certs", "auto-renew", "--threshold", "21d", "-o", "json")
+	if exitCode(err) != autoRenewExitPending {
+		t.Fatalf("expected exit status %d for a dry run with renewals due, got %v", autoRenewExitPending, err)
+	}
+	var summary autoRenewSummary
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if summary.ServicesChecked != 2 || len(summary.Due) != 1 || summary.Due[0].UniqueID != "c1" || summary.Due[0].Result != "pending" {
+		t.Errorf("expected c1 of a1b2c3 to be due, got %+v", summary)
+	}
+	for _, request := range fake.Requests() {
+		if request.Method == "POST" {
+			t.Errorf("expected no rotation on a dry run, got %+v", request)
+		}
+	}
+
+	fake = newFake()
+	fake.HandleJSON("POST", "/services/a1b2c3/certificates/rotate", map[string]interface{}{"job_id": "j1"})
+	if _, err := runCommand(t, fake, "certs", "auto-renew", "--apply"); err != nil {
+		t.Fatalf("expected the renewal to succeed, got %v", err)
+	}
+
+	_, err = runCommand(t, newFake(), "certs", "auto-renew", "--apply")
+	if exitCode(err) != autoRenewExitFailed {
+		t.Errorf("expected exit status %d when a rotation fails, got %v", autoRenewExitFailed, err)
+	}
+
+	if _, err := runCommand(t, newFake(), "certs", "auto-renew", "--service-group", "frontend"); err != nil {
+		t.Errorf("expected nothing due in the frontend group, got %v", err)
+	}
+}
+
+func TestCertificateFind(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0x4f1a09c2),
+		Subject:      pkix.Name{CommonName: "unknown.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	file := filepath.Join(t.TempDir(), "unknown.pem")
+	if err := os.WriteFile(file, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	newFake := func() *client.FakeClient {
+		fake := client.NewFakeClient()
+		fake.HandleJSON("GET", "/certificates", []map[string]interface{}{
+			{"unique_id": "c1", "common_name": "api.example.com", "serial_number": "0a1b"},
+			{"unique_id": "c2", "common_name": "unknown.example.com", "serial_number": "4f1a09c2", "certificate": string(certPEM)},
+		})
+		fake.HandleJSON("GET", "/certificates/revoked", []map[string]interface{}{})
+		fake.HandleJSON("GET", "/services", fixtureServices)
+		fake.HandleJSON("GET", "/services/a1b2c3/certificates", []map[string]interface{}{{"unique_id": "c2"}})
+		fake.HandleJSON("GET", "/services/d4e5f6/certificates", []map[string]interface{}{})
+		return fake
+	}
+
+	for _, args := range [][]string{{"--serial", "4F:1A:09:C2"}, {"--file", file}} {
+		out, err := runCommand(t, newFake(), append([]string{"certs", "find", "-o", "json"}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var match certMatch
+		if err := json.Unmarshal([]byte(out), &match); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, out)
+		}
+		if match.UniqueID != "c2" || len(match.Services) != 1 || match.Services[0].Hash != "a1b2c3" || len(match.Fingerprint) != 64 {
+			t.Errorf("%v: expected c2 owned by a1b2c3, got %+v", args, match)
+		}
+	}
+
+	_, err = runCommand(t, newFake(), "certs", "find", "--fingerprint", strings.Repeat("ab", 32))
+	if err == nil || !strings.Contains(err.Error(), "no certificate with fingerprint") {
+		t.Errorf("expected an unknown fingerprint to fail, got %v", err)
+	}
+	_, err = runCommand(t, newFake(), "certs", "find", "--serial", "1", "--fingerprint", "ab")
+	if err == nil || !strings.Contains(err.Error(), "exactly one of") {
+		t.Errorf("expected conflicting flags to fail, got %v", err)
+	}
+}
+
+func TestCertificateDownload(t *testing.T) {
+	certPEM := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	sum := sha256.Sum256([]byte(certPEM))
+	checksum := hex.EncodeToString(sum[:])
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(public)
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "signing.pub")
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(certPEM)))
+
+	newFake := func(checksum string) *client.FakeClient {
+		fake := client.NewFakeClient()
+		fake.HandleJSON("GET", "/services/certificates/c1/details", map[string]interface{}{
+			"unique_id": "c1", "certificate": certPEM, "checksum_sha256": checksum, "signature": signature,
+		})
+		return fake
+	}
+
+	out := filepath.Join(dir, "c1.pem")
+	if _, err := runCommand(t, newFake(checksum), "certs", "download", "c1", "--out", out, "--verify-key", keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != certPEM {
+		t.Errorf("expected the PEM to be written, got %q", data)
+	}
+
+	bad := filepath.Join(dir, "bad.pem")
+	_, err = runCommand(t, newFake(strings.Repeat("0", 64)), "certs", "download", "c1", "--out", bad)
+	if err == nil || !strings.Contains(err.Error(), "CHECKSUM MISMATCH") {
+		t.Errorf("expected a checksum mismatch, got %v", err)
+	}
+	if _, statErr := os.Stat(bad); !os.IsNotExist(statErr) {
+		t.Errorf("expected no file after a checksum mismatch")
+	}
+
+	if _, err := runCommand(t, newFake(checksum), "certs", "download", "c1", "--out", out, "--checksum-only"); err != nil {
+		t.Errorf("expected the local file to verify, got %v", err)
+	}
+	os.WriteFile(out, []byte(certPEM+"tampered"), 0o644)
+	_, err = runCommand(t, newFake(checksum), "certs", "download", "c1", "--out", out, "--checksum-only")
+	if err == nil || !strings.Contains(err.Error(), "CHECKSUM MISMATCH") {
+		t.Errorf("expected the tampered file to fail verification, got %v", err)
+	}
+
+	otherPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ = x509.MarshalPKIXPublicKey(otherPublic)
+	otherKey := filepath.Join(dir, "other.pub")
+	os.WriteFile(otherKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+	_, err = runCommand(t, newFake(checksum), "certs", "download", "c1", "--out", filepath.Join(dir, "x.pem"), "--verify-key", otherKey)
+	if err == nil || !strings.Contains(err.Error(), "SIGNATURE MISMATCH") {
+		t.Errorf("expected a signature mismatch with another key, got %v", err)
+	}
+}
+
+func TestSSOLogin(t *testing.T) {
+	t.Setenv("ENDPOINT", "https://certfix.example.com")
+	previous := openBrowser
+	defer func() { openBrowser = previous }()
+
+	// callback plays the IdP: it redirects the browser back to the CLI
+	var forged int
+	callback := func(params url.Values) func(string) error {
+		return func(loginURL string) error {
+			parsed, err := url.Parse(loginURL)
+			if err != nil {
+				return err
+			}
+			if !strings.HasPrefix(loginURL, "https://certfix.example.com/api/v0.0.1/auth/sso/cli?") {
+				t.Errorf("unexpected login URL %s", loginURL)
+			}
+			query := parsed.Query()
+			go func() {
+				response, err := http.Get(query.Get("redirect_uri") + "?state=forged&token=stolen")
+				if err == nil {
+					forged = response.StatusCode
+					response.Body.Close()
+				}
+				params.Set("state", query.Get("state"))
+				if response, err := http.Get(query.Get("redirect_uri") + "?" + params.Encode()); err == nil {
+					response.Body.Close()
+				}
+			}()
+			return nil
+		}
+	}
+
+	openBrowser = callback(url.Values{"token": {testToken}})
+	output, err := runCommand(t, client.NewFakeClient(), "login", "--sso")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Successfully logged in") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	if forged != http.StatusBadRequest {
+		t.Errorf("expected a callback with the wrong state to be refused, got status %d", forged)
+	}
+	if token, err := auth.GetToken(); err != nil || token != testToken {
+		t.Errorf("expected the SSO token to be stored, got %q (%v)", token, err)
+	}
+
+	openBrowser = callback(url.Values{"error": {"access_denied"}, "error_description": {"user is not assigned"}})
+	_, err = runCommand(t, client.NewFakeClient(), "login", "--sso")
+	if err == nil || !strings.Contains(err.Error(), "access_denied: user is not assigned") {
+		t.Errorf("expected the IdP error, got %v", err)
+	}
+
+	openBrowser = func(string) error { return nil }
+	_, err = runCommand(t, client.NewFakeClient(), "login", "--sso", "--sso-timeout", "100ms")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+
+	_, err = runCommand(t, client.NewFakeClient(), "login", "--sso", "--email", "me@example.com")
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("expected --sso with --email to be refused, got %v", err)
+	}
+}
+
+func TestCertfixHome(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.HomeEnv, dir)
+
+	if got := config.GetConfigDir(); got != dir {
+		t.Errorf("expected the config directory to be %s, got %s", dir, got)
+	}
+	if err := auth.StoreToken(testToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "token.json")); err != nil {
+		t.Errorf("expected the token under $%s: %v", config.HomeEnv, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "token.json.lock")); !os.IsNotExist(err) {
+		t.Errorf("expected the lock to be released, got %v", err)
+	}
+	if token, err := auth.GetToken(); err != nil || token != testToken {
+		t.Errorf("expected the token to be read back, got %q (%v)", token, err)
+	}
+}
+
+func TestFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	unlock, err := config.Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		second, err := config.Lock(path)
+		if err != nil {
+			t.Error(err)
+			second = func() {}
+		}
+		acquired <- second
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("expected the second lock to wait for the first")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case second := <-acquired:
+		second()
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the second lock once the first was released")
+	}
+
+	// A lock left behind by a process that died is taken over
+	os.WriteFile(path+".lock", []byte("99999\n"), 0600)
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(path+".lock", old, old)
+	unlock, err = config.Lock(path)
+	if err != nil {
+		t.Fatalf("expected a stale lock to be taken over, got %v", err)
+	}
+	unlock()
+}
+
+func TestLegacyDirMigration(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the XDG directories are used on Linux and other Unix systems")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "cfg"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	configDir := filepath.Join(home, "cfg", "certfix")
+	stateDir := filepath.Join(home, "state", "certfix")
+
+	legacy := filepath.Join(home, ".certfix")
+	os.MkdirAll(filepath.Join(legacy, "tokens"), 0700)
+	os.WriteFile(filepath.Join(legacy, "config.yaml"), []byte("endpoint: https://certfix.example.com\n"), 0600)
+	os.WriteFile(filepath.Join(legacy, "token.json"), []byte("{}"), 0600)
+	os.WriteFile(filepath.Join(legacy, "tokens", "staging.json"), []byte("{}"), 0600)
+	os.WriteFile(filepath.Join(legacy, "agent.lock"), []byte("1\n"), 0600)
+
+	// Nothing moves while a certfix process holds a lock
+	if message, err := config.MigrateLegacyDir(); err != nil || message != "" {
+		t.Fatalf("expected no migration while a lock is held, got %q (%v)", message, err)
+	}
+	if got := config.GetConfigDir(); got != legacy {
+		t.Errorf("expected ~/.certfix to stay in use, got %s", got)
+	}
+
+	os.Remove(filepath.Join(legacy, "agent.lock"))
+	message, err := config.MigrateLegacyDir()
+	if err != nil || !strings.Contains(message, configDir) {
+		t.Fatalf("expected a migration to %s, got %q (%v)", configDir, message, err)
+	}
+	for _, path := range []string{
+		filepath.Join(configDir, "config.yaml"),
+		filepath.Join(stateDir, "token.json"),
+		filepath.Join(stateDir, "tokens", "staging.json"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s after the migration: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("expected ~/.certfix to be removed, got %v", err)
+	}
+	if config.GetConfigDir() != configDir || config.GetStateDir() != stateDir {
+		t.Errorf("expected %s and %s in use, got %s and %s", configDir, stateDir, config.GetConfigDir(), config.GetStateDir())
+	}
+	if message, err := config.MigrateLegacyDir(); err != nil || message != "" {
+		t.Errorf("expected a second migration to do nothing, got %q (%v)", message, err)
+	}
+
+	// Relative XDG paths are invalid and ignored
+	t.Setenv("XDG_STATE_HOME", "state")
+	if got := config.GetStateDir(); got != filepath.Join(home, ".local", "state", "certfix") {
+		t.Errorf("expected a relative XDG_STATE_HOME to be ignored, got %s", got)
+	}
+}
+
+func TestServicesGetInclude(t *testing.T) {
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("GET", "/services/a1b2c3", fixtureServices[0])
+	fake.HandleJSON("GET", "/services/a1b2c3/keys/list", []map[string]interface{}{{"key_id": "k1"}})
+	fake.HandleJSON("GET", "/services/a1b2c3/matrix/relations", []map[string]interface{}{})
+	fake.HandleJSON("GET", "/services/a1b2c3/certificates", []map[string]interface{}{{"unique_id": "c1"}})
+
+	output, err := runCommand(t, fake, "services", "get", "a1b2c3", "--include", "all", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var service map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &service); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	for _, include := range []string{"keys", "relations", "certificates", "rotation"} {
+		if _, ok := service[include]; !ok {
+			t.Errorf("expected %s in the JSON output", include)
+		}
+	}
+	if keys, _ := service["keys"].([]interface{}); len(keys) != 1 {
+		t.Errorf("expected one key, got %v", service["keys"])
+	}
+
+	// Without --include only the service itself is fetched
+	fake = client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("GET", "/services/a1b2c3", fixtureServices[0])
+	if _, err := runCommand(t, fake, "services", "get", "a1b2c3"); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range fake.Requests() {
+		if strings.Count(r.Endpoint, "/") > 2 {
+			t.Errorf("unexpected request %s %s", r.Method, r.Endpoint)
+		}
+	}
+
+	fake.Handle("GET", "/services/a1b2c3/keys/list", 500, `{"error":"boom"}`)
+	_, err = runCommand(t, fake, "services", "get", "a1b2c3", "--include", "keys")
+	if err == nil || !strings.Contains(err.Error(), "failed to list keys") {
+		t.Errorf("expected the failed section to fail the command, got %v", err)
+	}
+
+	_, err = runCommand(t, fake, "services", "get", "a1b2c3", "--include", "secrets")
+	if err == nil || !strings.Contains(err.Error(), "invalid --include") {
+		t.Errorf("expected an invalid --include to be refused, got %v", err)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	deleted := map[string]interface{}{
+		"service_hash": "0ld5vc", "service_name": "retired-api", "active": false, "deleted_at": "2026-10-01T12:00:00Z",
+	}
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services?include_deleted=true", append(append([]map[string]interface{}{}, fixtureServices...), deleted))
+	output, err := runCommand(t, fake, "services", "list", "--include-deleted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "retired-api") || !strings.Contains(output, "Deleted") {
+		t.Errorf("expected the deleted service to be listed as Deleted:\n%s", output)
+	}
+
+	fake.HandleJSON("GET", "/policies?include_deleted=true", []map[string]interface{}{
+		{"policy_id": 3, "name": "manual", "strategy": "manual", "enabled": false, "deleted": true},
+	})
+	output, err = runCommand(t, fake, "policy", "list", "--include-deleted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Deleted") {
+		t.Errorf("expected the deleted policy to be listed as Deleted:\n%s", output)
+	}
+
+	fake.HandleJSON("POST", "/services/0ld5vc/restore", map[string]interface{}{"service_hash": "0ld5vc", "service_name": "retired-api"})
+	output, err = runCommand(t, fake, "services", "restore", "0ld5vc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Service 0ld5vc restored") || !strings.Contains(output, "retired-api") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+
+	fake.HandleJSON("POST", "/policies/3/restore", map[string]interface{}{"policy_id": 3, "name": "manual"})
+	if output, err = runCommand(t, fake, "policy", "restore", "3"); err != nil || !strings.Contains(output, "Policy 3 restored") {
+		t.Errorf("expected the policy to be restored, got %v:\n%s", err, output)
+	}
+
+	_, err = runCommand(t, fake, "services", "restore", "ffffff")
+	if err == nil || !strings.Contains(err.Error(), "no deleted service ffffff can be restored") {
+		t.Errorf("expected a restore the server refuses with 404 to explain why, got %v", err)
+	}
+
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("DELETE", "/services/a1b2c3", map[string]interface{}{"deleted_at": "2026-10-16T09:00:00Z"})
+	output, err = runCommand(t, fake, "services", "delete", "a1b2c3", "--force")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "certfix services restore a1b2c3") {
+		t.Errorf("expected a restore hint after a restorable delete:\n%s", output)
+	}
+}
+
+func TestWebhookListen(t *testing.T) {
+	// Occupy the listen address so the relay stops right after setting up
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	listen := busy.Addr().String()
+	publicURL := "http://" + listen + "/"
+	args := []string{"webhook", "listen", "a1b2c3", "--forward", "http://localhost:8080/hook", "--listen", listen}
+
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("POST", "/services/a1b2c3/webhooks/temporary", map[string]interface{}{"webhook_id": "tmp-1"})
+	fake.HandleJSON("DELETE", "/services/a1b2c3/webhooks/temporary/tmp-1", map[string]interface{}{})
+	output, err := runCommand(t, fake, args...)
+	if err == nil || !strings.Contains(err.Error(), "failed to listen") {
+		t.Fatalf("expected the busy address to stop the relay, got %v", err)
+	}
+	if !strings.Contains(output, "Temporary webhook tmp-1 removed") {
+		t.Errorf("expected the temporary webhook to be removed:\n%s", output)
+	}
+	for _, r := range fake.Requests() {
+		if r.Method == "PUT" {
+			t.Errorf("expected the service's own webhook to be left alone, got PUT %s", r.Endpoint)
+		}
+	}
+
+	// Without temporary webhooks the service's webhook is only diverted
+	// after confirmation
+	fake = client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("GET", "/services/a1b2c3", map[string]interface{}{"service_hash": "a1b2c3", "webhook_url": "https://consumer.example.com/hook"})
+	fake.HandleJSON("PUT", "/services/a1b2c3", map[string]interface{}{})
+	_, err = runCommand(t, fake, args...)
+	if err == nil || !strings.Contains(err.Error(), "confirmation required") {
+		t.Errorf("expected diverting to need confirmation, got %v", err)
+	}
+	for _, r := range fake.Requests() {
+		if r.Method == "PUT" {
+			t.Errorf("expected no PUT without confirmation, got %s", r.Endpoint)
+		}
+	}
+
+	// The webhook still reads as the consumer's when restoring, as if someone
+	// changed it meanwhile: it is not overwritten
+	output, err = runCommand(t, fake, append(args, "--force")...)
+	if err == nil || !strings.Contains(err.Error(), "failed to listen") {
+		t.Fatalf("expected the busy address to stop the relay, got %v", err)
+	}
+	var puts []interface{}
+	for _, r := range fake.Requests() {
+		if r.Method == "PUT" {
+			puts = append(puts, r.Payload)
+		}
+	}
+	if len(puts) != 1 || !strings.Contains(fmt.Sprint(puts[0]), publicURL) {
+		t.Errorf("expected one PUT diverting the webhook to %s, got %v", publicURL, puts)
+	}
+	if strings.Contains(output, "restored to") {
+		t.Errorf("expected a webhook changed meanwhile not to be restored:\n%s", output)
+	}
+}
+
+func TestSettingFlagsNotSaved(t *testing.T) {
+	defer config.SetRunOverrides(nil)
+
+	_, err := runCommand(t, client.NewFakeClient(), "profile", "add", "flags", "--api-url", "https://flags.example.com",
+		"--timeout", "2m", "--retries", "7", "--reason", "INC-123 hotfix", "--field-manager", "alice", "--trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.GetRequestTimeout(); got != 2*time.Minute {
+		t.Errorf("expected --timeout to apply to the run, got %s", got)
+	}
+	if got := config.GetRetryAttempts(); got != 7 {
+		t.Errorf("expected --retries to apply to the run, got %d", got)
+	}
+	if config.GetChangeReason() != "INC-123 hotfix" || config.GetFieldManager() != "alice" || !config.TraceRequests() {
+		t.Errorf("expected --reason, --field-manager and --trace to apply to the run")
+	}
+
+	data, err := os.ReadFile(filepath.Join(config.GetConfigDir(), "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "https://flags.example.com") {
+		t.Errorf("expected the profile to be saved, got:\n%s", data)
+	}
+	// Neither the flags nor the defaults are written with the change
+	for _, key := range []string{"timeout:", "retry_attempts:", "cache_ttl:", "change_reason:", "field_manager:", "trace:"} {
+		if strings.Contains(string(data), key) {
+			t.Errorf("expected no %s in the config file, got:\n%s", key, data)
+		}
+	}
+}
+
+func TestCachedNameLookup(t *testing.T) {
+	// The cache still has payments-api under a hash it no longer has
+	snapshot := cache.New(config.GetAPIEndpoint())
+	snapshot.SyncedAt = time.Now()
+	snapshot.Put("/services", []byte(`[{"service_hash":"0ld000","service_name":"payments-api"}]`))
+	if err := snapshot.Save(); err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Clear()
+
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("GET", "/services/0ld000/keys/list", []interface{}{})
+	fake.HandleJSON("PUT", "/services/a1b2c3/keys/k1/toggle", map[string]interface{}{})
+
+	// Reads may use the cache
+	if _, err := runCommand(t, fake, "keys", "list", "payments-api"); err != nil {
+		t.Fatal(err)
+	}
+	// Changes look the name up in the API
+	if _, err := runCommand(t, fake, "keys", "toggle", "payments-api", "k1"); err != nil {
+		t.Fatalf("expected the name to be looked up in the API, got %v", err)
+	}
+}
+
+func TestHistoryRedaction(t *testing.T) {
+	defer resetFlags(rootCmd)
+	defer os.Remove(history.GetPath())
+
+	cmd, _, err := rootCmd.Find([]string{"api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.ParseFlags([]string{"POST", "/services?token=abc", "--data", `{"password":"hunter2"}`}); err != nil {
+		t.Fatal(err)
+	}
+	recordHistory(cmd, time.Now(), nil)
+
+	data, err := os.ReadFile(history.GetPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"abc", "hunter2"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("expected %q to be redacted, got %s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), `"command":"api"`) {
+		t.Errorf("expected the command to be recorded, got %s", data)
+	}
+
+	cmd, _, err = rootCmd.Find([]string{"apply"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.ParseFlags([]string{"--notify", "slack://hooks.slack.com/services/T000/B000/XXXX"}); err != nil {
+		t.Fatal(err)
+	}
+	recordHistory(cmd, time.Now(), nil)
+
+	data, err = os.ReadFile(history.GetPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "XXXX") || !strings.Contains(string(data), `"notify":"***"`) {
+		t.Errorf("expected the webhook URL to be redacted, got %s", data)
+	}
+}
+
+func TestApplySkipExisting(t *testing.T) {
+	cfg := models.CertfixConfig{Policies: []models.PolicyConfig{{Name: "nightly", Strategy: "events"}}}
+
+	for _, tt := range []struct {
+		existing   existingResources
+		wantCreate bool
+	}{
+		{skipExistingServices, true},
+		{skipAllExisting, false},
+	} {
+		fake := client.NewFakeClient()
+		fake.HandleJSON("GET", "/policies", []map[string]interface{}{{"policy_id": "p1", "name": "nightly"}})
+		fake.HandleJSON("POST", "/policies", map[string]interface{}{"policy_id": "p2"})
+
+		var created []models.CreatedResource
+		if err := applyConfiguration(&cfg, fake, testToken, &created, tt.existing); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(created) == 1; got != tt.wantCreate {
+			t.Errorf("mode %d: expected the existing policy to be created again: %v, got %+v", tt.existing, tt.wantCreate, fake.Requests())
+		}
+	}
+}
+
+func TestAgentLock(t *testing.T) {
+	release, err := acquireAgentLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acquireAgentLock(); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("expected a running agent to keep its lock, got %v", err)
+	}
+	release()
+
+	// The lock of an agent that died is taken over
+	if runtime.GOOS != "windows" {
+		os.WriteFile(agentLockPath(), []byte("2147483646\n"), 0600)
+		release, err = acquireAgentLock()
+		if err != nil {
+			t.Fatalf("expected a stale lock to be taken over, got %v", err)
+		}
+		release()
+	}
+}
+
+func TestSigningSecretStorage(t *testing.T) {
+	if err := config.Set("profiles.signer.endpoint", "https://signer.certfix.invalid"); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetProfileSigningKey("signer", "cli-ops", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	defer config.SetProfileSigningKey("signer", "", "")
+
+	if keyID, secret := config.GetProfileSigningKey("signer"); keyID != "cli-ops" || secret != "s3cret" {
+		t.Errorf("expected the signing key to be read back, got %q and %q", keyID, secret)
+	}
+	data, err := os.ReadFile(filepath.Join(config.GetConfigDir(), "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("expected the secret to stay out of the config file, got:\n%s", data)
+	}
+	path := filepath.Join(config.GetStateDir(), "signing", "signer")
+	if info, err := os.Stat(path); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0600) {
+		t.Errorf("expected the secret in %s, readable only by the user: %v", path, err)
+	}
+
+	if err := config.SetProfileSigningKey("signer", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, secret := config.GetProfileSigningKey("signer"); secret != "" {
+		t.Errorf("expected signing to be turned off, got secret %q", secret)
+	}
+}
+
+func TestMatrixIncoming(t *testing.T) {
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("GET", "/services/d4e5f6", fixtureServices[1])
+	fake.HandleJSON("GET", "/services/d4e5f6/matrix", map[string]interface{}{"service": "legacy-billing", "relations": []interface{}{}})
+	fake.HandleJSON("GET", "/services/a1b2c3/matrix/relations", []map[string]interface{}{
+		{"relation_id": 4, "related_service_hash": "d4e5f6", "relation_type": "depends_on", "enabled": true},
+	})
+	fake.HandleJSON("GET", "/services/d4e5f6/matrix/relations", []interface{}{})
+
+	output, err := runCommand(t, fake, "matrix", "get", "d4e5f6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "IN ") || !strings.Contains(output, "payments-api") {
+		t.Errorf("expected the incoming relation of payments-api, got:\n%s", output)
+	}
+
+	// The relations of every service are not read with --outgoing-only
+	before := len(fake.Requests())
+	output, err = runCommand(t, fake, "matrix", "get", "d4e5f6", "--outgoing-only")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.Requests())-before != 2 || !strings.Contains(output, "No relations found.") {
+		t.Errorf("expected only the service and its matrix to be read, got %+v\n%s", fake.Requests()[before:], output)
+	}
+
+	for _, flag := range []string{"--reverse", "-r", "--incoming"} {
+		output, err = runCommand(t, fake, "matrix", "list", "d4e5f6", flag)
+		if err != nil || !strings.Contains(output, "payments-api") {
+			t.Errorf("expected matrix list %s to show payments-api, got %v:\n%s", flag, err, output)
+		}
+	}
+}
+
+func TestKeysAddExpiry(t *testing.T) {
+	tests := []struct {
+		args []string
+		days int
+	}{
+		{args: []string{"--expiration", "30"}, days: 30},
+		{args: []string{"--expires-at", time.Now().AddDate(0, 0, 10).Format(time.RFC3339)}, days: 10},
+		{args: []string{"--no-expiry"}, days: 36500},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			fake := client.NewFakeClient()
+			fake.HandleJSON("GET", "/services", fixtureServices)
+			fake.HandleJSON("GET", "/services/a1b2c3", fixtureServices[0])
+			fake.HandleJSON("POST", "/services/a1b2c3/keys", map[string]interface{}{"key_id": 1, "key_name": "ci", "api_key": "cfx_secret"})
+
+			if _, err := runCommand(t, fake, append([]string{"keys", "add", "a1b2c3", "--name", "ci"}, tt.args...)...); err != nil {
+				t.Fatal(err)
+			}
+			requests := fake.Requests()
+			payload, _ := requests[len(requests)-1].Payload.(map[string]interface{})
+			if payload["expiration_days"] != tt.days || len(payload) != 3 {
+				t.Errorf("expected only expiration_days=%d for the expiry, got %v", tt.days, payload)
+			}
+		})
+	}
+}
+
+func TestVersionCheckCache(t *testing.T) {
+	defer os.Remove(versionCheckPath())
+
+	requests := 0
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+		w.Write([]byte(`{"version":"2.4.0"}`))
+	}))
+	defer server.Close()
+
+	// A failed check is not repeated until versionCheckRetry has passed
+	if info := cachedServerInfo(server.URL); info != nil || requests != 1 {
+		t.Fatalf("expected one failed check, got %+v after %d requests", info, requests)
+	}
+	status = http.StatusOK
+	if info := cachedServerInfo(server.URL); info != nil || requests != 1 {
+		t.Errorf("expected the failure to be cached, got %+v after %d requests", info, requests)
+	}
+
+	saveServerInfo(&serverInfo{Endpoint: server.URL, Failed: true, CheckedAt: time.Now().Add(-versionCheckRetry)})
+	if info := cachedServerInfo(server.URL); info == nil || info.Version != "2.4.0" || requests != 2 {
+		t.Errorf("expected the check to be retried, got %+v after %d requests", info, requests)
+	}
+	if info := cachedServerInfo(server.URL); info == nil || requests != 2 {
+		t.Errorf("expected the successful check to be cached, got %+v after %d requests", info, requests)
+	}
+}
+
+func TestIntegrationKeyScopes(t *testing.T) {
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/me", map[string]interface{}{"is_super_user": true})
+	fake.HandleJSON("POST", "/integration-keys", map[string]interface{}{"name": "ci", "key": "ik_new"})
+
+	// A server that ignores scopes must not have the key shown as scoped
+	output, err := runCommand(t, fake, "integration-keys", "create", "ci", "--events", "e1,e2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Scopes: all events") {
+		t.Errorf("expected only the scopes reported by the server, got:\n%s", output)
+	}
+
+	fake.HandleJSON("POST", "/integration-keys", map[string]interface{}{"name": "ci", "key": "ik_new", "scopes": map[string]interface{}{"event_ids": []string{"e1", "e2"}}})
+	output, err = runCommand(t, fake, "integration-keys", "create", "ci", "--events", "e1,e2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Scopes: events: e1, e2") {
+		t.Errorf("expected the scopes reported by the server, got:\n%s", output)
+	}
+}
+
+func TestIntegrationKeyRotateOverlap(t *testing.T) {
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/me", map[string]interface{}{"is_super_user": true})
+	fake.HandleJSON("PATCH", "/integration-keys/7/rotate", map[string]interface{}{"name": "ci", "key": "ik_new"})
+
+	output, err := runCommand(t, fake, "integration-keys", "rotate", "7", "--overlap", "1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, "remains valid") {
+		t.Errorf("expected no expiry for the previous key when the server does not report one, got:\n%s", output)
+	}
+
+	fake.HandleJSON("PATCH", "/integration-keys/7/rotate", map[string]interface{}{"name": "ci", "key": "ik_new", "previous_key_expires_at": "2026-10-16T12:00:00Z"})
+	output, err = runCommand(t, fake, "integration-keys", "rotate", "7", "--overlap", "1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "remains valid until 2026-10-16 12:00 UTC") {
+		t.Errorf("expected the expiry reported by the server, got:\n%s", output)
+	}
+}
+
+func TestKeysRotateExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt interface{}
+		days      int
+	}{
+		{name: "expiring", expiresAt: time.Now().AddDate(0, 0, 30).UTC().Format(time.RFC3339), days: 30},
+		{name: "never expiring", expiresAt: nil, days: noExpiryDays},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := client.NewFakeClient()
+			fake.HandleJSON("GET", "/services", fixtureServices)
+			fake.HandleJSON("GET", "/services/a1b2c3", fixtureServices[0])
+			fake.HandleJSON("GET", "/services/a1b2c3/keys/list", []map[string]interface{}{
+				{"key_id": 1, "key_name": "ci", "enabled": true, "expires_at": tt.expiresAt},
+			})
+			fake.HandleJSON("POST", "/services/a1b2c3/keys", map[string]interface{}{"key_id": 2, "key_name": "ci", "api_key": "cfx_secret"})
+			fake.HandleJSON("DELETE", "/services/a1b2c3/keys/1", map[string]interface{}{})
+
+			if _, err := runCommand(t, fake, "keys", "rotate", "a1b2c3", "1", "--delete-old"); err != nil {
+				t.Fatal(err)
+			}
+			for _, request := range fake.Requests() {
+				if request.Method != "POST" {
+					continue
+				}
+				if payload, _ := request.Payload.(map[string]interface{}); payload["expiration_days"] != tt.days {
+					t.Errorf("expected expiration_days=%d for the replacement, got %v", tt.days, payload)
+				}
+			}
+		})
+	}
+}
+
+func TestCommandRequests(t *testing.T) {
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("GET", "/services/a1b2c3", fixtureServices[0])
+	fake.HandleJSON("PUT", "/services/a1b2c3", map[string]interface{}{"service_hash": "a1b2c3", "active": false})
+
+	if _, err := runCommand(t, fake, "services", "deactivate", "a1b2c3"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hash is looked up on its own rather than by listing every service
+	requests := fake.Requests()
+	if len(requests) != 2 || requests[0].Endpoint != "/services/a1b2c3" || requests[1].Method != "PUT" {
+		t.Fatalf("expected a lookup and 1 update, got %+v", requests)
+	}
+	request := requests[1]
+	if request.Token != testToken {
+		t.Errorf("expected the stored session token, got %q", request.Token)
+	}
+	payload, _ := request.Payload.(map[string]interface{})
+	if active, ok := payload["active"].(bool); !ok || active {
+		t.Errorf("expected active=false in the payload, got %v", request.Payload)
+	}
+}
+
+func TestIdentifierResolution(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		wantPath   string
+		wantErr    string
+	}{
+		{name: "hash", identifier: "d4e5f6", wantPath: "/services/d4e5f6/keys/list"},
+		{name: "exact name", identifier: "Payments-API", wantPath: "/services/a1b2c3/keys/list"},
+		{name: "partial name", identifier: "billing", wantPath: "/services/d4e5f6/keys/list"},
+		{name: "ambiguous", identifier: "a", wantErr: "service 'a' is ambiguous, 2 match"},
+		{name: "unknown", identifier: "checkout", wantErr: "no fake response for GET /services/checkout/keys/list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := client.NewFakeClient()
+			fake.HandleJSON("GET", "/services", fixtureServices)
+			fake.HandleJSON("GET", "/services/a1b2c3/keys/list", []interface{}{})
+			fake.HandleJSON("GET", "/services/d4e5f6/keys/list", []interface{}{})
+
+			_, err := runCommand(t, fake, "keys", "list", tt.identifier)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, request := range fake.Requests() {
+				if request.Endpoint == tt.wantPath {
+					return
+				}
+			}
+			t.Errorf("expected a request to %s, got %+v", tt.wantPath, fake.Requests())
+		})
+	}
+
+	// Commands that change a service take a partial name only with --by-name
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/services", fixtureServices)
+	fake.HandleJSON("PUT", "/services/d4e5f6/keys/k1/toggle", map[string]interface{}{})
+	_, err := runCommand(t, fake, "keys", "toggle", "billing", "k1")
+	if err == nil || !strings.Contains(err.Error(), "PUT /services/billing/keys/k1/toggle") {
+		t.Errorf("expected a partial name to be passed on unresolved, got %v", err)
+	}
+	if _, err := runCommand(t, fake, "keys", "toggle", "k1", "--by-name", "billing"); err != nil {
+		t.Errorf("expected --by-name to accept a partial name, got %v", err)
+	}
+}
+
+// addTestProfile configures a staging profile with a stored session
+func addTestProfile(t *testing.T) {
+	t.Helper()
+	if err := config.Set("profiles.staging.endpoint", "https://staging.certfix.invalid"); err != nil {
+		t.Fatal(err)
+	}
+	config.SetProfile("staging")
+	err := auth.StoreToken(testToken)
+	config.SetProfile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// useProfileClients makes commands talk to fakes by profile name
+func useProfileClients(t *testing.T, fakes map[string]*client.FakeClient) {
+	previous := newProfileClient
+	newProfileClient = func(profile string) client.APIClient { return fakes[profile] }
+	t.Cleanup(func() { newProfileClient = previous })
+}
+
+func TestDiff(t *testing.T) {
+	addTestProfile(t)
+
+	prod := client.NewFakeClient()
+	prod.HandleJSON("GET", "/policies", []map[string]interface{}{
+		{"policy_id": 1, "name": "nightly", "strategy": "maintenance_window", "enabled": true, "cron_config": map[string]interface{}{"minute": "0", "hour": "2"}},
+	})
+	prod.HandleJSON("GET", "/services", fixtureServices)
+	prod.HandleJSON("GET", "/services/a1b2c3/keys/list", []map[string]interface{}{{"key_name": "ci", "enabled": true}})
+	prod.HandleJSON("GET", "/services/a1b2c3/matrix/relations", []map[string]interface{}{{"related_service_hash": "d4e5f6", "relation_type": "depends_on"}})
+	prod.HandleJSON("GET", "/services/d4e5f6/keys/list", []interface{}{})
+	prod.HandleJSON("GET", "/services/d4e5f6/matrix/relations", []interface{}{})
+
+	staging := client.NewFakeClient()
+	staging.HandleJSON("GET", "/policies", []map[string]interface{}{
+		{"policy_id": 9, "name": "nightly", "strategy": "maintenance_window", "enabled": true, "cron_config": map[string]interface{}{"minute": "30", "hour": "2"}},
+	})
+	staging.HandleJSON("GET", "/services", []map[string]interface{}{
+		{"service_hash": "f0f0f0", "service_name": "payments-api", "active": false, "service_group_name": "payments", "policy_name": "nightly"},
+		{"service_hash": "0a0b0c", "service_name": "checkout", "active": true},
+	})
+	staging.HandleJSON("GET", "/services/f0f0f0/keys/list", []interface{}{})
+	staging.HandleJSON("GET", "/services/f0f0f0/matrix/relations", []interface{}{})
+	staging.HandleJSON("GET", "/services/0a0b0c/keys/list", []interface{}{})
+	staging.HandleJSON("GET", "/services/0a0b0c/matrix/relations", []interface{}{})
+
+	useProfileClients(t, map[string]*client.FakeClient{"default": prod, "staging": staging})
+
+	output, err := runCommand(t, client.NewFakeClient(), "diff", "--from", "profile:default", "--to", "profile:staging", "--only", "policies,services")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "diff", output)
+}
+
+func TestMigrate(t *testing.T) {
+	addTestProfile(t)
+
+	source := client.NewFakeClient()
+	source.HandleJSON("GET", "/service-groups", []map[string]interface{}{
+		{"service_group_id": "g1", "name": "payments", "enabled": true},
+		{"service_group_id": "g2", "name": "web", "enabled": true},
+	})
+	source.HandleJSON("GET", "/service-groups/g1", map[string]interface{}{"service_group_id": "g1", "name": "payments"})
+	source.HandleJSON("GET", "/events", []interface{}{})
+	source.HandleJSON("GET", "/policies", []map[string]interface{}{
+		{"policy_id": 1, "name": "nightly", "strategy": "maintenance_window", "enabled": true},
+		{"policy_id": 2, "name": "unused", "strategy": "events", "enabled": true},
+	})
+	source.HandleJSON("GET", "/services", fixtureServices)
+	source.HandleJSON("GET", "/services/a1b2c3/keys/list", []map[string]interface{}{{"key_name": "ci", "enabled": true}})
+	source.HandleJSON("GET", "/services/a1b2c3/matrix/relations", []map[string]interface{}{{"related_service_hash": "d4e5f6"}})
+
+	target := client.NewFakeClient()
+	target.HandleJSON("POST", "/policies", map[string]interface{}{"policy_id": "p9"})
+	target.HandleJSON("POST", "/service-groups", map[string]interface{}{"service_group_id": "g9"})
+	target.HandleJSON("GET", "/service-groups/name/payments-eu", map[string]interface{}{"service_group_id": "g9"})
+	target.HandleJSON("GET", "/policies", []map[string]interface{}{{"policy_id": "p9", "name": "nightly"}})
+	target.HandleJSON("POST", "/services", map[string]interface{}{})
+	target.HandleJSON("POST", "/services/a1b2c3/keys", map[string]interface{}{"key_id": "k9"})
+	useProfileClients(t, map[string]*client.FakeClient{"default": source, "staging": target})
+
+	output, err := runCommand(t, client.NewFakeClient(), "migrate", "--from", "profile:default", "--to", "profile:staging",
+		"--group", "payments", "--rename", "payments=payments-eu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Migrated 4 resource(s)") {
+		t.Errorf("expected 4 migrated resources, got %q", output)
+	}
+
+	var created []string
+	for _, request := range target.Requests() {
+		if request.Method == "POST" {
+			payload, _ := request.Payload.(map[string]interface{})
+			created = append(created, fmt.Sprintf("%s %v", request.Endpoint, payload["name"]))
+			if request.Endpoint == "/services" && (payload["service_group_id"] != "g9" || payload["policy_id"] != "p9") {
+				t.Errorf("expected the service to reference the migrated group and policy, got %v", payload)
+			}
+		}
+	}
+	want := []string{"/policies nightly", "/service-groups payments-eu", "/services <nil>", "/services/a1b2c3/keys <nil>"}
+	if strings.Join(created, ", ") != strings.Join(want, ", ") {
+		t.Errorf("expected creates %v in dependency order, got %v", want, created)
+	}
+}
@@ -0,0 +1,216 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// serverVersionPath is the unauthenticated endpoint describing the server
+	serverVersionPath = "/version"
+
+	// versionCheckTTL is how long a startup compatibility check is cached
+	versionCheckTTL = 24 * time.Hour
+
+	// versionCheckRetry is how long a failed startup check is cached, so an
+	// unreachable server does not cost every command the full timeout
+	versionCheckRetry = 15 * time.Minute
+
+	// versionCheckTimeout bounds the startup check so commands are never held up
+	versionCheckTimeout = 3 * time.Second
+
+	// versionCheckEnv disables the startup check when set to any value
+	versionCheckEnv = "CERTFIX_NO_VERSION_CHECK"
+)
+
+// serverInfo is what the server reports about its version and features
+type serverInfo struct {
+	Endpoint      string    `json:"endpoint"`
+	Supported     bool      `json:"supported"`
+	Version       string    `json:"version,omitempty"`
+	APIVersion    string    `json:"api_version,omitempty"`
+	MinCLIVersion string    `json:"min_cli_version,omitempty"`
+	Features      []string  `json:"features,omitempty"`
+	Failed        bool      `json:"failed,omitempty"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// commandFeatures maps command path prefixes to the server feature they need.
+// Servers that do not list features are assumed to support everything.
+var commandFeatures = map[string]string{
+	"instances":        "instances",
+	"integration-keys": "integration_keys",
+	"events send":      "external_events",
+	"service-groups":   "service_groups",
+	"personal-tokens":  "personal_tokens",
+	"matrix":           "service_matrix",
+	"ca":               "ca",
+	"teams":            "teams",
+	"services restore": "soft_delete",
+	"policy restore":   "soft_delete",
+}
+
+// skipVersionCheck lists top-level commands that never trigger the startup check
+var skipVersionCheck = map[string]bool{
+	"version":   true,
+	"help":      true,
+	"configure": true,
+	"history":   true,
+	"telemetry": true,
+	"ping":      true,
+}
+
+// fetchServerInfo asks the server for its version. Servers without the version
+// endpoint are reported with Supported set to false.
+func fetchServerInfo(endpoint string) (*serverInfo, error) {
+	apiClient := client.NewHTTPClient(endpoint)
+	status, body, err := apiClient.RawWithAuth("GET", serverVersionPath, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	info := &serverInfo{Endpoint: endpoint, CheckedAt: time.Now()}
+	if status == 404 {
+		return info, nil
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("request failed with status %d", status)
+	}
+	if err := json.Unmarshal(body, info); err != nil {
+		return nil, fmt.Errorf("failed to parse server version: %w", err)
+	}
+	info.Endpoint = endpoint
+	info.Supported = true
+	info.CheckedAt = time.Now()
+	sort.Strings(info.Features)
+	return info, nil
+}
+
+// requiredFeature returns the server feature a command needs, if any
+func requiredFeature(cmd *cobra.Command) string {
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	for prefix, feature := range commandFeatures {
+		if path == prefix || strings.HasPrefix(path, prefix+" ") {
+			return feature
+		}
+	}
+	return ""
+}
+
+// compatibilityWarnings lists the problems between this CLI and the server. A
+// nil cmd checks the CLI version only.
+func compatibilityWarnings(info *serverInfo, cmd *cobra.Command) []string {
+	var warnings []string
+	if !info.Supported {
+		return warnings
+	}
+
+	if info.MinCLIVersion != "" {
+		current, err := parseVersion(Version)
+		minimum, minErr := parseVersion(info.MinCLIVersion)
+		if err == nil && minErr == nil && compareVersions(current, minimum) < 0 {
+			warnings = append(warnings, fmt.Sprintf("certfix v%s is older than v%s, the oldest CLI supported by %s; please upgrade",
+				strings.TrimPrefix(Version, "v"), strings.TrimPrefix(info.MinCLIVersion, "v"), info.Endpoint))
+		}
+	}
+
+	if cmd != nil && len(info.Features) > 0 {
+		if feature := requiredFeature(cmd); feature != "" {
+			i := sort.SearchStrings(info.Features, feature)
+			if i == len(info.Features) || info.Features[i] != feature {
+				warnings = append(warnings, fmt.Sprintf("the server does not support %q, which '%s' uses; the command may fail",
+					feature, cmd.CommandPath()))
+			}
+		}
+	}
+	return warnings
+}
+
+// versionCheckPath is the cache of the last startup check
+func versionCheckPath() string {
+	return filepath.Join(config.GetStateDir(), "version-check.json")
+}
+
+// saveServerInfo caches the result of a startup check
+func saveServerInfo(info *serverInfo) {
+	if data, err := json.Marshal(info); err == nil {
+		os.MkdirAll(filepath.Dir(versionCheckPath()), 0700)
+		os.WriteFile(versionCheckPath(), data, 0600)
+	}
+}
+
+// cachedServerInfo returns the server info for endpoint, fetching it at most
+// once per versionCheckTTL, or once per versionCheckRetry after a failure. Any
+// failure yields nil: the check is advisory.
+func cachedServerInfo(endpoint string) *serverInfo {
+	log := logger.GetLogger()
+
+	if data, err := os.ReadFile(versionCheckPath()); err == nil {
+		var cached serverInfo
+		if json.Unmarshal(data, &cached) == nil && cached.Endpoint == endpoint {
+			switch age := time.Since(cached.CheckedAt); {
+			case cached.Failed && age < versionCheckRetry:
+				return nil
+			case !cached.Failed && age < versionCheckTTL:
+				return &cached
+			}
+		}
+	}
+
+	failed := &serverInfo{Endpoint: endpoint, Failed: true, CheckedAt: time.Now()}
+
+	result := make(chan *serverInfo, 1)
+	go func() {
+		info, err := fetchServerInfo(endpoint)
+		if err != nil {
+			log.Debugf("Version check failed: %v", err)
+		}
+		result <- info
+	}()
+
+	select {
+	case info := <-result:
+		if info == nil {
+			saveServerInfo(failed)
+			return nil
+		}
+		saveServerInfo(info)
+		return info
+	case <-time.After(versionCheckTimeout):
+		log.Debugf("Version check timed out")
+		saveServerInfo(failed)
+		return nil
+	}
+}
+
+// checkServerCompatibility prints startup warnings about the server to stderr
+func checkServerCompatibility(cmd *cobra.Command) {
+	if os.Getenv(versionCheckEnv) != "" || offline || !cmd.HasParent() {
+		return
+	}
+	top := cmd
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+	if skipVersionCheck[top.Name()] {
+		return
+	}
+
+	info := cachedServerInfo(config.GetAPIEndpoint())
+	if info == nil {
+		return
+	}
+	for _, warning := range compatibilityWarnings(info, cmd) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
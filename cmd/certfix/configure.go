@@ -31,11 +31,13 @@ Set up your API endpoint URL and other essential settings.`,
 		apiURL, _ := cmd.Flags().GetString("api-url")
 		timeout, _ := cmd.Flags().GetInt("timeout")
 		retryAttempts, _ := cmd.Flags().GetInt("retry-attempts")
+		fieldManager, _ := cmd.Flags().GetString("field-manager")
 
 		// Check if any flags were provided
 		hasFlags := cmd.Flags().Changed("api-url") || 
 					cmd.Flags().Changed("timeout") || 
-					cmd.Flags().Changed("retry-attempts")
+					cmd.Flags().Changed("retry-attempts") ||
+					cmd.Flags().Changed("field-manager")
 
 		// If no flags provided, run interactive configuration
 		if !hasFlags {
@@ -88,6 +90,17 @@ Set up your API endpoint URL and other essential settings.`,
 			fmt.Printf("✓ Retry attempts configured: %d\n", retryAttempts)
 		}
 
+		// Set field manager if provided
+		if cmd.Flags().Changed("field-manager") {
+			if err := config.Set("field_manager", strings.TrimSpace(fieldManager)); err != nil {
+				log.WithError(err).Error("Failed to set field manager")
+				return fmt.Errorf("failed to set field manager: %w", err)
+			}
+
+			log.Infof("Field manager set to: %s", fieldManager)
+			fmt.Printf("✓ Field manager configured: %s\n", fieldManager)
+		}
+
 		fmt.Println("\nConfiguration saved successfully!")
 		return nil
 	},
@@ -184,7 +197,7 @@ func interactiveConfigure() error {
 	fmt.Printf("✓ Timeout configured: %d seconds\n", timeout)
 
 	// Configure retry attempts
-	currentRetry := 3
+	currentRetry := 0
 	if retry, ok := configs["retry_attempts"]; ok {
 		if r, ok := retry.(int); ok {
 			currentRetry = r
@@ -241,11 +254,15 @@ func showCurrentConfig() error {
 	if retryAttempts, ok := configs["retry_attempts"]; ok {
 		fmt.Printf("Retry Attempts:  %v\n", retryAttempts)
 	}
+	if fieldManager, ok := configs["field_manager"]; ok && fieldManager != "" {
+		fmt.Printf("Field Manager:   %v\n", fieldManager)
+	}
 
 	fmt.Println("\nTo change settings, use:")
 	fmt.Println("  certfix configure --api-url <url>")
 	fmt.Println("  certfix configure --timeout <seconds>")
 	fmt.Println("  certfix configure --retry-attempts <count>")
+	fmt.Println("  certfix configure --field-manager <name>")
 
 	return nil
 }
@@ -257,4 +274,5 @@ func init() {
 	configureCmd.Flags().StringP("api-url", "a", "", "API endpoint URL (e.g., https://api.certfix.io)")
 	configureCmd.Flags().IntP("timeout", "t", 0, "Request timeout in seconds")
 	configureCmd.Flags().IntP("retry-attempts", "r", 0, "Number of retry attempts for failed requests")
+	configureCmd.Flags().String("field-manager", "", "Name sent with every change for the server's audit log (e.g. ops-alice, ci-deploy)")
 }
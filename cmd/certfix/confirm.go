@@ -0,0 +1,68 @@
+package certfix
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// skipConfirmation reports whether the user already confirmed on the command
+// line, with --yes or the command's own --force
+func skipConfirmation(cmd *cobra.Command) bool {
+	force, _ := cmd.Flags().GetBool("force")
+	return force || assumeYes
+}
+
+// canPrompt reports whether a confirmation can be asked. It fails when stdin
+// is not a terminal, so scripts and CI jobs get an error instead of hanging or
+// reading their piped input as an answer.
+func canPrompt(cmd *cobra.Command) error {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil
+	}
+	cmd.SilenceUsage = true
+	if cmd.Flags().Lookup("force") != nil {
+		return fmt.Errorf("confirmation required but stdin is not a terminal; pass --force or --yes to proceed")
+	}
+	return fmt.Errorf("confirmation required but stdin is not a terminal; pass --yes to proceed")
+}
+
+// confirm asks a y/N question and reports whether the user answered yes. It
+// returns true without asking when skipConfirmation does.
+func confirm(cmd *cobra.Command, prompt string) (bool, error) {
+	if skipConfirmation(cmd) {
+		return true, nil
+	}
+	if err := canPrompt(cmd); err != nil {
+		return false, err
+	}
+
+	fmt.Printf("%s (y/N): ", prompt)
+	answer := strings.ToLower(readAnswer())
+	return answer == "y" || answer == "yes", nil
+}
+
+// confirmTyped asks the user to type expected to confirm, for actions that
+// cannot be undone. It returns true without asking when skipConfirmation does.
+func confirmTyped(cmd *cobra.Command, prompt, expected string) (bool, error) {
+	if skipConfirmation(cmd) {
+		return true, nil
+	}
+	if err := canPrompt(cmd); err != nil {
+		return false, err
+	}
+
+	fmt.Println(prompt)
+	fmt.Printf("Type '%s' to confirm: ", expected)
+	return readAnswer() == expected, nil
+}
+
+// readAnswer reads a line from stdin without its surrounding whitespace
+func readAnswer() string {
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line)
+}
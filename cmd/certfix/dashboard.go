@@ -5,8 +5,6 @@ import (
 	"fmt"
 
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -23,8 +21,7 @@ var dashboardCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/dashboard/stats", token)
 		if err != nil {
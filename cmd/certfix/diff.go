@@ -0,0 +1,374 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// newProfileClient returns the client used to talk to the API of a profile.
+// Tests replace it with fakes.
+var newProfileClient = func(profile string) client.APIClient {
+	return api.NewProfileHTTPClient(profile)
+}
+
+// environment is a profile a command reads from or writes to
+type environment struct {
+	Profile  string `json:"profile"`
+	Endpoint string `json:"endpoint"`
+
+	client client.APIClient
+	token  string
+}
+
+// openEnvironment parses a "profile:<name>" argument and connects to the
+// profile with its stored session
+func openEnvironment(spec string) (*environment, error) {
+	name := spec
+	if prefix, rest, ok := strings.Cut(spec, ":"); ok {
+		if prefix != "profile" {
+			return nil, fmt.Errorf("invalid environment '%s': use profile:<name>", spec)
+		}
+		name = rest
+	}
+	name = strings.ToLower(name)
+	if !config.HasProfile(name) {
+		return nil, fmt.Errorf("profile '%s' not found: add it with 'certfix profile add %s --api-url <url>'", name, name)
+	}
+
+	token, err := auth.GetProfileToken(name)
+	if err != nil {
+		return nil, fmt.Errorf("profile '%s': %w", name, err)
+	}
+	return &environment{
+		Profile:  name,
+		Endpoint: config.GetProfileAPIEndpoint(name),
+		client:   newProfileClient(name),
+		token:    token,
+	}, nil
+}
+
+// fieldChange is a field whose value differs between two environments. An
+// empty value means the field is not set.
+type fieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// resourceChange is a resource that only exists in one environment or whose
+// fields differ
+type resourceChange struct {
+	Type   string        `json:"type"`
+	Name   string        `json:"name"`
+	Change string        `json:"change"` // "added", "removed" or "changed"
+	Fields []fieldChange `json:"fields,omitempty"`
+}
+
+// diffKindLabels name the resource types in the diff output
+var diffKindLabels = map[string]string{
+	"events":         "Events",
+	"policies":       "Policies",
+	"service-groups": "Service groups",
+	"services":       "Services",
+}
+
+// comparableResources flattens the resources of a kind into name → field →
+// value. Identifiers, timestamps and counters are left out since they always
+// differ between environments; relations refer to services by name.
+func comparableResources(cfg *models.CertfixConfig, kind string) map[string]map[string]string {
+	resources := make(map[string]map[string]string)
+	switch kind {
+	case "events":
+		for _, e := range cfg.Events {
+			resources[e.Name] = map[string]string{
+				"severity": e.Severity,
+				"enabled":  fmt.Sprintf("%t", e.Enabled),
+			}
+		}
+	case "policies":
+		for _, p := range cfg.Policies {
+			fields := map[string]string{
+				"strategy": p.Strategy,
+				"enabled":  fmt.Sprintf("%t", p.Enabled),
+			}
+			if strategy, err := parseStrategy(p.Strategy); err == nil {
+				fields["strategy"] = strategy
+			}
+			for key, value := range p.CronConfig {
+				fields["cron_config."+key] = value
+			}
+			for key, value := range p.EventConfig {
+				data, _ := json.Marshal(value)
+				fields["event_config."+key] = strings.Trim(string(data), `"`)
+			}
+			if p.GradualConfig != nil {
+				if p.GradualConfig.BatchSize > 0 {
+					fields["gradual_config.batch_size"] = fmt.Sprintf("%d", p.GradualConfig.BatchSize)
+				}
+				fields["gradual_config.interval"] = p.GradualConfig.Interval
+			}
+			resources[p.Name] = fields
+		}
+	case "service-groups":
+		for _, g := range cfg.ServiceGroups {
+			resources[g.Name] = map[string]string{
+				"description": g.Description,
+				"enabled":     fmt.Sprintf("%t", g.Enabled),
+			}
+		}
+	case "services":
+		names := make(map[string]string, len(cfg.Services))
+		for _, s := range cfg.Services {
+			names[s.Hash] = s.Name
+		}
+		for _, s := range cfg.Services {
+			dnsNames := append([]string(nil), s.DNSNames...)
+			sort.Strings(dnsNames)
+			fields := map[string]string{
+				"active":         fmt.Sprintf("%t", s.Active),
+				"webhook_url":    s.WebhookURL,
+				"group":          s.GroupName,
+				"policy":         s.PolicyName,
+				"reload_service": s.ReloadService,
+				"dns_names":      strings.Join(dnsNames, ", "),
+			}
+			for key, value := range s.Labels {
+				fields["labels."+key] = value
+			}
+			for _, key := range s.Keys {
+				fields["keys."+key.Name] = "disabled"
+				if key.Enabled {
+					fields["keys."+key.Name] = "enabled"
+				}
+			}
+			for _, rel := range s.Relations {
+				target := rel.TargetHash
+				if name, ok := names[target]; ok {
+					target = name
+				}
+				var parts []string
+				for _, part := range []string{rel.Type, rel.Direction, rel.Description} {
+					if part != "" {
+						parts = append(parts, part)
+					}
+				}
+				if len(parts) == 0 {
+					parts = append(parts, "related")
+				}
+				fields["relations."+target] = strings.Join(parts, " ")
+			}
+			resources[s.Name] = fields
+		}
+	}
+	return resources
+}
+
+// diffEnvironments compares the exports of two environments kind by kind
+func diffEnvironments(from, to *models.CertfixConfig, kinds map[string]bool) []resourceChange {
+	var changes []resourceChange
+	for _, kind := range environmentKinds {
+		if !kinds[kind] {
+			continue
+		}
+		before := comparableResources(from, kind)
+		after := comparableResources(to, kind)
+
+		names := make(map[string]bool)
+		for name := range before {
+			names[name] = true
+		}
+		for name := range after {
+			names[name] = true
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+
+		for _, name := range sorted {
+			oldFields, inFrom := before[name]
+			newFields, inTo := after[name]
+			switch {
+			case !inFrom:
+				changes = append(changes, resourceChange{Type: kind, Name: name, Change: "added"})
+			case !inTo:
+				changes = append(changes, resourceChange{Type: kind, Name: name, Change: "removed"})
+			default:
+				if fields := diffFields(oldFields, newFields); len(fields) > 0 {
+					changes = append(changes, resourceChange{Type: kind, Name: name, Change: "changed", Fields: fields})
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// diffFields returns the fields whose values differ, sorted by name
+func diffFields(from, to map[string]string) []fieldChange {
+	keys := make(map[string]bool)
+	for key := range from {
+		keys[key] = true
+	}
+	for key := range to {
+		keys[key] = true
+	}
+	var fields []fieldChange
+	for key := range keys {
+		if from[key] != to[key] {
+			fields = append(fields, fieldChange{Field: key, From: from[key], To: to[key]})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return fields
+}
+
+// printDiff renders the changes grouped by resource type
+func printDiff(from, to *environment, changes []resourceChange, kinds map[string]bool) {
+	fmt.Printf("Comparing %s (%s) with %s (%s)\n", from.Profile, from.Endpoint, to.Profile, to.Endpoint)
+
+	value := func(v string) string {
+		if v == "" {
+			return "(unset)"
+		}
+		return v
+	}
+
+	counts := make(map[string]int)
+	for _, kind := range environmentKinds {
+		if !kinds[kind] {
+			continue
+		}
+		fmt.Println()
+
+		var ofKind []resourceChange
+		for _, change := range changes {
+			if change.Type == kind {
+				ofKind = append(ofKind, change)
+			}
+		}
+		if len(ofKind) == 0 {
+			fmt.Printf("%s: no differences\n", diffKindLabels[kind])
+			continue
+		}
+
+		fmt.Printf("%s:\n", diffKindLabels[kind])
+		for _, change := range ofKind {
+			counts[change.Change]++
+			switch change.Change {
+			case "added":
+				fmt.Printf("  + %s (only in %s)\n", change.Name, to.Profile)
+			case "removed":
+				fmt.Printf("  - %s (only in %s)\n", change.Name, from.Profile)
+			default:
+				fmt.Printf("  ~ %s\n", change.Name)
+				for _, field := range change.Fields {
+					fmt.Printf("      %s: %s → %s\n", field.Field, value(field.From), value(field.To))
+				}
+			}
+		}
+	}
+
+	fmt.Println()
+	if len(changes) == 0 {
+		fmt.Println("No differences")
+		return
+	}
+	fmt.Printf("%d difference(s): %d only in %s, %d only in %s, %d changed\n",
+		len(changes), counts["removed"], from.Profile, counts["added"], to.Profile, counts["changed"])
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff --from profile:<name> --to profile:<name>",
+	Short: "Compare the resources of two environments",
+	Long: `Compare the events, policies, service groups and services of two environments
+configured as profiles (see 'certfix profile').
+
+Both environments are exported the way 'certfix apply' reads them and matched
+by name. The diff lists resources that only exist on one side and, for those
+in both, each field whose value differs. Services are compared with their
+keys and relations. IDs, hashes and timestamps are ignored since they differ
+between environments anyway.`,
+	Example: `  certfix diff --from profile:prod --to profile:staging
+  certfix diff --from profile:prod --to profile:staging --only services,policies
+  certfix diff --from profile:prod --to profile:staging -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromSpec, _ := cmd.Flags().GetString("from")
+		toSpec, _ := cmd.Flags().GetString("to")
+		only, _ := cmd.Flags().GetString("only")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		if offline {
+			return fmt.Errorf("diff reads both environments from their APIs and does not support --offline")
+		}
+		kinds, err := parseEnvironmentKinds(only)
+		if err != nil {
+			return err
+		}
+
+		from, err := openEnvironment(fromSpec)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		to, err := openEnvironment(toSpec)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if from.Profile == to.Profile {
+			return fmt.Errorf("--from and --to are the same profile")
+		}
+
+		fromConfig, err := exportEnvironment(from.client, from.token, kinds, "")
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to export %s: %w", from.Profile, err)
+		}
+		toConfig, err := exportEnvironment(to.client, to.token, kinds, "")
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to export %s: %w", to.Profile, err)
+		}
+
+		changes := diffEnvironments(fromConfig, toConfig, kinds)
+
+		if outputFormat == "json" {
+			if changes == nil {
+				changes = []resourceChange{}
+			}
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"from":    from,
+				"to":      to,
+				"changes": changes,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		printDiff(from, to, changes, kinds)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	// Diff command flags
+	diffCmd.Flags().String("from", "", "Environment to compare from, as profile:<name> (required)")
+	diffCmd.Flags().String("to", "", "Environment to compare with, as profile:<name> (required)")
+	diffCmd.Flags().String("only", "", "Comma-separated resource types to compare (events, policies, service-groups, services)")
+	diffCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	diffCmd.MarkFlagRequired("from")
+	diffCmd.MarkFlagRequired("to")
+}
@@ -0,0 +1,166 @@
+package certfix
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// download is a file served by the API together with what the server says
+// about it: the hex SHA-256 checksum of the content and, optionally, a
+// detached signature over the content
+type download struct {
+	Content   []byte
+	Checksum  string
+	Signature []byte
+}
+
+// newDownload reads the checksum and signature fields of an API response
+// that carries content
+func newDownload(response map[string]interface{}, content []byte) (*download, error) {
+	d := &download{
+		Content:  content,
+		Checksum: strings.ToLower(firstField(response, "checksum_sha256", "sha256")),
+	}
+	if signature := stringField(response, "signature"); signature != "" {
+		decoded, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil {
+			return nil, fmt.Errorf("the server returned a malformed signature: %w", err)
+		}
+		d.Signature = decoded
+	}
+	return d, nil
+}
+
+// verify checks content, which is the download's own content or a local
+// copy of it, against the server's checksum and, when verifyKey names a
+// public key file, against the server's signature. It returns a warning when
+// the server provided no checksum to check.
+func (d *download) verify(content []byte, verifyKey string) (string, error) {
+	warning := ""
+	if d.Checksum == "" {
+		warning = "the server provided no checksum; the content was not verified"
+	} else {
+		sum := sha256.Sum256(content)
+		if actual := hex.EncodeToString(sum[:]); actual != d.Checksum {
+			return "", fmt.Errorf("CHECKSUM MISMATCH: expected SHA-256 %s, got %s; the content was altered or corrupted", d.Checksum, actual)
+		}
+	}
+
+	if verifyKey == "" {
+		return warning, nil
+	}
+	if len(d.Signature) == 0 {
+		return "", fmt.Errorf("--verify-key was given but the server provided no signature")
+	}
+	key, err := loadVerifyKey(verifyKey)
+	if err != nil {
+		return "", err
+	}
+	if err := verifySignature(key, content, d.Signature); err != nil {
+		return "", fmt.Errorf("SIGNATURE MISMATCH: %w", err)
+	}
+	return warning, nil
+}
+
+// loadVerifyKey reads a PEM public key, or the public key of a PEM
+// certificate, from path
+func loadVerifyKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verification key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the certificate in %s: %w", path, err)
+		}
+		return cert.PublicKey, nil
+	}
+	return nil, fmt.Errorf("unsupported PEM block '%s' in %s (expected a public key or certificate)", block.Type, path)
+}
+
+// verifySignature checks a detached signature over content: RSA PKCS #1 v1.5
+// or ECDSA over its SHA-256 hash, or Ed25519 over the content itself
+func verifySignature(key crypto.PublicKey, content, signature []byte) error {
+	digest := sha256.Sum256(content)
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("the signature does not match the content")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], signature) {
+			return fmt.Errorf("the signature does not match the content")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, content, signature) {
+			return fmt.Errorf("the signature does not match the content")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported verification key type %T", key)
+}
+
+// writeVerified verifies the download and only then writes it to path. The
+// file is written next to path and renamed into place, so a failed download
+// never leaves a partial file behind.
+func writeVerified(path string, d *download, verifyKey string, perm os.FileMode) (string, error) {
+	warning, err := d.verify(d.Content, verifyKey)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(d.Content); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return "", fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to write output file: %w", err)
+	}
+	return warning, nil
+}
+
+// verifyLocalFile checks an existing file against the download's checksum
+// and signature. The server's content must match too, when it sent any.
+func verifyLocalFile(path string, d *download, verifyKey string) (string, error) {
+	local, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if d.Checksum == "" && len(d.Content) > 0 && !bytes.Equal(local, d.Content) {
+		return "", fmt.Errorf("MISMATCH: %s differs from the server's copy", path)
+	}
+	return d.verify(local, verifyKey)
+}
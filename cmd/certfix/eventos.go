@@ -8,6 +8,7 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/certfix/certfix-cli/internal/api"
 	"github.com/certfix/certfix-cli/internal/auth"
 	"github.com/certfix/certfix-cli/internal/config"
 	"github.com/certfix/certfix-cli/pkg/client"
@@ -43,8 +44,7 @@ var eventosListCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Determine endpoint
 		var apiEndpoint string
@@ -56,7 +56,7 @@ var eventosListCmd = &cobra.Command{
 			apiEndpoint = "/events"
 		}
 
-		log.Debugf("GET %s%s", endpoint, apiEndpoint)
+		log.Debugf("GET %s%s", config.GetAPIEndpoint(), apiEndpoint)
 
 		// Make request
 		response, err := apiClient.GetWithAuth(apiEndpoint, token)
@@ -77,9 +77,12 @@ var eventosListCmd = &cobra.Command{
 			}
 		}
 
-		if len(eventos) == 0 {
-			fmt.Println("No events found.")
-			return nil
+		eventos, err = filterByTime(cmd, eventos)
+		if err != nil {
+			return err
+		}
+		if err := sortItems(cmd, eventSortColumns, eventos); err != nil {
+			return err
 		}
 
 		// Output format
@@ -89,35 +92,43 @@ var eventosListCmd = &cobra.Command{
 			return nil
 		}
 
-		// Table format
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tNAME\tEXTERNAL ID\tCOUNTER\tSEVERITY\tSTATUS\tCREATED AT")
-		fmt.Fprintln(w, "----\t----\t-----------\t-------\t--------\t------\t----------")
-
-		for _, evento := range eventos {
-			id := fmt.Sprintf("%v", evento["event_id"])
-			name := fmt.Sprintf("%v", evento["name"])
-			severity := strings.ToUpper(fmt.Sprintf("%v", evento["severity"]))
-			enabled := evento["enabled"].(bool)
-			status := "Inactive"
-			if enabled {
-				status = "Active"
-			}
-			createdAt := ""
-			if evento["created_at"] != nil {
-				if t, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", evento["created_at"])); err == nil {
-					createdAt = t.Format("2006-01-02 15:04")
-				}
-			}
-
-			fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\t%s\t%s\n", id, name, evento["external_id"], evento["counter"], severity, status, createdAt)
+		if len(eventos) == 0 {
+			fmt.Println("No events found.")
+			return nil
 		}
-		w.Flush()
 
+		printEventsTable(eventos)
 		return nil
 	},
 }
 
+// printEventsTable renders the events list table
+func printEventsTable(eventos []map[string]interface{}) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tEXTERNAL ID\tCOUNTER\tSEVERITY\tSTATUS\tCREATED AT")
+	fmt.Fprintln(w, "----\t----\t-----------\t-------\t--------\t------\t----------")
+
+	for _, evento := range eventos {
+		id := fmt.Sprintf("%v", evento["event_id"])
+		name := fmt.Sprintf("%v", evento["name"])
+		severity := strings.ToUpper(fmt.Sprintf("%v", evento["severity"]))
+		enabled := evento["enabled"].(bool)
+		status := "Inactive"
+		if enabled {
+			status = "Active"
+		}
+		createdAt := ""
+		if evento["created_at"] != nil {
+			if t, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", evento["created_at"])); err == nil {
+				createdAt = t.Format("2006-01-02 15:04")
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\t%s\t%s\n", id, name, stringOrNA(evento, "external_id"), evento["counter"], severity, status, createdAt)
+	}
+	w.Flush()
+}
+
 var eventosGetCmd = &cobra.Command{
 	Use:   "get <event-id>",
 	Short: "Get details of a specific event",
@@ -134,8 +145,7 @@ var eventosGetCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Make request
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/events/%s", eventoID), token)
@@ -224,8 +234,7 @@ var eventosCreateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -324,8 +333,14 @@ var eventosUpdateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
+
+		// Read the event first so the update carries its ETag and fails
+		// rather than overwrite a change made meanwhile
+		if _, err := apiClient.GetWithAuth(fmt.Sprintf("/events/%s", eventoID), token); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get event: %w", err)
+		}
 
 		log.Infof("Updating event: %s", eventoID)
 
@@ -350,11 +365,242 @@ var eventosUpdateCmd = &cobra.Command{
 	},
 }
 
+var eventosUpsertCmd = &cobra.Command{
+	Use:   "upsert",
+	Short: "Create or update an event by external ID",
+	Long: `Create an event with the given external ID, or update the existing one when an
+event with that external ID already exists. This lets monitoring integrations
+manage events by their own identifiers without tracking certfix event IDs.
+
+Creating an event requires --name and --severity; when updating, only the flags
+given are changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+
+		// Get flags
+		externalID, _ := cmd.Flags().GetString("external-id")
+		name, _ := cmd.Flags().GetString("name")
+		severity, _ := cmd.Flags().GetString("severity")
+		enabledValue, _ := cmd.Flags().GetBool("enabled")
+		resetUnit, _ := cmd.Flags().GetString("reset-unit")
+		resetValue, _ := cmd.Flags().GetInt("reset-value")
+
+		if severity != "" && !isValidSeverity(severity) {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid severity: %s (must be one of: low, medium, high, critical)", severity)
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		existing, err := findEventByExternalID(apiClient, token, externalID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		if existing == nil {
+			if name == "" || severity == "" {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("no event with external ID '%s' exists; --name and --severity are required to create it", externalID)
+			}
+
+			payload := map[string]interface{}{
+				"external_id":      externalID,
+				"name":             name,
+				"severity":         strings.ToLower(severity),
+				"enabled":          enabledValue,
+				"reset_time_unit":  resetUnit,
+				"reset_time_value": resetValue,
+			}
+
+			log.Infof("Creating event: %s (external ID %s)", name, externalID)
+
+			response, err := apiClient.PostWithAuth("/events", payload, token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to create event: %w", err)
+			}
+
+			fmt.Printf("✓ Event created successfully\n")
+			fmt.Printf("ID:          %v\n", response["event_id"])
+			fmt.Printf("External ID: %s\n", externalID)
+			return nil
+		}
+
+		// Only send the fields that differ from the existing event
+		eventoID := fmt.Sprintf("%v", existing["event_id"])
+		payload := make(map[string]interface{})
+		if name != "" && name != fmt.Sprintf("%v", existing["name"]) {
+			payload["name"] = name
+		}
+		if severity != "" && !strings.EqualFold(severity, fmt.Sprintf("%v", existing["severity"])) {
+			payload["severity"] = strings.ToLower(severity)
+		}
+		if current, _ := existing["enabled"].(bool); cmd.Flags().Changed("enabled") && current != enabledValue {
+			payload["enabled"] = enabledValue
+		}
+		if cmd.Flags().Changed("reset-unit") && resetUnit != fmt.Sprintf("%v", existing["reset_time_unit"]) {
+			payload["reset_time_unit"] = resetUnit
+		}
+		if cmd.Flags().Changed("reset-value") && resetValue != int(toFloat(existing["reset_time_value"])) {
+			payload["reset_time_value"] = resetValue
+		}
+
+		if len(payload) == 0 {
+			fmt.Printf("Event %s (external ID %s) is already up to date\n", eventoID, externalID)
+			return nil
+		}
+
+		log.Infof("Updating event: %s (external ID %s)", eventoID, externalID)
+
+		if _, err := apiClient.PutWithAuth(fmt.Sprintf("/events/%s", eventoID), payload, token); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to update event: %w", err)
+		}
+
+		fmt.Printf("✓ Event updated successfully\n")
+		fmt.Printf("ID:          %s\n", eventoID)
+		fmt.Printf("External ID: %s\n", externalID)
+		return nil
+	},
+}
+
+// eventOrSeverityArgs requires an event ID unless --severity selects events
+func eventOrSeverityArgs(cmd *cobra.Command, args []string) error {
+	if severity, _ := cmd.Flags().GetString("severity"); severity != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("an event ID cannot be combined with --severity")
+		}
+		return nil
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// setEventsEnabledBySeverity enables or disables every event of a severity,
+// after showing the events that would change and asking for confirmation
+func setEventsEnabledBySeverity(cmd *cobra.Command, severity string, enabled bool) error {
+	action, progress := "disable", "Disabling"
+	if enabled {
+		action, progress = "enable", "Enabling"
+	}
+
+	if !isValidSeverity(severity) {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("invalid severity: %s (must be one of: low, medium, high, critical)", severity)
+	}
+	severity = strings.ToLower(severity)
+
+	// Get authentication token
+	token, err := auth.GetToken()
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	// Create API client
+	apiClient := newAPIClient()
+
+	response, err := apiClient.GetWithAuth(fmt.Sprintf("/events/severity/%s", severity), token)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	var targets []map[string]interface{}
+	unchanged := 0
+	for _, evento := range parseArrayResponse(response) {
+		if !strings.EqualFold(fmt.Sprintf("%v", evento["severity"]), severity) {
+			continue
+		}
+		if current, _ := evento["enabled"].(bool); current == enabled {
+			unchanged++
+			continue
+		}
+		targets = append(targets, evento)
+	}
+
+	if len(targets) == 0 {
+		fmt.Printf("Nothing to do: no %s events need to be %sd (%d already %sd).\n", severity, action, unchanged, action)
+		return nil
+	}
+
+	fmt.Printf("The following %d %s event(s) will be %sd:\n", len(targets), severity, action)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "  ID\tNAME\tEXTERNAL ID\tCOUNTER")
+	fmt.Fprintln(w, "  --\t----\t-----------\t-------")
+	for _, evento := range targets {
+		fmt.Fprintf(w, "  %v\t%v\t%s\t%v\n", evento["event_id"], evento["name"], stringOrNA(evento, "external_id"), evento["counter"])
+	}
+	w.Flush()
+	if unchanged > 0 {
+		fmt.Printf("%d event(s) already %sd will be skipped.\n", unchanged, action)
+	}
+
+	// Confirm bulk change
+	confirmed, err := confirm(cmd, "\nProceed?")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Operation cancelled.")
+		return nil
+	}
+
+	var changed, failed []string
+	payload := map[string]interface{}{
+		"enabled": enabled,
+	}
+	for i, evento := range targets {
+		eventoID := fmt.Sprintf("%v", evento["event_id"])
+		fmt.Printf("[%d/%d] %s %s... ", i+1, len(targets), progress, eventoID)
+		if _, err := apiClient.PutWithAuth(fmt.Sprintf("/events/%s", eventoID), payload, token); err != nil {
+			fmt.Printf("Failed: %v\n", err)
+			failed = append(failed, eventoID)
+			continue
+		}
+		fmt.Printf("OK\n")
+		changed = append(changed, eventoID)
+	}
+
+	fmt.Printf("\nSummary: %d succeeded, %d failed (total %d)\n", len(changed), len(failed), len(targets))
+	if len(changed) > 0 {
+		fmt.Printf("Changed: %s\n", strings.Join(changed, ", "))
+	}
+	if len(failed) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s failed for: %s", action, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// isValidSeverity reports whether s is one of the event severity levels
+func isValidSeverity(s string) bool {
+	for _, v := range []string{"low", "medium", "high", "critical"} {
+		if strings.ToLower(s) == v {
+			return true
+		}
+	}
+	return false
+}
+
 var eventosEnableCmd = &cobra.Command{
 	Use:   "enable <event-id>",
 	Short: "Enable an event",
-	Args:  cobra.ExactArgs(1),
+	Long: `Enable an event by ID, or every event of a severity with --severity. The bulk
+form lists the events that would change and asks for confirmation first.`,
+	Args: eventOrSeverityArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if severity, _ := cmd.Flags().GetString("severity"); severity != "" {
+			return setEventsEnabledBySeverity(cmd, severity, true)
+		}
 		eventoID := args[0]
 
 		// Get authentication token
@@ -365,8 +611,7 @@ var eventosEnableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -388,8 +633,13 @@ var eventosEnableCmd = &cobra.Command{
 var eventosDisableCmd = &cobra.Command{
 	Use:   "disable <event-id>",
 	Short: "Disable an event",
-	Args:  cobra.ExactArgs(1),
+	Long: `Disable an event by ID, or every event of a severity with --severity. The bulk
+form lists the events that would change and asks for confirmation first.`,
+	Args: eventOrSeverityArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if severity, _ := cmd.Flags().GetString("severity"); severity != "" {
+			return setEventsEnabledBySeverity(cmd, severity, false)
+		}
 		eventoID := args[0]
 
 		// Get authentication token
@@ -400,8 +650,7 @@ var eventosDisableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -429,18 +678,6 @@ var eventosDeleteCmd = &cobra.Command{
 		log := logger.GetLogger()
 		eventoID := args[0]
 
-		// Confirm deletion
-		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Are you sure you want to delete event %s? (y/N): ", eventoID)
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
-		}
-
 		// Get authentication token
 		token, err := auth.GetToken()
 		if err != nil {
@@ -449,8 +686,32 @@ var eventosDeleteCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
+
+		if err := requirePermission(cmd, "event:delete"); err != nil {
+			return err
+		}
+
+		// Warn about policies and services that rely on the event
+		policies, services, err := eventUsage(apiClient, token, eventoID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if len(policies) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: event %s is used by %d policy(ies) and %d service(s).\n", eventoID, len(policies), len(services))
+			fmt.Fprintf(os.Stderr, "Run 'certfix events usage %s' for details.\n", eventoID)
+		}
+
+		// Confirm deletion
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete event %s?", eventoID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
+		}
 
 		log.Infof("Deleting event: %s", eventoID)
 
@@ -466,6 +727,335 @@ var eventosDeleteCmd = &cobra.Command{
 	},
 }
 
+var eventosResetCounterCmd = &cobra.Command{
+	Use:   "reset-counter <event-id>",
+	Short: "Reset an event counter to zero",
+	Long: `Reset the counter of an event to zero. Use this after handling an incident so
+that a partly filled counter does not trigger a rotation at an unplanned moment.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+		eventoID := args[0]
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		evento, err := apiClient.GetWithAuth(fmt.Sprintf("/events/%s", eventoID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get event: %w", err)
+		}
+
+		counter := int(toFloat(evento["counter"]))
+		if counter == 0 {
+			fmt.Printf("Counter of event %v is already 0\n", evento["name"])
+			return nil
+		}
+
+		// Confirm reset
+		confirmed, err := confirm(cmd, fmt.Sprintf("Reset the counter of event %v from %d to 0?", evento["name"], counter))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Reset cancelled.")
+			return nil
+		}
+
+		log.Infof("Resetting counter of event: %s", eventoID)
+
+		// Make request
+		_, err = apiClient.PostWithAuth(fmt.Sprintf("/events/%s/reset-counter", eventoID), map[string]interface{}{}, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to reset event counter: %w", err)
+		}
+
+		fmt.Printf("✓ Event counter reset successfully (was %d)\n", counter)
+		return nil
+	},
+}
+
+// readEventPayload reads the JSON object attached to fired or sent
+// occurrences. An empty path means no payload.
+func readEventPayload(path string) (map[string]interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload file: %w", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("payload file must contain a JSON object: %w", err)
+	}
+	return payload, nil
+}
+
+var eventosFireCmd = &cobra.Command{
+	Use:   "fire <event-id>",
+	Short: "Post synthetic occurrences of an event",
+	Long: `Post one or more synthetic occurrences of an event, as a monitoring system
+would. Use this to verify that a policy with the Events strategy triggers a
+rotation once its threshold is reached before relying on it in production.
+
+After firing, the event counter and the progress of every policy that uses the
+event are shown.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+		eventoID := args[0]
+		count, _ := cmd.Flags().GetInt("count")
+		payloadFile, _ := cmd.Flags().GetString("payload")
+
+		if count < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+
+		// Optional payload attached to every occurrence
+		eventPayload, err := readEventPayload(payloadFile)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		evento, err := apiClient.GetWithAuth(fmt.Sprintf("/events/%s", eventoID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get event: %w", err)
+		}
+		if enabled, _ := evento["enabled"].(bool); !enabled {
+			fmt.Fprintf(os.Stderr, "Warning: event %v is inactive; occurrences may be ignored\n", evento["name"])
+		}
+
+		payload := map[string]interface{}{
+			"source": "certfix-cli",
+		}
+		if eventPayload != nil {
+			payload["payload"] = eventPayload
+		}
+
+		log.Infof("Firing event %s %d time(s)", eventoID, count)
+
+		fired := 0
+		for i := 1; i <= count; i++ {
+			fmt.Printf("[%d/%d] Firing %v... ", i, count, evento["name"])
+			response, err := apiClient.PostWithAuth(fmt.Sprintf("/events/%s/occurrences", eventoID), payload, token)
+			if err != nil {
+				fmt.Printf("Failed: %v\n", err)
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to fire event after %d occurrence(s): %w", fired, err)
+			}
+			fired++
+			if counter, ok := response["counter"]; ok {
+				fmt.Printf("OK (counter %v)\n", counter)
+			} else {
+				fmt.Printf("OK\n")
+			}
+		}
+
+		fmt.Printf("\n✓ Fired %d occurrence(s) of event %v\n", fired, evento["name"])
+
+		// Show where the event stands against the policies that use it
+		evento, err = apiClient.GetWithAuth(fmt.Sprintf("/events/%s", eventoID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get event: %w", err)
+		}
+		counter := int(toFloat(evento["counter"]))
+		fmt.Printf("Counter:  %d\n", counter)
+
+		policies, err := eventPolicies(apiClient, token, eventoID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if len(policies) == 0 {
+			fmt.Println("No policies use this event.")
+			return nil
+		}
+
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "POLICY\tSTATUS\tPROGRESS\tRESULT")
+		fmt.Fprintln(w, "------\t------\t--------\t------")
+		for _, policy := range policies {
+			eventConfig := policy["event_config"].(map[string]interface{})
+			total := int(toFloat(eventConfig["total_events"]))
+
+			status := "Inactive"
+			result := "policy disabled, no rotation"
+			if enabled, _ := policy["enabled"].(bool); enabled {
+				status = "Active"
+				if remaining := total - counter; remaining > 0 {
+					result = fmt.Sprintf("%d more occurrence(s) needed", remaining)
+				} else {
+					result = "threshold reached, rotation should trigger"
+				}
+			}
+			fmt.Fprintf(w, "%v\t%s\t%d/%d\t%s\n", policy["name"], status, counter, total, result)
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var eventosUsageCmd = &cobra.Command{
+	Use:   "usage <event-id>",
+	Short: "Show the policies and services that depend on an event",
+	Long: `Show the policies whose event configuration references an event and the
+services attached to those policies. Run this before 'certfix events delete' to
+see what would stop rotating.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		eventoID := args[0]
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		evento, err := apiClient.GetWithAuth(fmt.Sprintf("/events/%s", eventoID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get event: %w", err)
+		}
+
+		policies, services, err := eventUsage(apiClient, token, eventoID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"event_id": eventoID,
+				"policies": policies,
+				"services": services,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Event: %v (%s)\n\n", evento["name"], eventoID)
+
+		if len(policies) == 0 {
+			fmt.Println("No policies use this event; it can be deleted safely.")
+			return nil
+		}
+
+		fmt.Println("Policies:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "  ID\tNAME\tTHRESHOLD\tSTATUS")
+		fmt.Fprintln(w, "  --\t----\t---------\t------")
+		for _, policy := range policies {
+			eventConfig := policy["event_config"].(map[string]interface{})
+			status := "Inactive"
+			if enabled, _ := policy["enabled"].(bool); enabled {
+				status = "Active"
+			}
+			fmt.Fprintf(w, "  %v\t%v\t%v\t%s\n", policy["policy_id"], policy["name"], eventConfig["total_events"], status)
+		}
+		w.Flush()
+
+		fmt.Println("\nServices:")
+		if len(services) == 0 {
+			fmt.Println("  No services are attached to these policies.")
+		} else {
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "  HASH\tNAME\tPOLICY")
+			fmt.Fprintln(w, "  ----\t----\t------")
+			for _, svc := range services {
+				fmt.Fprintf(w, "  %v\t%v\t%s\n", svc["service_hash"], svc["service_name"], stringOrNA(svc, "policy_name"))
+			}
+			w.Flush()
+		}
+
+		fmt.Printf("\nDeleting this event affects %d policy(ies) and %d service(s).\n", len(policies), len(services))
+		return nil
+	},
+}
+
+// eventPolicies returns the policies whose event configuration references the
+// given event
+func eventPolicies(apiClient client.APIClient, token, eventoID string) ([]map[string]interface{}, error) {
+	response, err := apiClient.GetWithAuth("/policies", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	var policies []map[string]interface{}
+	for _, policy := range parseArrayResponse(response) {
+		if eventConfig, ok := policy["event_config"].(map[string]interface{}); ok && fmt.Sprintf("%v", eventConfig["event_id"]) == eventoID {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+// eventUsage returns the policies that reference an event and the services
+// attached to any of those policies
+func eventUsage(apiClient client.APIClient, token, eventoID string) ([]map[string]interface{}, []map[string]interface{}, error) {
+	policies, err := eventPolicies(apiClient, token, eventoID)
+	if err != nil || len(policies) == 0 {
+		return policies, nil, err
+	}
+
+	policyIDs := make(map[string]bool)
+	for _, policy := range policies {
+		policyIDs[fmt.Sprintf("%v", policy["policy_id"])] = true
+	}
+
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	var services []map[string]interface{}
+	for _, svc := range parseArrayResponse(response) {
+		if policyIDs[stringOrNA(svc, "policy_id")] {
+			services = append(services, svc)
+		}
+	}
+	return policies, services, nil
+}
+
+// eventSortColumns are the --sort-by columns of 'events list'
+var eventSortColumns = sortColumns{
+	"id":          "event_id",
+	"name":        "name",
+	"external_id": "external_id",
+	"counter":     "counter",
+	"severity":    "severity",
+	"status":      "enabled",
+	"created_at":  "created_at",
+}
+
 func init() {
 	rootCmd.AddCommand(eventosCmd)
 
@@ -477,11 +1067,17 @@ func init() {
 	eventosCmd.AddCommand(eventosEnableCmd)
 	eventosCmd.AddCommand(eventosDisableCmd)
 	eventosCmd.AddCommand(eventosDeleteCmd)
+	eventosCmd.AddCommand(eventosFireCmd)
+	eventosCmd.AddCommand(eventosResetCounterCmd)
+	eventosCmd.AddCommand(eventosUsageCmd)
+	eventosCmd.AddCommand(eventosUpsertCmd)
 
 	// List command flags
 	eventosListCmd.Flags().StringP("severity", "s", "", "Filter by severity (low, medium, high, critical)")
 	eventosListCmd.Flags().BoolP("enabled", "e", false, "Show only enabled events")
 	eventosListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	enableSorting(eventSortColumns, eventosListCmd)
+	enableTimeFilters(false, eventosListCmd)
 
 	// Get command flags
 	eventosGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
@@ -502,6 +1098,36 @@ func init() {
 	eventosUpdateCmd.Flags().String("reset-unit", "", "New reset unit: minutes, hours, days")
 	eventosUpdateCmd.Flags().Int("reset-value", 0, "New reset counter value")
 
+	// Enable/disable command flags
+	for _, c := range []*cobra.Command{eventosEnableCmd, eventosDisableCmd} {
+		c.Flags().StringP("severity", "s", "", "Act on all events with this severity instead of a single event")
+		c.Flags().BoolP("force", "f", false, "Skip confirmation for --severity")
+	}
+
+	// Upsert command flags
+	eventosUpsertCmd.Flags().String("external-id", "", "External ID of the event (required)")
+	eventosUpsertCmd.Flags().StringP("name", "n", "", "Name of the event (required when creating)")
+	eventosUpsertCmd.Flags().StringP("severity", "s", "", "Severity level: low, medium, high, critical (required when creating)")
+	eventosUpsertCmd.Flags().BoolP("enabled", "e", true, "Enable the event (default: true)")
+	eventosUpsertCmd.Flags().String("reset-unit", "hours", "Reset unit: minutes, hours, days")
+	eventosUpsertCmd.Flags().Int("reset-value", 0, "Reset counter if no events within this value (0 = never)")
+	eventosUpsertCmd.MarkFlagRequired("external-id")
+
 	// Delete command flags
 	eventosDeleteCmd.Flags().BoolP("force", "f", false, "Force deletion without confirmation")
+
+	// Usage command flags
+	eventosUsageCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Reset counter command flags
+	eventosResetCounterCmd.Flags().BoolP("force", "f", false, "Reset without confirmation")
+
+	// Fire command flags
+	eventosFireCmd.Flags().IntP("count", "c", 1, "Number of occurrences to post")
+	eventosFireCmd.Flags().StringP("payload", "p", "", "JSON file with a payload to attach to each occurrence")
+
+	// Allow selecting the event by external ID
+	enableIdentifierResolver(api.KindEvent, eventosGetCmd, eventosUsageCmd, eventosHistoryCmd)
+	enableExactIdentifierResolver(api.KindEvent, eventosUpdateCmd, eventosEnableCmd, eventosDisableCmd, eventosDeleteCmd, eventosResetCounterCmd, eventosFireCmd)
+	enableEventByExternalID(eventosGetCmd, eventosUpdateCmd, eventosEnableCmd, eventosDisableCmd)
 }
@@ -0,0 +1,266 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+var eventosHistoryCmd = &cobra.Command{
+	Use:   "history <event-id>",
+	Short: "Show recent occurrences of an event",
+	Long: `Show the occurrences of an event within a time window, with the time, the
+source that reported it and the counter value after it was recorded.
+
+--since accepts durations such as 30m, 24h, 7d or 2w, or a date such as
+2026-01-01.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		eventoID := args[0]
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		since, err := parseTimeBound(sinceFlag, false)
+		if err != nil {
+			return err
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		occurrences, err := fetchOccurrences(apiClient, token, eventoID, since)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(occurrences, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(occurrences) == 0 {
+			fmt.Printf("No occurrences in the last %s.\n", sinceFlag)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "TIMESTAMP\tSOURCE\tCOUNTER")
+		fmt.Fprintln(w, "---------\t------\t-------")
+		for _, occ := range occurrences {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", formatTimestamp(occurrenceTime(occ), "2006-01-02 15:04:05", "N/A"), stringOrNA(occ, "source"), occurrenceCounter(occ))
+		}
+		w.Flush()
+
+		fmt.Printf("\n%d occurrence(s) in the last %s\n", len(occurrences), sinceFlag)
+		return nil
+	},
+}
+
+var eventosTailCmd = &cobra.Command{
+	Use:   "tail [event-id]",
+	Short: "Show the latest event occurrences, optionally following new ones",
+	Long: `Show the latest occurrences of an event, or of all events when no event ID is
+given. With --follow the command keeps polling and prints new occurrences as
+they are recorded until interrupted, so operators can watch event-driven
+rotation conditions build up.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		follow, _ := cmd.Flags().GetBool("follow")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		lines, _ := cmd.Flags().GetInt("lines")
+
+		if follow && interval < time.Second {
+			return fmt.Errorf("poll interval must be at least 1s")
+		}
+
+		eventoID := ""
+		if len(args) == 1 {
+			eventoID = args[0]
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		// Event names for the all-events view
+		names := make(map[string]string)
+		if eventoID == "" {
+			response, err := apiClient.GetWithAuth("/events", token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to list events: %w", err)
+			}
+			for _, evento := range parseArrayResponse(response) {
+				names[fmt.Sprintf("%v", evento["event_id"])] = fmt.Sprintf("%v", evento["name"])
+			}
+		}
+
+		printOccurrence := func(occ map[string]interface{}) {
+			timestamp := formatTimestamp(occurrenceTime(occ), "2006-01-02 15:04:05", "N/A")
+			if eventoID == "" {
+				name := stringOrNA(occ, "event_name")
+				if n, ok := names[stringOrNA(occ, "event_id")]; ok && name == "N/A" {
+					name = n
+				}
+				fmt.Printf("%s  %-20s  source=%s  counter=%s\n", timestamp, name, stringOrNA(occ, "source"), occurrenceCounter(occ))
+			} else {
+				fmt.Printf("%s  source=%s  counter=%s\n", timestamp, stringOrNA(occ, "source"), occurrenceCounter(occ))
+			}
+		}
+
+		occurrences, err := fetchOccurrences(apiClient, token, eventoID, time.Time{})
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if len(occurrences) > lines {
+			occurrences = occurrences[len(occurrences)-lines:]
+		}
+
+		seen := make(map[string]bool)
+		last := time.Time{}
+		for _, occ := range occurrences {
+			printOccurrence(occ)
+			seen[occurrenceKey(occ)] = true
+			if t := occurrenceTimestamp(occ); t.After(last) {
+				last = t
+			}
+		}
+
+		if !follow {
+			return nil
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(interrupt)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-interrupt:
+				return nil
+			case <-ticker.C:
+			}
+
+			occurrences, err := fetchOccurrences(apiClient, token, eventoID, last)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				continue
+			}
+			for _, occ := range occurrences {
+				key := occurrenceKey(occ)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				printOccurrence(occ)
+				if t := occurrenceTimestamp(occ); t.After(last) {
+					last = t
+				}
+			}
+		}
+	},
+}
+
+// fetchOccurrences returns the occurrences of an event (or of all events when
+// eventoID is empty) recorded at or after since, oldest first. A zero since
+// fetches everything the API returns.
+func fetchOccurrences(apiClient client.APIClient, token, eventoID string, since time.Time) ([]map[string]interface{}, error) {
+	apiEndpoint := "/events/occurrences"
+	if eventoID != "" {
+		apiEndpoint = fmt.Sprintf("/events/%s/occurrences", eventoID)
+	}
+	if !since.IsZero() {
+		apiEndpoint += "?since=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+	}
+
+	response, err := apiClient.GetWithAuth(apiEndpoint, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event occurrences: %w", err)
+	}
+
+	var occurrences []map[string]interface{}
+	for _, occ := range parseArrayResponse(response) {
+		if !since.IsZero() && occurrenceTimestamp(occ).Before(since) {
+			continue
+		}
+		occurrences = append(occurrences, occ)
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrenceTimestamp(occurrences[i]).Before(occurrenceTimestamp(occurrences[j]))
+	})
+	return occurrences, nil
+}
+
+// occurrenceTime returns the raw timestamp of an occurrence
+func occurrenceTime(occ map[string]interface{}) interface{} {
+	if occ["occurred_at"] != nil {
+		return occ["occurred_at"]
+	}
+	return occ["created_at"]
+}
+
+// occurrenceTimestamp returns the parsed timestamp of an occurrence, or the
+// zero time when it is missing
+func occurrenceTimestamp(occ map[string]interface{}) time.Time {
+	t, _ := time.Parse(time.RFC3339, fmt.Sprintf("%v", occurrenceTime(occ)))
+	return t
+}
+
+// occurrenceCounter returns the counter value recorded with an occurrence
+func occurrenceCounter(occ map[string]interface{}) string {
+	if v := stringOrNA(occ, "counter_value"); v != "N/A" {
+		return v
+	}
+	return stringOrNA(occ, "counter")
+}
+
+// occurrenceKey identifies an occurrence so polling does not print it twice
+func occurrenceKey(occ map[string]interface{}) string {
+	if id := stringOrNA(occ, "occurrence_id"); id != "N/A" {
+		return id
+	}
+	return fmt.Sprintf("%v|%v|%v|%v", occ["event_id"], occurrenceTime(occ), occ["source"], occurrenceCounter(occ))
+}
+
+func init() {
+	eventosCmd.AddCommand(eventosHistoryCmd)
+	eventosCmd.AddCommand(eventosTailCmd)
+
+	// History command flags
+	eventosHistoryCmd.Flags().String("since", "24h", "How far back to look, as a duration or a date (e.g. 30m, 24h, 7d, 2026-01-01)")
+	eventosHistoryCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Tail command flags
+	eventosTailCmd.Flags().BoolP("follow", "f", false, "Keep polling and print new occurrences")
+	eventosTailCmd.Flags().Duration("interval", 5*time.Second, "Poll interval when following")
+	eventosTailCmd.Flags().IntP("lines", "n", 10, "Number of recent occurrences to show first")
+}
@@ -0,0 +1,120 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// eventIngestPath is the public endpoint external systems post events to
+	eventIngestPath = "/external/events"
+
+	// integrationKeyHeader carries the integration key on ingestion requests
+	integrationKeyHeader = "X-Integration-Key"
+
+	// integrationKeyEnv is read when --integration-key is not given
+	integrationKeyEnv = "CERTFIX_INTEGRATION_KEY"
+)
+
+// resolveIntegrationKey returns the key given on the command line, reading it
+// from an environment variable when the value is "env:NAME", and from
+// CERTFIX_INTEGRATION_KEY when no value is given.
+func resolveIntegrationKey(value string) (string, error) {
+	source := "--integration-key"
+	switch {
+	case value == "":
+		source = integrationKeyEnv
+		value = os.Getenv(integrationKeyEnv)
+	case strings.HasPrefix(value, "env:"):
+		source = strings.TrimPrefix(value, "env:")
+		value = os.Getenv(source)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("no integration key found in %s (use --integration-key <key> or env:<VAR>)", source)
+	}
+	return value, nil
+}
+
+var eventosSendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Send an external event using an integration key",
+	Long: `Post occurrences of an event to the public ingestion endpoint, authenticated
+with an integration key instead of your login session, exactly as a monitoring
+system would. Use it to test the full external pipeline end to end.
+
+The key can be passed directly, as env:<VAR> to read it from an environment
+variable, or omitted to use CERTFIX_INTEGRATION_KEY.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+		keyValue, _ := cmd.Flags().GetString("integration-key")
+		externalID, _ := cmd.Flags().GetString("external-id")
+		count, _ := cmd.Flags().GetInt("count")
+		payloadFile, _ := cmd.Flags().GetString("payload")
+
+		if count < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+
+		integrationKey, err := resolveIntegrationKey(keyValue)
+		if err != nil {
+			return err
+		}
+
+		eventPayload, err := readEventPayload(payloadFile)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		payload := map[string]interface{}{
+			"external_id": externalID,
+			"source":      "certfix-cli",
+		}
+		if eventPayload != nil {
+			payload["payload"] = eventPayload
+		}
+		headers := map[string]string{integrationKeyHeader: integrationKey}
+
+		log.Infof("Sending event %s %d time(s)", externalID, count)
+
+		sent := 0
+		for i := 1; i <= count; i++ {
+			fmt.Printf("[%d/%d] Sending %s... ", i, count, externalID)
+			response, err := apiClient.PostWithHeaders(eventIngestPath, payload, headers)
+			if err != nil {
+				fmt.Printf("Failed: %v\n", err)
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to send event after %d occurrence(s): %w", sent, err)
+			}
+			sent++
+			if counter, ok := response["counter"]; ok {
+				fmt.Printf("OK (counter %v)\n", counter)
+			} else {
+				fmt.Printf("OK\n")
+			}
+		}
+
+		fmt.Printf("\n✓ Sent %d occurrence(s) of event %s\n", sent, externalID)
+		return nil
+	},
+}
+
+func init() {
+	eventosCmd.AddCommand(eventosSendCmd)
+
+	// Send command flags
+	eventosSendCmd.Flags().String("integration-key", "", "Integration key, or env:<VAR> to read it from an environment variable (default: $"+integrationKeyEnv+")")
+	eventosSendCmd.Flags().String("external-id", "", "External ID of the event to send (required)")
+	eventosSendCmd.Flags().IntP("count", "c", 1, "Number of occurrences to send")
+	eventosSendCmd.Flags().StringP("payload", "p", "", "JSON file attached to every occurrence")
+	eventosSendCmd.MarkFlagRequired("external-id")
+}
@@ -0,0 +1,267 @@
+package certfix
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/models"
+)
+
+// environmentKinds are the resource types exported from an environment, in
+// the order they depend on each other
+var environmentKinds = []string{"events", "policies", "service-groups", "services"}
+
+// environmentKindAliases are the other accepted spellings in --only
+var environmentKindAliases = map[string]string{
+	"event":          "events",
+	"eventos":        "events",
+	"policy":         "policies",
+	"service-group":  "service-groups",
+	"service_groups": "service-groups",
+	"groups":         "service-groups",
+	"service":        "services",
+	"svc":            "services",
+}
+
+// parseEnvironmentKinds parses a comma-separated --only value into a set of
+// environmentKinds. An empty value selects all of them.
+func parseEnvironmentKinds(only string) (map[string]bool, error) {
+	kinds := make(map[string]bool)
+	if strings.TrimSpace(only) == "" {
+		for _, kind := range environmentKinds {
+			kinds[kind] = true
+		}
+		return kinds, nil
+	}
+	for _, value := range strings.Split(only, ",") {
+		kind := strings.ToLower(strings.TrimSpace(value))
+		if alias, ok := environmentKindAliases[kind]; ok {
+			kind = alias
+		}
+		valid := false
+		for _, k := range environmentKinds {
+			if kind == k {
+				valid = true
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid resource type '%s' (must be one of: %s)", value, strings.Join(environmentKinds, ", "))
+		}
+		kinds[kind] = true
+	}
+	return kinds, nil
+}
+
+// exportConcurrency limits parallel per-service requests during an export
+const exportConcurrency = 8
+
+// exportEnvironment reads the resources of the selected kinds into the shape
+// of an apply file. Services come with their keys and relations; when group
+// is set only the services of that service group are exported.
+func exportEnvironment(apiClient client.APIClient, token string, kinds map[string]bool, group string) (*models.CertfixConfig, error) {
+	cfg := &models.CertfixConfig{}
+
+	list := func(kind, path string) ([]map[string]interface{}, error) {
+		response, err := apiClient.GetWithAuth(path, token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", kind, err)
+		}
+		return parseArrayResponse(response), nil
+	}
+
+	if kinds["events"] {
+		events, err := list("events", "/events")
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			cfg.Events = append(cfg.Events, models.EventConfig{
+				Name:     stringField(e, "name"),
+				Severity: stringField(e, "severity"),
+				Enabled:  boolField(e, "enabled"),
+			})
+		}
+	}
+
+	if kinds["policies"] {
+		policies, err := list("policies", "/policies")
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range policies {
+			cfg.Policies = append(cfg.Policies, exportPolicy(p))
+		}
+	}
+
+	if kinds["service-groups"] {
+		groups, err := list("service groups", "/service-groups")
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			cfg.ServiceGroups = append(cfg.ServiceGroups, models.ServiceGroupConfig{
+				Name:        stringField(g, "name"),
+				Description: stringField(g, "description"),
+				Enabled:     boolField(g, "enabled"),
+			})
+		}
+	}
+
+	if kinds["services"] {
+		services, err := list("services", "/services")
+		if err != nil {
+			return nil, err
+		}
+		if group != "" {
+			var inGroup []map[string]interface{}
+			for _, svc := range services {
+				if stringField(svc, "service_group_name") == group {
+					inGroup = append(inGroup, svc)
+				}
+			}
+			services = inGroup
+		}
+		if cfg.Services, err = exportServices(apiClient, token, services); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// exportPolicy converts a policy as returned by the API into its apply form
+func exportPolicy(p map[string]interface{}) models.PolicyConfig {
+	policy := models.PolicyConfig{
+		Name:     stringField(p, "name"),
+		Strategy: stringField(p, "strategy"),
+		Enabled:  boolField(p, "enabled"),
+	}
+	if cronConfig, ok := p["cron_config"].(map[string]interface{}); ok && len(cronConfig) > 0 {
+		policy.CronConfig = make(map[string]string, len(cronConfig))
+		for key, value := range cronConfig {
+			if value != nil {
+				policy.CronConfig[key] = fmt.Sprintf("%v", value)
+			}
+		}
+	}
+	if eventConfig, ok := p["event_config"].(map[string]interface{}); ok && len(eventConfig) > 0 {
+		policy.EventConfig = eventConfig
+	}
+	if gradual, ok := p["gradual_config"].(map[string]interface{}); ok && len(gradual) > 0 {
+		policy.GradualConfig = &models.GradualConfig{BatchSize: int(toFloat(gradual["batch_size"]))}
+		if seconds := toFloat(gradual["interval_seconds"]); seconds > 0 {
+			policy.GradualConfig.Interval = (time.Duration(seconds) * time.Second).String()
+		}
+	}
+	return policy
+}
+
+// exportServices converts services as returned by the API into their apply
+// form, fetching the keys and relations of each
+func exportServices(apiClient client.APIClient, token string, services []map[string]interface{}) ([]models.ServiceConfig, error) {
+	var (
+		exported = make([]models.ServiceConfig, len(services))
+		errs     = make([]error, len(services))
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, exportConcurrency)
+	)
+	for i, svc := range services {
+		service := models.ServiceConfig{
+			Hash:          stringField(svc, "service_hash"),
+			Name:          stringField(svc, "service_name"),
+			Active:        boolField(svc, "active"),
+			WebhookURL:    stringField(svc, "webhook_url"),
+			GroupName:     stringField(svc, "service_group_name"),
+			PolicyName:    stringField(svc, "policy_name"),
+			ReloadService: stringField(svc, "reload_service"),
+		}
+		if labels := serviceLabels(svc); len(labels) > 0 {
+			service.Labels = labels
+		}
+		if names, ok := svc["dns_names"].([]interface{}); ok {
+			for _, name := range names {
+				service.DNSNames = append(service.DNSNames, fmt.Sprintf("%v", name))
+			}
+		}
+		exported[i] = service
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			hash := exported[i].Hash
+			response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys/list", hash), token)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to list keys of %s: %w", hash, err)
+				return
+			}
+			for _, key := range parseArrayResponse(response) {
+				exported[i].Keys = append(exported[i].Keys, models.ServiceKeyConfig{
+					Name:           stringField(key, "key_name"),
+					Enabled:        boolField(key, "enabled"),
+					ExpirationDays: keyExpirationDays(key),
+				})
+			}
+
+			response, err = apiClient.GetWithAuth(fmt.Sprintf("/services/%s/matrix/relations", hash), token)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to list relations of %s: %w", hash, err)
+				return
+			}
+			for _, rel := range parseArrayResponse(response) {
+				exported[i].Relations = append(exported[i].Relations, models.ServiceRelationConfig{
+					TargetHash:  relatedHash(rel),
+					Type:        stringField(rel, "relation_type"),
+					Description: stringField(rel, "description"),
+					Direction:   stringField(rel, "direction"),
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return exported, nil
+}
+
+// neverExpiresDays is the expiration given to exported keys without one,
+// as suggested for apply files
+const neverExpiresDays = 36500
+
+// keyExpirationDays returns the days left until a key expires, rounded up and
+// at least 1, in the form apply files use
+func keyExpirationDays(key map[string]interface{}) int {
+	expiresAt, err := time.Parse(time.RFC3339, stringField(key, "expires_at"))
+	if err != nil {
+		return neverExpiresDays
+	}
+	days := int(math.Ceil(time.Until(expiresAt).Hours() / 24))
+	if days < 1 {
+		days = 1
+	}
+	return days
+}
+
+// stringField returns a field as a string, or "" when it is missing or null
+func stringField(m map[string]interface{}, key string) string {
+	if v, ok := m[key]; ok && v != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return ""
+}
+
+// boolField returns a boolean field, false when it is missing
+func boolField(m map[string]interface{}, key string) bool {
+	v, _ := m[key].(bool)
+	return v
+}
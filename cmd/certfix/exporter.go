@@ -0,0 +1,334 @@
+package certfix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+// metricFamily is one Prometheus metric and its samples
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string
+	samples []metricSample
+}
+
+// metricSample is a single labelled value of a metric
+type metricSample struct {
+	labels map[string]string
+	value  float64
+}
+
+// add appends a sample with labels given as name/value pairs
+func (f *metricFamily) add(value float64, labels ...string) {
+	sample := metricSample{labels: make(map[string]string), value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		sample.labels[labels[i]] = labels[i+1]
+	}
+	f.samples = append(f.samples, sample)
+}
+
+// writeMetrics renders metric families in the Prometheus text exposition format
+func writeMetrics(b *strings.Builder, families []*metricFamily) {
+	for _, f := range families {
+		fmt.Fprintf(b, "# HELP %s %s\n", f.name, f.help)
+		fmt.Fprintf(b, "# TYPE %s %s\n", f.name, f.kind)
+		for _, s := range f.samples {
+			b.WriteString(f.name)
+			if len(s.labels) > 0 {
+				names := make([]string, 0, len(s.labels))
+				for name := range s.labels {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				pairs := make([]string, len(names))
+				for i, name := range names {
+					pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(s.labels[name]))
+				}
+				b.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			fmt.Fprintf(b, " %g\n", s.value)
+		}
+	}
+}
+
+// labelEscaper escapes label values as the text exposition format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// exporterCollector gathers CertFix state for the exporter
+type exporterCollector struct {
+	apiClient client.APIClient
+	days      int
+}
+
+// collect scrapes the API and returns the metric families. Each source is
+// collected independently so one failing endpoint does not hide the others;
+// certfix_collector_success reports which ones worked.
+func (c *exporterCollector) collect() []*metricFamily {
+	log := logger.GetLogger()
+
+	success := &metricFamily{name: "certfix_collector_success", help: "Whether the last collection of a source succeeded.", kind: "gauge"}
+	duration := &metricFamily{name: "certfix_scrape_duration_seconds", help: "Time taken to collect all metrics from the API.", kind: "gauge"}
+	certExpiry := &metricFamily{name: "certfix_certificate_expiry_timestamp_seconds", help: "Expiration time of valid certificates as a Unix timestamp.", kind: "gauge"}
+	keyExpiry := &metricFamily{name: "certfix_service_key_expiry_timestamp_seconds", help: "Expiration time of enabled service keys as a Unix timestamp.", kind: "gauge"}
+	serviceActive := &metricFamily{name: "certfix_service_active", help: "Whether a service is active (1) or inactive (0).", kind: "gauge"}
+	instances := &metricFamily{name: "certfix_instances", help: "Number of registered instances by status.", kind: "gauge"}
+
+	start := time.Now()
+	report := func(source string, err error) {
+		if err != nil {
+			log.Warnf("Failed to collect %s: %v", source, err)
+			success.add(0, "collector", source)
+			return
+		}
+		success.add(1, "collector", source)
+	}
+
+	token, err := auth.GetToken()
+	if err != nil {
+		for _, source := range []string{"certificates", "services", "keys", "instances"} {
+			report(source, err)
+		}
+		duration.add(time.Since(start).Seconds())
+		return []*metricFamily{success, duration}
+	}
+
+	// Certificates
+	certs, err := newAPI().ListValidCertificates()
+	if err == nil {
+		for _, cert := range certs {
+			expiresAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", cert["expires_at"]))
+			if err != nil {
+				continue
+			}
+			certExpiry.add(float64(expiresAt.Unix()),
+				"common_name", stringOrNA(cert, "common_name"),
+				"serial_number", stringOrNA(cert, "serial_number"),
+				"type", stringOrNA(cert, "certificate_type"))
+		}
+	}
+	report("certificates", err)
+
+	// Services and their keys
+	response, err := c.apiClient.GetWithAuth("/services", token)
+	if err == nil {
+		services := parseArrayResponse(response)
+		for _, svc := range services {
+			active := 0.0
+			if isActive, _ := svc["active"].(bool); isActive {
+				active = 1
+			}
+			serviceActive.add(active,
+				"service_hash", stringOrNA(svc, "service_hash"),
+				"service_name", stringOrNA(svc, "service_name"))
+		}
+		report("services", nil)
+
+		cutoff := time.Now().AddDate(0, 0, c.days)
+		keys, failures := expiringKeys(c.apiClient, token, services, cutoff, false)
+		for _, key := range keys {
+			expiresAt, _ := time.Parse(time.RFC3339, fmt.Sprintf("%v", key["expires_at"]))
+			keyExpiry.add(float64(expiresAt.Unix()),
+				"service_hash", stringOrNA(key, "service_hash"),
+				"service_name", stringOrNA(key, "service_name"),
+				"key_id", stringOrNA(key, "key_id"),
+				"key_name", stringOrNA(key, "key_name"))
+		}
+		if len(failures) > 0 {
+			report("keys", fmt.Errorf("%s", strings.Join(failures, "; ")))
+		} else {
+			report("keys", nil)
+		}
+	} else {
+		report("services", err)
+		report("keys", err)
+	}
+
+	// Instances
+	all, err := newAPI().ListAllInstances()
+	if err == nil {
+		markLostInstances(all)
+		counts := map[string]int{"Online": 0, "Offline": 0, "Lost": 0}
+		for _, instance := range all {
+			counts[stringOrNA(instance, "status")]++
+		}
+		statuses := make([]string, 0, len(counts))
+		for status := range counts {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			instances.add(float64(counts[status]), "status", status)
+		}
+	}
+	report("instances", err)
+
+	duration.add(time.Since(start).Seconds())
+	return []*metricFamily{success, duration, certExpiry, keyExpiry, serviceActive, instances}
+}
+
+// exporterState holds the most recent scrape, served to every request
+type exporterState struct {
+	mu      sync.RWMutex
+	body    string
+	updated time.Time
+}
+
+func (s *exporterState) set(families []*metricFamily) {
+	now := time.Now()
+	last := &metricFamily{name: "certfix_last_scrape_timestamp_seconds", help: "Time of the last collection as a Unix timestamp.", kind: "gauge"}
+	last.add(float64(now.Unix()))
+
+	var b strings.Builder
+	writeMetrics(&b, append(families, last))
+
+	s.mu.Lock()
+	s.body, s.updated = b.String(), now
+	s.mu.Unlock()
+}
+
+func (s *exporterState) get() (string, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.body, s.updated
+}
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Expose CertFix health as Prometheus metrics",
+	Long: `Run an HTTP server that exposes certificate expirations, service key
+expirations, service activity and instance status as Prometheus metrics.
+
+The API is scraped every --interval in the background and /metrics always
+serves the latest results, so Prometheus scrapes are cheap. /healthz returns
+200 once the first collection has finished.
+
+Metrics:
+  certfix_certificate_expiry_timestamp_seconds   valid certificates
+  certfix_service_key_expiry_timestamp_seconds   enabled keys expiring within --days
+  certfix_service_active                         1 for active services, 0 otherwise
+  certfix_instances                              instance count by status (Online, Offline, Lost)
+  certfix_collector_success                      1 if a source was collected successfully
+  certfix_scrape_duration_seconds                time taken by the last collection
+  certfix_last_scrape_timestamp_seconds          time of the last collection`,
+	Example: `  certfix exporter --listen :9109
+  certfix exporter --listen 127.0.0.1:9109 --interval 5m --days 90`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+		listen, _ := cmd.Flags().GetString("listen")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		days, _ := cmd.Flags().GetInt("days")
+
+		if interval < 10*time.Second {
+			return fmt.Errorf("--interval must be at least 10s")
+		}
+		if days < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+
+		// Fail early when not logged in
+		if _, err := auth.GetToken(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		// Create API client
+		endpoint := config.GetAPIEndpoint()
+		collector := &exporterCollector{apiClient: newAPIClient(), days: days}
+		state := &exporterState{}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			body, updated := state.get()
+			if updated.IsZero() {
+				http.Error(w, "first collection in progress", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+			fmt.Fprint(w, body)
+		})
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			if _, updated := state.get(); updated.IsZero() {
+				http.Error(w, "starting", http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintln(w, "ok")
+		})
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprintf(w, "CertFix exporter for %s\nMetrics: /metrics\n", endpoint)
+		})
+
+		server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		serverErr := make(chan error, 1)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
+
+		fmt.Printf("Serving metrics for %s on %s/metrics (every %s)\n", endpoint, listen, interval)
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(interrupt)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		collected := make(chan []*metricFamily, 1)
+		collecting := true
+		go func() { collected <- collector.collect() }()
+
+		for {
+			select {
+			case families := <-collected:
+				state.set(families)
+				collecting = false
+				log.Debugf("Collection finished")
+			case <-ticker.C:
+				// Skip a tick rather than pile up collections on a slow API
+				if !collecting {
+					collecting = true
+					go func() { collected <- collector.collect() }()
+				}
+			case err := <-serverErr:
+				return fmt.Errorf("failed to serve metrics: %w", err)
+			case <-interrupt:
+				fmt.Println("\nShutting down...")
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				return server.Shutdown(ctx)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exporterCmd)
+
+	// Exporter command flags
+	exporterCmd.Flags().String("listen", ":9109", "Address to serve metrics on")
+	exporterCmd.Flags().Duration("interval", time.Minute, "How often to collect metrics from the API")
+	exporterCmd.Flags().IntP("days", "d", 90, "Export service keys expiring within this many days")
+}
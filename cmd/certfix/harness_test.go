@@ -0,0 +1,123 @@
+package certfix
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// update rewrites the golden files with the current output:
+//
+//	go test ./cmd/certfix -update
+var update = flag.Bool("update", false, "update golden files")
+
+// testToken is the session token stored for every test, an unsigned JWT
+// expiring in 2100
+const testToken = "eyJhbGciOiJub25lIn0.eyJleHAiOjQxMDI0NDQ4MDB9."
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	// Keep tests away from the real configuration and from the network
+	home, err := os.MkdirTemp("", "certfix-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	for _, env := range []string{"XDG_CONFIG_HOME", "XDG_STATE_HOME", config.HomeEnv} {
+		os.Unsetenv(env)
+	}
+	os.Setenv(versionCheckEnv, "1")
+	os.Setenv("CERTFIX_TELEMETRY", "off")
+	initConfig()
+	if err := auth.StoreToken(testToken); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// runCommand runs the CLI with args against fake and returns what it wrote to
+// stdout
+func runCommand(t *testing.T, fake *client.FakeClient, args ...string) (string, error) {
+	t.Helper()
+
+	previous := newAPIClient
+	newAPIClient = func() client.APIClient { return fake }
+	defer func() { newAPIClient = previous }()
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	output := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- data
+	}()
+
+	rootCmd.SetArgs(args)
+	rootCmd.SetErr(io.Discard)
+	_, runErr := rootCmd.ExecuteC()
+
+	w.Close()
+	os.Stdout = stdout
+	data := <-output
+	resetFlags(rootCmd)
+	return string(data), runErr
+}
+
+// resetFlags restores every flag changed by a previous run, since cobra keeps
+// flag values between executions of the same command tree
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+// assertGolden compares output with testdata/<name>.golden
+func assertGolden(t *testing.T, name, output string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(want, []byte(output)) {
+		t.Errorf("output does not match %s\n--- want\n%s\n--- got\n%s", path, want, output)
+	}
+}
@@ -2,20 +2,38 @@ package certfix
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/certfix/certfix-cli/internal/api"
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
 	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/certfix/certfix-cli/pkg/logger"
 	"github.com/spf13/cobra"
 )
 
+// newAPIClient returns the client commands use to talk to the API, or the
+// local cache with --offline. Tests replace it with a fake.
+var newAPIClient = func() client.APIClient {
+	if offline {
+		return newCacheClient()
+	}
+	return api.NewHTTPClient()
+}
+
+// newAPI returns an internal/api client that sends its requests through
+// newAPIClient
+func newAPI() *api.Client {
+	return api.New(newAPIClient())
+}
+
 // requireSuperuser fetches the current user via /me and returns an error if the
-// user does not have superuser privileges. It also initialises the logger so that
-// commands which define their own PersistentPreRunE do not skip the root-level
-// logger initialisation.
+// user does not have superuser privileges. It also initialises the logger and
+// checks server compatibility so that commands which define their own
+// PersistentPreRunE do not skip the root-level setup.
 func requireSuperuser(cmd *cobra.Command, args []string) error {
 	logger.InitLogger(verbose)
+	checkServerCompatibility(cmd)
 
 	token, err := auth.GetToken()
 	if err != nil {
@@ -23,8 +41,7 @@ func requireSuperuser(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	endpoint := config.GetAPIEndpoint()
-	apiClient := client.NewHTTPClient(endpoint)
+	apiClient := newAPIClient()
 
 	response, err := apiClient.GetWithAuth("/me", token)
 	if err != nil {
@@ -40,3 +57,92 @@ func requireSuperuser(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseArrayResponse extracts the list of objects from a response whose body
+// was a JSON array (wrapped by the HTTP client under "_array_data").
+func parseArrayResponse(response map[string]interface{}) []map[string]interface{} {
+	// Never nil, so empty lists encode as [] rather than null
+	items := []map[string]interface{}{}
+	if response["_is_array"] != nil {
+		if arr, ok := response["_array_data"].([]interface{}); ok {
+			for _, item := range arr {
+				if m, ok := item.(map[string]interface{}); ok {
+					items = append(items, m)
+				}
+			}
+		}
+	}
+	return items
+}
+
+// formatTimestamp renders an RFC3339 value using the given layout, returning
+// fallback when the value is missing or cannot be parsed.
+func formatTimestamp(value interface{}, layout, fallback string) string {
+	if value == nil {
+		return fallback
+	}
+	t, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", value))
+	if err != nil {
+		return fallback
+	}
+	return t.Format(layout)
+}
+
+// stringOrNA returns the string form of m[key], or "N/A" when it is missing.
+func stringOrNA(m map[string]interface{}, key string) string {
+	if v, ok := m[key]; ok && v != nil && fmt.Sprintf("%v", v) != "<nil>" {
+		return fmt.Sprintf("%v", v)
+	}
+	return "N/A"
+}
+
+// toFloat converts a JSON number (or numeric string) to float64, returning 0
+// for anything else.
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	}
+	return 0
+}
+
+// parseHumanDuration parses a duration such as "90m", "24h", "7d" or "2w". Go
+// duration syntax is accepted as is; "d" (days) and "w" (weeks) are added for
+// the longer spans used when looking back over history.
+func parseHumanDuration(s string) (time.Duration, error) {
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		value, err := strconv.Atoi(s[:n-1])
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid duration '%s' (e.g. 30m, 24h, 7d)", s)
+		}
+		unit := 24 * time.Hour
+		if s[n-1] == 'w' {
+			unit *= 7
+		}
+		return time.Duration(value) * unit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration '%s' (e.g. 30m, 24h, 7d)", s)
+	}
+	return d, nil
+}
+
+// formatAge renders a duration in its largest whole unit, e.g. "45s", "12m",
+// "3h" or "2d", for "time since" columns.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
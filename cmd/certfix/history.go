@@ -0,0 +1,165 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/certfix/certfix-cli/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show locally recorded operations",
+	Long: `Every command that changes state on the server (create, update, delete,
+rotate, apply, ...) is recorded in history.jsonl in the state directory
+together with its arguments, result and endpoint, so you can reconstruct what
+was run during an incident. Secret flag values are never recorded.
+
+Pass --no-history to any command to leave it out.`,
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded operations, most recent first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		failedOnly, _ := cmd.Flags().GetBool("failed")
+		commandFilter, _ := cmd.Flags().GetString("command")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		entries, err := history.Load()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Entry numbers are positions in the file so they stay stable for
+		// history show
+		var numbers []int
+		for i := len(entries) - 1; i >= 0; i-- {
+			entry := entries[i]
+			if failedOnly && entry.Result != history.ResultError {
+				continue
+			}
+			if commandFilter != "" && !strings.Contains(entry.Command, commandFilter) {
+				continue
+			}
+			numbers = append(numbers, i+1)
+			if limit > 0 && len(numbers) == limit {
+				break
+			}
+		}
+
+		if outputFormat == "json" {
+			selected := make([]history.Entry, 0, len(numbers))
+			for _, n := range numbers {
+				selected = append(selected, entries[n-1])
+			}
+			data, _ := json.MarshalIndent(selected, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(numbers) == 0 {
+			fmt.Println("No operations recorded.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "#\tTIME\tCOMMAND\tTARGET\tRESULT")
+		fmt.Fprintln(w, "-\t----\t-------\t------\t------")
+		for _, n := range numbers {
+			entry := entries[n-1]
+			target := entry.Target
+			if target == "" {
+				target = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+				n,
+				entry.Time.Local().Format("2006-01-02 15:04:05"),
+				entry.Command,
+				target,
+				entry.Result,
+			)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <number>",
+	Short: "Show the details of a recorded operation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		n, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid entry number %q (see 'certfix history list')", args[0])
+		}
+
+		entries, err := history.Load()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if n > len(entries) {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("entry %d not found (%d recorded)", n, len(entries))
+		}
+		entry := entries[n-1]
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(entry, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Entry:     #%d\n", n)
+		fmt.Printf("Time:      %s\n", entry.Time.Local().Format("2006-01-02 15:04:05 MST"))
+		fmt.Printf("Command:   certfix %s\n", strings.TrimSpace(entry.Command+" "+strings.Join(entry.Args, " ")))
+		if len(entry.Flags) > 0 {
+			names := make([]string, 0, len(entry.Flags))
+			for name := range entry.Flags {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			fmt.Printf("Flags:\n")
+			for _, name := range names {
+				fmt.Printf("  --%s=%s\n", name, entry.Flags[name])
+			}
+		}
+		fmt.Printf("Endpoint:  %s\n", entry.Endpoint)
+		fmt.Printf("Result:    %s\n", entry.Result)
+		if entry.Error != "" {
+			fmt.Printf("Error:     %s\n", entry.Error)
+		}
+		if entry.Duration != "" {
+			fmt.Printf("Duration:  %s\n", entry.Duration)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+
+	// List command flags
+	historyListCmd.Flags().IntP("limit", "n", 20, "Maximum number of entries to show (0 for all)")
+	historyListCmd.Flags().Bool("failed", false, "Show only failed operations")
+	historyListCmd.Flags().String("command", "", "Show only commands containing this text (e.g. \"keys\")")
+	historyListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Show command flags
+	historyShowCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
@@ -9,30 +9,61 @@ import (
 	"time"
 
 	"github.com/certfix/certfix-cli/internal/api"
-	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/spf13/cobra"
 )
 
 var instancesCmd = &cobra.Command{
-	Use:   "instances",
-	Short: "Manage instances",
-	Long:  `Manage service instances including listing, getting details, viewing logs, and deleting instances.`,
+	Use:     "instances",
+	Aliases: []string{"instance"},
+	Short:   "Manage instances",
+	Long:    `Manage service instances including listing, getting details, viewing logs, and deregistering instances.`,
 }
 
-// instanceTableWriter writes a tabular list of instances.
-func instanceTableWriter(instances []map[string]interface{}) {
-	// Apply "Lost" logic: mark as Lost if last_seen_at > 5 minutes ago
+// instanceLostAfter is how long an instance may go without checking in before
+// it is considered Lost
+const instanceLostAfter = 5 * time.Minute
+
+// instanceSilence returns how long ago an instance last checked in. ok is false
+// when last_seen_at is missing or malformed.
+func instanceSilence(instance map[string]interface{}) (silence time.Duration, ok bool) {
+	lastSeen, _ := instance["last_seen_at"].(string)
+	if lastSeen == "" {
+		return 0, false
+	}
+	lastSeenTime, err := time.Parse(time.RFC3339, lastSeen)
+	if err != nil {
+		return 0, false
+	}
+	return time.Since(lastSeenTime), true
+}
+
+// markLostInstances marks an instance as Lost if last_seen_at > 5 minutes ago
+func markLostInstances(instances []map[string]interface{}) {
 	for _, instance := range instances {
-		lastSeen, _ := instance["last_seen_at"].(string)
-		if lastSeen != "" {
-			lastSeenTime, err := time.Parse(time.RFC3339, lastSeen)
-			if err == nil && time.Since(lastSeenTime) > 5*time.Minute {
-				instance["status"] = "Lost"
-			}
+		if silence, ok := instanceSilence(instance); ok && silence > instanceLostAfter {
+			instance["status"] = "Lost"
 		}
 	}
+}
+
+// instanceLabel identifies an instance in watch mode output
+func instanceLabel(instance map[string]interface{}) string {
+	return fmt.Sprintf("%v (%v)", instance["hostname"], instance["id"])
+}
+
+// instanceStatuses returns the status of each instance keyed by its label
+func instanceStatuses(instances []map[string]interface{}) map[string]string {
+	statuses := make(map[string]string)
+	for _, instance := range instances {
+		statuses[instanceLabel(instance)] = fmt.Sprintf("%v", instance["status"])
+	}
+	return statuses
+}
+
+// instanceTableWriter writes a tabular list of instances. changes maps instance
+// labels to a status transition to display instead of the plain status.
+func instanceTableWriter(instances []map[string]interface{}, changes map[string]string) {
+	markLostInstances(instances)
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	fmt.Fprintln(w, "ID\tHOSTNAME\tOS\tIP ADDRESS\tSTATUS\tLAST SEEN\tVERSION")
@@ -53,6 +84,9 @@ func instanceTableWriter(instances []map[string]interface{}) {
 		osInfo := fmt.Sprintf("%s / %s", osType, arch)
 		ip := s("ip_address")
 		status := s("status")
+		if change, ok := changes[instanceLabel(instance)]; ok {
+			status = change
+		}
 		lastSeen := s("last_seen_at")
 		if t, err := time.Parse(time.RFC3339, lastSeen); err == nil {
 			lastSeen = t.Format("2006-01-02 15:04")
@@ -64,32 +98,114 @@ func instanceTableWriter(instances []map[string]interface{}) {
 	w.Flush()
 }
 
+// watchInstances refreshes an instance table produced by fetch until interrupted
+func watchInstances(cmd *cobra.Command, interval time.Duration, title string, fetch func() ([]map[string]interface{}, error), table func([]map[string]interface{}, map[string]string)) error {
+	notifiers, err := notifiersFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	return runWatch(interval, title, notifiers, func(prev map[string]string) (map[string]string, error) {
+		instances, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		markLostInstances(instances)
+		if err := sortItems(cmd, instanceSortColumns, instances); err != nil {
+			return nil, err
+		}
+		statuses := instanceStatuses(instances)
+		if len(instances) == 0 {
+			fmt.Println("No instances found.")
+		} else {
+			table(instances, statusChanges(prev, statuses))
+		}
+		return statuses, nil
+	})
+}
+
 var instancesListCmd = &cobra.Command{
-	Use:   "list <key-id>",
+	Use:   "list [key-id]",
 	Short: "List all instances by service key",
-	Long:  `List all instances associated with a specific service key ID.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `List all instances associated with a specific service key ID.
+
+With --service the instances of every key of the service are listed together,
+and with --group those of every service in a service group.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		serviceHash, _ := cmd.Flags().GetString("service")
+		groupID, _ := cmd.Flags().GetString("group")
+		selectors := len(args)
+		if serviceHash != "" {
+			selectors++
+		}
+		if groupID != "" {
+			selectors++
+		}
+		if len(args) > 1 || selectors != 1 {
+			return fmt.Errorf("specify exactly one of <key-id>, --service or --group")
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		keyID := args[0]
+		serviceHash, _ := cmd.Flags().GetString("service")
+		groupID, _ := cmd.Flags().GetString("group")
 		outputFormat, _ := cmd.Flags().GetString("output")
+		watch, _ := cmd.Flags().GetBool("watch")
+		watchInterval, _ := cmd.Flags().GetDuration("interval")
+
+		if groupID != "" && !looksLikeID(groupID) {
+			resolved, err := resolveServiceGroupID(groupID)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			groupID = resolved
+		}
 
-		apiClient := api.NewClient()
+		apiClient := newAPI()
+
+		title := "certfix instances list"
+		list := apiClient.ListInstancesByKey
+		table := instanceKeyTableWriter
+		selector := ""
+		switch {
+		case serviceHash != "":
+			title += " --service " + serviceHash
+			list = apiClient.ListInstancesByService
+			table = instanceServiceTableWriter
+			selector = serviceHash
+		case groupID != "":
+			title += " --group " + groupID
+			list = apiClient.ListInstancesByServiceGroup
+			table = instanceServiceTableWriter
+			selector = groupID
+		default:
+			title += " " + args[0]
+			selector = args[0]
+		}
 
-		instances, err := apiClient.ListInstancesByKey(keyID)
+		if watch {
+			cmd.SilenceUsage = true
+			fetch := func() ([]map[string]interface{}, error) {
+				instances, err := list(selector)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list instances: %w", err)
+				}
+				return instances, nil
+			}
+			return watchInstances(cmd, watchInterval, title, fetch, table)
+		}
+
+		instances, err := list(selector)
 		if err != nil {
 			cmd.SilenceUsage = true
 			return fmt.Errorf("failed to list instances: %w", err)
 		}
 
 		// Apply "Lost" logic to all instances before output
-		for _, instance := range instances {
-			lastSeen, _ := instance["last_seen_at"].(string)
-			if lastSeen != "" {
-				lastSeenTime, err := time.Parse(time.RFC3339, lastSeen)
-				if err == nil && time.Since(lastSeenTime) > 5*time.Minute {
-					instance["status"] = "Lost"
-				}
-			}
+		markLostInstances(instances)
+
+		if err := sortItems(cmd, instanceSortColumns, instances); err != nil {
+			return err
 		}
 
 		if outputFormat == "json" {
@@ -98,44 +214,90 @@ var instancesListCmd = &cobra.Command{
 			return nil
 		}
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "HOSTNAME\tOS\tIP ADDRESS\tSTATUS\tREGISTERED\tLAST SEEN\tVERSION")
-		fmt.Fprintln(w, "--------\t--\t----------\t------\t----------\t---------\t-------")
+		if len(instances) == 0 && len(args) == 0 {
+			fmt.Println("No instances found.")
+			return nil
+		}
 
-		for _, instance := range instances {
-			s := func(k string) string {
-				if v, ok := instance[k]; ok && v != nil {
-					return fmt.Sprintf("%v", v)
-				}
-				return "N/A"
+		table(instances, nil)
+		return nil
+	},
+}
+
+// instanceKeyTableWriter writes the instance table used by 'instances list',
+// which shows registration time instead of the instance ID.
+func instanceKeyTableWriter(instances []map[string]interface{}, changes map[string]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "HOSTNAME\tOS\tIP ADDRESS\tSTATUS\tREGISTERED\tLAST SEEN\tVERSION")
+	fmt.Fprintln(w, "--------\t--\t----------\t------\t----------\t---------\t-------")
+
+	for _, instance := range instances {
+		s := func(k string) string {
+			if v, ok := instance[k]; ok && v != nil {
+				return fmt.Sprintf("%v", v)
 			}
+			return "N/A"
+		}
 
-			hostname := s("hostname")
-			osType := s("os_type")
-			arch := s("architecture")
-			osInfo := fmt.Sprintf("%s / %s", osType, arch)
+		hostname := s("hostname")
+		osType := s("os_type")
+		arch := s("architecture")
+		osInfo := fmt.Sprintf("%s / %s", osType, arch)
 
-			ip := s("ip_address")
-			status := s("status")
+		ip := s("ip_address")
+		status := s("status")
+		if change, ok := changes[instanceLabel(instance)]; ok {
+			status = change
+		}
 
-			registered := s("first_registered_at")
-			if t, err := time.Parse(time.RFC3339, registered); err == nil {
-				registered = t.Format("2006-01-02 15:04")
-			}
+		registered := s("first_registered_at")
+		if t, err := time.Parse(time.RFC3339, registered); err == nil {
+			registered = t.Format("2006-01-02 15:04")
+		}
 
-			lastSeen := s("last_seen_at")
-			if t, err := time.Parse(time.RFC3339, lastSeen); err == nil {
-				lastSeen = t.Format("2006-01-02 15:04")
-			}
+		lastSeen := s("last_seen_at")
+		if t, err := time.Parse(time.RFC3339, lastSeen); err == nil {
+			lastSeen = t.Format("2006-01-02 15:04")
+		}
 
-			version := s("agent_version")
+		version := s("agent_version")
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", hostname, osInfo, ip, status, registered, lastSeen, version)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", hostname, osInfo, ip, status, registered, lastSeen, version)
+	}
+	w.Flush()
+}
+
+// instanceServiceTableWriter writes the instance table used when instances are
+// aggregated across keys, showing which service and key each one belongs to.
+func instanceServiceTableWriter(instances []map[string]interface{}, changes map[string]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tHOSTNAME\tSERVICE\tKEY ID\tIP ADDRESS\tSTATUS\tLAST SEEN\tVERSION")
+	fmt.Fprintln(w, "--\t--------\t-------\t------\t----------\t------\t---------\t-------")
+
+	for _, instance := range instances {
+		s := func(k string) string {
+			if v, ok := instance[k]; ok && v != nil {
+				return fmt.Sprintf("%v", v)
+			}
+			return "N/A"
 		}
-		w.Flush()
 
-		return nil
-	},
+		service := s("service_name")
+		if service == "N/A" {
+			service = s("service_hash")
+		}
+		status := s("status")
+		if change, ok := changes[instanceLabel(instance)]; ok {
+			status = change
+		}
+		lastSeen := s("last_seen_at")
+		if t, err := time.Parse(time.RFC3339, lastSeen); err == nil {
+			lastSeen = t.Format("2006-01-02 15:04")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s("id"), s("hostname"), service, s("key_id"), s("ip_address"), status, lastSeen, s("agent_version"))
+	}
+	w.Flush()
 }
 
 var instancesListAllCmd = &cobra.Command{
@@ -144,31 +306,31 @@ var instancesListAllCmd = &cobra.Command{
 	Long:  `List all service instances across all services.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		outputFormat, _ := cmd.Flags().GetString("output")
+		watch, _ := cmd.Flags().GetBool("watch")
+		watchInterval, _ := cmd.Flags().GetDuration("interval")
 
-		token, err := auth.GetToken()
-		if err != nil {
+		apiClient := newAPI()
+
+		if watch {
 			cmd.SilenceUsage = true
-			return err
+			fetch := func() ([]map[string]interface{}, error) {
+				instances, err := apiClient.ListAllInstances()
+				if err != nil {
+					return nil, fmt.Errorf("failed to list instances: %w", err)
+				}
+				return instances, nil
+			}
+			return watchInstances(cmd, watchInterval, "certfix instances list-all", fetch, instanceTableWriter)
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
-
-		response, err := apiClient.GetWithAuth("/instances", token)
+		instances, err := apiClient.ListAllInstances()
 		if err != nil {
 			cmd.SilenceUsage = true
 			return fmt.Errorf("failed to list instances: %w", err)
 		}
 
-		var instances []map[string]interface{}
-		if response["_is_array"] != nil {
-			if arr, ok := response["_array_data"].([]interface{}); ok {
-				for _, item := range arr {
-					if inst, ok := item.(map[string]interface{}); ok {
-						instances = append(instances, inst)
-					}
-				}
-			}
+		if err := sortItems(cmd, instanceSortColumns, instances); err != nil {
+			return err
 		}
 
 		if outputFormat == "json" {
@@ -182,7 +344,7 @@ var instancesListAllCmd = &cobra.Command{
 			return nil
 		}
 
-		instanceTableWriter(instances)
+		instanceTableWriter(instances, nil)
 		return nil
 	},
 }
@@ -194,31 +356,31 @@ var instancesListByServiceCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceHash := args[0]
 		outputFormat, _ := cmd.Flags().GetString("output")
+		watch, _ := cmd.Flags().GetBool("watch")
+		watchInterval, _ := cmd.Flags().GetDuration("interval")
 
-		token, err := auth.GetToken()
-		if err != nil {
+		apiClient := newAPI()
+
+		if watch {
 			cmd.SilenceUsage = true
-			return err
+			fetch := func() ([]map[string]interface{}, error) {
+				instances, err := apiClient.ListServiceInstances(serviceHash)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list instances: %w", err)
+				}
+				return instances, nil
+			}
+			return watchInstances(cmd, watchInterval, "certfix instances list-by-service "+serviceHash, fetch, instanceTableWriter)
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
-
-		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/instances", serviceHash), token)
+		instances, err := apiClient.ListServiceInstances(serviceHash)
 		if err != nil {
 			cmd.SilenceUsage = true
 			return fmt.Errorf("failed to list instances: %w", err)
 		}
 
-		var instances []map[string]interface{}
-		if response["_is_array"] != nil {
-			if arr, ok := response["_array_data"].([]interface{}); ok {
-				for _, item := range arr {
-					if inst, ok := item.(map[string]interface{}); ok {
-						instances = append(instances, inst)
-					}
-				}
-			}
+		if err := sortItems(cmd, instanceSortColumns, instances); err != nil {
+			return err
 		}
 
 		if outputFormat == "json" {
@@ -232,7 +394,7 @@ var instancesListByServiceCmd = &cobra.Command{
 			return nil
 		}
 
-		instanceTableWriter(instances)
+		instanceTableWriter(instances, nil)
 		return nil
 	},
 }
@@ -245,16 +407,7 @@ var instancesGetCmd = &cobra.Command{
 		instanceID := args[0]
 		outputFormat, _ := cmd.Flags().GetString("output")
 
-		token, err := auth.GetToken()
-		if err != nil {
-			cmd.SilenceUsage = true
-			return err
-		}
-
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
-
-		response, err := apiClient.GetWithAuth(fmt.Sprintf("/instances/%s", instanceID), token)
+		response, err := newAPI().GetInstance(instanceID)
 		if err != nil {
 			cmd.SilenceUsage = true
 			return fmt.Errorf("failed to get instance: %w", err)
@@ -274,6 +427,7 @@ var instancesGetCmd = &cobra.Command{
 		}
 
 		fmt.Printf("ID:           %s\n", s("id"))
+		fmt.Printf("Name:         %s\n", s("name"))
 		fmt.Printf("Hostname:     %s\n", s("hostname"))
 		fmt.Printf("OS:           %s / %s\n", s("os_type"), s("architecture"))
 		fmt.Printf("IP Address:   %s\n", s("ip_address"))
@@ -287,41 +441,77 @@ var instancesGetCmd = &cobra.Command{
 	},
 }
 
-var instancesDeleteCmd = &cobra.Command{
-	Use:     "delete <instance-id>",
-	Aliases: []string{"rm", "remove"},
-	Short:   "Delete an instance",
-	Args:    cobra.ExactArgs(1),
+var instancesDeregisterCmd = &cobra.Command{
+	Use:     "deregister <instance-id>",
+	Aliases: []string{"delete", "rm", "remove"},
+	Short:   "Deregister an instance",
+	Long: `Remove a registered instance, typically a decommissioned host. The instance is
+shown before confirmation; an agent that is still running will register the
+host again on its next check-in.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		instanceID := args[0]
-		force, _ := cmd.Flags().GetBool("force")
-
-		if !force {
-			fmt.Printf("Are you sure you want to delete instance %s? (y/N): ", instanceID)
-			var ans string
-			fmt.Scanln(&ans)
-			if strings.ToLower(ans) != "y" && strings.ToLower(ans) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
+
+		apiClient := newAPI()
+
+		instance, err := apiClient.GetInstance(instanceID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get instance: %w", err)
+		}
+		markLostInstances([]map[string]interface{}{instance})
+
+		if !skipConfirmation(cmd) {
+			fmt.Printf("Instance:  %s\n", instanceLabel(instance))
+			fmt.Printf("Status:    %s\n", stringOrNA(instance, "status"))
+			fmt.Printf("Last Seen: %s\n", formatTimestamp(instance["last_seen_at"], "2006-01-02 15:04", "N/A"))
+			if instance["status"] == "Online" {
+				fmt.Fprintln(os.Stderr, "Warning: this instance is still checking in and will register again unless its agent is stopped.")
 			}
 		}
+		if err := requirePermission(cmd, "instance:deregister"); err != nil {
+			return err
+		}
 
-		token, err := auth.GetToken()
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to deregister instance %s?", instanceID))
 		if err != nil {
-			cmd.SilenceUsage = true
 			return err
 		}
+		if !confirmed {
+			fmt.Println("Deregistration cancelled.")
+			return nil
+		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		if err := apiClient.DeleteInstance(instanceID); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to deregister instance: %w", err)
+		}
 
-		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/instances/%s", instanceID), token)
+		fmt.Printf("✓ Instance deregistered successfully\n")
+		return nil
+	},
+}
+
+var instancesRenameCmd = &cobra.Command{
+	Use:   "rename <instance-id> <name>",
+	Short: "Set the display name of an instance",
+	Long: `Set a display name for an instance. The hostname reported by the agent is kept;
+pass an empty name to clear the display name.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		instanceID := args[0]
+		name := strings.TrimSpace(args[1])
+
+		response, err := newAPI().RenameInstance(instanceID, name)
 		if err != nil {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to delete instance: %w", err)
+			return fmt.Errorf("failed to rename instance: %w", err)
 		}
 
-		fmt.Printf("✓ Instance deleted successfully\n")
+		fmt.Printf("✓ Instance renamed successfully\n")
+		fmt.Printf("ID:       %s\n", instanceID)
+		fmt.Printf("Name:     %s\n", stringOrNA(response, "name"))
+		fmt.Printf("Hostname: %s\n", stringOrNA(response, "hostname"))
 		return nil
 	},
 }
@@ -335,37 +525,12 @@ var instancesLogsCmd = &cobra.Command{
 		outputFormat, _ := cmd.Flags().GetString("output")
 		limit, _ := cmd.Flags().GetInt("limit")
 
-		token, err := auth.GetToken()
-		if err != nil {
-			cmd.SilenceUsage = true
-			return err
-		}
-
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
-
-		apiEndpoint := fmt.Sprintf("/instances/%s/logs", instanceID)
-		if limit > 0 {
-			apiEndpoint = fmt.Sprintf("%s?limit=%d", apiEndpoint, limit)
-		}
-
-		response, err := apiClient.GetWithAuth(apiEndpoint, token)
+		logs, err := newAPI().GetInstanceLogs(instanceID, limit)
 		if err != nil {
 			cmd.SilenceUsage = true
 			return fmt.Errorf("failed to get instance logs: %w", err)
 		}
 
-		var logs []map[string]interface{}
-		if response["_is_array"] != nil {
-			if arr, ok := response["_array_data"].([]interface{}); ok {
-				for _, item := range arr {
-					if log, ok := item.(map[string]interface{}); ok {
-						logs = append(logs, log)
-					}
-				}
-			}
-		}
-
 		if outputFormat == "json" {
 			data, _ := json.MarshalIndent(logs, "", "  ")
 			fmt.Println(string(data))
@@ -399,20 +564,128 @@ var instancesLogsCmd = &cobra.Command{
 	},
 }
 
+var instancesPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Deregister instances that have been lost for a long time",
+	Long: `Deregister every instance that has not checked in for longer than --lost-for,
+optionally limited to one service. The matching instances are listed before
+anything is removed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lostForValue, _ := cmd.Flags().GetString("lost-for")
+		serviceHash, _ := cmd.Flags().GetString("service")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		lostFor, err := parseHumanDuration(lostForValue)
+		if err != nil {
+			return err
+		}
+		if lostFor < instanceLostAfter {
+			return fmt.Errorf("--lost-for must be at least %s, the time after which an instance is considered lost", instanceLostAfter)
+		}
+
+		apiClient := newAPI()
+
+		var instances []map[string]interface{}
+		if serviceHash != "" {
+			instances, err = apiClient.ListServiceInstances(serviceHash)
+		} else {
+			instances, err = apiClient.ListAllInstances()
+		}
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list instances: %w", err)
+		}
+
+		var stale []map[string]interface{}
+		for _, instance := range instances {
+			if silence, ok := instanceSilence(instance); ok && silence > lostFor {
+				stale = append(stale, instance)
+			}
+		}
+
+		if len(stale) == 0 {
+			fmt.Printf("No instances have been lost for more than %s.\n", lostForValue)
+			return nil
+		}
+
+		instanceTableWriter(stale, nil)
+		fmt.Printf("\n%d instance(s) will be deregistered.\n", len(stale))
+
+		if dryRun {
+			fmt.Println("Dry run: no instances deregistered.")
+			return nil
+		}
+
+		if err := requirePermission(cmd, "instance:deregister"); err != nil {
+			return err
+		}
+
+		// Confirm deregistration
+		confirmed, err := confirm(cmd, "Are you sure you want to deregister these instances?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deregistration cancelled.")
+			return nil
+		}
+
+		ids := make([]string, 0, len(stale))
+		for _, instance := range stale {
+			ids = append(ids, fmt.Sprintf("%v", instance["id"]))
+		}
+
+		cmd.SilenceUsage = true
+		return runServiceBulk(ids, "Deregistering instance", apiClient.DeleteInstance)
+	},
+}
+
+// instanceSortColumns are the --sort-by columns of the instance lists
+var instanceSortColumns = sortColumns{
+	"id":         "id",
+	"hostname":   "hostname",
+	"service":    "service_name",
+	"key_id":     "key_id",
+	"os":         "os_type",
+	"ip_address": "ip_address",
+	"status":     "status",
+	"registered": "first_registered_at",
+	"last_seen":  "last_seen_at",
+	"version":    "agent_version",
+}
+
 func init() {
 	rootCmd.AddCommand(instancesCmd)
 	instancesCmd.AddCommand(instancesListCmd)
 	instancesCmd.AddCommand(instancesListAllCmd)
 	instancesCmd.AddCommand(instancesListByServiceCmd)
 	instancesCmd.AddCommand(instancesGetCmd)
-	instancesCmd.AddCommand(instancesDeleteCmd)
+	instancesCmd.AddCommand(instancesDeregisterCmd)
+	instancesCmd.AddCommand(instancesRenameCmd)
+	instancesCmd.AddCommand(instancesPruneCmd)
 	instancesCmd.AddCommand(instancesLogsCmd)
 
 	instancesListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	instancesListCmd.Flags().StringP("service", "s", "", "List the instances of all keys of this service hash")
+	instancesListCmd.Flags().StringP("group", "g", "", "List the instances of all services in this service group (ID or name)")
 	instancesListAllCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 	instancesListByServiceCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	for _, c := range []*cobra.Command{instancesListCmd, instancesListAllCmd, instancesListByServiceCmd} {
+		c.Flags().BoolP("watch", "w", false, "Refresh the list continuously, highlighting status changes")
+		c.Flags().Duration("interval", 5*time.Second, "Refresh interval for --watch")
+		c.Flags().StringArray("notify", nil, "With --watch, send status changes to a slack://, teams://, mailto: or https:// target (repeatable)")
+	}
+	enableSorting(instanceSortColumns, instancesListCmd, instancesListAllCmd, instancesListByServiceCmd)
 	instancesGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
-	instancesDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	instancesDeregisterCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 	instancesLogsCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 	instancesLogsCmd.Flags().IntP("limit", "l", 50, "Maximum number of log entries to show")
+
+	instancesPruneCmd.Flags().String("lost-for", "7d", "Deregister instances not seen for longer than this (e.g. 12h, 7d, 2w)")
+	instancesPruneCmd.Flags().StringP("service", "s", "", "Only prune instances of this service hash")
+	instancesPruneCmd.Flags().Bool("dry-run", false, "Show the instances that would be deregistered without removing them")
+	instancesPruneCmd.Flags().BoolP("force", "f", false, "Deregister without confirmation")
+
+	enableIdentifierResolver(api.KindService, instancesListByServiceCmd)
 }
@@ -0,0 +1,178 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// instanceCertSerial returns the serial of the certificate an instance reports
+// as installed, or "" when it has not reported one
+func instanceCertSerial(instance map[string]interface{}) string {
+	for _, key := range []string{"certificate_serial", "cert_serial"} {
+		if serial := stringOrNA(instance, key); serial != "N/A" && serial != "" {
+			return serial
+		}
+	}
+	return ""
+}
+
+// latestCertificate returns the most recently issued certificate of a service,
+// or nil when none has been issued
+func latestCertificate(apiClient client.APIClient, token, serviceHash string) (map[string]interface{}, error) {
+	response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates?include_history=true", serviceHash), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificates of service %s: %w", serviceHash, err)
+	}
+	var latest map[string]interface{}
+	for _, cert := range parseArrayResponse(response) {
+		if latest == nil || fmt.Sprintf("%v", cert["created_at"]) > fmt.Sprintf("%v", latest["created_at"]) {
+			latest = cert
+		}
+	}
+	return latest, nil
+}
+
+var instancesCertStatusCmd = &cobra.Command{
+	Use:   "cert-status [key-id]",
+	Short: "Show which certificate each instance has deployed",
+	Long: `Compare, per instance, the certificate serial the agent reports as installed
+with the latest certificate issued for its service, and show how long ago the
+last successful deployment happened. Use it to follow a rotation rollout.
+
+Instances are selected by key ID or, with --service, across all keys of a
+service.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		serviceHash, _ := cmd.Flags().GetString("service")
+		if len(args) > 1 || (len(args) == 1) == (serviceHash != "") {
+			return fmt.Errorf("specify exactly one of <key-id> or --service")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceHash, _ := cmd.Flags().GetString("service")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		var instances []map[string]interface{}
+		if serviceHash != "" {
+			instances, err = newAPI().ListInstancesByService(serviceHash)
+		} else {
+			instances, err = newAPI().ListInstancesByKey(args[0])
+		}
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list instances: %w", err)
+		}
+		markLostInstances(instances)
+
+		// Latest certificate per service, fetched once per service
+		latest := make(map[string]map[string]interface{})
+		for _, instance := range instances {
+			hash := stringOrNA(instance, "service_hash")
+			if hash == "N/A" {
+				continue
+			}
+			if _, ok := latest[hash]; ok {
+				continue
+			}
+			cert, err := latestCertificate(apiClient, token, hash)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			latest[hash] = cert
+		}
+
+		rows := make([]map[string]interface{}, 0, len(instances))
+		upToDate, outdated := 0, 0
+		for _, instance := range instances {
+			installed := instanceCertSerial(instance)
+			latestSerial := ""
+			if cert := latest[stringOrNA(instance, "service_hash")]; cert != nil {
+				latestSerial = stringOrNA(cert, "serial_number")
+			}
+
+			state := "unknown"
+			switch {
+			case installed == "" || latestSerial == "":
+			case installed == latestSerial:
+				state = "up to date"
+				upToDate++
+			default:
+				state = "outdated"
+				outdated++
+			}
+
+			row := map[string]interface{}{
+				"id":                instance["id"],
+				"hostname":          instance["hostname"],
+				"service_hash":      instance["service_hash"],
+				"status":            instance["status"],
+				"installed_serial":  installed,
+				"latest_serial":     latestSerial,
+				"state":             state,
+				"last_deployed_at":  instance["last_deployed_at"],
+				"since_last_deploy": "",
+			}
+			if deployedAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", instance["last_deployed_at"])); err == nil {
+				row["since_last_deploy"] = formatAge(time.Since(deployedAt))
+			}
+			rows = append(rows, row)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(rows, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(rows) == 0 {
+			fmt.Println("No instances found.")
+			return nil
+		}
+
+		orNA := func(s interface{}) string {
+			if s == nil || s == "" {
+				return "N/A"
+			}
+			return fmt.Sprintf("%v", s)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "HOSTNAME\tSTATUS\tINSTALLED SERIAL\tLATEST SERIAL\tSTATE\tLAST DEPLOYED")
+		fmt.Fprintln(w, "--------\t------\t----------------\t-------------\t-----\t-------------")
+		for _, row := range rows {
+			lastDeployed := "N/A"
+			if age := row["since_last_deploy"].(string); age != "" {
+				lastDeployed = age + " ago"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", orNA(row["hostname"]), orNA(row["status"]), orNA(row["installed_serial"]), orNA(row["latest_serial"]), row["state"], lastDeployed)
+		}
+		w.Flush()
+		fmt.Printf("\n%d of %d instance(s) up to date, %d outdated.\n", upToDate, len(rows), outdated)
+
+		return nil
+	},
+}
+
+func init() {
+	instancesCmd.AddCommand(instancesCertStatusCmd)
+
+	// Cert-status command flags
+	instancesCertStatusCmd.Flags().StringP("service", "s", "", "Show the instances of all keys of this service hash")
+	instancesCertStatusCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
@@ -0,0 +1,217 @@
+package certfix
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// parseVersion splits a version such as "v1.4.2" or "1.4.2-rc1" into its
+// numeric components. Pre-release and build suffixes are ignored.
+func parseVersion(version string) ([]int, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, fmt.Errorf("invalid version '%s'", version)
+	}
+	var parts []int
+	for _, field := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version '%s'", version)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
+
+// compareVersions returns -1, 0 or 1 as a is older than, equal to or newer
+// than b. Missing components count as 0, so "1.2" equals "1.2.0".
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+var instancesVersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "Report agent versions across all instances",
+	Long: `Group all instances by the agent version they run. With --min-version every
+instance running an older agent (or not reporting a version) is flagged as
+outdated and listed below the summary.
+
+JSON and CSV output contain one record per instance, ready to feed into a
+patching campaign.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		minVersion, _ := cmd.Flags().GetString("min-version")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		if outputFormat != "table" && outputFormat != "json" && outputFormat != "csv" {
+			return fmt.Errorf("invalid output format '%s' (valid: table, json, csv)", outputFormat)
+		}
+
+		var minimum []int
+		if minVersion != "" {
+			var err error
+			if minimum, err = parseVersion(minVersion); err != nil {
+				return fmt.Errorf("invalid --min-version: %w", err)
+			}
+		}
+
+		instances, err := newAPI().ListAllInstances()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list instances: %w", err)
+		}
+		markLostInstances(instances)
+
+		type versionGroup struct {
+			version  string
+			parsed   []int
+			total    int
+			lost     int
+			outdated bool
+		}
+		groups := make(map[string]*versionGroup)
+		var outdated []map[string]interface{}
+		records := make([]map[string]interface{}, 0, len(instances))
+		for _, instance := range instances {
+			version := stringOrNA(instance, "agent_version")
+			if version == "N/A" || version == "" {
+				version = "unknown"
+			}
+
+			g, ok := groups[version]
+			if !ok {
+				g = &versionGroup{version: version}
+				g.parsed, _ = parseVersion(version)
+				g.outdated = minimum != nil && (g.parsed == nil || compareVersions(g.parsed, minimum) < 0)
+				groups[version] = g
+			}
+			g.total++
+			if instance["status"] == "Lost" {
+				g.lost++
+			}
+			if g.outdated {
+				outdated = append(outdated, instance)
+			}
+
+			records = append(records, map[string]interface{}{
+				"id":            instance["id"],
+				"hostname":      instance["hostname"],
+				"service_hash":  instance["service_hash"],
+				"agent_version": version,
+				"status":        instance["status"],
+				"last_seen_at":  instance["last_seen_at"],
+				"outdated":      g.outdated,
+			})
+		}
+
+		switch outputFormat {
+		case "json":
+			data, _ := json.MarshalIndent(records, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		case "csv":
+			columns := []string{"id", "hostname", "service_hash", "agent_version", "status", "last_seen_at", "outdated"}
+			w := csv.NewWriter(os.Stdout)
+			w.Write(columns)
+			for _, record := range records {
+				row := make([]string, 0, len(columns))
+				for _, col := range columns {
+					value := ""
+					if v := record[col]; v != nil {
+						value = fmt.Sprintf("%v", v)
+					}
+					row = append(row, value)
+				}
+				w.Write(row)
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to write CSV: %w", err)
+			}
+			return nil
+		}
+
+		if len(instances) == 0 {
+			fmt.Println("No instances found.")
+			return nil
+		}
+
+		// Newest version first, unparseable versions last
+		sorted := make([]*versionGroup, 0, len(groups))
+		for _, g := range groups {
+			sorted = append(sorted, g)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			a, b := sorted[i].parsed, sorted[j].parsed
+			if a == nil || b == nil {
+				return b == nil && a != nil
+			}
+			return compareVersions(a, b) > 0
+		})
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tINSTANCES\tLOST\tSTATUS")
+		fmt.Fprintln(w, "-------\t---------\t----\t------")
+		for _, g := range sorted {
+			status := "OK"
+			if g.outdated {
+				status = "Outdated"
+			} else if minimum == nil {
+				status = "-"
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", g.version, g.total, g.lost, status)
+		}
+		w.Flush()
+
+		if minimum == nil {
+			fmt.Printf("\n%d instance(s) on %d agent version(s).\n", len(instances), len(groups))
+			return nil
+		}
+
+		if len(outdated) == 0 {
+			fmt.Printf("\nAll %d instance(s) run agent %s or newer.\n", len(instances), minVersion)
+			return nil
+		}
+
+		fmt.Printf("\nInstances below agent %s:\n", minVersion)
+		instanceTableWriter(outdated, nil)
+		fmt.Printf("\n%d of %d instance(s) outdated.\n", len(outdated), len(instances))
+
+		return nil
+	},
+}
+
+func init() {
+	instancesCmd.AddCommand(instancesVersionsCmd)
+
+	// Versions command flags
+	instancesVersionsCmd.Flags().String("min-version", "", "Flag instances running an agent older than this version (e.g. 1.4.0)")
+	instancesVersionsCmd.Flags().StringP("output", "o", "table", "Output format (table, json, csv)")
+}
@@ -4,11 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
 	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/spf13/cobra"
 )
@@ -33,8 +33,7 @@ var ikListCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/integration-keys", token)
 		if err != nil {
@@ -59,8 +58,8 @@ var ikListCmd = &cobra.Command{
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tNAME\tSTATUS\tLAST USED\tEXPIRES AT")
-		fmt.Fprintln(w, "----\t----\t------\t---------\t----------")
+		fmt.Fprintln(w, "ID\tNAME\tSTATUS\tSCOPES\tLAST USED\tEXPIRES AT")
+		fmt.Fprintln(w, "----\t----\t------\t------\t---------\t----------")
 
 		for _, k := range keys {
 			lastUsed := "Never"
@@ -80,20 +79,106 @@ var ikListCmd = &cobra.Command{
 				status = "Enabled"
 			}
 
-			fmt.Fprintf(w, "%v\t%v\t%s\t%s\t%s\n", k["key_id"], k["name"], status, lastUsed, expiresAt)
+			fmt.Fprintf(w, "%v\t%v\t%s\t%s\t%s\t%s\n", k["key_id"], k["name"], status, integrationKeyScopes(k), lastUsed, expiresAt)
 		}
 		w.Flush()
 		return nil
 	},
 }
 
+// findIntegrationKey returns the integration key with the given ID
+func findIntegrationKey(apiClient client.APIClient, token, keyID string) (map[string]interface{}, error) {
+	response, err := apiClient.GetWithAuth("/integration-keys", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list integration keys: %w", err)
+	}
+	for _, key := range parseArrayResponse(response) {
+		if fmt.Sprintf("%v", key["key_id"]) == keyID {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("integration key %s not found", keyID)
+}
+
+// integrationKeyScopes describes what an integration key may ingest
+func integrationKeyScopes(key map[string]interface{}) string {
+	scopes, _ := key["scopes"].(map[string]interface{})
+	var parts []string
+	if events, ok := scopes["event_ids"].([]interface{}); ok && len(events) > 0 {
+		ids := make([]string, 0, len(events))
+		for _, id := range events {
+			ids = append(ids, fmt.Sprintf("%v", id))
+		}
+		parts = append(parts, "events: "+strings.Join(ids, ", "))
+	}
+	if groupID := stringOrNA(scopes, "service_group_id"); groupID != "N/A" && groupID != "" {
+		parts = append(parts, "service group: "+groupID)
+	}
+	if len(parts) == 0 {
+		return "all events"
+	}
+	return strings.Join(parts, "; ")
+}
+
+var ikGetCmd = &cobra.Command{
+	Use:   "get <key-id>",
+	Short: "Show details of an integration key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyID := args[0]
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		token, err := auth.GetToken()
+		if err != nil {
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		key, err := findIntegrationKey(apiClient, token, keyID)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(key, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		status := "Disabled"
+		if enabled, _ := key["enabled"].(bool); enabled {
+			status = "Enabled"
+		}
+		createdBy := stringOrNA(key, "created_by_email")
+		if createdBy == "N/A" {
+			createdBy = stringOrNA(key, "created_by")
+		}
+
+		fmt.Printf("ID:         %v\n", key["key_id"])
+		fmt.Printf("Name:       %v\n", key["name"])
+		fmt.Printf("Status:     %s\n", status)
+		fmt.Printf("Scopes:     %s\n", integrationKeyScopes(key))
+		fmt.Printf("Created By: %s\n", createdBy)
+		fmt.Printf("Created At: %s\n", formatTimestamp(key["created_at"], "2006-01-02 15:04", "N/A"))
+		fmt.Printf("Last Used:  %s\n", formatTimestamp(key["last_used_at"], "2006-01-02 15:04", "Never"))
+		fmt.Printf("Expires At: %s\n", formatTimestamp(key["expires_at"], "2006-01-02 15:04", "Never"))
+		return nil
+	},
+}
+
 var ikCreateCmd = &cobra.Command{
 	Use:   "create <name>",
 	Short: "Create a new integration key",
-	Args:  cobra.ExactArgs(1),
+	Long: `Create a new integration key. By default the key can ingest any event; use
+--events and/or --service-group to restrict it, so that a leaked key can only
+be used for the events it was meant for.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 		expiresIn, _ := cmd.Flags().GetInt("expires-in")
+		eventIDs, _ := cmd.Flags().GetStringSlice("events")
+		serviceGroupID, _ := cmd.Flags().GetString("service-group")
 
 		if expiresIn < 0 {
 			cmd.SilenceUsage = true
@@ -105,8 +190,7 @@ var ikCreateCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		payload := map[string]interface{}{
 			"name": name,
@@ -115,14 +199,42 @@ var ikCreateCmd = &cobra.Command{
 			payload["expires_in_days"] = expiresIn
 		}
 
+		scopes := map[string]interface{}{}
+		var events []interface{}
+		for _, id := range eventIDs {
+			if id = strings.TrimSpace(id); id != "" {
+				events = append(events, id)
+			}
+		}
+		if len(events) > 0 {
+			scopes["event_ids"] = events
+		}
+		if serviceGroupID != "" {
+			if !looksLikeID(serviceGroupID) {
+				if serviceGroupID, err = resolveServiceGroupID(serviceGroupID); err != nil {
+					return err
+				}
+			}
+			scopes["service_group_id"] = serviceGroupID
+		}
+		if len(scopes) > 0 {
+			payload["scopes"] = scopes
+		}
+
 		response, err := apiClient.PostWithAuth("/integration-keys", payload, token)
 		if err != nil {
 			return fmt.Errorf("failed to create integration key: %w", err)
 		}
 
 		fmt.Printf("✓ Integration key created successfully\n")
-		fmt.Printf("Name: %v\n", response["name"])
-		fmt.Printf("Key:  %v\n", response["key"])
+		fmt.Printf("Name:   %v\n", response["name"])
+		fmt.Printf("Key:    %v\n", response["key"])
+		// Only the scopes the server reports are shown, since it may ignore them
+		keyScopes := integrationKeyScopes(response)
+		fmt.Printf("Scopes: %s\n", keyScopes)
+		if len(scopes) > 0 && keyScopes == "all events" {
+			fmt.Fprintf(os.Stderr, "Warning: the server did not report the requested scopes; the key may accept any event\n")
+		}
 		fmt.Println("\nIMPORTANT: Store this key safely. It will not be shown again.")
 		return nil
 	},
@@ -139,8 +251,7 @@ var ikDeleteCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/integration-keys/%s", keyID), token)
 		if err != nil {
@@ -155,20 +266,32 @@ var ikDeleteCmd = &cobra.Command{
 var ikRotateCmd = &cobra.Command{
 	Use:   "rotate <key-id>",
 	Short: "Rotate an integration key (generate a new key value)",
-	Args:  cobra.ExactArgs(1),
+	Long: `Generate a new value for an integration key. By default the old value stops
+working immediately; with --overlap it keeps being accepted for the given
+period so senders can be switched over without dropping events.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		keyID := args[0]
 		outputFormat, _ := cmd.Flags().GetString("output")
+		overlap, _ := cmd.Flags().GetDuration("overlap")
+
+		if overlap < 0 {
+			return fmt.Errorf("--overlap cannot be negative")
+		}
 
 		token, err := auth.GetToken()
 		if err != nil {
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
+
+		var payload interface{}
+		if overlap > 0 {
+			payload = map[string]interface{}{"overlap_seconds": int(overlap.Seconds())}
+		}
 
-		response, err := apiClient.PatchWithAuth(fmt.Sprintf("/integration-keys/%s/rotate", keyID), nil, token)
+		response, err := apiClient.PatchWithAuth(fmt.Sprintf("/integration-keys/%s/rotate", keyID), payload, token)
 		if err != nil {
 			return fmt.Errorf("failed to rotate integration key: %w", err)
 		}
@@ -182,6 +305,14 @@ var ikRotateCmd = &cobra.Command{
 		fmt.Printf("✓ Integration key rotated successfully\n")
 		fmt.Printf("Name: %v\n", response["name"])
 		fmt.Printf("Key:  %v\n", response["key"])
+		if overlap > 0 {
+			// Only the server knows whether it honoured the overlap
+			if oldExpiry := formatTimestamp(response["previous_key_expires_at"], "2006-01-02 15:04 MST", ""); oldExpiry != "" {
+				fmt.Printf("\nThe previous key remains valid until %s.\n", oldExpiry)
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: the server did not report an overlap; the previous key may no longer be accepted\n")
+			}
+		}
 		fmt.Println("\nIMPORTANT: Store the new key safely. It will not be shown again.")
 		return nil
 	},
@@ -200,8 +331,7 @@ var ikToggleCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.PatchWithAuth(fmt.Sprintf("/integration-keys/%s/toggle", keyID), nil, token)
 		if err != nil {
@@ -225,16 +355,84 @@ var ikToggleCmd = &cobra.Command{
 	},
 }
 
+// setIntegrationKeyEnabled toggles an integration key if it is not already in
+// the requested state. It reports whether the key was changed.
+func setIntegrationKeyEnabled(keyID string, enabled bool) (bool, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return false, err
+	}
+
+	apiClient := newAPIClient()
+
+	key, err := findIntegrationKey(apiClient, token, keyID)
+	if err != nil {
+		return false, err
+	}
+	if current, _ := key["enabled"].(bool); current == enabled {
+		return false, nil
+	}
+	if _, err := apiClient.PatchWithAuth(fmt.Sprintf("/integration-keys/%s/toggle", keyID), nil, token); err != nil {
+		return false, fmt.Errorf("failed to toggle integration key: %w", err)
+	}
+	return true, nil
+}
+
+var ikEnableCmd = &cobra.Command{
+	Use:   "enable <key-id>",
+	Short: "Enable an integration key",
+	Long:  `Enable an integration key. Does nothing if the key is already enabled.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changed, err := setIntegrationKeyEnabled(args[0], true)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			fmt.Printf("✓ Integration key is already enabled\n")
+			return nil
+		}
+		fmt.Printf("✓ Integration key enabled successfully\n")
+		return nil
+	},
+}
+
+var ikDisableCmd = &cobra.Command{
+	Use:   "disable <key-id>",
+	Short: "Disable an integration key",
+	Long:  `Disable an integration key. Does nothing if the key is already disabled.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changed, err := setIntegrationKeyEnabled(args[0], false)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			fmt.Printf("✓ Integration key is already disabled\n")
+			return nil
+		}
+		fmt.Printf("✓ Integration key disabled successfully\n")
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(integrationKeysCmd)
 	integrationKeysCmd.AddCommand(ikListCmd)
+	integrationKeysCmd.AddCommand(ikGetCmd)
 	integrationKeysCmd.AddCommand(ikCreateCmd)
 	integrationKeysCmd.AddCommand(ikRotateCmd)
 	integrationKeysCmd.AddCommand(ikToggleCmd)
+	integrationKeysCmd.AddCommand(ikEnableCmd)
+	integrationKeysCmd.AddCommand(ikDisableCmd)
 	integrationKeysCmd.AddCommand(ikDeleteCmd)
 
 	ikListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	ikGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 	ikCreateCmd.Flags().IntP("expires-in", "e", 0, "Expiration in days (0 = never)")
+	ikCreateCmd.Flags().StringSlice("events", nil, "Restrict the key to these event IDs (comma-separated)")
+	ikCreateCmd.Flags().String("service-group", "", "Restrict the key to events of this service group (ID or name)")
 	ikRotateCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	ikRotateCmd.Flags().Duration("overlap", 0, "Keep accepting the old key value for this long (e.g. 1h)")
 	ikToggleCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 }
@@ -4,15 +4,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"text/tabwriter"
 	"time"
 
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/audit"
 	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/clipboard"
 	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/certfix"
 	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/certfix/certfix-cli/pkg/models"
+	"github.com/certfix/certfix-cli/pkg/notify"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var keysCmd = &cobra.Command{
@@ -32,6 +42,7 @@ var keysListCmd = &cobra.Command{
 		log := logger.GetLogger()
 		serviceHash := args[0]
 		outputFormat, _ := cmd.Flags().GetString("output")
+		unusedOnly, _ := cmd.Flags().GetBool("unused")
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -41,11 +52,10 @@ var keysListCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		apiEndpoint := fmt.Sprintf("/services/%s/keys/list", serviceHash)
-		log.Debugf("GET %s%s", endpoint, apiEndpoint)
+		log.Debugf("GET %s%s", config.GetAPIEndpoint(), apiEndpoint)
 
 		// Make request
 		response, err := apiClient.GetWithAuth(apiEndpoint, token)
@@ -66,9 +76,37 @@ var keysListCmd = &cobra.Command{
 			}
 		}
 
-		if len(keys) == 0 {
-			fmt.Println("No API keys found.")
-			return nil
+		// Join the instances registered against each key
+		byKey, err := instancesByKey(apiClient, token, serviceHash)
+		if err != nil {
+			if unusedOnly {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to load instances needed for --unused: %w", err)
+			}
+			log.Debugf("Could not load instances: %v", err)
+		}
+		for _, key := range keys {
+			if byKey != nil {
+				key["registered_instances"] = byKey[fmt.Sprintf("%v", key["key_id"])]
+			}
+		}
+
+		if unusedOnly {
+			var unused []map[string]interface{}
+			for _, key := range keys {
+				if isUnusedKey(key, byKey) {
+					unused = append(unused, key)
+				}
+			}
+			keys = unused
+		}
+
+		keys, err = filterByTime(cmd, keys)
+		if err != nil {
+			return err
+		}
+		if err := sortItems(cmd, keySortColumns, keys); err != nil {
+			return err
 		}
 
 		// Output format
@@ -78,10 +116,15 @@ var keysListCmd = &cobra.Command{
 			return nil
 		}
 
+		if len(keys) == 0 {
+			fmt.Println("No API keys found.")
+			return nil
+		}
+
 		// Table format
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "KEY ID\tKEY NAME\tAPI KEY\tSTATUS\tEXPIRATION\tCREATED AT")
-		fmt.Fprintln(w, "------\t--------\t-------\t------\t----------\t----------")
+		fmt.Fprintln(w, "KEY ID\tKEY NAME\tAPI KEY\tSTATUS\tEXPIRATION\tLAST USED\tUSES\tINSTANCES\tCREATED AT")
+		fmt.Fprintln(w, "------\t--------\t-------\t------\t----------\t---------\t----\t---------\t----------")
 
 		for _, key := range keys {
 			keyID := fmt.Sprintf("%v", key["key_id"])
@@ -116,7 +159,9 @@ var keysListCmd = &cobra.Command{
 				}
 			}
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", keyID, keyName, apiKey, status, expiresAt, createdAt)
+			lastUsed, uses, instances := keyUsage(key, byKey)
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", keyID, keyName, apiKey, status, expiresAt, lastUsed, uses, instances, createdAt)
 		}
 		w.Flush()
 
@@ -141,8 +186,7 @@ var keysGetCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Make request
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys", serviceHash), token)
@@ -151,6 +195,16 @@ var keysGetCmd = &cobra.Command{
 			return fmt.Errorf("failed to get service keys data: %w", err)
 		}
 
+		// Join the instances registered against each key
+		byKey, _ := instancesByKey(apiClient, token, serviceHash)
+		if keys, ok := response["keys"].([]interface{}); ok && byKey != nil {
+			for _, item := range keys {
+				if key, ok := item.(map[string]interface{}); ok {
+					key["registered_instances"] = byKey[fmt.Sprintf("%v", key["key_id"])]
+				}
+			}
+		}
+
 		// Output format
 		if outputFormat == "json" {
 			data, _ := json.MarshalIndent(response, "", "  ")
@@ -170,8 +224,8 @@ var keysGetCmd = &cobra.Command{
 		if keys, ok := response["keys"].([]interface{}); ok && len(keys) > 0 {
 			fmt.Println("API Keys:")
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-			fmt.Fprintln(w, "  KEY ID\tKEY NAME\tSTATUS\tEXPIRES AT")
-			fmt.Fprintln(w, "  ------\t--------\t------\t----------")
+			fmt.Fprintln(w, "  KEY ID\tKEY NAME\tSTATUS\tEXPIRES AT\tLAST USED\tUSES\tINSTANCES")
+			fmt.Fprintln(w, "  ------\t--------\t------\t----------\t---------\t----\t---------")
 
 			for _, item := range keys {
 				if key, ok := item.(map[string]interface{}); ok {
@@ -192,7 +246,20 @@ var keysGetCmd = &cobra.Command{
 						}
 					}
 
-					fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", keyID, keyName, status, expiresAt)
+					lastUsed, uses, _ := keyUsage(key, byKey)
+					hostnames := "N/A"
+					if byKey != nil {
+						hostnames = "none"
+						if instances := byKey[keyID]; len(instances) > 0 {
+							names := make([]string, 0, len(instances))
+							for _, instance := range instances {
+								names = append(names, fmt.Sprintf("%v", instance["hostname"]))
+							}
+							hostnames = strings.Join(names, ", ")
+						}
+					}
+
+					fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\t%s\n", keyID, keyName, status, expiresAt, lastUsed, uses, hostnames)
 				}
 			}
 			w.Flush()
@@ -204,11 +271,58 @@ var keysGetCmd = &cobra.Command{
 	},
 }
 
+// instancesByKey groups the instances registered for a service by the ID of the
+// API key they authenticated with.
+func instancesByKey(apiClient client.APIClient, token, serviceHash string) (map[string][]map[string]interface{}, error) {
+	response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/instances", serviceHash), token)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string][]map[string]interface{})
+	for _, instance := range parseArrayResponse(response) {
+		keyID := stringOrNA(instance, "key_id")
+		if keyID == "N/A" {
+			continue
+		}
+		byKey[keyID] = append(byKey[keyID], instance)
+	}
+	return byKey, nil
+}
+
+// keyUsage returns the last-used time, usage count and registered instance
+// count of a key for display. byKey may be nil if instances could not be loaded.
+func keyUsage(key map[string]interface{}, byKey map[string][]map[string]interface{}) (lastUsed, uses, instances string) {
+	lastUsed = formatTimestamp(key["last_used_at"], "2006-01-02 15:04", "never")
+	uses = "N/A"
+	if key["usage_count"] != nil {
+		uses = fmt.Sprintf("%d", int(toFloat(key["usage_count"])))
+	}
+	instances = "N/A"
+	if byKey != nil {
+		instances = fmt.Sprintf("%d", len(byKey[fmt.Sprintf("%v", key["key_id"])]))
+	}
+	return lastUsed, uses, instances
+}
+
+// isUnusedKey reports whether a key has never been used and has no instances
+// registered against it.
+func isUnusedKey(key map[string]interface{}, byKey map[string][]map[string]interface{}) bool {
+	if key["last_used_at"] != nil || toFloat(key["usage_count"]) > 0 {
+		return false
+	}
+	return len(byKey[fmt.Sprintf("%v", key["key_id"])]) == 0
+}
+
 var keysAddCmd = &cobra.Command{
 	Use:   "add <service-hash>",
 	Short: "Add a new API key to a service",
-	Long:  `Add a new API key to a service with a name and expiration period.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Add a new API key to a service with a name and expiry.
+
+The expiry can be given as a number of days (--expiration), an absolute date
+(--expires-at 2026-01-01, rounded up to whole days), or set 100 years out with
+--no-expiry.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log := logger.GetLogger()
 		serviceHash := args[0]
@@ -216,6 +330,9 @@ var keysAddCmd = &cobra.Command{
 		// Get flags
 		keyName, _ := cmd.Flags().GetString("name")
 		expirationDays, _ := cmd.Flags().GetInt("expiration")
+		expiresAtRaw, _ := cmd.Flags().GetString("expires-at")
+		noExpiry, _ := cmd.Flags().GetBool("no-expiry")
+		enabled, _ := cmd.Flags().GetBool("enabled")
 
 		// Validate required fields
 		if keyName == "" {
@@ -223,10 +340,37 @@ var keysAddCmd = &cobra.Command{
 			return fmt.Errorf("key name is required (use --name)")
 		}
 
-		if expirationDays <= 0 {
-			cmd.SilenceUsage = true
-			return fmt.Errorf("expiration days must be greater than 0 (use --expiration)")
+		// Prepare payload
+		payload := map[string]interface{}{
+			"key_name": keyName,
+			"enabled":  enabled,
+		}
+
+		// The API takes the expiry as a number of days, as apply sends it
+		expiryDescription := fmt.Sprintf("expires in %d days", expirationDays)
+		switch {
+		case noExpiry:
+			expirationDays = noExpiryDays
+			expiryDescription = "never expires"
+		case expiresAtRaw != "":
+			expiresAt, err := parseExpiryDate(expiresAtRaw)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			if !expiresAt.After(time.Now()) {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("--expires-at must be in the future")
+			}
+			expirationDays = int((time.Until(expiresAt) + 24*time.Hour - 1) / (24 * time.Hour))
+			expiryDescription = "expires at " + expiresAt.Format("2006-01-02")
+		default:
+			if expirationDays <= 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("expiration days must be greater than 0 (use --expiration, --expires-at or --no-expiry)")
+			}
 		}
+		payload["expiration_days"] = expirationDays
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -236,16 +380,9 @@ var keysAddCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
-
-		// Prepare payload
-		payload := map[string]interface{}{
-			"key_name":        keyName,
-			"expiration_days": expirationDays,
-		}
+		apiClient := newAPIClient()
 
-		log.Infof("Adding API key: %s (expires in %d days)", keyName, expirationDays)
+		log.Infof("Adding API key: %s (%s)", keyName, expiryDescription)
 
 		// Make request
 		response, err := apiClient.PostWithAuth(fmt.Sprintf("/services/%s/keys", serviceHash), payload, token)
@@ -257,19 +394,94 @@ var keysAddCmd = &cobra.Command{
 		fmt.Printf("✓ API key added successfully\n")
 		fmt.Printf("Key ID:     %v\n", response["key_id"])
 		fmt.Printf("Key Name:   %v\n", response["key_name"])
-		fmt.Printf("API Key:    %v\n", response["api_key"])
-		fmt.Printf("Expires At: %v\n", response["expires_at"])
+		secret, captured := revealNewKey(cmd, "key.created", serviceHash, response)
+		fmt.Printf("API Key:    %s\n", secret)
+		fmt.Printf("Expires At: %s\n", formatTimestamp(response["expires_at"], time.RFC3339, "never"))
 		enabledStatus := "Disabled"
 		if enabled, ok := response["enabled"].(bool); ok && enabled {
 			enabledStatus = "Enabled"
 		}
 		fmt.Printf("Status:     %s\n", enabledStatus)
-		fmt.Printf("\n⚠️  Important: Save the API key now. It won't be shown again in full.\n")
+		printNewKeyNotice(captured)
 
 		return nil
 	},
 }
 
+// revealNewKey handles the secret of a newly created key: it copies it to the
+// clipboard with --copy, records an audit entry, and returns the value to print,
+// which is masked unless --show-secret was passed. The returned bool reports
+// whether the user received the secret (shown or copied).
+func revealNewKey(cmd *cobra.Command, action, serviceHash string, key map[string]interface{}) (string, bool) {
+	log := logger.GetLogger()
+	showSecret, _ := cmd.Flags().GetBool("show-secret")
+	copySecret, _ := cmd.Flags().GetBool("copy")
+	secret := fmt.Sprintf("%v", key["api_key"])
+
+	copied := false
+	if copySecret {
+		if err := clipboard.Copy(secret); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else {
+			copied = true
+		}
+	}
+
+	err := audit.Record(action, map[string]string{
+		"service_hash":  serviceHash,
+		"key_id":        fmt.Sprintf("%v", key["key_id"]),
+		"key_name":      fmt.Sprintf("%v", key["key_name"]),
+		"secret_shown":  strconv.FormatBool(showSecret),
+		"secret_copied": strconv.FormatBool(copied),
+	})
+	if err != nil {
+		log.Warnf("Could not write audit log: %v", err)
+	}
+
+	display := secret
+	if !showSecret {
+		display = maskSecret(secret) + " (hidden, use --show-secret to display)"
+	}
+	if copied {
+		display += " [copied to clipboard]"
+	}
+	return display, showSecret || copied
+}
+
+// printNewKeyNotice reminds the user that the secret cannot be retrieved later
+func printNewKeyNotice(captured bool) {
+	if !captured {
+		fmt.Printf("\n⚠️  Important: The API key was not displayed and won't be shown again in full.\n")
+		fmt.Printf("   Use --copy or --show-secret when creating keys, or 'certfix keys rotate' to issue a new one.\n")
+		return
+	}
+	fmt.Printf("\n⚠️  Important: Save the API key now. It won't be shown again in full.\n")
+}
+
+// maskSecret hides all but the first and last four characters of a secret
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", len(secret)-8) + secret[len(secret)-4:]
+}
+
+// noExpiryDays is the expiry given to keys created with --no-expiry, since
+// the API has no way to create a key that never expires
+const noExpiryDays = certfix.NoExpiryDays
+
+// parseExpiryDate accepts either a plain date (interpreted as midnight local
+// time) or a full RFC3339 timestamp.
+func parseExpiryDate(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s' (use YYYY-MM-DD or RFC3339)", value)
+}
+
 var keysToggleCmd = &cobra.Command{
 	Use:   "toggle <service-hash> <key-id>",
 	Short: "Toggle an API key (enable/disable)",
@@ -287,8 +499,7 @@ var keysToggleCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		log.Infof("Toggling API key: %s", keyID)
 
@@ -315,6 +526,7 @@ var keysToggleCmd = &cobra.Command{
 var keysEnableCmd = &cobra.Command{
 	Use:   "enable <service-hash> <key-id>",
 	Short: "Enable an API key",
+	Long:  `Enable an API key. Does nothing if the key is already enabled.`,
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceHash := args[0]
@@ -328,18 +540,19 @@ var keysEnableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
-		// Make request (toggle endpoint toggles the current state)
-		_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s/keys/%s/toggle", serviceHash, keyID), nil, token)
+		changed, err := setKeyEnabled(apiClient, token, serviceHash, keyID, true)
 		if err != nil {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to toggle API key: %w", err)
+			return err
 		}
 
-		fmt.Printf("✓ API key toggled\n")
-		fmt.Printf("Note: The toggle endpoint switches the current state. Use 'get' or 'list' to verify the new status.\n")
+		if !changed {
+			fmt.Printf("✓ API key is already enabled\n")
+			return nil
+		}
+		fmt.Printf("✓ API key enabled successfully\n")
 		return nil
 	},
 }
@@ -347,6 +560,7 @@ var keysEnableCmd = &cobra.Command{
 var keysDisableCmd = &cobra.Command{
 	Use:   "disable <service-hash> <key-id>",
 	Short: "Disable an API key",
+	Long:  `Disable an API key. Does nothing if the key is already disabled.`,
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceHash := args[0]
@@ -360,42 +574,61 @@ var keysDisableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
-		// Make request (toggle endpoint toggles the current state)
-		_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s/keys/%s/toggle", serviceHash, keyID), nil, token)
+		changed, err := setKeyEnabled(apiClient, token, serviceHash, keyID, false)
 		if err != nil {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to toggle API key: %w", err)
+			return err
 		}
 
-		fmt.Printf("✓ API key toggled\n")
-		fmt.Printf("Note: The toggle endpoint switches the current state. Use 'get' or 'list' to verify the new status.\n")
+		if !changed {
+			fmt.Printf("✓ API key is already disabled\n")
+			return nil
+		}
+		fmt.Printf("✓ API key disabled successfully\n")
 		return nil
 	},
 }
 
+// setKeyEnabled brings an API key to the requested state. The API only offers a
+// toggle endpoint, so the current state is fetched first and the key is only
+// toggled when needed. It reports whether the key was changed.
+func setKeyEnabled(apiClient client.APIClient, token, serviceHash, keyID string, enabled bool) (bool, error) {
+	response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys/list", serviceHash), token)
+	if err != nil {
+		return false, fmt.Errorf("failed to list service keys: %w", err)
+	}
+
+	for _, key := range parseArrayResponse(response) {
+		if fmt.Sprintf("%v", key["key_id"]) != keyID {
+			continue
+		}
+		if current, _ := key["enabled"].(bool); current == enabled {
+			return false, nil
+		}
+		if _, err := apiClient.PutWithAuth(fmt.Sprintf("/services/%s/keys/%s/toggle", serviceHash, keyID), nil, token); err != nil {
+			return false, fmt.Errorf("failed to toggle API key: %w", err)
+		}
+		return true, nil
+	}
+
+	return false, fmt.Errorf("API key %s not found for service %s", keyID, serviceHash)
+}
+
 var keysDeleteCmd = &cobra.Command{
-	Use:     "delete <service-hash> <key-id>",
+	Use:     "delete <service-hash> [key-id]",
 	Aliases: []string{"rm", "remove"},
 	Short:   "Delete an API key",
-	Args:    cobra.ExactArgs(2),
+	Long:    `Delete an API key by ID, or by name with --name.`,
+	Args:    cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log := logger.GetLogger()
 		serviceHash := args[0]
-		keyID := args[1]
+		keyName, _ := cmd.Flags().GetString("name")
 
-		// Confirm deletion
-		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Are you sure you want to delete API key %s? (y/N): ", keyID)
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
+		if (len(args) == 2) == (keyName != "") {
+			return fmt.Errorf("specify either a key ID or --name")
 		}
 
 		// Get authentication token
@@ -406,8 +639,32 @@ var keysDeleteCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
+
+		var keyID string
+		if keyName != "" {
+			keyID, err = resolveKeyID(apiClient, token, serviceHash, keyName)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+		} else {
+			keyID = args[1]
+		}
+
+		if err := requirePermission(cmd, "key:delete"); err != nil {
+			return err
+		}
+
+		// Confirm deletion
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete API key %s?", keyID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
+		}
 
 		log.Infof("Deleting API key: %s", keyID)
 
@@ -423,6 +680,502 @@ var keysDeleteCmd = &cobra.Command{
 	},
 }
 
+// resolveKeyID finds the ID of the key with the given name, failing if no key or
+// more than one key has that name.
+func resolveKeyID(apiClient client.APIClient, token, serviceHash, keyName string) (string, error) {
+	response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys/list", serviceHash), token)
+	if err != nil {
+		return "", fmt.Errorf("failed to list service keys: %w", err)
+	}
+
+	var matches []string
+	for _, key := range parseArrayResponse(response) {
+		if fmt.Sprintf("%v", key["key_name"]) == keyName {
+			matches = append(matches, fmt.Sprintf("%v", key["key_id"]))
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no API key named '%s' found for service %s", keyName, serviceHash)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%d API keys are named '%s' (%s); delete by key ID instead", len(matches), keyName, strings.Join(matches, ", "))
+}
+
+var keysPruneCmd = &cobra.Command{
+	Use:   "prune <service-hash>",
+	Short: "Delete disabled and/or expired API keys",
+	Long: `Delete every API key of a service that matches the given criteria. A key is
+removed if it matches any of the selected criteria. The matching keys are
+listed before anything is deleted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceHash := args[0]
+		disabled, _ := cmd.Flags().GetBool("disabled")
+		expired, _ := cmd.Flags().GetBool("expired")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if !disabled && !expired {
+			return fmt.Errorf("select keys to prune with --disabled and/or --expired")
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys/list", serviceHash), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list service keys: %w", err)
+		}
+
+		var keyIDs []string
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "KEY ID\tKEY NAME\tSTATUS\tEXPIRES AT\tREASON")
+		fmt.Fprintln(w, "------\t--------\t------\t----------\t------")
+		for _, key := range parseArrayResponse(response) {
+			var reasons []string
+			isEnabled, _ := key["enabled"].(bool)
+			if disabled && !isEnabled {
+				reasons = append(reasons, "disabled")
+			}
+			if expiresAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", key["expires_at"])); expired && err == nil && time.Now().After(expiresAt) {
+				reasons = append(reasons, "expired")
+			}
+			if len(reasons) == 0 {
+				continue
+			}
+
+			status := "Disabled"
+			if isEnabled {
+				status = "Enabled"
+			}
+			keyID := fmt.Sprintf("%v", key["key_id"])
+			keyIDs = append(keyIDs, keyID)
+			fmt.Fprintf(w, "%s\t%v\t%s\t%s\t%s\n", keyID, key["key_name"], status, formatTimestamp(key["expires_at"], "2006-01-02", "never"), strings.Join(reasons, ", "))
+		}
+
+		if len(keyIDs) == 0 {
+			fmt.Println("No API keys match the prune criteria.")
+			return nil
+		}
+		w.Flush()
+		fmt.Printf("\n%d API key(s) will be deleted.\n", len(keyIDs))
+
+		if dryRun {
+			fmt.Println("Dry run: no keys deleted.")
+			return nil
+		}
+
+		if err := requirePermission(cmd, "key:delete"); err != nil {
+			return err
+		}
+
+		// Confirm deletion
+		confirmed, err := confirm(cmd, "Are you sure you want to delete these API keys?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
+		}
+
+		cmd.SilenceUsage = true
+		return runServiceBulk(keyIDs, "Deleting API key", func(keyID string) error {
+			_, err := apiClient.DeleteWithAuth(fmt.Sprintf("/services/%s/keys/%s", serviceHash, keyID), token)
+			return err
+		})
+	},
+}
+
+var keysRotateCmd = &cobra.Command{
+	Use:   "rotate <service-hash> <key-id>",
+	Short: "Replace an API key with a new one",
+	Long: `Create a replacement API key with the same name and expiry date as an existing
+key, print the new secret once, and retire the old key.
+
+By default the old key is disabled immediately. Use --grace to let the old key
+keep working for a while (it is set to expire after the grace period), or
+--delete-old to delete it outright.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+		serviceHash := args[0]
+		keyID := args[1]
+
+		grace, _ := cmd.Flags().GetDuration("grace")
+		deleteOld, _ := cmd.Flags().GetBool("delete-old")
+		expirationDays, _ := cmd.Flags().GetInt("expiration")
+
+		if grace > 0 && deleteOld {
+			return fmt.Errorf("--grace and --delete-old cannot be used together")
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		// Find the key being replaced
+		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys/list", serviceHash), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list service keys: %w", err)
+		}
+		var oldKey map[string]interface{}
+		for _, key := range parseArrayResponse(response) {
+			if fmt.Sprintf("%v", key["key_id"]) == keyID {
+				oldKey = key
+				break
+			}
+		}
+		if oldKey == nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("API key %s not found for service %s", keyID, serviceHash)
+		}
+
+		// Keep the same expiry date unless overridden. A key that never
+		// expires is replaced by one created as with 'keys add --no-expiry'.
+		if expirationDays <= 0 {
+			expirationDays = noExpiryDays
+			if oldKey["expires_at"] != nil {
+				expiresAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", oldKey["expires_at"]))
+				if err != nil {
+					cmd.SilenceUsage = true
+					return fmt.Errorf("failed to read the expiry of API key %s (use --expiration): %w", keyID, err)
+				}
+				expirationDays = int(time.Until(expiresAt).Hours()/24 + 0.5)
+				if expirationDays < 1 {
+					expirationDays = 1
+				}
+			}
+		}
+
+		payload := map[string]interface{}{
+			"key_name":        oldKey["key_name"],
+			"expiration_days": expirationDays,
+		}
+
+		log.Infof("Creating replacement for API key: %s", keyID)
+
+		newKey, err := apiClient.PostWithAuth(fmt.Sprintf("/services/%s/keys", serviceHash), payload, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to create replacement API key (old key left unchanged): %w", err)
+		}
+
+		fmt.Printf("✓ Replacement API key created\n")
+		fmt.Printf("Key ID:     %v\n", newKey["key_id"])
+		fmt.Printf("Key Name:   %v\n", newKey["key_name"])
+		secret, captured := revealNewKey(cmd, "key.rotated", serviceHash, newKey)
+		fmt.Printf("API Key:    %s\n", secret)
+		fmt.Printf("Expires At: %v\n", newKey["expires_at"])
+		printNewKeyNotice(captured)
+		fmt.Println()
+
+		// Retire the old key
+		switch {
+		case deleteOld:
+			if _, err := apiClient.DeleteWithAuth(fmt.Sprintf("/services/%s/keys/%s", serviceHash, keyID), token); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("new key created but failed to delete old key %s: %w", keyID, err)
+			}
+			fmt.Printf("✓ Old API key %s deleted\n", keyID)
+		case grace > 0:
+			retireAt := time.Now().Add(grace).UTC()
+			update := map[string]interface{}{"expires_at": retireAt.Format(time.RFC3339)}
+			if _, err := apiClient.PutWithAuth(fmt.Sprintf("/services/%s/keys/%s", serviceHash, keyID), update, token); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("new key created but failed to shorten expiry of old key %s: %w", keyID, err)
+			}
+			fmt.Printf("✓ Old API key %s will expire at %s\n", keyID, retireAt.Format("2006-01-02 15:04 MST"))
+		default:
+			if _, err := setKeyEnabled(apiClient, token, serviceHash, keyID, false); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("new key created but failed to disable old key %s: %w", keyID, err)
+			}
+			fmt.Printf("✓ Old API key %s disabled\n", keyID)
+		}
+
+		return nil
+	},
+}
+
+// expiringKeysMessage summarises the result of keys expiring for notifications
+func expiringKeysMessage(results []map[string]interface{}, failures []string, days int) notify.Message {
+	msg := notify.Message{
+		Title:    fmt.Sprintf("%d API key(s) expiring within %d days", len(results), days),
+		Severity: notify.SeverityWarning,
+	}
+	for _, r := range results {
+		when := fmt.Sprintf("in %v days", r["days_left"])
+		if expiresAt, err := time.Parse(time.RFC3339, stringField(r, "expires_at")); err == nil && time.Now().After(expiresAt) {
+			when = "already expired"
+			msg.Severity = notify.SeverityError
+		}
+		msg.Lines = append(msg.Lines, fmt.Sprintf("%v (%v): key %v expires %s, %s", r["service_name"], r["service_hash"], r["key_name"], formatTimestamp(r["expires_at"], "2006-01-02", "N/A"), when))
+	}
+	for _, failure := range failures {
+		msg.Lines = append(msg.Lines, "Failed to check "+failure)
+	}
+	return msg
+}
+
+// expiringKeys fetches the keys of the given services concurrently and returns
+// those expiring before cutoff (including already expired keys), soonest
+// first, along with a description of every service whose keys could not be
+// listed.
+func expiringKeys(apiClient client.APIClient, token string, services []map[string]interface{}, cutoff time.Time, includeDisabled bool) ([]map[string]interface{}, []string) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		results  []map[string]interface{}
+		failures []string
+	)
+	sem := make(chan struct{}, keysExpiringConcurrency)
+	for _, svc := range services {
+		wg.Add(1)
+		go func(svc map[string]interface{}) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			hash := fmt.Sprintf("%v", svc["service_hash"])
+			keysResponse, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys/list", hash), token)
+			if err != nil {
+				mu.Lock()
+				failures = append(failures, fmt.Sprintf("%s: %v", hash, err))
+				mu.Unlock()
+				return
+			}
+
+			for _, key := range parseArrayResponse(keysResponse) {
+				if enabled, _ := key["enabled"].(bool); !enabled && !includeDisabled {
+					continue
+				}
+				expiresAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", key["expires_at"]))
+				if err != nil || expiresAt.After(cutoff) {
+					continue
+				}
+				mu.Lock()
+				results = append(results, map[string]interface{}{
+					"service_hash": hash,
+					"service_name": svc["service_name"],
+					"key_id":       key["key_id"],
+					"key_name":     key["key_name"],
+					"enabled":      key["enabled"],
+					"expires_at":   expiresAt.Format(time.RFC3339),
+					"days_left":    int(time.Until(expiresAt).Hours() / 24),
+				})
+				mu.Unlock()
+			}
+		}(svc)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i]["expires_at"].(string) < results[j]["expires_at"].(string)
+	})
+	return results, failures
+}
+
+var keysExpiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "List API keys expiring soon across all services",
+	Long: `Check the API keys of every service and report the ones that expire within the
+given number of days (including keys that have already expired), soonest first.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		includeDisabled, _ := cmd.Flags().GetBool("include-disabled")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		notifiers, err := notifiersFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth("/services", token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+		services := parseArrayResponse(response)
+
+		cutoff := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+		results, failures := expiringKeys(apiClient, token, services, cutoff, includeDisabled)
+
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "Warning: failed to list keys for service %s\n", failure)
+		}
+
+		if len(results) > 0 || len(failures) > 0 {
+			sendNotification(notifiers, expiringKeysMessage(results, failures, days))
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(results, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(results) == 0 {
+			fmt.Printf("No API keys expire within %d days (%d services checked).\n", days, len(services))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tSERVICE HASH\tKEY NAME\tKEY ID\tEXPIRES AT\tDAYS LEFT")
+		fmt.Fprintln(w, "-------\t------------\t--------\t------\t----------\t---------")
+		for _, r := range results {
+			daysLeft := fmt.Sprintf("%d", r["days_left"])
+			if expiresAt, err := time.Parse(time.RFC3339, stringField(r, "expires_at")); err == nil && time.Now().After(expiresAt) {
+				daysLeft = "expired"
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%s\t%s\n", r["service_name"], r["service_hash"], r["key_name"], r["key_id"], formatTimestamp(r["expires_at"], "2006-01-02", "N/A"), daysLeft)
+		}
+		w.Flush()
+		fmt.Printf("\n%d key(s) expiring within %d days across %d services.\n", len(results), days, len(services))
+
+		return nil
+	},
+}
+
+// keysExpiringConcurrency limits parallel requests made by 'keys expiring'
+const keysExpiringConcurrency = 8
+
+var keysBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap <service-hash> <key-id>",
+	Short: "Write an agent configuration file for an API key",
+	Long: `Write a ready-to-use agent configuration containing the API endpoint, service
+hash and API key, so provisioning a new host is a single file copy.
+
+If the API only returns a masked key, pass the full secret with --api-key
+(for example the value printed by 'keys add' or 'keys rotate').`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceHash := args[0]
+		keyID := args[1]
+		outFile, _ := cmd.Flags().GetString("out")
+		apiKey, _ := cmd.Flags().GetString("api-key")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if outFile != "-" && !force {
+			if _, err := os.Stat(outFile); err == nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("file %s already exists (use --force to overwrite)", outFile)
+			}
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys", serviceHash), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get service keys data: %w", err)
+		}
+
+		var key map[string]interface{}
+		if keys, ok := response["keys"].([]interface{}); ok {
+			for _, item := range keys {
+				if k, ok := item.(map[string]interface{}); ok && fmt.Sprintf("%v", k["key_id"]) == keyID {
+					key = k
+					break
+				}
+			}
+		}
+		if key == nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("API key %s not found for service %s", keyID, serviceHash)
+		}
+		if enabled, _ := key["enabled"].(bool); !enabled {
+			fmt.Fprintf(os.Stderr, "Warning: API key %s is disabled; the agent will not be able to authenticate until it is enabled\n", keyID)
+		}
+
+		if apiKey == "" {
+			apiKey = stringOrNA(key, "api_key")
+			if apiKey == "N/A" || strings.Contains(apiKey, "*") || strings.HasSuffix(apiKey, "...") {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("the API does not return the full secret for key %s; pass it with --api-key", keyID)
+			}
+		}
+
+		agentConfig := models.AgentConfig{
+			Endpoint:    strings.TrimSuffix(config.GetDefaultEndpoint(), "/"),
+			ServiceHash: serviceHash,
+			KeyID:       keyID,
+			APIKey:      apiKey,
+		}
+		if service, ok := response["service"].(map[string]interface{}); ok {
+			agentConfig.ServiceName = stringOrNA(service, "service_name")
+		}
+
+		data, err := yaml.Marshal(&agentConfig)
+		if err != nil {
+			return fmt.Errorf("failed to encode agent configuration: %w", err)
+		}
+		data = append([]byte("# certfix agent configuration generated by 'certfix keys bootstrap'\n"), data...)
+
+		if outFile == "-" {
+			fmt.Print(string(data))
+			return nil
+		}
+
+		// The file contains a secret, so keep it private to the owner
+		if err := os.WriteFile(outFile, data, 0600); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to write agent configuration: %w", err)
+		}
+
+		fmt.Printf("✓ Agent configuration written to %s\n", outFile)
+		fmt.Printf("\n⚠️  Important: This file contains the API key. Copy it to the host securely and delete local copies.\n")
+		return nil
+	},
+}
+
+// keySortColumns are the --sort-by columns of 'keys list'
+var keySortColumns = sortColumns{
+	"key_id":     "key_id",
+	"name":       "key_name",
+	"status":     "enabled",
+	"expires_at": "expires_at",
+	"last_used":  "last_used_at",
+	"uses":       "usage_count",
+	"created_at": "created_at",
+}
+
 func init() {
 	rootCmd.AddCommand(keysCmd)
 
@@ -434,19 +1187,63 @@ func init() {
 	keysCmd.AddCommand(keysEnableCmd)
 	keysCmd.AddCommand(keysDisableCmd)
 	keysCmd.AddCommand(keysDeleteCmd)
+	keysCmd.AddCommand(keysRotateCmd)
+	keysCmd.AddCommand(keysExpiringCmd)
+	keysCmd.AddCommand(keysBootstrapCmd)
+	keysCmd.AddCommand(keysPruneCmd)
 
 	// List command flags
 	keysListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	enableSorting(keySortColumns, keysListCmd)
+	enableTimeFilters(true, keysListCmd)
+	keysListCmd.Flags().Bool("unused", false, "Show only keys that have never been used and have no registered instances")
 
 	// Get command flags
 	keysGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 
 	// Add command flags
 	keysAddCmd.Flags().StringP("name", "n", "", "Name of the API key (required)")
-	keysAddCmd.Flags().IntP("expiration", "e", 365, "Expiration period in days (required)")
+	keysAddCmd.Flags().IntP("expiration", "e", 365, "Expiration period in days")
+	keysAddCmd.Flags().String("expires-at", "", "Absolute expiry date (YYYY-MM-DD or RFC3339)")
+	keysAddCmd.Flags().Bool("no-expiry", false, "Create a key that expires in 100 years")
+	keysAddCmd.Flags().Bool("enabled", true, "Create the key enabled (use --enabled=false to create it disabled)")
 	keysAddCmd.MarkFlagRequired("name")
-	keysAddCmd.MarkFlagRequired("expiration")
+	keysAddCmd.MarkFlagsMutuallyExclusive("expiration", "expires-at", "no-expiry")
 
 	// Delete command flags
 	keysDeleteCmd.Flags().BoolP("force", "f", false, "Force deletion without confirmation")
+	keysDeleteCmd.Flags().StringP("name", "n", "", "Delete the key with this name instead of by ID")
+
+	// Prune command flags
+	keysPruneCmd.Flags().Bool("disabled", false, "Delete disabled keys")
+	keysPruneCmd.Flags().Bool("expired", false, "Delete expired keys")
+	keysPruneCmd.Flags().Bool("dry-run", false, "Show the keys that would be deleted without deleting them")
+	keysPruneCmd.Flags().BoolP("force", "f", false, "Delete without confirmation")
+
+	// Rotate command flags
+	keysRotateCmd.Flags().Duration("grace", 0, "Keep the old key valid for this long instead of disabling it (e.g. 24h)")
+	keysRotateCmd.Flags().Bool("delete-old", false, "Delete the old key instead of disabling it")
+	keysRotateCmd.Flags().IntP("expiration", "e", 0, "Expiration period in days for the new key (default: same expiry as the old key)")
+
+	// Expiring command flags
+	keysExpiringCmd.Flags().IntP("days", "d", 30, "Report keys expiring within this many days")
+	keysExpiringCmd.Flags().Bool("include-disabled", false, "Include disabled keys")
+	keysExpiringCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	enableNotify(keysExpiringCmd)
+
+	// Bootstrap command flags
+	keysBootstrapCmd.Flags().String("out", "agent.yaml", "Output file ('-' for stdout)")
+	keysBootstrapCmd.Flags().String("api-key", "", "Full API key secret, if the API only returns a masked key")
+	keysBootstrapCmd.Flags().BoolP("force", "f", false, "Overwrite the output file if it exists")
+
+	// Secret handling flags for commands that create keys
+	for _, c := range []*cobra.Command{keysAddCmd, keysRotateCmd} {
+		c.Flags().Bool("show-secret", false, "Print the full API key instead of a masked value")
+		c.Flags().Bool("copy", false, "Copy the API key to the clipboard")
+	}
+
+	// Allow selecting the service by name
+	enableIdentifierResolver(api.KindService, keysListCmd, keysGetCmd)
+	enableExactIdentifierResolver(api.KindService, keysAddCmd, keysToggleCmd, keysEnableCmd, keysDisableCmd, keysDeleteCmd, keysRotateCmd, keysBootstrapCmd, keysPruneCmd)
+	enableServiceByName(keysListCmd, keysGetCmd, keysAddCmd, keysToggleCmd, keysEnableCmd, keysDisableCmd, keysDeleteCmd, keysRotateCmd, keysBootstrapCmd, keysPruneCmd)
 }
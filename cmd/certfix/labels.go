@@ -0,0 +1,144 @@
+package certfix
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/certfix/certfix-cli/pkg/client"
+)
+
+// labelKeyPattern restricts label keys to a portable set of characters
+var labelKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+
+// parseLabels parses --label key=value values
+func parseLabels(values []string) (map[string]string, error) {
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !labelKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid label '%s': use key=value with a key of letters, digits, '.', '_', '-' or '/'", value)
+		}
+		labels[key] = strings.TrimSpace(val)
+	}
+	return labels, nil
+}
+
+// serviceLabels returns the labels of a service as returned by the API
+func serviceLabels(svc map[string]interface{}) map[string]string {
+	labels := make(map[string]string)
+	if raw, ok := svc["labels"].(map[string]interface{}); ok {
+		for key := range raw {
+			labels[key] = stringField(raw, key)
+		}
+	}
+	return labels
+}
+
+// formatLabels renders labels as a sorted key=value list
+func formatLabels(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for key, value := range labels {
+		parts = append(parts, key+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// labelRequirement is one comma-separated term of a selector
+type labelRequirement struct {
+	key    string
+	value  string
+	negate bool // != or !key
+	exists bool // key or !key, without a value
+}
+
+// labelSelector selects resources by label, e.g. "team=payments,env!=prod".
+// A term is key=value (or key==value), key!=value, key (the label is set) or
+// !key (it is not). All terms must match.
+type labelSelector []labelRequirement
+
+// parseSelector parses a --selector value
+func parseSelector(s string) (labelSelector, error) {
+	var selector labelSelector
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var req labelRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			req.key, req.value, _ = strings.Cut(term, "!=")
+			req.negate = true
+		case strings.Contains(term, "=="):
+			req.key, req.value, _ = strings.Cut(term, "==")
+		case strings.Contains(term, "="):
+			req.key, req.value, _ = strings.Cut(term, "=")
+		case strings.HasPrefix(term, "!"):
+			req.key, req.negate, req.exists = term[1:], true, true
+		default:
+			req.key, req.exists = term, true
+		}
+		req.key, req.value = strings.TrimSpace(req.key), strings.TrimSpace(req.value)
+		if !labelKeyPattern.MatchString(req.key) {
+			return nil, fmt.Errorf("invalid selector term '%s': use key=value, key!=value, key or !key", term)
+		}
+		selector = append(selector, req)
+	}
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("empty selector")
+	}
+	return selector, nil
+}
+
+// matches reports whether labels satisfy every term of the selector
+func (s labelSelector) matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.key]
+		var match bool
+		if req.exists {
+			match = ok
+		} else {
+			match = ok && value == req.value
+		}
+		if match == req.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// selectServices returns the services whose labels match selector
+func selectServices(services []map[string]interface{}, selector labelSelector) []map[string]interface{} {
+	var selected []map[string]interface{}
+	for _, svc := range services {
+		if selector.matches(serviceLabels(svc)) {
+			selected = append(selected, svc)
+		}
+	}
+	return selected
+}
+
+// selectedServiceHashes lists the services matching a --selector value and
+// returns their hashes
+func selectedServiceHashes(apiClient client.APIClient, token, selectorValue string) ([]string, error) {
+	selector, err := parseSelector(selectorValue)
+	if err != nil {
+		return nil, err
+	}
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	var hashes []string
+	for _, svc := range selectServices(parseArrayResponse(response), selector) {
+		hashes = append(hashes, stringField(svc, "service_hash"))
+	}
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("no services match selector '%s'", selectorValue)
+	}
+	return hashes, nil
+}
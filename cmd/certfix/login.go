@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/certfix/certfix-cli/internal/auth"
 	"github.com/certfix/certfix-cli/internal/config"
@@ -20,7 +21,15 @@ var loginCmd = &cobra.Command{
 	Long: `Login to Certfix services using your email and personal access token.
 This will store an authentication token for subsequent commands.
 
-Run without flags for interactive mode, or provide credentials via flags.`,
+Run without flags for interactive mode, or provide credentials via flags.
+
+With --sso the login happens in the browser instead, through your
+organization's identity provider: the CLI opens the login page and receives
+the session token on a temporary localhost callback.`,
+	Example: `  certfix login
+  certfix login --email me@example.com --token <personal-access-token>
+  certfix login --sso
+  certfix login --sso --no-browser --sso-port 8765`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log := logger.GetLogger()
 
@@ -36,6 +45,31 @@ Run without flags for interactive mode, or provide credentials via flags.`,
 
 		email, _ := cmd.Flags().GetString("email")
 		personalToken, _ := cmd.Flags().GetString("token")
+		sso, _ := cmd.Flags().GetBool("sso")
+
+		if sso {
+			if cmd.Flags().Changed("email") || cmd.Flags().Changed("token") {
+				return fmt.Errorf("--sso cannot be combined with --email or --token")
+			}
+			noBrowser, _ := cmd.Flags().GetBool("no-browser")
+			port, _ := cmd.Flags().GetInt("sso-port")
+			timeout, _ := cmd.Flags().GetDuration("sso-timeout")
+			cmd.SilenceUsage = true
+
+			log.Info("Attempting to login with SSO...")
+			token, err := ssoLogin(port, noBrowser, timeout)
+			if err != nil {
+				return fmt.Errorf("login failed: %w", err)
+			}
+			if err := auth.StoreToken(token); err != nil {
+				log.WithError(err).Error("Failed to store authentication token")
+				return fmt.Errorf("failed to store token: %w", err)
+			}
+
+			log.Info("Successfully logged in")
+			fmt.Println("✓ Successfully logged in to Certfix")
+			return nil
+		}
 
 		// Interactive mode if no flags provided
 		if !cmd.Flags().Changed("email") && !cmd.Flags().Changed("token") {
@@ -109,4 +143,8 @@ func init() {
 
 	loginCmd.Flags().StringP("email", "e", "", "Email for authentication")
 	loginCmd.Flags().StringP("token", "t", "", "Personal access token for authentication")
+	loginCmd.Flags().Bool("sso", false, "Log in through your identity provider in the browser")
+	loginCmd.Flags().Bool("no-browser", false, "With --sso, print the login URL instead of opening a browser")
+	loginCmd.Flags().Int("sso-port", 0, "With --sso, localhost port for the callback (default: any free port)")
+	loginCmd.Flags().Duration("sso-timeout", 5*time.Minute, "With --sso, how long to wait for the login to complete")
 }
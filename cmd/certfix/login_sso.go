@@ -0,0 +1,106 @@
+package certfix
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/browser"
+	"github.com/certfix/certfix-cli/internal/config"
+)
+
+// openBrowser opens the SSO login page; tests replace it to play the IdP
+var openBrowser = browser.Open
+
+// ssoPage is shown in the browser once the callback was received
+const ssoPage = `<!DOCTYPE html>
+<html><head><title>Certfix CLI</title></head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 4em">
+<h2>%s</h2><p>You can close this window and return to the terminal.</p>
+</body></html>`
+
+// ssoResult is what the callback listener received
+type ssoResult struct {
+	token string
+	err   error
+}
+
+// ssoLogin runs the browser-based login: it listens on a localhost port,
+// sends the browser to the server's SSO login page with that port as the
+// redirect URI, and waits for the IdP to redirect back with the session
+// token. The state parameter ties the callback to this login, so requests
+// forged by other pages are refused.
+func ssoLogin(port int, noBrowser bool, timeout time.Duration) (string, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return "", fmt.Errorf("failed to start the callback listener: %w", err)
+	}
+	defer listener.Close()
+
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return "", fmt.Errorf("failed to generate login state: %w", err)
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr().String())
+	loginURL := config.GetAPIEndpoint() + "/auth/sso/cli?" + url.Values{
+		"redirect_uri": {redirectURI},
+		"state":        {state},
+	}.Encode()
+
+	results := make(chan ssoResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.FormValue("state")), []byte(state)) != 1 {
+			http.Error(w, "invalid login state", http.StatusBadRequest)
+			return
+		}
+
+		result := ssoResult{token: r.FormValue("token")}
+		if idpErr := r.FormValue("error"); idpErr != "" {
+			if description := r.FormValue("error_description"); description != "" {
+				idpErr += ": " + description
+			}
+			result = ssoResult{err: fmt.Errorf("the identity provider refused the login: %s", idpErr)}
+		} else if result.token == "" {
+			result = ssoResult{err: fmt.Errorf("the callback carried no token")}
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, ssoPage, "Login failed")
+		} else {
+			fmt.Fprintf(w, ssoPage, "Logged in to Certfix")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if noBrowser {
+		fmt.Printf("Open this URL in your browser to log in:\n\n  %s\n\n", loginURL)
+	} else if err := openBrowser(loginURL); err != nil {
+		fmt.Printf("Could not open a browser (%v).\nOpen this URL to log in:\n\n  %s\n\n", err, loginURL)
+	} else {
+		fmt.Printf("Opening your browser to log in. If it does not open, visit:\n\n  %s\n\n", loginURL)
+	}
+	fmt.Println("Waiting for the login to complete...")
+
+	select {
+	case result := <-results:
+		return result.token, result.err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("timed out after %s waiting for the login to complete", timeout)
+	}
+}
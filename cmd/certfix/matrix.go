@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"text/tabwriter"
 	"time"
 
+	"github.com/certfix/certfix-cli/internal/api"
 	"github.com/certfix/certfix-cli/internal/auth"
 	"github.com/certfix/certfix-cli/internal/config"
 	"github.com/certfix/certfix-cli/pkg/client"
@@ -26,12 +28,18 @@ var matrixListCmd = &cobra.Command{
 	Use:     "list <service-hash>",
 	Aliases: []string{"ls"},
 	Short:   "List all relations for a service",
-	Long:    `List all service relations for a specific service.`,
-	Args:    cobra.ExactArgs(1),
+	Long: `List all service relations for a specific service.
+
+By default the outgoing relations are shown (the services this service depends
+on). With --reverse the incoming relations are shown instead: the services that
+depend on this one.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log := logger.GetLogger()
 		serviceHash := args[0]
 		outputFormat, _ := cmd.Flags().GetString("output")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+		incoming, _ := cmd.Flags().GetBool("incoming")
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -41,36 +49,38 @@ var matrixListCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
-
-		apiEndpoint := fmt.Sprintf("/services/%s/matrix/relations", serviceHash)
-		log.Debugf("GET %s%s", endpoint, apiEndpoint)
-
-		// Make request
-		response, err := apiClient.GetWithAuth(apiEndpoint, token)
-		if err != nil {
-			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to list service relations: %w", err)
-		}
+		apiClient := newAPIClient()
 
-		// Parse response
 		var relations []map[string]interface{}
-		if response["_is_array"] != nil {
-			if arr, ok := response["_array_data"].([]interface{}); ok {
-				for _, item := range arr {
-					if rel, ok := item.(map[string]interface{}); ok {
-						relations = append(relations, rel)
+		if reverse || incoming {
+			relations, err = loadIncomingRelations(apiClient, token, serviceHash)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+		} else {
+			apiEndpoint := fmt.Sprintf("/services/%s/matrix/relations", serviceHash)
+			log.Debugf("GET %s%s", config.GetAPIEndpoint(), apiEndpoint)
+
+			// Make request
+			response, err := apiClient.GetWithAuth(apiEndpoint, token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to list service relations: %w", err)
+			}
+
+			// Parse response
+			if response["_is_array"] != nil {
+				if arr, ok := response["_array_data"].([]interface{}); ok {
+					for _, item := range arr {
+						if rel, ok := item.(map[string]interface{}); ok {
+							relations = append(relations, rel)
+						}
 					}
 				}
 			}
 		}
 
-		if len(relations) == 0 {
-			fmt.Println("No service relations found.")
-			return nil
-		}
-
 		// Output format
 		if outputFormat == "json" {
 			data, _ := json.MarshalIndent(relations, "", "  ")
@@ -78,10 +88,15 @@ var matrixListCmd = &cobra.Command{
 			return nil
 		}
 
+		if len(relations) == 0 {
+			fmt.Println("No service relations found.")
+			return nil
+		}
+
 		// Table format
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "RELATION ID\tSOURCE SERVICE\tRELATED SERVICE\tSTATUS\tCREATED AT")
-		fmt.Fprintln(w, "-----------\t--------------\t---------------\t------\t----------")
+		fmt.Fprintln(w, "RELATION ID\tSOURCE SERVICE\tRELATED SERVICE\tTYPE\tDIRECTION\tDESCRIPTION\tSTATUS\tCREATED AT")
+		fmt.Fprintln(w, "-----------\t--------------\t---------------\t----\t---------\t-----------\t------\t----------")
 
 		for _, rel := range relations {
 			relationID := fmt.Sprintf("%v", rel["relation_id"])
@@ -118,7 +133,12 @@ var matrixListCmd = &cobra.Command{
 				}
 			}
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", relationID, sourceName, relatedName, status, createdAt)
+			description := stringOrNA(rel, "description")
+			if len(description) > 30 {
+				description = description[:27] + "..."
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", relationID, sourceName, relatedName, stringOrNA(rel, "relation_type"), stringOrNA(rel, "direction"), description, status, createdAt)
 		}
 		w.Flush()
 
@@ -129,11 +149,16 @@ var matrixListCmd = &cobra.Command{
 var matrixGetCmd = &cobra.Command{
 	Use:   "get <service-hash>",
 	Short: "Get matrix data for a service",
-	Long:  `Get complete matrix data for a service including all available services.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Get complete matrix data for a service including all available services.
+
+Relations are shown in both directions: OUT for services this service depends
+on and IN for services that depend on it. Finding the incoming relations reads
+the relations of every service; --outgoing-only skips them.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceHash := args[0]
 		outputFormat, _ := cmd.Flags().GetString("output")
+		outgoingOnly, _ := cmd.Flags().GetBool("outgoing-only")
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -143,8 +168,7 @@ var matrixGetCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Make request
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/matrix", serviceHash), token)
@@ -153,8 +177,20 @@ var matrixGetCmd = &cobra.Command{
 			return fmt.Errorf("failed to get matrix data: %w", err)
 		}
 
+		var incoming []map[string]interface{}
+		if !outgoingOnly {
+			incoming, err = loadIncomingRelations(apiClient, token, serviceHash)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+		}
+
 		// Output format
 		if outputFormat == "json" {
+			if !outgoingOnly {
+				response["incoming_relations"] = incoming
+			}
 			data, _ := json.MarshalIndent(response, "", "  ")
 			fmt.Println(string(data))
 			return nil
@@ -163,33 +199,36 @@ var matrixGetCmd = &cobra.Command{
 		// Pretty print
 		fmt.Printf("Service: %v\n\n", response["service"])
 
-		if relations, ok := response["relations"].([]interface{}); ok && len(relations) > 0 {
+		outgoing, _ := response["relations"].([]interface{})
+		if len(outgoing) > 0 || len(incoming) > 0 {
 			fmt.Println("Current Relations:")
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-			fmt.Fprintln(w, "  RELATION ID\tRELATED SERVICE\tSTATUS")
-			fmt.Fprintln(w, "  -----------\t---------------\t------")
+			fmt.Fprintln(w, "  IN/OUT\tRELATION ID\tSERVICE\tTYPE\tSTATUS")
+			fmt.Fprintln(w, "  ------\t-----------\t-------\t----\t------")
 
-			for _, item := range relations {
-				if rel, ok := item.(map[string]interface{}); ok {
-					relationID := fmt.Sprintf("%v", rel["relation_id"])
-					if len(relationID) > 12 {
-						relationID = relationID[:12] + "..."
-					}
+			printRelation := func(flow string, rel map[string]interface{}, nameKey string) {
+				relationID := fmt.Sprintf("%v", rel["relation_id"])
+				if len(relationID) > 12 {
+					relationID = relationID[:12] + "..."
+				}
 
-					relatedName := "N/A"
-					if rel["related_service_name"] != nil && rel["related_service_name"] != "<nil>" {
-						relatedName = fmt.Sprintf("%v", rel["related_service_name"])
-					}
+				enabled, _ := rel["enabled"].(bool)
+				status := "Disabled"
+				if enabled {
+					status = "Enabled"
+				}
 
-					enabled := rel["enabled"].(bool)
-					status := "Disabled"
-					if enabled {
-						status = "Enabled"
-					}
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", flow, relationID, stringOrNA(rel, nameKey), stringOrNA(rel, "relation_type"), status)
+			}
 
-					fmt.Fprintf(w, "  %s\t%s\t%s\n", relationID, relatedName, status)
+			for _, item := range outgoing {
+				if rel, ok := item.(map[string]interface{}); ok {
+					printRelation("OUT", rel, "related_service_name")
 				}
 			}
+			for _, rel := range incoming {
+				printRelation("IN", rel, "source_service_name")
+			}
 			w.Flush()
 		} else {
 			fmt.Println("No relations found.")
@@ -226,13 +265,29 @@ var matrixAddCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
+
+		relationType, _ := cmd.Flags().GetString("type")
+		description, _ := cmd.Flags().GetString("description")
+		direction, _ := cmd.Flags().GetString("direction")
+
+		if direction != "" && !isValidRelationDirection(direction) {
+			return fmt.Errorf("invalid direction '%s' (valid: %s)", direction, strings.Join(relationDirections, ", "))
+		}
 
 		// Prepare payload
 		payload := map[string]interface{}{
 			"related_service_hash": relatedServiceHash,
 		}
+		if relationType != "" {
+			payload["relation_type"] = relationType
+		}
+		if description != "" {
+			payload["description"] = description
+		}
+		if direction != "" {
+			payload["direction"] = direction
+		}
 
 		log.Infof("Adding service relation: %s -> %s", sourceServiceHash, relatedServiceHash)
 
@@ -251,15 +306,37 @@ var matrixAddCmd = &cobra.Command{
 		if response["enabled"].(bool) {
 			enabledStatus = "Enabled"
 		}
+		if t := stringOrNA(response, "relation_type"); t != "N/A" {
+			fmt.Printf("Type:             %s\n", t)
+		}
+		if d := stringOrNA(response, "direction"); d != "N/A" {
+			fmt.Printf("Direction:        %s\n", d)
+		}
+		if d := stringOrNA(response, "description"); d != "N/A" {
+			fmt.Printf("Description:      %s\n", d)
+		}
 		fmt.Printf("Status:           %s\n", enabledStatus)
 
 		return nil
 	},
 }
 
+// relationDirections are the values accepted by 'matrix add --direction'
+var relationDirections = []string{"outbound", "inbound", "bidirectional"}
+
+func isValidRelationDirection(direction string) bool {
+	for _, d := range relationDirections {
+		if d == direction {
+			return true
+		}
+	}
+	return false
+}
+
 var matrixEnableCmd = &cobra.Command{
 	Use:   "enable <service-hash> <relation-id>",
 	Short: "Enable a service relation",
+	Long:  `Enable a service relation. Does nothing if the relation is already enabled.`,
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceHash := args[0]
@@ -273,19 +350,19 @@ var matrixEnableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
-		// Make request (toggle endpoint toggles the current state, so we need to check first)
-		// For simplicity, we'll just call toggle and inform the user
-		_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s/matrix/relations/%s/toggle", serviceHash, relationID), nil, token)
+		changed, err := setRelationEnabled(apiClient, token, serviceHash, relationID, true)
 		if err != nil {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to toggle service relation: %w", err)
+			return err
 		}
 
-		fmt.Printf("✓ Service relation toggled\n")
-		fmt.Printf("Note: The toggle endpoint switches the current state. Use 'get' or 'list' to verify the new status.\n")
+		if !changed {
+			fmt.Printf("✓ Service relation is already enabled\n")
+			return nil
+		}
+		fmt.Printf("✓ Service relation enabled successfully\n")
 		return nil
 	},
 }
@@ -293,6 +370,7 @@ var matrixEnableCmd = &cobra.Command{
 var matrixDisableCmd = &cobra.Command{
 	Use:   "disable <service-hash> <relation-id>",
 	Short: "Disable a service relation",
+	Long:  `Disable a service relation. Does nothing if the relation is already disabled.`,
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceHash := args[0]
@@ -306,22 +384,47 @@ var matrixDisableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
-		// Make request (toggle endpoint toggles the current state)
-		_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s/matrix/relations/%s/toggle", serviceHash, relationID), nil, token)
+		changed, err := setRelationEnabled(apiClient, token, serviceHash, relationID, false)
 		if err != nil {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to toggle service relation: %w", err)
+			return err
 		}
 
-		fmt.Printf("✓ Service relation toggled\n")
-		fmt.Printf("Note: The toggle endpoint switches the current state. Use 'get' or 'list' to verify the new status.\n")
+		if !changed {
+			fmt.Printf("✓ Service relation is already disabled\n")
+			return nil
+		}
+		fmt.Printf("✓ Service relation disabled successfully\n")
 		return nil
 	},
 }
 
+// setRelationEnabled brings a service relation to the requested state, toggling
+// it only when its current state differs. It reports whether it was changed.
+func setRelationEnabled(apiClient client.APIClient, token, serviceHash, relationID string, enabled bool) (bool, error) {
+	response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/matrix/relations", serviceHash), token)
+	if err != nil {
+		return false, fmt.Errorf("failed to list service relations: %w", err)
+	}
+
+	for _, rel := range parseArrayResponse(response) {
+		if fmt.Sprintf("%v", rel["relation_id"]) != relationID {
+			continue
+		}
+		if current, _ := rel["enabled"].(bool); current == enabled {
+			return false, nil
+		}
+		if _, err := apiClient.PutWithAuth(fmt.Sprintf("/services/%s/matrix/relations/%s/toggle", serviceHash, relationID), nil, token); err != nil {
+			return false, fmt.Errorf("failed to toggle service relation: %w", err)
+		}
+		return true, nil
+	}
+
+	return false, fmt.Errorf("service relation %s not found for service %s", relationID, serviceHash)
+}
+
 var matrixToggleCmd = &cobra.Command{
 	Use:   "toggle <service-hash> <relation-id>",
 	Short: "Toggle a service relation (enable/disable)",
@@ -339,8 +442,7 @@ var matrixToggleCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		log.Infof("Toggling service relation: %s", relationID)
 
@@ -375,16 +477,18 @@ var matrixDeleteCmd = &cobra.Command{
 		serviceHash := args[0]
 		relationID := args[1]
 
+		if err := requirePermission(cmd, "relation:delete"); err != nil {
+			return err
+		}
+
 		// Confirm deletion
-		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Are you sure you want to delete service relation %s? (y/N): ", relationID)
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete service relation %s?", relationID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
 		}
 
 		// Get authentication token
@@ -395,8 +499,7 @@ var matrixDeleteCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		log.Infof("Deleting service relation: %s", relationID)
 
@@ -412,6 +515,364 @@ var matrixDeleteCmd = &cobra.Command{
 	},
 }
 
+// serviceGraph holds services and the relations between them, keyed by the
+// source service hash.
+type serviceGraph struct {
+	services  map[string]map[string]interface{}
+	order     []string
+	relations map[string][]map[string]interface{}
+}
+
+// matrixFetchConcurrency limits parallel requests when walking the matrix
+const matrixFetchConcurrency = 8
+
+// loadServiceGraph fetches the given services' outgoing relations concurrently
+func loadServiceGraph(apiClient client.APIClient, token string, services []map[string]interface{}) (*serviceGraph, error) {
+	graph := &serviceGraph{
+		services:  make(map[string]map[string]interface{}),
+		relations: make(map[string][]map[string]interface{}),
+	}
+	for _, svc := range services {
+		hash := fmt.Sprintf("%v", svc["service_hash"])
+		graph.services[hash] = svc
+		graph.order = append(graph.order, hash)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, matrixFetchConcurrency)
+	for _, hash := range graph.order {
+		wg.Add(1)
+		go func(hash string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/matrix/relations", hash), token)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to list relations for service %s: %w", hash, err)
+				}
+				return
+			}
+			graph.relations[hash] = parseArrayResponse(response)
+		}(hash)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return graph, nil
+}
+
+// loadIncomingRelations returns the relations of other services that point at
+// the given service. The API only lists relations by source, so every service's
+// relations are fetched and filtered. The source service hash and name are
+// filled in on each relation when the API omits them.
+func loadIncomingRelations(apiClient client.APIClient, token, serviceHash string) ([]map[string]interface{}, error) {
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	graph, err := loadServiceGraph(apiClient, token, parseArrayResponse(response))
+	if err != nil {
+		return nil, err
+	}
+
+	var incoming []map[string]interface{}
+	for _, source := range graph.order {
+		for _, rel := range graph.relations[source] {
+			if relatedHash(rel) != serviceHash {
+				continue
+			}
+			if stringOrNA(rel, "source_service_hash") == "N/A" {
+				rel["source_service_hash"] = source
+			}
+			if stringOrNA(rel, "source_service_name") == "N/A" {
+				rel["source_service_name"] = graph.name(source, nil)
+			}
+			incoming = append(incoming, rel)
+		}
+	}
+	return incoming, nil
+}
+
+// relatedHash returns the hash of the service a relation points to
+func relatedHash(rel map[string]interface{}) string {
+	if hash := stringOrNA(rel, "related_service_hash"); hash != "N/A" {
+		return hash
+	}
+	return stringOrNA(rel, "target_service_hash")
+}
+
+// name returns a display name for a service hash
+func (g *serviceGraph) name(hash string, rel map[string]interface{}) string {
+	if svc, ok := g.services[hash]; ok {
+		return fmt.Sprintf("%v", svc["service_name"])
+	}
+	if rel != nil {
+		if name := stringOrNA(rel, "related_service_name"); name != "N/A" {
+			return name
+		}
+	}
+	return hash
+}
+
+var matrixGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the service matrix as a dependency graph",
+	Long: `Walk the relations of all services (or the services of one group) and print a
+dependency graph in Graphviz DOT, Mermaid or JSON format.
+
+Examples:
+  certfix matrix graph --format dot | dot -Tsvg > matrix.svg
+  certfix matrix graph --group <group-id> --format mermaid`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		groupID, _ := cmd.Flags().GetString("group")
+		format, _ := cmd.Flags().GetString("format")
+		includeDisabled, _ := cmd.Flags().GetBool("include-disabled")
+
+		if format != "dot" && format != "mermaid" && format != "json" {
+			return fmt.Errorf("invalid format '%s' (valid: dot, mermaid, json)", format)
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		servicesEndpoint := "/services"
+		if groupID != "" {
+			servicesEndpoint = fmt.Sprintf("/services/group/%s", groupID)
+		}
+		response, err := apiClient.GetWithAuth(servicesEndpoint, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+
+		graph, err := loadServiceGraph(apiClient, token, parseArrayResponse(response))
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		type edge struct {
+			Source  string `json:"source"`
+			Target  string `json:"target"`
+			Type    string `json:"type,omitempty"`
+			Enabled bool   `json:"enabled"`
+		}
+		type node struct {
+			Hash string `json:"hash"`
+			Name string `json:"name"`
+		}
+		var nodes []node
+		var edges []edge
+		seen := make(map[string]bool)
+		addNode := func(hash string, rel map[string]interface{}) {
+			if !seen[hash] {
+				seen[hash] = true
+				nodes = append(nodes, node{Hash: hash, Name: graph.name(hash, rel)})
+			}
+		}
+		for _, hash := range graph.order {
+			addNode(hash, nil)
+			for _, rel := range graph.relations[hash] {
+				enabled, _ := rel["enabled"].(bool)
+				if !enabled && !includeDisabled {
+					continue
+				}
+				target := relatedHash(rel)
+				addNode(target, rel)
+				relType := ""
+				if t := stringOrNA(rel, "relation_type"); t != "N/A" {
+					relType = t
+				}
+				edges = append(edges, edge{Source: hash, Target: target, Type: relType, Enabled: enabled})
+			}
+		}
+
+		switch format {
+		case "json":
+			data, _ := json.MarshalIndent(map[string]interface{}{"nodes": nodes, "edges": edges}, "", "  ")
+			fmt.Println(string(data))
+		case "dot":
+			fmt.Println("digraph certfix_matrix {")
+			fmt.Println("  rankdir=LR;")
+			fmt.Println("  node [shape=box];")
+			for _, n := range nodes {
+				fmt.Printf("  %q [label=%q];\n", n.Hash, n.Name)
+			}
+			for _, e := range edges {
+				var attrs []string
+				if e.Type != "" {
+					attrs = append(attrs, fmt.Sprintf("label=%q", e.Type))
+				}
+				if !e.Enabled {
+					attrs = append(attrs, "style=dashed")
+				}
+				if len(attrs) > 0 {
+					fmt.Printf("  %q -> %q [%s];\n", e.Source, e.Target, strings.Join(attrs, ", "))
+				} else {
+					fmt.Printf("  %q -> %q;\n", e.Source, e.Target)
+				}
+			}
+			fmt.Println("}")
+		case "mermaid":
+			// Mermaid node IDs must be simple identifiers, so number the nodes
+			ids := make(map[string]string)
+			fmt.Println("graph LR")
+			for i, n := range nodes {
+				ids[n.Hash] = fmt.Sprintf("s%d", i)
+				fmt.Printf("  %s[\"%s\"]\n", ids[n.Hash], strings.ReplaceAll(n.Name, "\"", "#quot;"))
+			}
+			for _, e := range edges {
+				arrow := "-->"
+				if !e.Enabled {
+					arrow = "-.->"
+				}
+				if e.Type != "" {
+					fmt.Printf("  %s %s|%s| %s\n", ids[e.Source], arrow, e.Type, ids[e.Target])
+				} else {
+					fmt.Printf("  %s %s %s\n", ids[e.Source], arrow, ids[e.Target])
+				}
+			}
+		}
+
+		return nil
+	},
+}
+
+// impactNode is a service affected by a rotation, with the services that are in
+// turn affected through it.
+type impactNode struct {
+	Hash       string        `json:"service_hash"`
+	Name       string        `json:"service_name"`
+	Depth      int           `json:"depth"`
+	Dependents []*impactNode `json:"dependents,omitempty"`
+}
+
+var matrixImpactCmd = &cobra.Command{
+	Use:   "impact <service-hash>",
+	Short: "Show which services are affected when a service's certificate rotates",
+	Long: `Compute the services that depend on a service, directly or transitively, by
+following relations in reverse (services whose relations point at it). These are
+the services affected when its certificate rotates.
+
+Each affected service is shown once, at the shallowest depth it is reached.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceHash := args[0]
+		maxDepth, _ := cmd.Flags().GetInt("depth")
+		includeDisabled, _ := cmd.Flags().GetBool("include-disabled")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth("/services", token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+
+		graph, err := loadServiceGraph(apiClient, token, parseArrayResponse(response))
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if _, ok := graph.services[serviceHash]; !ok {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("service %s not found", serviceHash)
+		}
+
+		// Build reverse adjacency: target -> sources that depend on it
+		dependents := make(map[string][]string)
+		for _, source := range graph.order {
+			for _, rel := range graph.relations[source] {
+				if enabled, _ := rel["enabled"].(bool); !enabled && !includeDisabled {
+					continue
+				}
+				target := relatedHash(rel)
+				dependents[target] = append(dependents[target], source)
+			}
+		}
+
+		// Breadth-first so each service appears at its shallowest depth
+		root := &impactNode{Hash: serviceHash, Name: graph.name(serviceHash, nil)}
+		visited := map[string]bool{serviceHash: true}
+		queue := []*impactNode{root}
+		direct, total := 0, 0
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			if maxDepth > 0 && current.Depth >= maxDepth {
+				continue
+			}
+			for _, hash := range dependents[current.Hash] {
+				if visited[hash] {
+					continue
+				}
+				visited[hash] = true
+				child := &impactNode{Hash: hash, Name: graph.name(hash, nil), Depth: current.Depth + 1}
+				current.Dependents = append(current.Dependents, child)
+				queue = append(queue, child)
+				total++
+				if child.Depth == 1 {
+					direct++
+				}
+			}
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(root, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("%s (%s)\n", root.Name, root.Hash)
+		printImpactTree(root.Dependents, "")
+		if total == 0 {
+			fmt.Println("  No dependent services.")
+			return nil
+		}
+		fmt.Printf("\n%d service(s) affected: %d direct, %d transitive\n", total, direct, total-direct)
+		return nil
+	},
+}
+
+func printImpactTree(nodes []*impactNode, prefix string) {
+	for i, n := range nodes {
+		branch, childPrefix := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, childPrefix = "└── ", "    "
+		}
+		fmt.Printf("%s%s%s (%s)\n", prefix, branch, n.Name, n.Hash)
+		printImpactTree(n.Dependents, prefix+childPrefix)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(matrixCmd)
 
@@ -423,13 +884,38 @@ func init() {
 	matrixCmd.AddCommand(matrixEnableCmd)
 	matrixCmd.AddCommand(matrixDisableCmd)
 	matrixCmd.AddCommand(matrixDeleteCmd)
+	matrixCmd.AddCommand(matrixGraphCmd)
+	matrixCmd.AddCommand(matrixImpactCmd)
 
 	// List command flags
 	matrixListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	matrixListCmd.Flags().BoolP("reverse", "r", false, "List incoming relations (services that depend on this one)")
+	matrixListCmd.Flags().Bool("incoming", false, "Alias for --reverse")
+
+	// Add command flags
+	matrixAddCmd.Flags().StringP("type", "t", "", "Relation type (e.g. depends_on)")
+	matrixAddCmd.Flags().String("description", "", "Free-text description of the relation")
+	matrixAddCmd.Flags().String("direction", "", "Relation direction (outbound, inbound, bidirectional)")
 
 	// Get command flags
 	matrixGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	matrixGetCmd.Flags().Bool("outgoing-only", false, "Skip incoming relations, which reads the relations of every service")
 
 	// Delete command flags
 	matrixDeleteCmd.Flags().BoolP("force", "f", false, "Force deletion without confirmation")
+
+	// Graph command flags
+	matrixGraphCmd.Flags().StringP("group", "g", "", "Only include services from this service group")
+	matrixGraphCmd.Flags().StringP("format", "f", "dot", "Output format (dot, mermaid, json)")
+	matrixGraphCmd.Flags().Bool("include-disabled", false, "Include disabled relations (drawn dashed)")
+
+	// Impact command flags
+	matrixImpactCmd.Flags().IntP("depth", "d", 0, "Maximum depth to follow (0 for unlimited)")
+	matrixImpactCmd.Flags().Bool("include-disabled", false, "Follow disabled relations too")
+	matrixImpactCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Allow selecting the service by name
+	enableIdentifierResolver(api.KindService, matrixImpactCmd, matrixListCmd, matrixGetCmd)
+	enableExactIdentifierResolver(api.KindService, matrixAddCmd, matrixToggleCmd, matrixEnableCmd, matrixDisableCmd, matrixDeleteCmd)
+	enableServiceByName(matrixImpactCmd, matrixListCmd, matrixGetCmd, matrixAddCmd, matrixToggleCmd, matrixEnableCmd, matrixDisableCmd, matrixDeleteCmd)
 }
@@ -0,0 +1,268 @@
+package certfix
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/models"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// relationImportRow is a relation read from an import file, with the line (CSV)
+// or entry number (YAML) it came from.
+type relationImportRow struct {
+	row      int
+	relation models.MatrixRelationConfig
+}
+
+var matrixImportCmd = &cobra.Command{
+	Use:   "import <relations.csv|relations.yaml>",
+	Short: "Create service relations from a CSV or YAML file",
+	Long: `Create service relations in bulk from a file.
+
+CSV files need a header row with the columns source_hash, target_hash and
+optionally type and enabled. YAML files contain a 'relations' list with the
+same fields:
+
+  relations:
+    - source_hash: payments-api-hash
+      target_hash: billing-hash
+      type: depends_on
+      enabled: true
+
+Every row is validated before anything is created, and relations that already
+exist are skipped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		rows, err := readRelationsFile(args[0])
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		// Validate that every referenced service exists
+		response, err := apiClient.GetWithAuth("/services", token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+		var sources []map[string]interface{}
+		known := make(map[string]map[string]interface{})
+		for _, svc := range parseArrayResponse(response) {
+			known[fmt.Sprintf("%v", svc["service_hash"])] = svc
+		}
+		var problems []string
+		usedSources := make(map[string]bool)
+		for _, r := range rows {
+			for _, hash := range []string{r.relation.SourceHash, r.relation.TargetHash} {
+				if _, ok := known[hash]; !ok {
+					problems = append(problems, fmt.Sprintf("row %d: service '%s' does not exist", r.row, hash))
+				}
+			}
+			if svc, ok := known[r.relation.SourceHash]; ok && !usedSources[r.relation.SourceHash] {
+				usedSources[r.relation.SourceHash] = true
+				sources = append(sources, svc)
+			}
+		}
+		if len(problems) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid relations file:\n  %s", strings.Join(problems, "\n  "))
+		}
+
+		// Skip relations that already exist
+		graph, err := loadServiceGraph(apiClient, token, sources)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		exists := func(r models.MatrixRelationConfig) bool {
+			for _, rel := range graph.relations[r.SourceHash] {
+				if relatedHash(rel) == r.TargetHash {
+					return true
+				}
+			}
+			return false
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ROW\tSOURCE\tTARGET\tTYPE\tENABLED\tACTION")
+		fmt.Fprintln(w, "---\t------\t------\t----\t-------\t------")
+		var pending []relationImportRow
+		for _, r := range rows {
+			action := "create"
+			if exists(r.relation) {
+				action = "skip (exists)"
+			} else {
+				pending = append(pending, r)
+			}
+			fmt.Fprintf(w, "%d\t%v\t%v\t%s\t%t\t%s\n", r.row, known[r.relation.SourceHash]["service_name"], known[r.relation.TargetHash]["service_name"], orNone(r.relation.Type), relationEnabled(r.relation), action)
+		}
+		w.Flush()
+		fmt.Printf("\n%d to create, %d already exist.\n", len(pending), len(rows)-len(pending))
+
+		if dryRun || len(pending) == 0 {
+			if dryRun {
+				fmt.Println("Dry run: no relations created.")
+			}
+			return nil
+		}
+
+		fmt.Println()
+		var failed []string
+		for i, r := range pending {
+			fmt.Printf("[%d/%d] row %d: %s -> %s... ", i+1, len(pending), r.row, r.relation.SourceHash, r.relation.TargetHash)
+
+			payload := map[string]interface{}{
+				"related_service_hash": r.relation.TargetHash,
+			}
+			if r.relation.Type != "" {
+				payload["relation_type"] = r.relation.Type
+			}
+
+			created, err := apiClient.PostWithAuth(fmt.Sprintf("/services/%s/matrix", r.relation.SourceHash), payload, token)
+			if err == nil && !relationEnabled(r.relation) {
+				_, err = setRelationEnabled(apiClient, token, r.relation.SourceHash, fmt.Sprintf("%v", created["relation_id"]), false)
+			}
+			if err != nil {
+				fmt.Printf("Failed: %v\n", err)
+				failed = append(failed, fmt.Sprintf("row %d", r.row))
+				continue
+			}
+			fmt.Printf("OK\n")
+		}
+
+		fmt.Printf("\nSummary: %d succeeded, %d failed (total %d)\n", len(pending)-len(failed), len(failed), len(pending))
+		if len(failed) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("import failed for: %s", strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+func relationEnabled(r models.MatrixRelationConfig) bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// readRelationsFile parses a CSV or YAML relations file (chosen by extension)
+// and validates every row, reporting all problems at once.
+func readRelationsFile(path string) ([]relationImportRow, error) {
+	var rows []relationImportRow
+	var problems []string
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		var file models.MatrixImportConfig
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		for i, relation := range file.Relations {
+			rows = append(rows, relationImportRow{row: i + 1, relation: relation})
+		}
+	case ".csv":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file: %w", err)
+		}
+		defer f.Close()
+
+		reader := csv.NewReader(f)
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("CSV file is empty")
+		}
+
+		index := make(map[string]int)
+		for i, name := range records[0] {
+			index[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		for _, required := range []string{"source_hash", "target_hash"} {
+			if _, ok := index[required]; !ok {
+				return nil, fmt.Errorf("CSV header must include a '%s' column", required)
+			}
+		}
+		cell := func(record []string, name string) string {
+			if pos, ok := index[name]; ok && pos < len(record) {
+				return strings.TrimSpace(record[pos])
+			}
+			return ""
+		}
+
+		for i, record := range records[1:] {
+			line := i + 2
+			relation := models.MatrixRelationConfig{
+				SourceHash: cell(record, "source_hash"),
+				TargetHash: cell(record, "target_hash"),
+				Type:       cell(record, "type"),
+			}
+			if raw := cell(record, "enabled"); raw != "" {
+				enabled, err := strconv.ParseBool(raw)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("row %d: invalid enabled value '%s' (use true or false)", line, raw))
+				}
+				relation.Enabled = &enabled
+			}
+			rows = append(rows, relationImportRow{row: line, relation: relation})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file type '%s' (use .csv, .yaml or .yml)", filepath.Ext(path))
+	}
+
+	seen := make(map[string]int)
+	for _, r := range rows {
+		switch {
+		case r.relation.SourceHash == "" || r.relation.TargetHash == "":
+			problems = append(problems, fmt.Sprintf("row %d: source_hash and target_hash are required", r.row))
+		case r.relation.SourceHash == r.relation.TargetHash:
+			problems = append(problems, fmt.Sprintf("row %d: a service cannot be related to itself", r.row))
+		default:
+			key := r.relation.SourceHash + "->" + r.relation.TargetHash
+			if prev, dup := seen[key]; dup {
+				problems = append(problems, fmt.Sprintf("row %d: duplicate of row %d", r.row, prev))
+			}
+			seen[key] = r.row
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid relations file:\n  %s", strings.Join(problems, "\n  "))
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("relations file has no entries")
+	}
+	return rows, nil
+}
+
+func init() {
+	matrixCmd.AddCommand(matrixImportCmd)
+
+	// Import command flags
+	matrixImportCmd.Flags().Bool("dry-run", false, "Validate and show the planned changes without creating relations")
+}
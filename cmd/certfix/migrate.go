@@ -0,0 +1,272 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/certfix/certfix-cli/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// parseRenames parses --rename old=new values
+func parseRenames(values []string) (map[string]string, error) {
+	renames := make(map[string]string, len(values))
+	for _, value := range values {
+		from, to, ok := strings.Cut(value, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid rename '%s': use old-name=new-name", value)
+		}
+		if _, dup := renames[from]; dup {
+			return nil, fmt.Errorf("'%s' is renamed more than once", from)
+		}
+		renames[from] = to
+	}
+	return renames, nil
+}
+
+// renameResources renames resources of every kind and the references services
+// make to groups and policies. It fails when a rename matches nothing, since
+// that is most likely a typo.
+func renameResources(cfg *models.CertfixConfig, renames map[string]string) error {
+	used := make(map[string]bool)
+	rename := func(name string) string {
+		if to, ok := renames[name]; ok {
+			used[name] = true
+			return to
+		}
+		return name
+	}
+
+	for i := range cfg.Events {
+		cfg.Events[i].Name = rename(cfg.Events[i].Name)
+	}
+	for i := range cfg.Policies {
+		cfg.Policies[i].Name = rename(cfg.Policies[i].Name)
+	}
+	for i := range cfg.ServiceGroups {
+		cfg.ServiceGroups[i].Name = rename(cfg.ServiceGroups[i].Name)
+	}
+	for i := range cfg.Services {
+		s := &cfg.Services[i]
+		s.Name = rename(s.Name)
+		if s.GroupName != "" {
+			s.GroupName = rename(s.GroupName)
+		}
+		if s.PolicyName != "" {
+			s.PolicyName = rename(s.PolicyName)
+		}
+	}
+
+	for from := range renames {
+		if !used[from] {
+			return fmt.Errorf("rename '%s': no migrated resource has that name", from)
+		}
+	}
+	return nil
+}
+
+// limitToGroup keeps a service group and the policies its services use, for
+// an export of the group's services. Events are kept since policies may refer
+// to them.
+func limitToGroup(cfg *models.CertfixConfig, group string) {
+	policies := make(map[string]bool)
+	for _, s := range cfg.Services {
+		policies[s.PolicyName] = true
+	}
+
+	var groups []models.ServiceGroupConfig
+	for _, g := range cfg.ServiceGroups {
+		if g.Name == group {
+			groups = append(groups, g)
+		}
+	}
+	cfg.ServiceGroups = groups
+
+	var kept []models.PolicyConfig
+	for _, p := range cfg.Policies {
+		if policies[p.Name] {
+			kept = append(kept, p)
+		}
+	}
+	cfg.Policies = kept
+}
+
+// dropExternalRelations removes relations to services that are not migrated,
+// since the target server may not have them. It returns a description of each
+// relation removed.
+func dropExternalRelations(cfg *models.CertfixConfig) []string {
+	migrated := make(map[string]bool, len(cfg.Services))
+	for _, s := range cfg.Services {
+		migrated[s.Hash] = true
+	}
+
+	var dropped []string
+	for i := range cfg.Services {
+		s := &cfg.Services[i]
+		var relations []models.ServiceRelationConfig
+		for _, rel := range s.Relations {
+			if migrated[rel.TargetHash] {
+				relations = append(relations, rel)
+			} else {
+				dropped = append(dropped, fmt.Sprintf("%s → %s", s.Name, rel.TargetHash))
+			}
+		}
+		s.Relations = relations
+	}
+	return dropped
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate --from profile:<name> --to profile:<name>",
+	Short: "Copy resources from one server to another",
+	Long: `Copy events, policies, service groups and services, with their keys and
+relations, from one server to another, e.g. when consolidating servers. Both
+are configured as profiles (see 'certfix profile').
+
+The source is exported the way 'certfix apply' reads files and applied to the
+target in dependency order: events, policies, service groups, services, keys,
+relations. Services keep their hashes so agents only need the new endpoint;
+references between resources are matched by name. If anything fails, the
+resources created so far are deleted again.
+
+--group migrates one service group: its services, the group itself and the
+policies those services use, plus all events. Relations to services that are
+not migrated are left out. --rename old=new gives a resource another name on
+the target and updates the references to it.
+
+API keys cannot be copied: the target issues new keys with the same names and
+remaining lifetimes, which must be distributed to the services.`,
+	Example: `  certfix migrate --from profile:old --to profile:new --dry-run
+  certfix migrate --from profile:old --to profile:new --group payments
+  certfix migrate --from profile:old --to profile:new --rename nightly=nightly-eu --skip-existing`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+
+		fromSpec, _ := cmd.Flags().GetString("from")
+		toSpec, _ := cmd.Flags().GetString("to")
+		group, _ := cmd.Flags().GetString("group")
+		only, _ := cmd.Flags().GetString("only")
+		renameValues, _ := cmd.Flags().GetStringArray("rename")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipExisting, _ := cmd.Flags().GetBool("skip-existing")
+
+		if offline {
+			return fmt.Errorf("migrate needs both servers and does not support --offline")
+		}
+		kinds, err := parseEnvironmentKinds(only)
+		if err != nil {
+			return err
+		}
+		renames, err := parseRenames(renameValues)
+		if err != nil {
+			return err
+		}
+		if group != "" {
+			// The group's services need the group and their policies
+			kinds["service-groups"], kinds["policies"] = true, true
+		}
+
+		from, err := openEnvironment(fromSpec)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		to, err := openEnvironment(toSpec)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if from.Endpoint == to.Endpoint {
+			return fmt.Errorf("--from and --to point to the same server (%s)", from.Endpoint)
+		}
+
+		groupName := ""
+		if group != "" {
+			match, err := api.New(from.client).NewResolver().Resolve(api.KindServiceGroup, group)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to find service group '%s' in %s: %w", group, from.Profile, err)
+			}
+			response, err := from.client.GetWithAuth(fmt.Sprintf("/service-groups/%s", match.ID), from.token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to get service group '%s': %w", group, err)
+			}
+			groupName = stringField(response, "name")
+		}
+
+		fmt.Printf("Exporting from %s (%s)\n", from.Profile, from.Endpoint)
+		cfg, err := exportEnvironment(from.client, from.token, kinds, groupName)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to export %s: %w", from.Profile, err)
+		}
+		if groupName != "" {
+			limitToGroup(cfg, groupName)
+		}
+		for _, dropped := range dropExternalRelations(cfg) {
+			fmt.Fprintf(os.Stderr, "Warning: skipping relation %s: the target service is not migrated\n", dropped)
+		}
+		if err := renameResources(cfg, renames); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		fmt.Printf("  - Events: %d\n", len(cfg.Events))
+		fmt.Printf("  - Policies: %d\n", len(cfg.Policies))
+		fmt.Printf("  - Service Groups: %d\n", len(cfg.ServiceGroups))
+		fmt.Printf("  - Services: %d\n", len(cfg.Services))
+
+		if dryRun {
+			fmt.Printf("\n=== DRY RUN MODE - Nothing will be created in %s ===\n\n", to.Profile)
+			printApplyPlan(cfg)
+			return nil
+		}
+
+		fmt.Printf("Applying to %s (%s)\n", to.Profile, to.Endpoint)
+		var createdResources []models.CreatedResource
+		existing := failOnExisting
+		if skipExisting {
+			existing = skipAllExisting
+		}
+		if err := applyConfiguration(cfg, to.client, to.token, &createdResources, existing); err != nil {
+			cmd.SilenceUsage = true
+			log.Errorf("Error during migration: %v", err)
+			log.Infof("Rolling back created resources...")
+			rollbackResources(to.client, to.token, createdResources)
+			return err
+		}
+
+		keys := 0
+		for _, resource := range createdResources {
+			if resource.Type == "key" {
+				keys++
+			}
+		}
+		fmt.Printf("✓ Migrated %d resource(s) from %s to %s\n", len(createdResources), from.Profile, to.Profile)
+		if keys > 0 {
+			fmt.Printf("Note: %s issued new secrets for %d API key(s); update the services using them.\n", to.Profile, keys)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	// Migrate command flags
+	migrateCmd.Flags().String("from", "", "Server to copy from, as profile:<name> (required)")
+	migrateCmd.Flags().String("to", "", "Server to copy to, as profile:<name> (required)")
+	migrateCmd.Flags().String("group", "", "Only migrate this service group (ID or name) and what its services use")
+	migrateCmd.Flags().String("only", "", "Comma-separated resource types to migrate (events, policies, service-groups, services)")
+	migrateCmd.Flags().StringArray("rename", nil, "Rename a resource on the target, as old-name=new-name (repeatable)")
+	migrateCmd.Flags().Bool("dry-run", false, "Show what would be created without changing the target")
+	migrateCmd.Flags().Bool("skip-existing", false, "Leave resources that already exist on the target alone")
+	migrateCmd.MarkFlagRequired("from")
+	migrateCmd.MarkFlagRequired("to")
+}
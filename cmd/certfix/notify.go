@@ -0,0 +1,35 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/certfix/certfix-cli/pkg/notify"
+	"github.com/spf13/cobra"
+)
+
+// enableNotify adds a repeatable --notify flag to commands that can push their
+// results to chat channels or email
+func enableNotify(cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().StringArray("notify", nil, "Send the result to a slack://, teams://, mailto: or https:// target (repeatable)")
+	}
+}
+
+// notifiersFromFlags parses the --notify targets of a command. It is called
+// before any work is done so a mistyped target fails fast.
+func notifiersFromFlags(cmd *cobra.Command) ([]notify.Notifier, error) {
+	targets, _ := cmd.Flags().GetStringArray("notify")
+	return notify.ParseAll(targets)
+}
+
+// sendNotification delivers msg to every notifier. Delivery failures are
+// reported on stderr and never fail the command.
+func sendNotification(notifiers []notify.Notifier, msg notify.Message) {
+	if len(notifiers) == 0 {
+		return
+	}
+	if err := notify.Send(notifiers, msg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
+	}
+}
@@ -0,0 +1,81 @@
+package certfix
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// pageInfo describes the page of a list that was shown
+type pageInfo struct {
+	Total int
+	Page  int
+	Pages int
+	First int // 1-based index of the first item shown, 0 when none are
+	Last  int
+}
+
+// enablePagination adds --limit, --page and --all to list commands. RunE
+// cuts the items down with paginateItems after filtering and sorting them.
+func enablePagination(defaultLimit int, cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().Int("limit", defaultLimit, "Maximum number of items per page")
+		c.Flags().Int("page", 1, "Page to show, starting at 1")
+		c.Flags().Bool("all", false, "Show every item instead of a page")
+	}
+}
+
+// paginateItems returns the page of items selected by the --limit, --page
+// and --all flags of cmd. When paged is false, as for JSON output, every item
+// is returned unless --limit or --page was given explicitly.
+func paginateItems(cmd *cobra.Command, items []map[string]interface{}, paged bool) ([]map[string]interface{}, pageInfo, error) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	page, _ := cmd.Flags().GetInt("page")
+	all, _ := cmd.Flags().GetBool("all")
+
+	if limit < 1 {
+		return nil, pageInfo{}, fmt.Errorf("--limit must be at least 1")
+	}
+	if page < 1 {
+		return nil, pageInfo{}, fmt.Errorf("--page must be at least 1")
+	}
+	explicit := cmd.Flags().Changed("limit") || cmd.Flags().Changed("page")
+	if all && explicit {
+		return nil, pageInfo{}, fmt.Errorf("--all cannot be combined with --limit or --page")
+	}
+
+	info := pageInfo{Total: len(items), Page: 1, Pages: 1}
+	if all || (!paged && !explicit) || len(items) == 0 {
+		if len(items) > 0 {
+			info.First, info.Last = 1, len(items)
+		}
+		return items, info, nil
+	}
+
+	info.Page = page
+	info.Pages = (len(items) + limit - 1) / limit
+	start := (page - 1) * limit
+	if start >= len(items) {
+		return nil, info, nil
+	}
+	end := min(start+limit, len(items))
+	info.First, info.Last = start+1, end
+	return items[start:end], info, nil
+}
+
+// printPageFooter tells the user which items were shown and how to see the
+// rest. It prints nothing when every item was shown.
+func printPageFooter(info pageInfo, noun string) {
+	if info.Pages <= 1 && info.Page == 1 {
+		return
+	}
+	if info.First == 0 {
+		fmt.Printf("\nPage %d is past the end: there are %d %s on %d pages.\n", info.Page, info.Total, noun, info.Pages)
+		return
+	}
+	fmt.Printf("\nShowing %d-%d of %d %s (page %d of %d).", info.First, info.Last, info.Total, noun, info.Page, info.Pages)
+	if info.Page < info.Pages {
+		fmt.Printf(" Use --page %d for more or --all for everything.", info.Page+1)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,239 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+// permissionsPath returns the role and permissions of the current user.
+// Servers that predate it answer 404, and commands then skip the pre-checks.
+const permissionsPath = "/me/permissions"
+
+// permissionResources maps the names a resource may be given to the one used
+// in permissions, such as "cert" in "cert:revoke"
+var permissionResources = map[string]string{
+	"cert":           "cert",
+	"certs":          "cert",
+	"certificate":    "cert",
+	"certificates":   "cert",
+	"service":        "service",
+	"services":       "service",
+	"svc":            "service",
+	"key":            "key",
+	"keys":           "key",
+	"policy":         "policy",
+	"policies":       "policy",
+	"event":          "event",
+	"events":         "event",
+	"service-group":  "service-group",
+	"service-groups": "service-group",
+	"relation":       "relation",
+	"relations":      "relation",
+	"matrix":         "relation",
+	"instance":       "instance",
+	"instances":      "instance",
+	"user":           "user",
+	"users":          "user",
+	"team":           "team",
+	"teams":          "team",
+}
+
+// rolePermissions is what the server reports about the current user's role
+type rolePermissions struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// allows reports whether the role grants permission, directly or through a
+// wildcard such as "cert:*" or "*"
+func (p *rolePermissions) allows(permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, granted := range p.Permissions {
+		if granted == "*" || granted == permission || granted == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// permissionName builds the permission for a verb on a resource, accepting
+// the resource names used by the commands, e.g. "revoke" and "certs" give
+// "cert:revoke"
+func permissionName(verb, resource string) (string, error) {
+	canonical, ok := permissionResources[strings.ToLower(resource)]
+	if !ok {
+		names := make([]string, 0, len(permissionResources))
+		for _, name := range permissionResources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unknown resource '%s' (valid: %s)", resource, strings.Join(uniqueStrings(names), ", "))
+	}
+	return canonical + ":" + strings.ToLower(verb), nil
+}
+
+// uniqueStrings drops adjacent duplicates from a sorted list
+func uniqueStrings(sorted []string) []string {
+	var unique []string
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
+
+// fetchPermissions asks the server for the current user's permissions. It
+// returns nil without an error when the server does not report them.
+func fetchPermissions(apiClient client.APIClient, token string) (*rolePermissions, error) {
+	status, body, err := apiClient.RawWithAuth(http.MethodGet, permissionsPath, nil, token)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, nil
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("request failed with status %d", status)
+	}
+	var permissions rolePermissions
+	if err := json.Unmarshal(body, &permissions); err != nil {
+		return nil, fmt.Errorf("failed to parse permissions: %w", err)
+	}
+	return &permissions, nil
+}
+
+// requirePermission fails fast when the server reports that the user's role
+// lacks permission, so destructive commands stop before their confirmation
+// prompt instead of failing with a 403 after it. When the permissions cannot
+// be fetched the command goes ahead and the server decides.
+func requirePermission(cmd *cobra.Command, permission string) error {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil
+	}
+	permissions, err := fetchPermissions(newAPIClient(), token)
+	if err != nil {
+		logger.GetLogger().Debugf("Failed to check permission %s: %v", permission, err)
+		return nil
+	}
+	if permissions == nil || permissions.allows(permission) {
+		return nil
+	}
+	cmd.SilenceUsage = true
+	if permissions.Role != "" {
+		return fmt.Errorf("your role (%s) lacks %s", permissions.Role, permission)
+	}
+	return fmt.Errorf("your role lacks %s", permission)
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect the current session's permissions",
+	Long:  `Inspect what the logged in user's role allows.`,
+}
+
+var authCanICmd = &cobra.Command{
+	Use:   "can-i <verb> <resource>",
+	Short: "Check whether your role allows an action",
+	Long: `Check whether the role of the logged in user allows a verb on a resource,
+as the server reports it. Prints "yes" and exits 0, or prints "no" and
+exits 1.
+
+Resources may be named as in the commands (certs, services, keys, policies,
+events, service-groups, relations, instances, users, teams) and are checked
+as permissions such as cert:revoke or service:delete. With --list the
+role's permissions are listed instead.`,
+	Example: `  certfix auth can-i revoke certs
+  certfix auth can-i delete service-groups
+  certfix auth can-i --list`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, _ := cmd.Flags().GetBool("list")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		var permission string
+		if !list {
+			var err error
+			permission, err = permissionName(args[0], args[1])
+			if err != nil {
+				return err
+			}
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		permissions, err := fetchPermissions(apiClient, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get permissions: %w", err)
+		}
+		if permissions == nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("the server does not report permissions")
+		}
+
+		if list {
+			sort.Strings(permissions.Permissions)
+			if outputFormat == "json" {
+				data, _ := json.MarshalIndent(permissions, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			fmt.Printf("Role: %s\n\n", orNone(permissions.Role))
+			for _, p := range permissions.Permissions {
+				fmt.Println(p)
+			}
+			return nil
+		}
+
+		allowed := permissions.allows(permission)
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"permission": permission,
+				"role":       permissions.Role,
+				"allowed":    allowed,
+			}, "", "  ")
+			fmt.Println(string(data))
+		} else if allowed {
+			fmt.Println("yes")
+		} else {
+			fmt.Println("no")
+		}
+		if !allowed {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return fmt.Errorf("your role lacks %s", permission)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authCanICmd)
+
+	authCanICmd.Flags().Bool("list", false, "List the permissions of your role")
+	authCanICmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
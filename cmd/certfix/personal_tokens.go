@@ -4,13 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -34,8 +31,7 @@ var patListCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/personal-tokens", token)
 		if err != nil {
@@ -124,8 +120,7 @@ var patCreateCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		payload := map[string]interface{}{
 			"name": name,
@@ -162,17 +157,15 @@ var patRevokeCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tokenID := args[0]
-		force, _ := cmd.Flags().GetBool("force")
 		outputFormat, _ := cmd.Flags().GetString("output")
 
-		if !force {
-			fmt.Printf("Are you sure you want to revoke token %s? (y/N): ", tokenID)
-			var ans string
-			fmt.Scanln(&ans)
-			if strings.ToLower(ans) != "y" && strings.ToLower(ans) != "yes" {
-				fmt.Println("Revocation cancelled.")
-				return nil
-			}
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to revoke token %s?", tokenID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Revocation cancelled.")
+			return nil
 		}
 
 		token, err := auth.GetToken()
@@ -181,8 +174,7 @@ var patRevokeCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.PatchWithAuth(fmt.Sprintf("/personal-tokens/%s/revoke", tokenID), nil, token)
 		if err != nil {
@@ -208,16 +200,14 @@ var patDeleteCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tokenID := args[0]
-		force, _ := cmd.Flags().GetBool("force")
-
-		if !force {
-			fmt.Printf("Are you sure you want to delete token %s? (y/N): ", tokenID)
-			var ans string
-			fmt.Scanln(&ans)
-			if strings.ToLower(ans) != "y" && strings.ToLower(ans) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
+
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete token %s?", tokenID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
 		}
 
 		token, err := auth.GetToken()
@@ -226,8 +216,7 @@ var patDeleteCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/personal-tokens/%s", tokenID), token)
 		if err != nil {
@@ -0,0 +1,235 @@
+package certfix
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// healthPath is the unauthenticated health endpoint of the API
+	healthPath = "/health"
+
+	// maxClockDrift is the clock difference with the server above which ping
+	// warns, since token expiry checks start to misbehave
+	maxClockDrift = time.Minute
+
+	// tlsExpiryWarning is how close to expiry the API's own certificate may get
+	// before ping warns
+	tlsExpiryWarning = 14 * 24 * time.Hour
+)
+
+// probeResult is the outcome of one request made by ping
+type probeResult struct {
+	Path      string `json:"path"`
+	OK        bool   `json:"ok"`
+	Status    int    `json:"status,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// tlsCertInfo describes the certificate the API presented
+type tlsCertInfo struct {
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	DNSNames   []string  `json:"dns_names,omitempty"`
+	NotAfter   time.Time `json:"not_after"`
+	DaysLeft   int       `json:"days_left"`
+	TLSVersion string    `json:"tls_version"`
+}
+
+// pingReport is everything ping found out about the API
+type pingReport struct {
+	Endpoint          string       `json:"endpoint"`
+	OK                bool         `json:"ok"`
+	Health            probeResult  `json:"health"`
+	Auth              *probeResult `json:"auth,omitempty"`
+	ServerTime        *time.Time   `json:"server_time,omitempty"`
+	ClockDriftSeconds *float64     `json:"clock_drift_seconds,omitempty"`
+	TLS               *tlsCertInfo `json:"tls,omitempty"`
+	Warnings          []string     `json:"warnings,omitempty"`
+}
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that the API is reachable and healthy",
+	Long: `Check the API health endpoint and report its latency, the TLS certificate the
+API presents and the drift between the local clock and the server's.
+
+With --auth the stored session is checked as well, with an authenticated
+request. The command exits non-zero when a check fails, so it can be used
+as a monitoring probe.`,
+	Example: `  certfix ping
+  certfix ping --auth -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+		checkAuth, _ := cmd.Flags().GetBool("auth")
+		cmd.SilenceUsage = true
+
+		token := ""
+		if checkAuth {
+			var err error
+			token, err = auth.GetToken()
+			if err != nil {
+				return err
+			}
+		}
+
+		report := runPing(config.GetAPIEndpoint(), token, checkAuth)
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printPingReport(report)
+		}
+
+		if !report.OK {
+			return fmt.Errorf("API at %s is not healthy", report.Endpoint)
+		}
+		return nil
+	},
+}
+
+// runPing probes the health endpoint and, with checkAuth, the current user
+// endpoint with token
+func runPing(endpoint, token string, checkAuth bool) *pingReport {
+	httpClient := &http.Client{Timeout: config.GetRequestTimeout()}
+	if keyID, secret := config.GetProfileSigningKey(config.GetProfile()); secret != "" {
+		httpClient.Transport = &client.SigningTransport{KeyID: keyID, Secret: []byte(secret)}
+	}
+
+	report := &pingReport{Endpoint: endpoint}
+
+	resp, health := probe(httpClient, endpoint, healthPath, "")
+	report.Health = health
+	if resp != nil {
+		if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+			// The server stamped the response about halfway through the request
+			local := resp.sent.Add(time.Duration(health.LatencyMs) * time.Millisecond / 2)
+			drift := date.Sub(local).Seconds()
+			report.ServerTime = &date
+			report.ClockDriftSeconds = &drift
+			if d := time.Duration(drift * float64(time.Second)); d > maxClockDrift || d < -maxClockDrift {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("local clock differs from the server's by %.0fs; session expiry checks may be wrong", drift))
+			}
+		}
+		if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+			report.TLS = describeTLSCert(resp.TLS.PeerCertificates[0], resp.TLS.Version)
+			if time.Until(report.TLS.NotAfter) < tlsExpiryWarning {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("the API's TLS certificate expires in %d days", report.TLS.DaysLeft))
+			}
+		}
+	}
+	report.OK = health.OK
+
+	if checkAuth {
+		_, authProbe := probe(httpClient, endpoint, "/me", token)
+		report.Auth = &authProbe
+		report.OK = report.OK && authProbe.OK
+	}
+	return report
+}
+
+// probedResponse is a response of probe with the time its request was sent
+type probedResponse struct {
+	*http.Response
+	sent time.Time
+}
+
+// probe makes a GET request to path, authenticated when token is set. The
+// response is nil when no response arrived.
+func probe(httpClient *http.Client, endpoint, path, token string) (*probedResponse, probeResult) {
+	result := probeResult{Path: path}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint+path, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return nil, result
+	}
+	req.Header.Set("User-Agent", "certfix-cli/1.0")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	sent := time.Now()
+	resp, err := httpClient.Do(req)
+	if err == nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	result.LatencyMs = time.Since(sent).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return nil, result
+	}
+
+	result.Status = resp.StatusCode
+	result.OK = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !result.OK {
+		result.Error = fmt.Sprintf("status %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return &probedResponse{Response: resp, sent: sent}, result
+}
+
+// describeTLSCert summarizes the certificate the API presented
+func describeTLSCert(cert *x509.Certificate, version uint16) *tlsCertInfo {
+	return &tlsCertInfo{
+		Subject:    cert.Subject.String(),
+		Issuer:     cert.Issuer.String(),
+		DNSNames:   cert.DNSNames,
+		NotAfter:   cert.NotAfter.UTC(),
+		DaysLeft:   int(time.Until(cert.NotAfter).Hours() / 24),
+		TLSVersion: tls.VersionName(version),
+	}
+}
+
+// printPingReport prints a report for humans
+func printPingReport(report *pingReport) {
+	fmt.Printf("Endpoint:     %s\n", report.Endpoint)
+	printProbe("Health:", report.Health)
+	if report.Auth != nil {
+		printProbe("Auth:", *report.Auth)
+	}
+	if report.ClockDriftSeconds != nil {
+		fmt.Printf("Clock drift:  %+.0fs (server time %s)\n", *report.ClockDriftSeconds, report.ServerTime.Format(time.RFC3339))
+	}
+	if report.TLS != nil {
+		fmt.Printf("TLS:          %s, %s\n", report.TLS.TLSVersion, report.TLS.Subject)
+		fmt.Printf("  Issuer:     %s\n", report.TLS.Issuer)
+		fmt.Printf("  Expires:    %s (%d days)\n", report.TLS.NotAfter.Format("2006-01-02"), report.TLS.DaysLeft)
+	}
+
+	fmt.Println()
+	for _, warning := range report.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	if report.OK {
+		fmt.Println("✓ API is healthy")
+	}
+}
+
+// printProbe prints the outcome of one probe
+func printProbe(label string, result probeResult) {
+	if result.OK {
+		fmt.Printf("%-13s ok, %d in %dms\n", label, result.Status, result.LatencyMs)
+		return
+	}
+	fmt.Printf("%-13s FAILED after %dms: %s\n", label, result.LatencyMs, result.Error)
+}
+
+func init() {
+	rootCmd.AddCommand(pingCmd)
+
+	pingCmd.Flags().Bool("auth", false, "Also check the stored session with an authenticated request")
+	pingCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
@@ -8,18 +8,113 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/certfix/certfix-cli/internal/api"
 	"github.com/certfix/certfix-cli/internal/auth"
 	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/internal/cron"
 	"github.com/certfix/certfix-cli/pkg/logger"
 	"github.com/spf13/cobra"
 )
 
-// Strategy mapping: display labels to enum values
+// Strategy mapping: accepted spellings to enum values. Keys are normalized with
+// normalizeStrategyKey, so display labels ("Maintenance Window"), enum values
+// ("maintenance_window"), English aliases and the legacy Portuguese labels all
+// resolve to the same enum.
 var strategyEnumMapping = map[string]string{
-	"Events":             "events",
-	"Gradual":            "gradual",
-	"Maintenance Window": "maintenance_window",
+	"events":               "events",
+	"event":                "events",
+	"eventos":              "events",
+	"gradual":              "gradual",
+	"maintenance-window":   "maintenance_window",
+	"maintenance":          "maintenance_window",
+	"janela-de-manutenção": "maintenance_window",
+	"janela-de-manutencao": "maintenance_window",
+}
+
+// validStrategies lists the strategy names shown in help and error messages
+const validStrategies = "gradual, maintenance-window, events"
+
+// normalizeStrategyKey lowercases a strategy name and treats spaces and
+// underscores like hyphens
+func normalizeStrategyKey(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(s)
+}
+
+// parseStrategy converts any accepted strategy spelling to its enum value
+func parseStrategy(s string) (string, error) {
+	if enum, ok := strategyEnumMapping[normalizeStrategyKey(s)]; ok {
+		return enum, nil
+	}
+	return "", fmt.Errorf("invalid strategy: %s (must be one of: %s)", s, validStrategies)
+}
+
+// cronScheduleFromConfig parses a policy cron_config object as returned by the API
+func cronScheduleFromConfig(cronConfig map[string]interface{}) (*cron.Schedule, error) {
+	field := func(key string) string {
+		if v, ok := cronConfig[key]; ok && v != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return "*"
+	}
+	return cron.Parse(field("minute"), field("hour"), field("day"), field("month"), field("weekday"))
+}
+
+// gradualConfigPayload builds the gradual_config sent to the API from a batch
+// size and an interval between batches. A zero batch size or interval is left
+// out so the server default applies.
+func gradualConfigPayload(batchSize int, interval time.Duration) (map[string]interface{}, error) {
+	if batchSize < 0 {
+		return nil, fmt.Errorf("gradual batch size must be at least 1")
+	}
+	if interval < 0 {
+		return nil, fmt.Errorf("gradual interval cannot be negative")
+	}
+	gradualConfig := make(map[string]interface{})
+	if batchSize > 0 {
+		gradualConfig["batch_size"] = batchSize
+	}
+	if interval > 0 {
+		gradualConfig["interval_seconds"] = int(interval.Seconds())
+	}
+	return gradualConfig, nil
+}
+
+// gradualConfigFromFlags returns the gradual_config for --gradual-batch-size and
+// --gradual-interval, or nil when neither flag was given
+func gradualConfigFromFlags(cmd *cobra.Command) (map[string]interface{}, error) {
+	if !cmd.Flags().Changed("gradual-batch-size") && !cmd.Flags().Changed("gradual-interval") {
+		return nil, nil
+	}
+	batchSize, _ := cmd.Flags().GetInt("gradual-batch-size")
+	interval, _ := cmd.Flags().GetDuration("gradual-interval")
+	if cmd.Flags().Changed("gradual-batch-size") && batchSize < 1 {
+		return nil, fmt.Errorf("--gradual-batch-size must be at least 1")
+	}
+	return gradualConfigPayload(batchSize, interval)
+}
+
+// cronFieldFlags are the per-field cron flags replaced by --cron
+var cronFieldFlags = []string{"cron-minute", "cron-hour", "cron-day", "cron-month", "cron-weekday"}
+
+// cronFlagFields returns the five cron fields from either --cron or the
+// individual --cron-* flags. Combining both is an error.
+func cronFlagFields(cmd *cobra.Command) ([]string, error) {
+	fields := make([]string, len(cronFieldFlags))
+	for i, name := range cronFieldFlags {
+		fields[i], _ = cmd.Flags().GetString(name)
+	}
+
+	expr, _ := cmd.Flags().GetString("cron")
+	if expr == "" {
+		return fields, nil
+	}
+	for _, name := range cronFieldFlags {
+		if cmd.Flags().Changed(name) {
+			return nil, fmt.Errorf("--cron cannot be combined with --%s", name)
+		}
+	}
+	return cron.Split(expr)
 }
 
 var policyCmd = &cobra.Command{
@@ -40,6 +135,7 @@ var policyListCmd = &cobra.Command{
 		// Get flags
 		strategy, _ := cmd.Flags().GetString("strategy")
 		enabledOnly, _ := cmd.Flags().GetBool("enabled")
+		includeDeleted, _ := cmd.Flags().GetBool("include-deleted")
 		outputFormat, _ := cmd.Flags().GetString("output")
 
 		// Get authentication token
@@ -50,20 +146,26 @@ var policyListCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Determine endpoint
 		var apiEndpoint string
 		if enabledOnly {
 			apiEndpoint = "/policies/enabled"
 		} else if strategy != "" {
-			apiEndpoint = fmt.Sprintf("/policies/strategy/%s", strategy)
+			enumStrategy, err := parseStrategy(strategy)
+			if err != nil {
+				return err
+			}
+			apiEndpoint = fmt.Sprintf("/policies/strategy/%s", enumStrategy)
 		} else {
 			apiEndpoint = "/policies"
 		}
+		if includeDeleted {
+			apiEndpoint += "?" + includeDeletedParam + "=true"
+		}
 
-		log.Debugf("GET %s%s", endpoint, apiEndpoint)
+		log.Debugf("GET %s%s", config.GetAPIEndpoint(), apiEndpoint)
 
 		// Make request
 		response, err := apiClient.GetWithAuth(apiEndpoint, token)
@@ -84,9 +186,8 @@ var policyListCmd = &cobra.Command{
 			}
 		}
 
-		if len(policies) == 0 {
-			fmt.Println("No policies found.")
-			return nil
+		if err := sortItems(cmd, policySortColumns, policies); err != nil {
+			return err
 		}
 
 		// Output format
@@ -96,35 +197,45 @@ var policyListCmd = &cobra.Command{
 			return nil
 		}
 
-		// Table format
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tNAME\tSTRATEGY\tSTATUS\tCREATED AT")
-		fmt.Fprintln(w, "----\t----\t--------\t------\t----------")
-
-		for _, policy := range policies {
-			id := fmt.Sprintf("%v", policy["policy_id"])
-			name := fmt.Sprintf("%v", policy["name"])
-			strategy := fmt.Sprintf("%v", policy["strategy"])
-			enabled := policy["enabled"].(bool)
-			status := "Inactive"
-			if enabled {
-				status = "Active"
-			}
-			createdAt := ""
-			if policy["created_at"] != nil {
-				if t, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", policy["created_at"])); err == nil {
-					createdAt = t.Format("2006-01-02 15:04")
-				}
-			}
-
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", id, name, strategy, status, createdAt)
+		if len(policies) == 0 {
+			fmt.Println("No policies found.")
+			return nil
 		}
-		w.Flush()
 
+		printPoliciesTable(policies)
 		return nil
 	},
 }
 
+// printPoliciesTable renders the policies list table
+func printPoliciesTable(policies []map[string]interface{}) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTRATEGY\tSTATUS\tCREATED AT")
+	fmt.Fprintln(w, "----\t----\t--------\t------\t----------")
+
+	for _, policy := range policies {
+		id := fmt.Sprintf("%v", policy["policy_id"])
+		name := fmt.Sprintf("%v", policy["name"])
+		strategy := fmt.Sprintf("%v", policy["strategy"])
+		enabled := policy["enabled"].(bool)
+		status := "Inactive"
+		if isDeleted(policy) {
+			status = "Deleted"
+		} else if enabled {
+			status = "Active"
+		}
+		createdAt := ""
+		if policy["created_at"] != nil {
+			if t, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", policy["created_at"])); err == nil {
+				createdAt = t.Format("2006-01-02 15:04")
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", id, name, strategy, status, createdAt)
+	}
+	w.Flush()
+}
+
 var policyGetCmd = &cobra.Command{
 	Use:   "get <policy-id>",
 	Short: "Get details of a specific policy",
@@ -141,8 +252,7 @@ var policyGetCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Make request
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/policies/%s", policyID), token)
@@ -186,6 +296,16 @@ var policyGetCmd = &cobra.Command{
 			fmt.Printf("  Total:     %v\n", eventConfig["total_events"])
 		}
 
+		if gradualConfig, ok := response["gradual_config"].(map[string]interface{}); ok {
+			fmt.Println("Gradual Config:")
+			fmt.Printf("  Batch Size: %s\n", stringOrNA(gradualConfig, "batch_size"))
+			interval := "N/A"
+			if gradualConfig["interval_seconds"] != nil {
+				interval = (time.Duration(toFloat(gradualConfig["interval_seconds"])) * time.Second).String()
+			}
+			fmt.Printf("  Interval:   %s\n", interval)
+		}
+
 		if response["created_at"] != nil {
 			fmt.Printf("Created At:  %v\n", response["created_at"])
 		}
@@ -209,11 +329,12 @@ var policyCreateCmd = &cobra.Command{
 		enabled, _ := cmd.Flags().GetBool("enabled")
 
 		// Cron flags
-		cronMinute, _ := cmd.Flags().GetString("cron-minute")
-		cronHour, _ := cmd.Flags().GetString("cron-hour")
-		cronDay, _ := cmd.Flags().GetString("cron-day")
-		cronMonth, _ := cmd.Flags().GetString("cron-month")
-		cronWeekday, _ := cmd.Flags().GetString("cron-weekday")
+		cronFields, err := cronFlagFields(cmd)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		cronMinute, cronHour, cronDay, cronMonth, cronWeekday := cronFields[0], cronFields[1], cronFields[2], cronFields[3], cronFields[4]
 
 		// Event flags
 		eventID, _ := cmd.Flags().GetString("event-id")
@@ -229,27 +350,28 @@ var policyCreateCmd = &cobra.Command{
 			return fmt.Errorf("strategy is required")
 		}
 
-		// Validate strategy
-		validStrategies := []string{"Gradual", "Maintenance Window", "Events"}
-		strategyValid := false
-		for _, v := range validStrategies {
-			if strategy == v {
-				strategyValid = true
-				break
-			}
+		// Validate strategy and map to enum value
+		enumStrategy, err := parseStrategy(strategy)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
 		}
-		if !strategyValid {
+
+		// Validate cron fields (ranges, steps and lists)
+		if _, err := cron.Parse(cronMinute, cronHour, cronDay, cronMonth, cronWeekday); err != nil {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("invalid strategy: %s (must be one of: Gradual, Maintenance Window, Events)", strategy)
+			return fmt.Errorf("invalid cron schedule: %w", err)
 		}
 
-		// Map to enum value
-		var enumStrategy string
-		if enumStrat, exists := strategyEnumMapping[strategy]; exists {
-			enumStrategy = enumStrat
-		} else {
+		// Gradual rollout flags
+		gradualConfig, err := gradualConfigFromFlags(cmd)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if gradualConfig != nil && enumStrategy != "gradual" {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to map strategy to enum value")
+			return fmt.Errorf("--gradual-batch-size and --gradual-interval only apply to the gradual strategy")
 		}
 
 		// Get authentication token
@@ -260,8 +382,7 @@ var policyCreateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -289,6 +410,11 @@ var policyCreateCmd = &cobra.Command{
 			}
 		}
 
+		// Add gradual config if provided (for Gradual strategy)
+		if gradualConfig != nil {
+			payload["gradual_config"] = gradualConfig
+		}
+
 		log.Infof("Creating policy: %s", name)
 
 		// Make request
@@ -327,11 +453,12 @@ var policyUpdateCmd = &cobra.Command{
 		enabledValue, _ := cmd.Flags().GetBool("enabled")
 
 		// Cron flags
-		cronMinute, _ := cmd.Flags().GetString("cron-minute")
-		cronHour, _ := cmd.Flags().GetString("cron-hour")
-		cronDay, _ := cmd.Flags().GetString("cron-day")
-		cronMonth, _ := cmd.Flags().GetString("cron-month")
-		cronWeekday, _ := cmd.Flags().GetString("cron-weekday")
+		cronFields, err := cronFlagFields(cmd)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		cronMinute, cronHour, cronDay, cronMonth, cronWeekday := cronFields[0], cronFields[1], cronFields[2], cronFields[3], cronFields[4]
 
 		// Event flags
 		eventID, _ := cmd.Flags().GetString("event-id")
@@ -346,26 +473,13 @@ var policyUpdateCmd = &cobra.Command{
 		}
 
 		if strategy != "" {
-			// Validate strategy
-			validStrategies := []string{"Gradual", "Maintenance Window", "Events"}
-			strategyValid := false
-			for _, v := range validStrategies {
-				if strategy == v {
-					strategyValid = true
-					break
-				}
-			}
-			if !strategyValid {
+			// Validate strategy and map to enum value
+			enumStrategy, err := parseStrategy(strategy)
+			if err != nil {
 				cmd.SilenceUsage = true
-				return fmt.Errorf("invalid strategy: %s (must be one of: Gradual, Maintenance Window, Events)", strategy)
-			}
-			// Map to enum value
-			if enumStrat, exists := strategyEnumMapping[strategy]; exists {
-				payload["strategy"] = enumStrat
-			} else {
-				cmd.SilenceUsage = true
-				return fmt.Errorf("failed to map strategy to enum value")
+				return err
 			}
+			payload["strategy"] = enumStrategy
 		}
 
 		if enabled {
@@ -374,6 +488,11 @@ var policyUpdateCmd = &cobra.Command{
 
 		// Add cron config if any cron flag is provided
 		if cronMinute != "" || cronHour != "" || cronDay != "" || cronMonth != "" || cronWeekday != "" {
+			// Validate cron fields (ranges, steps and lists)
+			if _, err := cron.Parse(cronMinute, cronHour, cronDay, cronMonth, cronWeekday); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("invalid cron schedule: %w", err)
+			}
 			payload["cron_config"] = map[string]interface{}{
 				"minute":  cronMinute,
 				"hour":    cronHour,
@@ -395,6 +514,16 @@ var policyUpdateCmd = &cobra.Command{
 			payload["event_config"] = eventConfig
 		}
 
+		// Add gradual config if provided
+		gradualConfig, err := gradualConfigFromFlags(cmd)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if gradualConfig != nil {
+			payload["gradual_config"] = gradualConfig
+		}
+
 		if len(payload) == 0 {
 			cmd.SilenceUsage = true
 			return fmt.Errorf("no fields to update")
@@ -408,8 +537,14 @@ var policyUpdateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
+
+		// Read the policy first so the update carries its ETag and fails
+		// rather than overwrite a change made meanwhile
+		if _, err := apiClient.GetWithAuth(fmt.Sprintf("/policies/%s", policyID), token); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get policy: %w", err)
+		}
 
 		log.Infof("Updating policy: %s", policyID)
 
@@ -449,8 +584,7 @@ var policyEnableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -484,8 +618,7 @@ var policyDisableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -513,16 +646,18 @@ var policyDeleteCmd = &cobra.Command{
 		log := logger.GetLogger()
 		policyID := args[0]
 
+		if err := requirePermission(cmd, "policy:delete"); err != nil {
+			return err
+		}
+
 		// Confirm deletion
-		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Are you sure you want to delete policy %s? (y/N): ", policyID)
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete policy %s?", policyID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
 		}
 
 		// Get authentication token
@@ -533,23 +668,291 @@ var policyDeleteCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		log.Infof("Deleting policy: %s", policyID)
 
 		// Make request
-		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/policies/%s", policyID), token)
+		response, err := apiClient.DeleteWithAuth(fmt.Sprintf("/policies/%s", policyID), token)
 		if err != nil {
 			cmd.SilenceUsage = true
 			return fmt.Errorf("failed to delete policy: %w", err)
 		}
 
 		fmt.Printf("✓ Policy deleted successfully\n")
+		restoreHint(response, "certfix policy restore "+policyID)
 		return nil
 	},
 }
 
+var policyNextRunsCmd = &cobra.Command{
+	Use:   "next-runs <policy-id>",
+	Short: "Preview the upcoming runs of a policy's cron schedule",
+	Long: `Compute the next execution times of a policy's cron schedule so maintenance
+windows can be checked before they happen. Times are shown in the local
+timezone unless --timezone is given (an IANA name such as Europe/Berlin or UTC).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policyID := args[0]
+		count, _ := cmd.Flags().GetInt("count")
+		timezone, _ := cmd.Flags().GetString("timezone")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		if count < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+
+		loc := time.Local
+		if timezone != "" {
+			var err error
+			if loc, err = time.LoadLocation(timezone); err != nil {
+				return fmt.Errorf("unknown timezone '%s'", timezone)
+			}
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth(fmt.Sprintf("/policies/%s", policyID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get policy: %w", err)
+		}
+
+		cronConfig, ok := response["cron_config"].(map[string]interface{})
+		if !ok {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("policy %v has no cron schedule", response["name"])
+		}
+
+		schedule, err := cronScheduleFromConfig(cronConfig)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("policy has an invalid cron configuration: %w", err)
+		}
+
+		runs := schedule.NextN(time.Now().In(loc), count)
+
+		if outputFormat == "json" {
+			times := make([]string, 0, len(runs))
+			for _, t := range runs {
+				times = append(times, t.Format(time.RFC3339))
+			}
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"policy_id": policyID,
+				"timezone":  loc.String(),
+				"next_runs": times,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Policy:   %v\n", response["name"])
+		fmt.Printf("Schedule: %v %v %v %v %v\n", cronConfig["minute"], cronConfig["hour"], cronConfig["day"], cronConfig["month"], cronConfig["weekday"])
+		if enabled, _ := response["enabled"].(bool); !enabled {
+			fmt.Println("Note:     policy is disabled; these runs will not happen until it is enabled")
+		}
+		fmt.Println()
+
+		if len(runs) == 0 {
+			fmt.Println("The schedule never fires.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "#\tTIME\tWEEKDAY\tIN")
+		fmt.Fprintln(w, "-\t----\t-------\t--")
+		for i, t := range runs {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", i+1, t.Format("2006-01-02 15:04 MST"), t.Weekday(), time.Until(t).Round(time.Minute))
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+// policySimulateMaxRuns caps the number of cron runs listed by 'policy simulate'
+const policySimulateMaxRuns = 100
+
+var policySimulateCmd = &cobra.Command{
+	Use:   "simulate <policy-id>",
+	Short: "Predict which services a policy rotates and when",
+	Long: `List the services attached to a policy and the rotations the policy would
+trigger within a time window: every run of its cron schedule, or the event
+counter status for the Events strategy. Each run rotates all attached active
+services at once, so use this to spot rotation storms before enabling a policy.
+
+--window accepts durations such as 24h, 7d or 2w.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policyID := args[0]
+		windowFlag, _ := cmd.Flags().GetString("window")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		window, err := parseHumanDuration(windowFlag)
+		if err != nil {
+			return err
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		policy, err := apiClient.GetWithAuth(fmt.Sprintf("/policies/%s", policyID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get policy: %w", err)
+		}
+
+		response, err := apiClient.GetWithAuth("/services", token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+		var services []map[string]interface{}
+		active := 0
+		for _, svc := range parseArrayResponse(response) {
+			if stringOrNA(svc, "policy_id") != policyID {
+				continue
+			}
+			services = append(services, svc)
+			if isActive, _ := svc["active"].(bool); isActive {
+				active++
+			}
+		}
+
+		now := time.Now()
+		end := now.Add(window)
+		result := map[string]interface{}{
+			"policy_id":       policyID,
+			"policy_name":     policy["name"],
+			"strategy":        policy["strategy"],
+			"enabled":         policy["enabled"],
+			"window":          windowFlag,
+			"services":        services,
+			"active_services": active,
+		}
+
+		var runs []time.Time
+		var condition string
+		scheduled := false
+		cronConfig, hasCron := policy["cron_config"].(map[string]interface{})
+		eventConfig, hasEvent := policy["event_config"].(map[string]interface{})
+		switch {
+		case fmt.Sprintf("%v", policy["strategy"]) == "events" && hasEvent:
+			eventID := fmt.Sprintf("%v", eventConfig["event_id"])
+			total := int(toFloat(eventConfig["total_events"]))
+			event, err := apiClient.GetWithAuth(fmt.Sprintf("/events/%s", eventID), token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to get event: %w", err)
+			}
+			counter := int(toFloat(event["counter"]))
+			remaining := total - counter
+			if remaining < 0 {
+				remaining = 0
+			}
+			result["event_id"] = eventID
+			result["event_counter"] = counter
+			result["event_threshold"] = total
+			condition = fmt.Sprintf("After %d more occurrence(s) of event %v (counter %d/%d); timing depends on when the event fires", remaining, event["name"], counter, total)
+		case hasCron:
+			schedule, err := cronScheduleFromConfig(cronConfig)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("policy has an invalid cron configuration: %w", err)
+			}
+			scheduled = true
+			for t := schedule.Next(now); !t.IsZero() && !t.After(end) && len(runs) < policySimulateMaxRuns; t = schedule.Next(t) {
+				runs = append(runs, t)
+			}
+			condition = fmt.Sprintf("Cron schedule %v %v %v %v %v", cronConfig["minute"], cronConfig["hour"], cronConfig["day"], cronConfig["month"], cronConfig["weekday"])
+		default:
+			condition = "Policy has no schedule or event configuration"
+		}
+		result["condition"] = condition
+
+		times := make([]string, 0, len(runs))
+		for _, t := range runs {
+			times = append(times, t.Format(time.RFC3339))
+		}
+		result["rotations"] = times
+		result["total_rotations"] = len(runs) * active
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Policy:     %v (%v)\n", policy["name"], policy["strategy"])
+		fmt.Printf("Window:     %s (until %s)\n", windowFlag, end.Format("2006-01-02 15:04 MST"))
+		fmt.Printf("Condition:  %s\n", condition)
+		if enabled, _ := policy["enabled"].(bool); !enabled {
+			fmt.Println("Note:       policy is disabled; this is what would happen once it is enabled")
+		}
+		fmt.Println()
+
+		if len(services) == 0 {
+			fmt.Println("No services are attached to this policy.")
+			return nil
+		}
+
+		fmt.Printf("Attached services (%d, %d active):\n", len(services), active)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "  HASH\tNAME\tSTATUS")
+		fmt.Fprintln(w, "  ----\t----\t------")
+		for _, svc := range services {
+			fmt.Fprintf(w, "  %v\t%v\t%s\n", svc["service_hash"], svc["service_name"], serviceStatus(svc))
+		}
+		w.Flush()
+
+		if scheduled {
+			fmt.Println()
+			if len(runs) == 0 {
+				fmt.Println("No rotations within the window.")
+				return nil
+			}
+			fmt.Println("Rotations within the window:")
+			w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "  TIME\tWEEKDAY\tSERVICES")
+			fmt.Fprintln(w, "  ----\t-------\t--------")
+			for _, t := range runs {
+				fmt.Fprintf(w, "  %s\t%s\t%d\n", t.Format("2006-01-02 15:04 MST"), t.Weekday(), active)
+			}
+			w.Flush()
+			if len(runs) == policySimulateMaxRuns {
+				fmt.Printf("  (showing the first %d runs)\n", policySimulateMaxRuns)
+			}
+			fmt.Printf("\n%d run(s), %d certificate rotation(s) in total\n", len(runs), len(runs)*active)
+		}
+
+		return nil
+	},
+}
+
+// policySortColumns are the --sort-by columns of 'policy list'
+var policySortColumns = sortColumns{
+	"id":         "policy_id",
+	"name":       "name",
+	"strategy":   "strategy",
+	"status":     "enabled",
+	"created_at": "created_at",
+}
+
 func init() {
 	rootCmd.AddCommand(policyCmd)
 
@@ -561,18 +964,22 @@ func init() {
 	policyCmd.AddCommand(policyEnableCmd)
 	policyCmd.AddCommand(policyDisableCmd)
 	policyCmd.AddCommand(policyDeleteCmd)
+	policyCmd.AddCommand(policyNextRunsCmd)
+	policyCmd.AddCommand(policySimulateCmd)
 
 	// List command flags
-	policyListCmd.Flags().StringP("strategy", "s", "", "Filter by strategy (Gradual, Maintenance Window, Events)")
+	policyListCmd.Flags().StringP("strategy", "s", "", "Filter by strategy (gradual, maintenance-window, events)")
 	policyListCmd.Flags().BoolP("enabled", "e", false, "Show only enabled policies")
+	policyListCmd.Flags().Bool("include-deleted", false, "Also list deleted policies that can be restored")
 	policyListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	enableSorting(policySortColumns, policyListCmd)
 
 	// Get command flags
 	policyGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 
 	// Create command flags
 	policyCreateCmd.Flags().StringP("name", "n", "", "Name of the policy (required)")
-	policyCreateCmd.Flags().StringP("strategy", "s", "", "Strategy: Gradual, Maintenance Window, or Events (required)")
+	policyCreateCmd.Flags().StringP("strategy", "s", "", "Strategy: gradual, maintenance-window, or events (required)")
 	policyCreateCmd.Flags().BoolP("enabled", "e", true, "Enable the policy immediately (default: true)")
 
 	// Cron configuration flags (for Gradual and Maintenance Window)
@@ -581,17 +988,22 @@ func init() {
 	policyCreateCmd.Flags().String("cron-day", "*", "Cron day (1-31 or *)")
 	policyCreateCmd.Flags().String("cron-month", "*", "Cron month (1-12 or *)")
 	policyCreateCmd.Flags().String("cron-weekday", "*", "Cron weekday (0-7 or *)")
+	policyCreateCmd.Flags().String("cron", "", "Cron expression with all five fields, e.g. \"0 3 * * 6\" (replaces --cron-*)")
 
 	// Event configuration flags (for Events strategy)
 	policyCreateCmd.Flags().String("event-id", "", "Event ID for Events strategy")
 	policyCreateCmd.Flags().Int("event-total", 1, "Total events for Events strategy")
 
+	// Gradual rollout flags (for Gradual strategy)
+	policyCreateCmd.Flags().Int("gradual-batch-size", 0, "Number of services rotated per batch")
+	policyCreateCmd.Flags().Duration("gradual-interval", 0, "Wait between batches (e.g. 10m)")
+
 	policyCreateCmd.MarkFlagRequired("name")
 	policyCreateCmd.MarkFlagRequired("strategy")
 
 	// Update command flags
 	policyUpdateCmd.Flags().StringP("name", "n", "", "New name for the policy")
-	policyUpdateCmd.Flags().StringP("strategy", "s", "", "New strategy: Gradual, Maintenance Window, or Events")
+	policyUpdateCmd.Flags().StringP("strategy", "s", "", "New strategy: gradual, maintenance-window, or events")
 	policyUpdateCmd.Flags().BoolP("enabled", "e", false, "Enable or disable the policy")
 
 	// Cron configuration flags
@@ -600,11 +1012,28 @@ func init() {
 	policyUpdateCmd.Flags().String("cron-day", "", "Cron day (1-31 or *)")
 	policyUpdateCmd.Flags().String("cron-month", "", "Cron month (1-12 or *)")
 	policyUpdateCmd.Flags().String("cron-weekday", "", "Cron weekday (0-7 or *)")
+	policyUpdateCmd.Flags().String("cron", "", "Cron expression with all five fields, e.g. \"0 3 * * 6\" (replaces --cron-*)")
 
 	// Event configuration flags
 	policyUpdateCmd.Flags().String("event-id", "", "Event ID for Events strategy")
 	policyUpdateCmd.Flags().Int("event-total", 0, "Total events for Events strategy")
 
+	// Gradual rollout flags
+	policyUpdateCmd.Flags().Int("gradual-batch-size", 0, "Number of services rotated per batch")
+	policyUpdateCmd.Flags().Duration("gradual-interval", 0, "Wait between batches (e.g. 10m)")
+
 	// Delete command flags
 	policyDeleteCmd.Flags().BoolP("force", "f", false, "Force deletion without confirmation")
+
+	// Next runs command flags
+	policyNextRunsCmd.Flags().IntP("count", "c", 5, "Number of upcoming runs to show")
+	policyNextRunsCmd.Flags().StringP("timezone", "z", "", "Timezone to show times in (e.g. UTC, Europe/Berlin)")
+	policyNextRunsCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Simulate command flags
+	policySimulateCmd.Flags().String("window", "7d", "How far ahead to simulate (e.g. 24h, 7d, 2w)")
+	policySimulateCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	enableIdentifierResolver(api.KindPolicy, policyGetCmd, policyNextRunsCmd, policySimulateCmd, policyServicesCmd, policyCloneCmd)
+	enableExactIdentifierResolver(api.KindPolicy, policyUpdateCmd, policyEnableCmd, policyDisableCmd, policyDeleteCmd, policyAttachCmd, policyDetachCmd)
 }
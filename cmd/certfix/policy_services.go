@@ -0,0 +1,102 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+var policyServicesCmd = &cobra.Command{
+	Use:   "services <policy-id>",
+	Short: "List the services attached to a policy",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policyID := args[0]
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		if _, err := apiClient.GetWithAuth(fmt.Sprintf("/policies/%s", policyID), token); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get policy: %w", err)
+		}
+
+		services, err := policyMembership.members(apiClient, token, policyID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(services, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(services) == 0 {
+			fmt.Println("No services are attached to this policy.")
+			return nil
+		}
+
+		printServicesTable(services, nil)
+		return nil
+	},
+}
+
+// policyMembership describes how services are attached to a policy
+var policyMembership = serviceMembership{
+	field: "policy_id",
+	kind:  "policy",
+	path:  "/policies/%s",
+}
+
+var policyAttachCmd = &cobra.Command{
+	Use:   "attach <policy-id> <service-hash[,service-hash...]|->",
+	Short: "Attach services to a policy",
+	Long: `Attach one or more services to a policy by updating each service. Services are
+given as a comma-separated list, '-' to read hashes from stdin, --from-file or
+--interactive. Services already attached to the policy are skipped; services
+attached to another policy are moved to this one.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setServicesMembership(cmd, args, policyMembership, true)
+	},
+}
+
+var policyDetachCmd = &cobra.Command{
+	Use:   "detach <policy-id> <service-hash[,service-hash...]|->",
+	Short: "Detach services from a policy",
+	Long: `Detach one or more services from a policy, leaving them without a policy.
+Services are given as for 'certfix policy attach'. Services that are not
+attached to the policy are skipped.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setServicesMembership(cmd, args, policyMembership, false)
+	},
+}
+
+func init() {
+	policyCmd.AddCommand(policyServicesCmd)
+	policyCmd.AddCommand(policyAttachCmd)
+	policyCmd.AddCommand(policyDetachCmd)
+
+	// Services command flags
+	policyServicesCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Attach/detach command flags
+	for _, c := range []*cobra.Command{policyAttachCmd, policyDetachCmd} {
+		c.Flags().String("from-file", "", "Read service hashes from a file (one per line)")
+		c.Flags().String("selector", "", "Select the services by labels, e.g. team=payments")
+		c.Flags().BoolP("interactive", "i", false, "Pick services interactively with a fuzzy-search list")
+	}
+}
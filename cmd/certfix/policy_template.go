@@ -0,0 +1,205 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/certfix/certfix-cli/pkg/models"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var policyCloneCmd = &cobra.Command{
+	Use:   "clone <policy-id>",
+	Short: "Create a copy of a policy under a new name",
+	Long: `Create a new policy with the same strategy, schedule and event configuration as
+an existing one. With --yaml nothing is created; an apply-compatible YAML
+snippet of the copy is printed instead, ready to be edited and passed to
+'certfix apply'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+		policyID := args[0]
+		name, _ := cmd.Flags().GetString("name")
+		asYAML, _ := cmd.Flags().GetBool("yaml")
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		source, err := apiClient.GetWithAuth(fmt.Sprintf("/policies/%s", policyID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get policy: %w", err)
+		}
+
+		policy := policyConfigFromResponse(source)
+		policy.Name = name
+		if cmd.Flags().Changed("enabled") {
+			policy.Enabled, _ = cmd.Flags().GetBool("enabled")
+		}
+
+		if asYAML {
+			return writePolicyYAML(policy)
+		}
+
+		payload := map[string]interface{}{
+			"name":     policy.Name,
+			"strategy": policy.Strategy,
+			"enabled":  policy.Enabled,
+		}
+		if cronConfig, ok := source["cron_config"].(map[string]interface{}); ok {
+			payload["cron_config"] = cronConfig
+		}
+		if len(policy.EventConfig) > 0 {
+			payload["event_config"] = policy.EventConfig
+		}
+		if gradualConfig, ok := source["gradual_config"].(map[string]interface{}); ok {
+			payload["gradual_config"] = gradualConfig
+		}
+
+		log.Infof("Cloning policy %s as: %s", policyID, name)
+
+		response, err := apiClient.PostWithAuth("/policies", payload, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to create policy: %w", err)
+		}
+
+		fmt.Printf("✓ Policy cloned successfully\n")
+		fmt.Printf("ID:       %v\n", response["policy_id"])
+		fmt.Printf("Name:     %v\n", response["name"])
+		fmt.Printf("Strategy: %v\n", response["strategy"])
+		fmt.Printf("Source:   %v (%s)\n", source["name"], policyID)
+
+		return nil
+	},
+}
+
+var policyTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Print an apply-compatible YAML template for a policy",
+	Long: `Print a YAML snippet for 'certfix apply' pre-filled with the fields required by
+the chosen strategy. Redirect it to a file, adjust the values and apply it:
+
+  certfix policy template --strategy gradual > policy.yaml
+  certfix apply policy.yaml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		strategy, _ := cmd.Flags().GetString("strategy")
+		name, _ := cmd.Flags().GetString("name")
+
+		enumStrategy, err := parseStrategy(strategy)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = strings.ReplaceAll(enumStrategy, "_", "-") + "-policy"
+		}
+
+		policy := models.PolicyConfig{
+			Name:     name,
+			Strategy: enumStrategy,
+			Enabled:  true,
+		}
+		if enumStrategy == "events" {
+			policy.EventConfig = map[string]interface{}{
+				"event_id":     "<event-id>",
+				"total_events": 10,
+			}
+		} else {
+			// Saturdays at 03:00
+			policy.Cron = "0 3 * * 6"
+		}
+		if enumStrategy == "gradual" {
+			policy.GradualConfig = &models.GradualConfig{
+				BatchSize: 5,
+				Interval:  "10m",
+			}
+		}
+
+		return writePolicyYAML(policy)
+	},
+}
+
+// policyConfigFromResponse converts a policy returned by the API into its
+// apply manifest form, using the single-string cron expression
+func policyConfigFromResponse(response map[string]interface{}) models.PolicyConfig {
+	policy := models.PolicyConfig{
+		Name:     fmt.Sprintf("%v", response["name"]),
+		Strategy: fmt.Sprintf("%v", response["strategy"]),
+	}
+	policy.Enabled, _ = response["enabled"].(bool)
+
+	if cronConfig, ok := response["cron_config"].(map[string]interface{}); ok {
+		fields := make([]string, 0, 5)
+		for _, key := range []string{"minute", "hour", "day", "month", "weekday"} {
+			value := "*"
+			if v := stringOrNA(cronConfig, key); v != "N/A" && v != "" {
+				value = v
+			}
+			fields = append(fields, value)
+		}
+		policy.Cron = strings.Join(fields, " ")
+	}
+
+	if eventConfig, ok := response["event_config"].(map[string]interface{}); ok {
+		policy.EventConfig = map[string]interface{}{
+			"event_id":     eventConfig["event_id"],
+			"total_events": int(toFloat(eventConfig["total_events"])),
+		}
+	}
+
+	if gradualConfig, ok := response["gradual_config"].(map[string]interface{}); ok {
+		policy.GradualConfig = &models.GradualConfig{
+			BatchSize: int(toFloat(gradualConfig["batch_size"])),
+		}
+		if seconds := toFloat(gradualConfig["interval_seconds"]); seconds > 0 {
+			policy.GradualConfig.Interval = (time.Duration(seconds) * time.Second).String()
+		}
+	}
+
+	return policy
+}
+
+// writePolicyYAML prints a policy as a manifest accepted by 'certfix apply'
+func writePolicyYAML(policy models.PolicyConfig) error {
+	manifest := struct {
+		Policies []models.PolicyConfig `yaml:"policies"`
+	}{
+		Policies: []models.PolicyConfig{policy},
+	}
+
+	data, err := yaml.Marshal(&manifest)
+	if err != nil {
+		return fmt.Errorf("failed to encode policy: %w", err)
+	}
+
+	fmt.Fprintln(os.Stdout, "# Apply with: certfix apply <file>")
+	fmt.Fprint(os.Stdout, string(data))
+	return nil
+}
+
+func init() {
+	policyCmd.AddCommand(policyCloneCmd)
+	policyCmd.AddCommand(policyTemplateCmd)
+
+	// Clone command flags
+	policyCloneCmd.Flags().StringP("name", "n", "", "Name of the new policy (required)")
+	policyCloneCmd.Flags().BoolP("enabled", "e", false, "Enable or disable the copy (default: same as the source)")
+	policyCloneCmd.Flags().Bool("yaml", false, "Print the copy as apply-compatible YAML instead of creating it")
+	policyCloneCmd.MarkFlagRequired("name")
+
+	// Template command flags
+	policyTemplateCmd.Flags().StringP("strategy", "s", "gradual", "Strategy: gradual, maintenance-window, or events")
+	policyTemplateCmd.Flags().StringP("name", "n", "", "Name to put in the template")
+}
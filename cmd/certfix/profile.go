@@ -0,0 +1,230 @@
+package certfix
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// profileNamePattern restricts profile names to what can be a config key and a
+// file name
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage configuration profiles",
+	Long: `Manage configuration profiles, one per environment (e.g. prod, staging).
+
+Each profile has its own API endpoint and its own login session. The top-level
+settings of the config file form the "default" profile. Select a profile for a
+single command with --profile <name> or the CERTFIX_PROFILE environment
+variable, or for all commands with 'certfix profile use <name>'.`,
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a profile or change its endpoint",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.ToLower(args[0])
+		apiURL, _ := cmd.Flags().GetString("api-url")
+
+		if name == config.DefaultProfile {
+			return fmt.Errorf("the default profile uses the top-level settings: run 'certfix configure --api-url %s'", apiURL)
+		}
+		if !profileNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid profile name '%s': use lowercase letters, digits, '-' and '_'", args[0])
+		}
+		if err := validateURL(apiURL); err != nil {
+			return fmt.Errorf("invalid API URL: %w", err)
+		}
+
+		if err := config.Set("profiles."+name+".endpoint", apiURL); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to save profile: %w", err)
+		}
+
+		fmt.Printf("✓ Profile '%s' configured: %s\n", name, apiURL)
+		fmt.Printf("Run 'certfix login --profile %s' to log in to it.\n", name)
+		return nil
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List configuration profiles",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		current := config.GetProfile()
+		var profiles []map[string]interface{}
+		for _, name := range config.ProfileNames() {
+			_, err := auth.GetProfileToken(name)
+			_, secret := config.GetProfileSigningKey(name)
+			profiles = append(profiles, map[string]interface{}{
+				"name":      name,
+				"endpoint":  config.GetProfileEndpoint(name),
+				"logged_in": err == nil,
+				"signed":    secret != "",
+				"current":   name == current,
+			})
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(profiles, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "CURRENT\tNAME\tENDPOINT\tLOGGED IN\tSIGNED")
+		fmt.Fprintln(w, "-------\t----\t--------\t---------\t------")
+		for _, p := range profiles {
+			marker := ""
+			if p["current"].(bool) {
+				marker = "*"
+			}
+			loggedIn := "no"
+			if p["logged_in"].(bool) {
+				loggedIn = "yes"
+			}
+			signed := "no"
+			if p["signed"].(bool) {
+				signed = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, p["name"], p["endpoint"], loggedIn, signed)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a profile the active one for all commands",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.ToLower(args[0])
+		if !config.HasProfile(name) {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("profile '%s' not found: add it with 'certfix profile add %s --api-url <url>'", name, name)
+		}
+
+		if err := config.Set("profile", name); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to save active profile: %w", err)
+		}
+
+		fmt.Printf("✓ Active profile: %s (%s)\n", name, config.GetProfileEndpoint(name))
+		return nil
+	},
+}
+
+var profileSigningCmd = &cobra.Command{
+	Use:   "signing <name>",
+	Short: "Sign the API requests of a profile with an HMAC key",
+	Long: `Sign every API request of a profile with HMAC-SHA256, for installations that
+require signed calls on top of the login session.
+
+The secret is read without echo from the terminal, or from the first line of
+stdin when it is not a terminal, so it stays out of the shell history. It is
+stored in the config file. Each request then carries X-Certfix-Timestamp,
+X-Certfix-Content-SHA256 (the hex SHA-256 of the body), X-Certfix-Key-Id and
+X-Certfix-Signature, the hex HMAC of
+"METHOD\nPATH?QUERY\nTIMESTAMP\nCONTENT-SHA256".`,
+	Example: `  certfix profile signing prod --key-id cli-ops
+  vault read -field=secret kv/certfix | certfix profile signing prod --key-id cli-ops
+  certfix profile signing prod --off`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.ToLower(args[0])
+		keyID, _ := cmd.Flags().GetString("key-id")
+		off, _ := cmd.Flags().GetBool("off")
+
+		if !config.HasProfile(name) {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("profile '%s' not found: add it with 'certfix profile add %s --api-url <url>'", name, name)
+		}
+
+		if off {
+			if err := config.SetProfileSigningKey(name, "", ""); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to save signing key: %w", err)
+			}
+			fmt.Printf("✓ Requests of profile '%s' are no longer signed\n", name)
+			return nil
+		}
+
+		secret, err := readSigningSecret()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if err := config.SetProfileSigningKey(name, keyID, secret); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to save signing key: %w", err)
+		}
+		fmt.Printf("✓ Requests of profile '%s' are signed", name)
+		if keyID != "" {
+			fmt.Printf(" with key '%s'", keyID)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+// readSigningSecret reads a signing secret from the terminal without echo, or
+// from the first line of stdin
+func readSigningSecret() (string, error) {
+	var secret string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Signing secret: ")
+		data, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret: %w", err)
+		}
+		secret = string(data)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read secret from stdin: %w", err)
+		}
+		secret = line
+	}
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", fmt.Errorf("empty signing secret (use --off to stop signing)")
+	}
+	return secret, nil
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileAddCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileSigningCmd)
+
+	// Add command flags
+	profileAddCmd.Flags().String("api-url", "", "API endpoint URL of the environment (required)")
+	profileAddCmd.MarkFlagRequired("api-url")
+
+	// List command flags
+	profileListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Signing command flags
+	profileSigningCmd.Flags().String("key-id", "", "ID of the signing key, sent so the server knows which secret to check against")
+	profileSigningCmd.Flags().Bool("off", false, "Stop signing the requests of the profile")
+}
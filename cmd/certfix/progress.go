@@ -0,0 +1,68 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// progressBarWidth is the number of characters between the brackets
+const progressBarWidth = 30
+
+// progressBar draws a single-line progress bar on stderr. It is safe for
+// concurrent use. When stderr is not a terminal nothing is drawn, so output
+// redirected to a file or pipe stays clean.
+type progressBar struct {
+	mu      sync.Mutex
+	label   string
+	total   int
+	done    int
+	failed  int
+	enabled bool
+}
+
+func newProgressBar(label string, total int) *progressBar {
+	p := &progressBar{
+		label:   label,
+		total:   total,
+		enabled: term.IsTerminal(int(os.Stderr.Fd())),
+	}
+	p.draw()
+	return p
+}
+
+// Done records a finished item and redraws the bar
+func (p *progressBar) Done(ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if !ok {
+		p.failed++
+	}
+	p.draw()
+}
+
+// Finish ends the progress line
+func (p *progressBar) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.enabled {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+func (p *progressBar) draw() {
+	if !p.enabled || p.total == 0 {
+		return
+	}
+	filled := p.done * progressBarWidth / p.total
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	status := ""
+	if p.failed > 0 {
+		status = fmt.Sprintf(", %d failed", p.failed)
+	}
+	fmt.Fprintf(os.Stderr, "\r%s [%s] %d/%d%s", p.label, bar, p.done, p.total, status)
+}
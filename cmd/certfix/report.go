@@ -0,0 +1,537 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/notify"
+	"github.com/spf13/cobra"
+)
+
+// reportBucket groups valid certificates by time left until expiry. Only the
+// urgent buckets list their certificates.
+type reportBucket struct {
+	Label        string              `json:"label"`
+	Count        int                 `json:"count"`
+	Certificates []reportCertificate `json:"certificates,omitempty"`
+}
+
+type reportCertificate struct {
+	CommonName string `json:"common_name"`
+	Serial     string `json:"serial_number"`
+	Type       string `json:"type"`
+	ExpiresAt  string `json:"expires_at"`
+	DaysLeft   int    `json:"days_left"`
+}
+
+type reportService struct {
+	Hash  string `json:"service_hash"`
+	Name  string `json:"service_name"`
+	Group string `json:"service_group"`
+}
+
+type reportRelation struct {
+	ID      string `json:"relation_id"`
+	Source  string `json:"source_service"`
+	Related string `json:"related_service"`
+	Type    string `json:"relation_type"`
+}
+
+type reportKey struct {
+	Service   string `json:"service"`
+	Hash      string `json:"service_hash"`
+	KeyName   string `json:"key_name"`
+	KeyID     string `json:"key_id"`
+	ExpiresAt string `json:"expires_at"`
+	DaysLeft  int    `json:"days_left"`
+}
+
+type reportInstance struct {
+	ID       string `json:"id"`
+	Hostname string `json:"hostname"`
+	Service  string `json:"service_hash"`
+	LastSeen string `json:"last_seen_at"`
+}
+
+// complianceReport is everything certfix report collects
+type complianceReport struct {
+	GeneratedAt           time.Time        `json:"generated_at"`
+	Endpoint              string           `json:"endpoint"`
+	KeyWindowDays         int              `json:"key_window_days"`
+	TotalCertificates     int              `json:"total_certificates"`
+	TotalServices         int              `json:"total_services"`
+	CertificateBuckets    []reportBucket   `json:"certificate_buckets"`
+	ServicesWithoutPolicy []reportService  `json:"services_without_policy"`
+	DisabledRelations     []reportRelation `json:"disabled_relations"`
+	ExpiringKeys          []reportKey      `json:"expiring_keys"`
+	LostInstances         []reportInstance `json:"lost_instances"`
+	Warnings              []string         `json:"warnings,omitempty"`
+}
+
+// certificateBuckets are the expiry buckets in the order of
+// certificateBucket. Only the urgent ones list their certificates.
+var certificateBuckets = []struct {
+	label  string
+	listed bool
+}{
+	{"Expired", true},
+	{"Within 7 days", true},
+	{"8-30 days", true},
+	{"31-90 days", false},
+	{"More than 90 days", false},
+}
+
+// certificateBucket returns the index in certificateBuckets for a certificate
+// expiring after the given duration
+func certificateBucket(left time.Duration) int {
+	day := 24 * time.Hour
+	switch {
+	case left <= 0:
+		return 0
+	case left <= 7*day:
+		return 1
+	case left <= 30*day:
+		return 2
+	case left <= 90*day:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// reportConcurrency limits parallel per-service requests
+const reportConcurrency = 8
+
+// collectReport gathers the report data. Sources that fail are recorded as
+// warnings so a partial report is still produced.
+func collectReport(apiClient client.APIClient, token string, days int) *complianceReport {
+	report := &complianceReport{
+		GeneratedAt:   time.Now(),
+		Endpoint:      config.GetAPIEndpoint(),
+		KeyWindowDays: days,
+	}
+	warn := func(format string, args ...interface{}) {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	// Certificates by expiry bucket
+	for _, b := range certificateBuckets {
+		report.CertificateBuckets = append(report.CertificateBuckets, reportBucket{Label: b.label})
+	}
+	certs, err := newAPI().ListValidCertificates()
+	if err != nil {
+		warn("failed to list certificates: %v", err)
+	}
+	for _, cert := range certs {
+		expiresAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", cert["expires_at"]))
+		if err != nil {
+			continue
+		}
+		report.TotalCertificates++
+		left := time.Until(expiresAt)
+		i := certificateBucket(left)
+		bucket := &report.CertificateBuckets[i]
+		bucket.Count++
+		if certificateBuckets[i].listed {
+			bucket.Certificates = append(bucket.Certificates, reportCertificate{
+				CommonName: stringOrNA(cert, "common_name"),
+				Serial:     stringOrNA(cert, "serial_number"),
+				Type:       stringOrNA(cert, "certificate_type"),
+				ExpiresAt:  expiresAt.Format("2006-01-02"),
+				DaysLeft:   int(left.Hours() / 24),
+			})
+		}
+	}
+	for i := range report.CertificateBuckets {
+		list := report.CertificateBuckets[i].Certificates
+		sort.Slice(list, func(a, b int) bool { return list[a].ExpiresAt < list[b].ExpiresAt })
+	}
+
+	// Services
+	response, err := apiClient.GetWithAuth("/services", token)
+	if err != nil {
+		warn("failed to list services: %v", err)
+	}
+	services := parseArrayResponse(response)
+	report.TotalServices = len(services)
+	for _, svc := range services {
+		if stringOrNA(svc, "policy_id") == "N/A" && stringOrNA(svc, "policy_name") == "N/A" {
+			report.ServicesWithoutPolicy = append(report.ServicesWithoutPolicy, reportService{
+				Hash:  stringOrNA(svc, "service_hash"),
+				Name:  stringOrNA(svc, "service_name"),
+				Group: stringOrNA(svc, "service_group_name"),
+			})
+		}
+	}
+
+	// Disabled relations, one request per service
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, reportConcurrency)
+	)
+	for _, svc := range services {
+		wg.Add(1)
+		go func(svc map[string]interface{}) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			hash := stringOrNA(svc, "service_hash")
+			response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/matrix/relations", hash), token)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				warn("failed to list relations of %s: %v", hash, err)
+				return
+			}
+			for _, rel := range parseArrayResponse(response) {
+				if enabled, _ := rel["enabled"].(bool); enabled {
+					continue
+				}
+				source := stringOrNA(rel, "source_service_name")
+				if source == "N/A" {
+					source = stringOrNA(svc, "service_name")
+				}
+				report.DisabledRelations = append(report.DisabledRelations, reportRelation{
+					ID:      stringOrNA(rel, "relation_id"),
+					Source:  source,
+					Related: stringOrNA(rel, "related_service_name"),
+					Type:    stringOrNA(rel, "relation_type"),
+				})
+			}
+		}(svc)
+	}
+	wg.Wait()
+	sort.Slice(report.DisabledRelations, func(i, j int) bool {
+		return report.DisabledRelations[i].Source < report.DisabledRelations[j].Source
+	})
+
+	// Keys expiring soon
+	keys, failures := expiringKeys(apiClient, token, services, time.Now().AddDate(0, 0, days), false)
+	for _, failure := range failures {
+		warn("failed to list keys of %s", failure)
+	}
+	for _, key := range keys {
+		report.ExpiringKeys = append(report.ExpiringKeys, reportKey{
+			Service:   stringOrNA(key, "service_name"),
+			Hash:      stringOrNA(key, "service_hash"),
+			KeyName:   stringOrNA(key, "key_name"),
+			KeyID:     stringOrNA(key, "key_id"),
+			ExpiresAt: formatTimestamp(key["expires_at"], "2006-01-02", "N/A"),
+			DaysLeft:  key["days_left"].(int),
+		})
+	}
+
+	// Lost instances
+	instances, err := newAPI().ListAllInstances()
+	if err != nil {
+		warn("failed to list instances: %v", err)
+	}
+	markLostInstances(instances)
+	for _, instance := range instances {
+		if instance["status"] != "Lost" {
+			continue
+		}
+		report.LostInstances = append(report.LostInstances, reportInstance{
+			ID:       stringOrNA(instance, "id"),
+			Hostname: stringOrNA(instance, "hostname"),
+			Service:  stringOrNA(instance, "service_hash"),
+			LastSeen: formatTimestamp(instance["last_seen_at"], "2006-01-02 15:04", "N/A"),
+		})
+	}
+
+	return report
+}
+
+// urgentCertificates counts certificates that are expired or expire within 30 days
+func (r *complianceReport) urgentCertificates() int {
+	count := 0
+	for i, b := range r.CertificateBuckets {
+		if certificateBuckets[i].listed {
+			count += b.Count
+		}
+	}
+	return count
+}
+
+// summary lists the headline numbers, used by the notification and the
+// rendered reports
+func (r *complianceReport) summary() []string {
+	return []string{
+		fmt.Sprintf("Certificates expired or expiring within 30 days: %d of %d", r.urgentCertificates(), r.TotalCertificates),
+		fmt.Sprintf("Services without a policy: %d of %d", len(r.ServicesWithoutPolicy), r.TotalServices),
+		fmt.Sprintf("Disabled relations: %d", len(r.DisabledRelations)),
+		fmt.Sprintf("API keys expiring within %d days: %d", r.KeyWindowDays, len(r.ExpiringKeys)),
+		fmt.Sprintf("Lost instances: %d", len(r.LostInstances)),
+	}
+}
+
+// hasFindings reports whether anything in the report needs attention
+func (r *complianceReport) hasFindings() bool {
+	return r.urgentCertificates() > 0 || len(r.ServicesWithoutPolicy) > 0 || len(r.DisabledRelations) > 0 ||
+		len(r.ExpiringKeys) > 0 || len(r.LostInstances) > 0
+}
+
+const reportMarkdownTemplate = `# CertFix Compliance Report
+
+Generated {{ .GeneratedAt.Format "2006-01-02 15:04 MST" }} for {{ .Endpoint }}
+
+## Summary
+{{ range .Summary }}
+- {{ . }}{{ end }}
+{{ if .Warnings }}
+> **Incomplete report:**
+{{- range .Warnings }}
+> - {{ . }}{{ end }}
+{{ end }}
+## Certificates by Expiry
+
+| Expires | Certificates |
+|---|---|
+{{- range .CertificateBuckets }}
+| {{ .Label }} | {{ .Count }} |{{ end }}
+{{ range .CertificateBuckets }}{{ if .Certificates }}
+### {{ .Label }}
+
+| Common Name | Type | Serial | Expires | Days Left |
+|---|---|---|---|---|
+{{- range .Certificates }}
+| {{ md .CommonName }} | {{ .Type }} | {{ .Serial }} | {{ .ExpiresAt }} | {{ .DaysLeft }} |{{ end }}
+{{ end }}{{ end }}
+## Services Without a Policy
+{{ if .ServicesWithoutPolicy }}
+| Service | Hash | Group |
+|---|---|---|
+{{- range .ServicesWithoutPolicy }}
+| {{ md .Name }} | {{ .Hash }} | {{ md .Group }} |{{ end }}
+{{ else }}
+None.
+{{ end }}
+## Disabled Relations
+{{ if .DisabledRelations }}
+| Relation | Source | Related | Type |
+|---|---|---|---|
+{{- range .DisabledRelations }}
+| {{ .ID }} | {{ md .Source }} | {{ md .Related }} | {{ .Type }} |{{ end }}
+{{ else }}
+None.
+{{ end }}
+## API Keys Expiring Within {{ .KeyWindowDays }} Days
+{{ if .ExpiringKeys }}
+| Service | Key | Key ID | Expires | Days Left |
+|---|---|---|---|---|
+{{- range .ExpiringKeys }}
+| {{ md .Service }} ({{ .Hash }}) | {{ md .KeyName }} | {{ .KeyID }} | {{ .ExpiresAt }} | {{ .DaysLeft }} |{{ end }}
+{{ else }}
+None.
+{{ end }}
+## Lost Instances
+{{ if .LostInstances }}
+| Instance | Hostname | Service | Last Seen |
+|---|---|---|---|
+{{- range .LostInstances }}
+| {{ .ID }} | {{ md .Hostname }} | {{ .Service }} | {{ .LastSeen }} |{{ end }}
+{{ else }}
+None.
+{{ end }}`
+
+const reportHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>CertFix Compliance Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 70rem; color: #222; }
+h1 { margin-bottom: 0; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: .3rem; margin-top: 2rem; }
+.meta { color: #666; }
+table { border-collapse: collapse; width: 100%; margin: .5rem 0 1rem; }
+th, td { border: 1px solid #ddd; padding: .35rem .6rem; text-align: left; }
+th { background: #f5f5f5; }
+.warning { background: #fff4e5; border-left: 4px solid #f0a020; padding: .5rem 1rem; }
+.none { color: #2e7d32; }
+</style>
+</head>
+<body>
+<h1>CertFix Compliance Report</h1>
+<p class="meta">Generated {{ .GeneratedAt.Format "2006-01-02 15:04 MST" }} for {{ .Endpoint }}</p>
+
+<h2>Summary</h2>
+<ul>{{ range .Summary }}<li>{{ . }}</li>{{ end }}</ul>
+{{ if .Warnings }}<div class="warning"><strong>Incomplete report:</strong><ul>{{ range .Warnings }}<li>{{ . }}</li>{{ end }}</ul></div>{{ end }}
+
+<h2>Certificates by Expiry</h2>
+<table><tr><th>Expires</th><th>Certificates</th></tr>
+{{ range .CertificateBuckets }}<tr><td>{{ .Label }}</td><td>{{ .Count }}</td></tr>{{ end }}
+</table>
+{{ range .CertificateBuckets }}{{ if .Certificates }}<h3>{{ .Label }}</h3>
+<table><tr><th>Common Name</th><th>Type</th><th>Serial</th><th>Expires</th><th>Days Left</th></tr>
+{{ range .Certificates }}<tr><td>{{ .CommonName }}</td><td>{{ .Type }}</td><td>{{ .Serial }}</td><td>{{ .ExpiresAt }}</td><td>{{ .DaysLeft }}</td></tr>{{ end }}
+</table>{{ end }}{{ end }}
+
+<h2>Services Without a Policy</h2>
+{{ if .ServicesWithoutPolicy }}<table><tr><th>Service</th><th>Hash</th><th>Group</th></tr>
+{{ range .ServicesWithoutPolicy }}<tr><td>{{ .Name }}</td><td>{{ .Hash }}</td><td>{{ .Group }}</td></tr>{{ end }}
+</table>{{ else }}<p class="none">None.</p>{{ end }}
+
+<h2>Disabled Relations</h2>
+{{ if .DisabledRelations }}<table><tr><th>Relation</th><th>Source</th><th>Related</th><th>Type</th></tr>
+{{ range .DisabledRelations }}<tr><td>{{ .ID }}</td><td>{{ .Source }}</td><td>{{ .Related }}</td><td>{{ .Type }}</td></tr>{{ end }}
+</table>{{ else }}<p class="none">None.</p>{{ end }}
+
+<h2>API Keys Expiring Within {{ .KeyWindowDays }} Days</h2>
+{{ if .ExpiringKeys }}<table><tr><th>Service</th><th>Key</th><th>Key ID</th><th>Expires</th><th>Days Left</th></tr>
+{{ range .ExpiringKeys }}<tr><td>{{ .Service }} ({{ .Hash }})</td><td>{{ .KeyName }}</td><td>{{ .KeyID }}</td><td>{{ .ExpiresAt }}</td><td>{{ .DaysLeft }}</td></tr>{{ end }}
+</table>{{ else }}<p class="none">None.</p>{{ end }}
+
+<h2>Lost Instances</h2>
+{{ if .LostInstances }}<table><tr><th>Instance</th><th>Hostname</th><th>Service</th><th>Last Seen</th></tr>
+{{ range .LostInstances }}<tr><td>{{ .ID }}</td><td>{{ .Hostname }}</td><td>{{ .Service }}</td><td>{{ .LastSeen }}</td></tr>{{ end }}
+</table>{{ else }}<p class="none">None.</p>{{ end }}
+</body>
+</html>
+`
+
+// reportView adds the computed summary to the report for the templates
+type reportView struct {
+	*complianceReport
+	Summary []string
+}
+
+// renderReport writes the report in the given format
+func renderReport(w io.Writer, report *complianceReport, format string) error {
+	view := reportView{complianceReport: report, Summary: report.summary()}
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "md":
+		// Pipes would break table cells
+		funcs := template.FuncMap{"md": func(s string) string { return strings.ReplaceAll(s, "|", `\|`) }}
+		tmpl := template.Must(template.New("report").Funcs(funcs).Parse(reportMarkdownTemplate))
+		return tmpl.Execute(w, view)
+	case "html":
+		tmpl := htmltemplate.Must(htmltemplate.New("report").Parse(reportHTMLTemplate))
+		return tmpl.Execute(w, view)
+	default:
+		return fmt.Errorf("invalid format '%s' (valid: html, md, json)", format)
+	}
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate an expiry and compliance report",
+	Long: `Generate a single shareable report covering certificates by expiry bucket,
+services without a policy, disabled relations, API keys expiring soon and lost
+instances.
+
+The format defaults to the extension of --out (.html, .md or .json) and to
+Markdown when writing to stdout. Sources that cannot be read are listed as
+warnings in the report instead of failing it.`,
+	Example: `  certfix report --out report.html
+  certfix report --format md > report.md
+  certfix report --format json --days 14 --notify slack://hooks.slack.com/services/...`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		days, _ := cmd.Flags().GetInt("days")
+
+		if format == "" {
+			switch strings.ToLower(filepath.Ext(out)) {
+			case ".html", ".htm":
+				format = "html"
+			case ".json":
+				format = "json"
+			default:
+				format = "md"
+			}
+		}
+		if format != "html" && format != "md" && format != "json" {
+			return fmt.Errorf("invalid format '%s' (valid: html, md, json)", format)
+		}
+
+		notifiers, err := notifiersFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		report := collectReport(apiClient, token, days)
+
+		if out == "" {
+			if err := renderReport(os.Stdout, report, format); err != nil {
+				return err
+			}
+		} else {
+			f, err := os.Create(out)
+			if err != nil {
+				return fmt.Errorf("failed to create report file: %w", err)
+			}
+			if err := renderReport(f, report, format); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to write report file: %w", err)
+			}
+			fmt.Printf("✓ Report written to %s\n", out)
+		}
+
+		for _, warning := range report.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+
+		msg := notify.Message{
+			Title:    "CertFix compliance report",
+			Severity: notify.SeverityInfo,
+			Lines:    report.summary(),
+		}
+		if report.hasFindings() {
+			msg.Severity = notify.SeverityWarning
+		}
+		if out != "" {
+			msg.Lines = append(msg.Lines, "Full report: "+out)
+		}
+		sendNotification(notifiers, msg)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	// Report command flags
+	reportCmd.Flags().StringP("format", "f", "", "Report format (html, md, json; default: from --out extension, else md)")
+	reportCmd.Flags().String("out", "", "Write the report to this file instead of stdout")
+	reportCmd.Flags().IntP("days", "d", 30, "Report API keys expiring within this many days")
+	enableNotify(reportCmd)
+}
@@ -0,0 +1,227 @@
+package certfix
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// enableServiceByName adds a --by-name flag to commands whose first argument is
+// a service hash. When the flag is set the hash argument is omitted and the
+// name is resolved to a hash before the command runs.
+func enableServiceByName(cmds ...*cobra.Command) {
+	enableArgResolver("by-name", "Select the service by name instead of hash", resolveServiceHash, cmds...)
+}
+
+// enableEventByExternalID adds a --by-external-id flag to commands whose first
+// argument is an event ID, resolving the external ID to the event ID.
+func enableEventByExternalID(cmds ...*cobra.Command) {
+	enableArgResolver("by-external-id", "Select the event by external ID instead of event ID", resolveEventID, cmds...)
+}
+
+// enableServiceGroupByName adds a --by-name flag to commands whose first
+// argument is a service group ID
+func enableServiceGroupByName(cmds ...*cobra.Command) {
+	enableArgResolver("by-name", "Select the service group by name instead of ID", resolveServiceGroupID, cmds...)
+}
+
+// enableIdentifierResolver lets the first positional argument of commands be
+// any identifier of a kind: a hash or ID, an external ID or a (partial) name.
+// It is resolved to the hash or ID before the command runs. An identifier that
+// matches nothing is passed on unchanged so the API reports it, and commands
+// whose argument was filled in by an enableArgResolver flag are left alone.
+func enableIdentifierResolver(kind api.Kind, cmds ...*cobra.Command) {
+	enableResolver(kind, resolveArgument, cmds...)
+}
+
+// enableExactIdentifierResolver is enableIdentifierResolver for commands that
+// change or delete the resource: a partial name is not resolved, so a typo
+// cannot select another resource. --by-name still accepts one.
+func enableExactIdentifierResolver(kind api.Kind, cmds ...*cobra.Command) {
+	enableResolver(kind, resolveExactArgument, cmds...)
+}
+
+func enableResolver(kind api.Kind, resolve func(api.Kind, string) (string, error), cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		run := c.RunE
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 && !resolverFlagUsed(cmd) {
+				resolved, err := resolve(kind, args[0])
+				if err != nil {
+					cmd.SilenceUsage = true
+					return err
+				}
+				args = append([]string{resolved}, args[1:]...)
+			}
+			return run(cmd, args)
+		}
+	}
+}
+
+// resolverFlags are the flags added by enableArgResolver
+var resolverFlags = []string{"by-name", "by-external-id"}
+
+// resolverFlagUsed reports whether the first argument came from a resolver flag
+func resolverFlagUsed(cmd *cobra.Command) bool {
+	for _, name := range resolverFlags {
+		if value, err := cmd.Flags().GetString(name); err == nil && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveIdentifier resolves an identifier of a kind to its hash or ID for a
+// read command. A fresh local cache is tried first; the API is asked when the
+// cache has no unique match, e.g. for resources created since the last sync.
+func resolveIdentifier(kind api.Kind, identifier string) (string, error) {
+	match, err := resolveMatch(kind, identifier)
+	if err != nil {
+		return "", err
+	}
+	return match.ID, nil
+}
+
+// resolveMatch resolves an identifier like resolveIdentifier, returning the
+// resource it matched so its name can be shown
+func resolveMatch(kind api.Kind, identifier string) (*api.Match, error) {
+	if cached := freshCacheAPI(); cached != nil {
+		if match, err := cached.NewResolver().Resolve(kind, identifier); err == nil {
+			return match, nil
+		}
+	}
+	return newAPI().NewResolver().Resolve(kind, identifier)
+}
+
+// resolveExactMatch resolves an identifier only by its hash, ID, external ID
+// or exact name. Commands that change or delete a resource use it so a partial
+// name cannot select the wrong one. It always asks the API: a cached name may
+// since have been renamed or given to another resource.
+func resolveExactMatch(kind api.Kind, identifier string) (*api.Match, error) {
+	return newAPI().NewResolver().ResolveExact(kind, identifier)
+}
+
+// resolveByName resolves the value of a --by-name flag. The flag selects the
+// resource of commands that change it as well as of reads, so the API is
+// always asked rather than the cache.
+func resolveByName(kind api.Kind, name string) (string, error) {
+	match, err := newAPI().NewResolver().Resolve(kind, name)
+	if err != nil {
+		return "", err
+	}
+	return match.ID, nil
+}
+
+// resolveArgument resolves a positional argument like resolveIdentifier, but
+// returns it unchanged when nothing matches
+func resolveArgument(kind api.Kind, identifier string) (string, error) {
+	resolved, err := resolveIdentifier(kind, identifier)
+	var notFound *api.NotFoundError
+	if errors.As(err, &notFound) {
+		return identifier, nil
+	}
+	return resolved, err
+}
+
+// resolveExactArgument is resolveArgument without partial names
+func resolveExactArgument(kind api.Kind, identifier string) (string, error) {
+	match, err := resolveExactMatch(kind, identifier)
+	var notFound *api.NotFoundError
+	if errors.As(err, &notFound) {
+		return identifier, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return match.ID, nil
+}
+
+// enableArgResolver adds a flag that replaces the first positional argument.
+// When the flag is set the argument is omitted and resolve turns the flag value
+// into the argument before the command runs.
+func enableArgResolver(flag, usage string, resolve func(string) (string, error), cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().String(flag, "", usage)
+
+		validateArgs := c.Args
+		run := c.RunE
+
+		c.Args = func(cmd *cobra.Command, args []string) error {
+			if value, _ := cmd.Flags().GetString(flag); value != "" {
+				// Validate as if the argument had been passed positionally
+				args = append([]string{value}, args...)
+			}
+			if validateArgs != nil {
+				return validateArgs(cmd, args)
+			}
+			return nil
+		}
+
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			if value, _ := cmd.Flags().GetString(flag); value != "" {
+				resolved, err := resolve(value)
+				if err != nil {
+					cmd.SilenceUsage = true
+					return err
+				}
+				args = append([]string{resolved}, args...)
+			}
+			return run(cmd, args)
+		}
+	}
+}
+
+// resolveServiceHash looks up a service by name. An exact (case-insensitive)
+// match wins; otherwise a single partial match is accepted. Multiple candidates
+// produce an error listing them so the user can disambiguate.
+func resolveServiceHash(name string) (string, error) {
+	return resolveByName(api.KindService, name)
+}
+
+// resolveEventID looks up an event by its external ID
+func resolveEventID(externalID string) (string, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return "", err
+	}
+
+	apiClient := newAPIClient()
+
+	evento, err := findEventByExternalID(apiClient, token, externalID)
+	if err != nil {
+		return "", err
+	}
+	if evento == nil {
+		return "", fmt.Errorf("no event found with external ID '%s'", externalID)
+	}
+	return fmt.Sprintf("%v", evento["event_id"]), nil
+}
+
+// findEventByExternalID returns the event with the given external ID, or nil
+// when there is none
+func findEventByExternalID(apiClient client.APIClient, token, externalID string) (map[string]interface{}, error) {
+	response, err := apiClient.GetWithAuth("/events", token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	for _, evento := range parseArrayResponse(response) {
+		if stringOrNA(evento, "external_id") == externalID {
+			return evento, nil
+		}
+	}
+	return nil, nil
+}
+
+// looksLikeID reports whether s has the shape of an API-assigned ID
+func looksLikeID(s string) bool {
+	return api.LooksLikeID(s)
+}
+
+// resolveServiceGroupID looks up a service group by name
+func resolveServiceGroupID(name string) (string, error) {
+	return resolveByName(api.KindServiceGroup, name)
+}
@@ -0,0 +1,249 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// resourceKind describes a resource type for the generic get and delete
+// commands. Adding an entry to resourceKinds makes it available to both.
+type resourceKind struct {
+	plural   string
+	singular string
+	aliases  []string
+	kind     api.Kind
+
+	// listPath lists every resource; itemPath is a format string taking the
+	// resource's hash or ID
+	listPath string
+	itemPath string
+
+	// deletable marks kinds that 'certfix delete' may remove
+	deletable bool
+
+	// printTable renders resources as a table
+	printTable func(items []map[string]interface{})
+}
+
+var resourceKinds = []*resourceKind{
+	{
+		plural:     "services",
+		singular:   "service",
+		aliases:    []string{"svc"},
+		kind:       api.KindService,
+		listPath:   "/services",
+		itemPath:   "/services/%s",
+		deletable:  true,
+		printTable: func(items []map[string]interface{}) { printServicesTable(items, nil) },
+	},
+	{
+		plural:     "policies",
+		singular:   "policy",
+		aliases:    []string{"pol"},
+		kind:       api.KindPolicy,
+		listPath:   "/policies",
+		itemPath:   "/policies/%s",
+		printTable: printPoliciesTable,
+	},
+	{
+		plural:     "events",
+		singular:   "event",
+		aliases:    []string{"ev", "eventos"},
+		kind:       api.KindEvent,
+		listPath:   "/events",
+		itemPath:   "/events/%s",
+		printTable: printEventsTable,
+	},
+}
+
+// lookupResourceKind finds a resource kind by its plural, singular or alias
+func lookupResourceKind(name string) (*resourceKind, error) {
+	name = strings.ToLower(name)
+	for _, rk := range resourceKinds {
+		if name == rk.plural || name == rk.singular {
+			return rk, nil
+		}
+		for _, alias := range rk.aliases {
+			if name == alias {
+				return rk, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown resource type '%s' (valid: %s)", name, strings.Join(resourceKindNames(false), ", "))
+}
+
+// resourceKindNames lists the plural names of the registered kinds, only the
+// deletable ones when deletable is set
+func resourceKindNames(deletable bool) []string {
+	var names []string
+	for _, rk := range resourceKinds {
+		if !deletable || rk.deletable {
+			names = append(names, rk.plural)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeResourceKinds completes the resource type argument
+func completeResourceKinds(deletable bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return resourceKindNames(deletable), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get <resource-type> [name]",
+	Short: "Display one or many resources",
+	Long: `Display resources of a type, or a single one when a name is given. The name may
+be a hash, an ID, an external ID (events) or a name, as for the resource's own
+commands.
+
+Resource types: services (svc), policies (pol), events (ev).`,
+	Example: `  certfix get services
+  certfix get policy nightly
+  certfix get events -o json`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeResourceKinds(false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		rk, err := lookupResourceKind(args[0])
+		if err != nil {
+			return err
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		var items []map[string]interface{}
+		if len(args) == 2 {
+			id, err := resolveArgument(rk.kind, args[1])
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			response, err := apiClient.GetWithAuth(fmt.Sprintf(rk.itemPath, id), token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to get %s: %w", rk.singular, err)
+			}
+			if outputFormat == "json" {
+				data, _ := json.MarshalIndent(response, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			items = []map[string]interface{}{response}
+		} else {
+			response, err := apiClient.GetWithAuth(rk.listPath, token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to list %s: %w", rk.plural, err)
+			}
+			items = parseArrayResponse(response)
+			if outputFormat == "json" {
+				data, _ := json.MarshalIndent(items, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+		}
+
+		if len(items) == 0 {
+			fmt.Printf("No %s found.\n", rk.plural)
+			return nil
+		}
+		rk.printTable(items)
+		return nil
+	},
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <resource-type> <name>",
+	Short: "Delete a resource",
+	Long: `Delete a single resource by its hash, ID or exact name. Partial names are
+not accepted, so a typo cannot select another resource.
+
+Resource types: services (svc).`,
+	Example: `  certfix delete service a1b2c3d4
+  certfix delete service payments-api --force`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeResourceKinds(true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rk, err := lookupResourceKind(args[0])
+		if err != nil {
+			return err
+		}
+		if !rk.deletable {
+			return fmt.Errorf("%s cannot be deleted with 'certfix delete' (valid: %s)", rk.plural, strings.Join(resourceKindNames(true), ", "))
+		}
+
+		match, err := resolveExactMatch(rk.kind, args[1])
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		id := match.ID
+
+		if err := requirePermission(cmd, rk.singular+":delete"); err != nil {
+			return err
+		}
+
+		// Confirm deletion by typing the hash or ID, showing the name it was
+		// resolved to so a wrong match is noticed
+		target := id
+		if match.Name != "" && match.Name != id {
+			target = fmt.Sprintf("%s (%s)", match.Name, id)
+		}
+		confirmed, err := confirmTyped(cmd, fmt.Sprintf("This permanently deletes %s %s.", rk.singular, target), id)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		if _, err := apiClient.DeleteWithAuth(fmt.Sprintf(rk.itemPath, id), token); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to delete %s: %w", rk.singular, err)
+		}
+
+		fmt.Printf("✓ %s%s deleted successfully\n", strings.ToUpper(rk.singular[:1]), rk.singular[1:])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(deleteCmd)
+
+	// Get command flags
+	getCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Delete command flags
+	deleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+}
@@ -0,0 +1,159 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+// includeDeletedParam asks list endpoints to return deleted resources that can
+// still be restored. Servers that delete for good ignore it.
+const includeDeletedParam = "include_deleted"
+
+// isDeleted reports whether a listed resource is deleted but restorable
+func isDeleted(item map[string]interface{}) bool {
+	if deleted, ok := item["deleted"].(bool); ok {
+		return deleted
+	}
+	return stringField(item, "deleted_at") != ""
+}
+
+// restoreHint is printed after a delete the server reports as restorable
+func restoreHint(response map[string]interface{}, command string) {
+	if stringField(response, "deleted_at") == "" {
+		return
+	}
+	if until := stringField(response, "restorable_until"); until != "" {
+		fmt.Printf("It can be restored until %s with '%s'\n", formatTimestamp(until, "2006-01-02 15:04", until), command)
+		return
+	}
+	fmt.Printf("It can be restored with '%s'\n", command)
+}
+
+// restoreResource undeletes the resource at path. Servers that do not keep
+// deleted resources, and resources that are gone for good, answer 404.
+func restoreResource(apiClient client.APIClient, token, path, kind, id string) (map[string]interface{}, error) {
+	status, body, err := apiClient.RawWithAuth(http.MethodPost, path+"/restore", nil, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore %s: %w", kind, err)
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("no deleted %s %s can be restored: it was deleted for good, or the server does not keep deleted resources", kind, id)
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("failed to restore %s: request failed with status %d: %s", kind, status, string(body))
+	}
+
+	restored := make(map[string]interface{})
+	if len(body) > 0 {
+		json.Unmarshal(body, &restored)
+	}
+	return restored, nil
+}
+
+var servicesRestoreCmd = &cobra.Command{
+	Use:   "restore <service-hash>",
+	Short: "Restore a deleted service",
+	Long: `Restore a service deleted with 'certfix services delete', together with its
+keys and certificates, on servers that keep deleted services recoverable.
+'certfix services list --include-deleted' lists the services that can be
+restored. The full hash is needed, since names only resolve to services that
+exist.`,
+	Example: `  certfix services list --include-deleted
+  certfix services restore a1b2c3`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+		serviceHash := args[0]
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		// Whoever may delete a service may undo it
+		if err := requirePermission(cmd, "service:delete"); err != nil {
+			return err
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		apiClient := newAPIClient()
+		cmd.SilenceUsage = true
+
+		log.Infof("Restoring service: %s", serviceHash)
+		restored, err := restoreResource(apiClient, token, "/services/"+serviceHash, "service", serviceHash)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(restored, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Printf("✓ Service %s restored\n", serviceHash)
+		if name := stringField(restored, "service_name"); name != "" {
+			fmt.Printf("Name:         %s\n", name)
+		}
+		return nil
+	},
+}
+
+var policyRestoreCmd = &cobra.Command{
+	Use:   "restore <policy-id>",
+	Short: "Restore a deleted policy",
+	Long: `Restore a policy deleted with 'certfix policy delete', on servers that keep
+deleted policies recoverable. 'certfix policy list --include-deleted' lists
+the policies that can be restored.`,
+	Example: `  certfix policy list --include-deleted
+  certfix policy restore 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log := logger.GetLogger()
+		policyID := args[0]
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		// Whoever may delete a policy may undo it
+		if err := requirePermission(cmd, "policy:delete"); err != nil {
+			return err
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		apiClient := newAPIClient()
+		cmd.SilenceUsage = true
+
+		log.Infof("Restoring policy: %s", policyID)
+		restored, err := restoreResource(apiClient, token, "/policies/"+policyID, "policy", policyID)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(restored, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Printf("✓ Policy %s restored\n", policyID)
+		if name := stringField(restored, "name"); name != "" {
+			fmt.Printf("Name:         %s\n", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesRestoreCmd)
+	servicesRestoreCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	policyCmd.AddCommand(policyRestoreCmd)
+	policyRestoreCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
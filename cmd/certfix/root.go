@@ -1,17 +1,34 @@
 package certfix
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/certfix/certfix-cli/internal/auth"
 	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/internal/history"
+	"github.com/certfix/certfix-cli/internal/telemetry"
+	"github.com/certfix/certfix-cli/internal/tracing"
+	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/certfix/certfix-cli/pkg/logger"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	verbose bool
+	verbose     bool
+	noHistory   bool
+	assumeYes   bool
+	offline     bool
+	profileName string
 )
 
+// profileEnv selects the profile when --profile is not given
+const profileEnv = "CERTFIX_PROFILE"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "certfix",
@@ -24,13 +41,109 @@ application configurations, and infrastructure operations.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// Initialize logger
 		logger.InitLogger(verbose)
+		auth.SetAllowExpired(offline)
+
+		// Warn early about an incompatible server instead of failing later
+		checkServerCompatibility(cmd)
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	start := time.Now()
+
+	// The command is recorded as the root span of the API requests it makes
+	stopTracing := tracing.Start(strings.TrimPrefix(Version, "v"))
+	spanName := rootCmd.Name()
+	if found, _, err := rootCmd.Find(os.Args[1:]); err == nil {
+		spanName = found.CommandPath()
+	}
+	endSpan := tracing.Span(spanName)
+
+	cmd, err := rootCmd.ExecuteC()
+	endSpan(err)
+	stopTracing()
+	if !noHistory && client.StateChanged() {
+		recordHistory(cmd, start, err)
+	}
+
+	exitStatus := 0
+	if err != nil {
+		exitStatus = 1
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			exitStatus = exitErr.code
+		}
+	}
+	if cmd != nil {
+		command := strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+		telemetry.Send(telemetry.NewEvent(command, time.Since(start), exitStatus, Version))
+	}
+
+	if err != nil {
+		os.Exit(exitStatus)
+	}
+}
+
+// exitError makes Execute exit with a specific status instead of 1, for
+// commands whose exit status tells scripts more than success or failure
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// sensitiveFlagWords mark flags whose values are never written to the history.
+// Request bodies and payloads are included since they can hold anything, and
+// --notify since the path of a Slack or Teams webhook URL is its secret.
+var sensitiveFlagWords = []string{"password", "token", "secret", "integration-key", "data", "payload", "body", "notify"}
+
+// unrecordedArgCommands are commands whose arguments are never written to the
+// history, such as the path and query string of 'certfix api'
+var unrecordedArgCommands = []string{"api"}
+
+// recordHistory appends the invocation to the local history. Failing to write
+// it never fails the command.
+func recordHistory(cmd *cobra.Command, start time.Time, err error) {
+	entry := history.Entry{
+		Time:     start.UTC(),
+		Command:  strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "),
+		Args:     cmd.Flags().Args(),
+		Result:   history.ResultOK,
+		Endpoint: config.GetAPIEndpoint(),
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	for _, command := range unrecordedArgCommands {
+		if entry.Command == command && len(entry.Args) > 0 {
+			entry.Args = []string{"***"}
+		}
+	}
+	if len(entry.Args) > 0 {
+		entry.Target = entry.Args[0]
+	}
+	if err != nil {
+		entry.Result = history.ResultError
+		entry.Error = err.Error()
+	}
+
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if entry.Flags == nil {
+			entry.Flags = make(map[string]string)
+		}
+		value := f.Value.String()
+		for _, word := range sensitiveFlagWords {
+			if strings.Contains(f.Name, word) {
+				value = "***"
+			}
+		}
+		entry.Flags[f.Name] = value
+	})
+
+	if err := history.Append(entry); err != nil {
+		logger.GetLogger().Debugf("Failed to record history: %v", err)
 	}
 }
 
@@ -39,8 +152,51 @@ func init() {
 
 	// Global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false, "do not record this command in the local history")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "answer yes to confirmation prompts")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "timeout for each API request, overriding the config (e.g. 120s)")
+	rootCmd.PersistentFlags().Int("retries", 0, "retries for failed idempotent API requests, overriding the config")
+	rootCmd.PersistentFlags().String("field-manager", "", "name of the operator or automation making changes, recorded in the server's audit log (overrides the config)")
+	rootCmd.PersistentFlags().String("reason", "", "why changes are made, e.g. a ticket number, recorded in the server's audit log")
+	rootCmd.PersistentFlags().Bool("trace", false, "print each API request with its status, duration and idempotency key to stderr")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "configuration profile to use instead of the active one (or set "+profileEnv+")")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "serve reads from the local cache instead of the API (see 'certfix cache sync')")
 }
 
 func initConfig() {
 	config.InitConfig("")
+
+	name := profileName
+	if name == "" {
+		name = os.Getenv(profileEnv)
+	}
+	if err := config.SetProfile(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	config.SetRunOverrides(settingOverrides())
+}
+
+// settingFlags maps the global flags that override a setting for one run to
+// the setting they override
+var settingFlags = map[string]string{
+	"timeout":       "timeout",
+	"retries":       "retry_attempts",
+	"field-manager": "field_manager",
+	"reason":        "change_reason",
+	"trace":         "trace",
+}
+
+// settingOverrides returns the settings given with global flags on this run.
+// They are passed to config rather than bound to viper, which would write them
+// into the config file with the next change to it.
+func settingOverrides() map[string]string {
+	overrides := map[string]string{}
+	for flagName, key := range settingFlags {
+		if f := rootCmd.PersistentFlags().Lookup(flagName); f != nil && f.Changed {
+			overrides[key] = f.Value.String()
+		}
+	}
+	return overrides
 }
@@ -8,9 +8,9 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/certfix/certfix-cli/internal/api"
 	"github.com/certfix/certfix-cli/internal/auth"
 	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/certfix/certfix-cli/pkg/logger"
 	"github.com/spf13/cobra"
 )
@@ -42,8 +42,7 @@ var serviceGroupsListCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Determine endpoint
 		var apiEndpoint string
@@ -53,7 +52,7 @@ var serviceGroupsListCmd = &cobra.Command{
 			apiEndpoint = "/service-groups"
 		}
 
-		log.Debugf("GET %s%s", endpoint, apiEndpoint)
+		log.Debugf("GET %s%s", config.GetAPIEndpoint(), apiEndpoint)
 
 		// Make request
 		response, err := apiClient.GetWithAuth(apiEndpoint, token)
@@ -74,9 +73,8 @@ var serviceGroupsListCmd = &cobra.Command{
 			}
 		}
 
-		if len(serviceGroups) == 0 {
-			fmt.Println("No service groups found.")
-			return nil
+		if err := sortItems(cmd, serviceGroupSortColumns, serviceGroups); err != nil {
+			return err
 		}
 
 		// Output format
@@ -86,6 +84,11 @@ var serviceGroupsListCmd = &cobra.Command{
 			return nil
 		}
 
+		if len(serviceGroups) == 0 {
+			fmt.Println("No service groups found.")
+			return nil
+		}
+
 		// Table format
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 		fmt.Fprintln(w, "ID\tNAME\tDESCRIPTION\tSTATUS\tCREATED AT")
@@ -119,7 +122,7 @@ var serviceGroupsListCmd = &cobra.Command{
 }
 
 var serviceGroupsGetCmd = &cobra.Command{
-	Use:   "get <service-group-id>",
+	Use:   "get <service-group-id|name>",
 	Short: "Get details of a specific service group",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -134,8 +137,7 @@ var serviceGroupsGetCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Make request
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/service-groups/%s", serviceGroupID), token)
@@ -198,8 +200,7 @@ var serviceGroupsCreateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -232,7 +233,7 @@ var serviceGroupsCreateCmd = &cobra.Command{
 }
 
 var serviceGroupsUpdateCmd = &cobra.Command{
-	Use:   "update <service-group-id>",
+	Use:   "update <service-group-id|name>",
 	Short: "Update an existing service group",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -242,27 +243,38 @@ var serviceGroupsUpdateCmd = &cobra.Command{
 		// Get flags
 		name, _ := cmd.Flags().GetString("name")
 		description, _ := cmd.Flags().GetString("description")
-		enabled := cmd.Flags().Changed("enabled")
+		clearDescription, _ := cmd.Flags().GetBool("clear-description")
 		enabledValue, _ := cmd.Flags().GetBool("enabled")
 
-		// Build update payload
+		if cmd.Flags().Changed("description") && clearDescription {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("--description and --clear-description cannot be used together")
+		}
+
+		// Build update payload from the flags that were actually given
 		payload := make(map[string]interface{})
 
-		if name != "" {
+		if cmd.Flags().Changed("name") {
+			if strings.TrimSpace(name) == "" {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("name cannot be empty")
+			}
 			payload["name"] = name
 		}
 
-		if description != "" {
+		if cmd.Flags().Changed("description") {
 			payload["description"] = description
+		} else if clearDescription {
+			payload["description"] = nil
 		}
 
-		if enabled {
+		if cmd.Flags().Changed("enabled") {
 			payload["enabled"] = enabledValue
 		}
 
 		if len(payload) == 0 {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("no fields to update (use --name, --description, or --enabled)")
+			return fmt.Errorf("no fields to update (use --name, --description, --clear-description, or --enabled)")
 		}
 
 		// Get authentication token
@@ -273,8 +285,14 @@ var serviceGroupsUpdateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
+
+		// Read the service group first so the update carries its ETag and fails
+		// rather than overwrite a change made meanwhile
+		if _, err := apiClient.GetWithAuth(fmt.Sprintf("/service-groups/%s", serviceGroupID), token); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get service group: %w", err)
+		}
 
 		log.Infof("Updating service group: %s", serviceGroupID)
 
@@ -300,7 +318,7 @@ var serviceGroupsUpdateCmd = &cobra.Command{
 }
 
 var serviceGroupsEnableCmd = &cobra.Command{
-	Use:   "enable <service-group-id>",
+	Use:   "enable <service-group-id|name>",
 	Short: "Enable a service group",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -314,8 +332,7 @@ var serviceGroupsEnableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -335,7 +352,7 @@ var serviceGroupsEnableCmd = &cobra.Command{
 }
 
 var serviceGroupsDisableCmd = &cobra.Command{
-	Use:   "disable <service-group-id>",
+	Use:   "disable <service-group-id|name>",
 	Short: "Disable a service group",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -349,8 +366,7 @@ var serviceGroupsDisableCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -370,7 +386,7 @@ var serviceGroupsDisableCmd = &cobra.Command{
 }
 
 var serviceGroupsDeleteCmd = &cobra.Command{
-	Use:     "delete <service-group-id>",
+	Use:     "delete <service-group-id|name>",
 	Aliases: []string{"rm", "remove"},
 	Short:   "Delete a service group",
 	Args:    cobra.ExactArgs(1),
@@ -378,16 +394,18 @@ var serviceGroupsDeleteCmd = &cobra.Command{
 		log := logger.GetLogger()
 		serviceGroupID := args[0]
 
+		if err := requirePermission(cmd, "service-group:delete"); err != nil {
+			return err
+		}
+
 		// Confirm deletion
-		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Are you sure you want to delete service group %s? (y/N): ", serviceGroupID)
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete service group %s?", serviceGroupID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
 		}
 
 		// Get authentication token
@@ -398,8 +416,7 @@ var serviceGroupsDeleteCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		log.Infof("Deleting service group: %s", serviceGroupID)
 
@@ -415,6 +432,15 @@ var serviceGroupsDeleteCmd = &cobra.Command{
 	},
 }
 
+// serviceGroupSortColumns are the --sort-by columns of 'service-groups list'
+var serviceGroupSortColumns = sortColumns{
+	"id":          "service_group_id",
+	"name":        "name",
+	"description": "description",
+	"status":      "enabled",
+	"created_at":  "created_at",
+}
+
 func init() {
 	rootCmd.AddCommand(serviceGroupsCmd)
 
@@ -430,6 +456,7 @@ func init() {
 	// List command flags
 	serviceGroupsListCmd.Flags().BoolP("enabled", "e", false, "Show only enabled service groups")
 	serviceGroupsListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	enableSorting(serviceGroupSortColumns, serviceGroupsListCmd)
 
 	// Get command flags
 	serviceGroupsGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
@@ -443,8 +470,13 @@ func init() {
 	// Update command flags
 	serviceGroupsUpdateCmd.Flags().StringP("name", "n", "", "New name for the service group")
 	serviceGroupsUpdateCmd.Flags().StringP("description", "d", "", "New description for the service group")
+	serviceGroupsUpdateCmd.Flags().Bool("clear-description", false, "Clear the description")
 	serviceGroupsUpdateCmd.Flags().BoolP("enabled", "e", false, "Enable or disable the service group")
 
 	// Delete command flags
 	serviceGroupsDeleteCmd.Flags().BoolP("force", "f", false, "Force deletion without confirmation")
+
+	enableIdentifierResolver(api.KindServiceGroup, serviceGroupsGetCmd)
+	enableExactIdentifierResolver(api.KindServiceGroup, serviceGroupsUpdateCmd, serviceGroupsEnableCmd, serviceGroupsDisableCmd, serviceGroupsDeleteCmd)
+	enableServiceGroupByName(serviceGroupsGetCmd, serviceGroupsUpdateCmd, serviceGroupsEnableCmd, serviceGroupsDisableCmd, serviceGroupsDeleteCmd)
 }
@@ -0,0 +1,107 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// serviceGroupMembership describes how services belong to a service group
+var serviceGroupMembership = serviceMembership{
+	field: "service_group_id",
+	kind:  "service group",
+	path:  "/service-groups/%s",
+}
+
+var serviceGroupsMembersCmd = &cobra.Command{
+	Use:   "members <service-group-id|name>",
+	Short: "List the services in a service group",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceGroupID := args[0]
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		if _, err := apiClient.GetWithAuth(fmt.Sprintf("/service-groups/%s", serviceGroupID), token); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get service group: %w", err)
+		}
+
+		services, err := serviceGroupMembership.members(apiClient, token, serviceGroupID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(services, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(services) == 0 {
+			fmt.Println("No services in this service group.")
+			return nil
+		}
+
+		printServicesTable(services, nil)
+		return nil
+	},
+}
+
+var serviceGroupsAddServiceCmd = &cobra.Command{
+	Use:   "add-service <service-group-id|name> <service-hash[,service-hash...]|->",
+	Short: "Add services to a service group",
+	Long: `Add one or more services to a service group by updating each service. Services
+are given as a comma-separated list, '-' to read hashes from stdin, --from-file
+or --interactive. Services already in the group are skipped; services in
+another group are moved to this one.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setServicesMembership(cmd, args, serviceGroupMembership, true)
+	},
+}
+
+var serviceGroupsRemoveServiceCmd = &cobra.Command{
+	Use:   "remove-service <service-group-id|name> <service-hash[,service-hash...]|->",
+	Short: "Remove services from a service group",
+	Long: `Remove one or more services from a service group, leaving them without a group.
+Services are given as for 'certfix service-groups add-service'. Services that
+are not in the group are skipped.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setServicesMembership(cmd, args, serviceGroupMembership, false)
+	},
+}
+
+func init() {
+	serviceGroupsCmd.AddCommand(serviceGroupsMembersCmd)
+	serviceGroupsCmd.AddCommand(serviceGroupsAddServiceCmd)
+	serviceGroupsCmd.AddCommand(serviceGroupsRemoveServiceCmd)
+
+	// Members command flags
+	serviceGroupsMembersCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Add/remove service command flags
+	for _, c := range []*cobra.Command{serviceGroupsAddServiceCmd, serviceGroupsRemoveServiceCmd} {
+		c.Flags().String("from-file", "", "Read service hashes from a file (one per line)")
+		c.Flags().String("selector", "", "Select the services by labels, e.g. team=payments")
+		c.Flags().BoolP("interactive", "i", false, "Pick services interactively with a fuzzy-search list")
+	}
+
+	enableIdentifierResolver(api.KindServiceGroup, serviceGroupsMembersCmd)
+	enableExactIdentifierResolver(api.KindServiceGroup, serviceGroupsAddServiceCmd, serviceGroupsRemoveServiceCmd)
+	enableServiceGroupByName(serviceGroupsMembersCmd, serviceGroupsAddServiceCmd, serviceGroupsRemoveServiceCmd)
+}
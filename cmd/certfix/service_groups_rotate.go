@@ -0,0 +1,170 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+var serviceGroupsRotateCmd = &cobra.Command{
+	Use:   "rotate <service-group-id|name>",
+	Short: "Rotate certificates for every service in a service group",
+	Long: `Trigger a certificate rotation for each active service in a service group.
+Inactive services are skipped unless --include-inactive is given.
+
+Rotations run --parallel at a time with a progress bar, followed by a summary
+of the services that failed. With --wait each rotation is followed until the
+new certificate has been issued.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceGroupID := args[0]
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		includeInactive, _ := cmd.Flags().GetBool("include-inactive")
+		wait, _ := cmd.Flags().GetBool("wait")
+		timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+
+		if parallel < 1 {
+			return fmt.Errorf("--parallel must be at least 1")
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		group, err := apiClient.GetWithAuth(fmt.Sprintf("/service-groups/%s", serviceGroupID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get service group: %w", err)
+		}
+
+		members, err := serviceGroupMembership.members(apiClient, token, serviceGroupID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		var services []map[string]interface{}
+		skipped := 0
+		for _, svc := range members {
+			if active, _ := svc["active"].(bool); !active && !includeInactive {
+				skipped++
+				continue
+			}
+			services = append(services, svc)
+		}
+
+		if len(services) == 0 {
+			fmt.Printf("No services to rotate in service group %v (%d inactive skipped).\n", group["name"], skipped)
+			return nil
+		}
+
+		if err := requirePermission(cmd, "service:rotate"); err != nil {
+			return err
+		}
+
+		// Confirm rotation
+		prompt := fmt.Sprintf("Rotate certificates for %d service(s) in service group %v", len(services), group["name"])
+		if skipped > 0 {
+			prompt += fmt.Sprintf(" (%d inactive skipped)", skipped)
+		}
+		confirmed, err := confirm(cmd, prompt+"?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Rotation cancelled.")
+			return nil
+		}
+
+		type rotationResult struct {
+			cert map[string]interface{}
+			err  error
+		}
+		results := make([]rotationResult, len(services))
+
+		progress := newProgressBar("Rotating", len(services))
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, parallel)
+		for i, svc := range services {
+			wg.Add(1)
+			go func(i int, hash string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				started := time.Now()
+				response, err := apiClient.PostWithAuth("/services/"+hash+"/certificates/rotate", map[string]interface{}{}, token)
+				if err == nil && wait {
+					results[i].cert, err = waitForRotation(apiClient, token, hash, response, started, timeout)
+				}
+				results[i].err = err
+				progress.Done(err == nil)
+			}(i, fmt.Sprintf("%v", svc["service_hash"]))
+		}
+		wg.Wait()
+		progress.Finish()
+
+		var failed []string
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		if wait {
+			fmt.Fprintln(w, "SERVICE\tNAME\tRESULT\tSERIAL\tEXPIRES AT")
+			fmt.Fprintln(w, "-------\t----\t------\t------\t----------")
+		} else {
+			fmt.Fprintln(w, "SERVICE\tNAME\tRESULT")
+			fmt.Fprintln(w, "-------\t----\t------")
+		}
+		for i, svc := range services {
+			hash := fmt.Sprintf("%v", svc["service_hash"])
+			result := "OK"
+			if results[i].err != nil {
+				result = fmt.Sprintf("Failed: %v", results[i].err)
+				failed = append(failed, hash)
+			}
+			if wait {
+				serial, expires := "", ""
+				if cert := results[i].cert; cert != nil {
+					serial = stringOrNA(cert, "serial_number")
+					expires = formatTimestamp(cert["expires_at"], "2006-01-02 15:04", "N/A")
+				}
+				fmt.Fprintf(w, "%s\t%v\t%s\t%s\t%s\n", hash, svc["service_name"], result, serial, expires)
+			} else {
+				fmt.Fprintf(w, "%s\t%v\t%s\n", hash, svc["service_name"], result)
+			}
+		}
+		w.Flush()
+
+		fmt.Printf("\nSummary: %d succeeded, %d failed (total %d)\n", len(services)-len(failed), len(failed), len(services))
+		if len(failed) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("rotation failed for: %s", strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	serviceGroupsCmd.AddCommand(serviceGroupsRotateCmd)
+
+	// Rotate command flags
+	serviceGroupsRotateCmd.Flags().IntP("parallel", "p", 4, "Number of rotations to run at the same time")
+	serviceGroupsRotateCmd.Flags().Bool("include-inactive", false, "Also rotate inactive services")
+	serviceGroupsRotateCmd.Flags().Bool("wait", false, "Wait for each rotation to complete and show the new certificate")
+	serviceGroupsRotateCmd.Flags().Duration("wait-timeout", 5*time.Minute, "Maximum time to wait per service with --wait")
+	serviceGroupsRotateCmd.Flags().BoolP("force", "f", false, "Rotate without confirmation")
+
+	enableExactIdentifierResolver(api.KindServiceGroup, serviceGroupsRotateCmd)
+	enableServiceGroupByName(serviceGroupsRotateCmd)
+}
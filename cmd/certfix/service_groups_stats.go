@@ -0,0 +1,166 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// serviceGroupStats summarizes the health of the services in one group
+type serviceGroupStats struct {
+	ServiceGroupID string `json:"service_group_id"`
+	Name           string `json:"name"`
+	Services       int    `json:"services"`
+	Active         int    `json:"active"`
+	Inactive       int    `json:"inactive"`
+	WithoutPolicy  int    `json:"without_policy"`
+	KeysExpiring   int    `json:"keys_expiring"`
+}
+
+var serviceGroupsStatsCmd = &cobra.Command{
+	Use:   "stats [service-group-id|name]",
+	Short: "Show a health overview per service group",
+	Long: `Show per service group the number of services, how many are active or inactive,
+how many have no policy attached and how many enabled API keys expire within
+--days (including keys that have already expired).
+
+Without an argument every group is listed, plus a "(no group)" row for
+services that do not belong to any group.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		var groups []map[string]interface{}
+		if len(args) == 1 {
+			group, err := apiClient.GetWithAuth(fmt.Sprintf("/service-groups/%s", args[0]), token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to get service group: %w", err)
+			}
+			groups = append(groups, group)
+		} else {
+			response, err := apiClient.GetWithAuth("/service-groups", token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to list service groups: %w", err)
+			}
+			groups = parseArrayResponse(response)
+		}
+
+		response, err := apiClient.GetWithAuth("/services", token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+
+		stats := make([]*serviceGroupStats, 0, len(groups)+1)
+		byGroup := make(map[string]*serviceGroupStats)
+		for _, group := range groups {
+			s := &serviceGroupStats{
+				ServiceGroupID: fmt.Sprintf("%v", group["service_group_id"]),
+				Name:           fmt.Sprintf("%v", group["name"]),
+			}
+			stats = append(stats, s)
+			byGroup[s.ServiceGroupID] = s
+		}
+		ungrouped := &serviceGroupStats{Name: "(no group)"}
+		if len(args) == 0 {
+			byGroup[""] = ungrouped
+		}
+
+		// Count services and remember which group each one belongs to
+		var services []map[string]interface{}
+		serviceGroup := make(map[string]*serviceGroupStats)
+		for _, svc := range parseArrayResponse(response) {
+			groupID := ""
+			if id, ok := svc["service_group_id"]; ok && id != nil {
+				groupID = fmt.Sprintf("%v", id)
+			}
+			s, ok := byGroup[groupID]
+			if !ok {
+				continue
+			}
+
+			s.Services++
+			if active, _ := svc["active"].(bool); active {
+				s.Active++
+			} else {
+				s.Inactive++
+			}
+			if policyID, ok := svc["policy_id"]; !ok || policyID == nil || fmt.Sprintf("%v", policyID) == "" {
+				s.WithoutPolicy++
+			}
+
+			services = append(services, svc)
+			serviceGroup[fmt.Sprintf("%v", svc["service_hash"])] = s
+		}
+		if ungrouped.Services > 0 {
+			stats = append(stats, ungrouped)
+		}
+
+		cutoff := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+		keys, failures := expiringKeys(apiClient, token, services, cutoff, false)
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "Warning: failed to list keys for service %s\n", failure)
+		}
+		for _, key := range keys {
+			if s, ok := serviceGroup[fmt.Sprintf("%v", key["service_hash"])]; ok {
+				s.KeysExpiring++
+			}
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(stats, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(stats) == 0 {
+			fmt.Println("No service groups found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tGROUP\tSERVICES\tACTIVE\tINACTIVE\tNO POLICY\tKEYS EXPIRING")
+		fmt.Fprintln(w, "--\t-----\t--------\t------\t--------\t---------\t-------------")
+		for _, s := range stats {
+			id := s.ServiceGroupID
+			if id == "" {
+				id = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\n", id, s.Name, s.Services, s.Active, s.Inactive, s.WithoutPolicy, s.KeysExpiring)
+		}
+		w.Flush()
+		fmt.Printf("\nKeys expiring: enabled API keys expiring within %d days.\n", days)
+
+		return nil
+	},
+}
+
+func init() {
+	serviceGroupsCmd.AddCommand(serviceGroupsStatsCmd)
+
+	// Stats command flags
+	serviceGroupsStatsCmd.Flags().IntP("days", "d", 30, "Count API keys expiring within this many days")
+	serviceGroupsStatsCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	enableIdentifierResolver(api.KindServiceGroup, serviceGroupsStatsCmd)
+	enableServiceGroupByName(serviceGroupsStatsCmd)
+}
@@ -3,11 +3,14 @@ package certfix
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/certfix/certfix-cli/internal/api"
 	"github.com/certfix/certfix-cli/internal/auth"
 	"github.com/certfix/certfix-cli/internal/config"
 	"github.com/certfix/certfix-cli/pkg/client"
@@ -16,39 +19,119 @@ import (
 )
 
 var servicesRotateCmd = &cobra.Command{
-	Use:   "rotate <service-hash[,service-hash,...]>",
+	Use:   "rotate <service-hash[,service-hash,...]|->",
 	Short: "Rotate certificate(s) for one or more services",
-	Long:  `Rotate the certificate for one or more services by hash. Example: certfix service rotate id1,id2,id3`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Rotate the certificate for one or more services by hash.
+
+Hashes can be passed as a comma-separated list, read from stdin with '-', or
+read from a file with --from-file (one hash per line).
+
+Examples:
+  certfix service rotate id1,id2,id3
+  certfix service rotate --from-file hashes.txt
+  cat hashes.txt | certfix service rotate -
+
+With --wait the command polls until each rotation has finished, prints the new
+certificate's serial and expiry, and exits non-zero if a rotation failed.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		hashes := strings.Split(args[0], ",")
+		hashes, err := collectServiceHashes(cmd, args)
+		if err != nil {
+			return err
+		}
 		token, err := auth.GetToken()
 		if err != nil {
 			cmd.SilenceUsage = true
 			return err
 		}
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
-		var failed []string
-		for _, hash := range hashes {
-			hash = strings.TrimSpace(hash)
-			if hash == "" { continue }
-			fmt.Printf("Rotating certificate for service: %s... ", hash)
-			_, err := apiClient.PostWithAuth("/services/"+hash+"/certificates/rotate", map[string]interface{}{}, token)
+		apiClient := newAPIClient()
+
+		wait, _ := cmd.Flags().GetBool("wait")
+		timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+
+		issued := make(map[string]map[string]interface{})
+		cmd.SilenceUsage = true
+		err = runServiceBulk(hashes, "Rotating certificate for service", func(hash string) error {
+			started := time.Now()
+			response, err := apiClient.PostWithAuth("/services/"+hash+"/certificates/rotate", map[string]interface{}{}, token)
+			if err != nil || !wait {
+				return err
+			}
+			cert, err := waitForRotation(apiClient, token, hash, response, started, timeout)
 			if err != nil {
-				fmt.Printf("Failed: %v\n", err)
-				failed = append(failed, hash)
-			} else {
-				fmt.Printf("OK\n")
+				return err
 			}
+			issued[hash] = cert
+			return nil
+		})
+
+		if len(issued) > 0 {
+			fmt.Println()
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "SERVICE\tSERIAL\tEXPIRES AT")
+			fmt.Fprintln(w, "-------\t------\t----------")
+			for _, hash := range hashes {
+				if cert, ok := issued[hash]; ok {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", hash, stringOrNA(cert, "serial_number"), formatTimestamp(cert["expires_at"], "2006-01-02 15:04", "N/A"))
+				}
+			}
+			w.Flush()
 		}
-		if len(failed) > 0 {
-			return fmt.Errorf("Failed to rotate for: %s", strings.Join(failed, ", "))
-		}
-		return nil
+		return err
 	},
 }
 
+// rotationPollInterval is how often --wait checks on a pending rotation
+const rotationPollInterval = 2 * time.Second
+
+// waitForRotation polls until the rotation started by response has finished and
+// returns the newly issued certificate. When the API returned a job ID the job
+// status is polled; otherwise the service's certificate history is watched for
+// a certificate issued after started.
+func waitForRotation(apiClient client.APIClient, token, hash string, response map[string]interface{}, started time.Time, timeout time.Duration) (map[string]interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	jobID := stringOrNA(response, "job_id")
+
+	for {
+		if jobID != "N/A" {
+			job, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates/rotate/%s", hash, jobID), token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get rotation status: %w", err)
+			}
+			switch strings.ToLower(stringOrNA(job, "status")) {
+			case "completed", "succeeded", "success":
+				if cert, ok := job["certificate"].(map[string]interface{}); ok {
+					return cert, nil
+				}
+				return job, nil
+			case "failed", "error":
+				reason := stringOrNA(job, "error")
+				if reason == "N/A" {
+					reason = stringOrNA(job, "message")
+				}
+				return nil, fmt.Errorf("rotation failed: %s", reason)
+			}
+		} else {
+			history, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates?include_history=true", hash), token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get certificate history: %w", err)
+			}
+			for _, cert := range parseArrayResponse(history) {
+				createdAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", cert["created_at"]))
+				// Allow for clock skew between client and server
+				if err == nil && createdAt.After(started.Add(-time.Minute)) {
+					return cert, nil
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for rotation to complete", timeout)
+		}
+		time.Sleep(rotationPollInterval)
+	}
+}
+
 var servicesCmd = &cobra.Command{
 	Use:     "services",
 	Aliases: []string{"service", "svc"},
@@ -66,8 +149,44 @@ var servicesListCmd = &cobra.Command{
 
 		// Get flags
 		activeOnly, _ := cmd.Flags().GetBool("active")
+		inactiveOnly, _ := cmd.Flags().GetBool("inactive")
 		groupID, _ := cmd.Flags().GetString("group")
+		nameFilter, _ := cmd.Flags().GetString("name")
+		policyFilter, _ := cmd.Flags().GetString("policy")
+		webhookSet, _ := cmd.Flags().GetBool("webhook-set")
+		sortBy, _ := cmd.Flags().GetString("sort")
 		outputFormat, _ := cmd.Flags().GetString("output")
+		watch, _ := cmd.Flags().GetBool("watch")
+		watchInterval, _ := cmd.Flags().GetDuration("interval")
+		tree, _ := cmd.Flags().GetBool("tree")
+		selectorValue, _ := cmd.Flags().GetString("selector")
+		includeDeleted, _ := cmd.Flags().GetBool("include-deleted")
+
+		if activeOnly && inactiveOnly {
+			return fmt.Errorf("--active and --inactive cannot be used together")
+		}
+		if tree && watch {
+			return fmt.Errorf("--tree cannot be combined with --watch")
+		}
+		if sortBy != "" {
+			// --sort is kept for compatibility: "-name" is --sort-by name --reverse
+			if cmd.Flags().Changed("sort-by") || cmd.Flags().Changed("reverse") {
+				return fmt.Errorf("--sort cannot be combined with --sort-by or --reverse")
+			}
+			sortKey := strings.TrimPrefix(sortBy, "-")
+			if serviceSortColumns[sortKey] == "" {
+				return fmt.Errorf("invalid sort field '%s' (valid: %s; prefix with '-' for descending)", sortBy, strings.Join(serviceSortColumns.names(), ", "))
+			}
+			cmd.Flags().Set("sort-by", sortKey)
+			cmd.Flags().Set("reverse", fmt.Sprintf("%t", sortKey != sortBy))
+		}
+		var selector labelSelector
+		if selectorValue != "" {
+			var err error
+			if selector, err = parseSelector(selectorValue); err != nil {
+				return err
+			}
+		}
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -77,8 +196,7 @@ var servicesListCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Determine endpoint
 		var apiEndpoint string
@@ -90,29 +208,89 @@ var servicesListCmd = &cobra.Command{
 			apiEndpoint = "/services"
 		}
 
-		log.Debugf("GET %s%s", endpoint, apiEndpoint)
+		// Filters are sent to the API as query parameters and re-applied
+		// client-side for servers that ignore them
+		query := url.Values{}
+		if nameFilter != "" {
+			query.Set("name", nameFilter)
+		}
+		if policyFilter != "" {
+			query.Set("policy", policyFilter)
+		}
+		if webhookSet {
+			query.Set("webhook_set", "true")
+		}
+		if inactiveOnly {
+			query.Set("active", "false")
+		}
+		if sortBy != "" {
+			query.Set("sort", sortBy)
+		}
+		if selectorValue != "" {
+			query.Set("selector", selectorValue)
+		}
+		if includeDeleted {
+			query.Set(includeDeletedParam, "true")
+		}
+		if len(query) > 0 {
+			apiEndpoint += "?" + query.Encode()
+		}
 
-		// Make request
-		response, err := apiClient.GetWithAuth(apiEndpoint, token)
-		if err != nil {
-			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to list services: %w", err)
+		log.Debugf("GET %s%s", config.GetAPIEndpoint(), apiEndpoint)
+
+		fetch := func() ([]map[string]interface{}, error) {
+			response, err := apiClient.GetWithAuth(apiEndpoint, token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list services: %w", err)
+			}
+
+			services := filterServices(parseArrayResponse(response), nameFilter, policyFilter, webhookSet, inactiveOnly)
+			if selector != nil {
+				services = selectServices(services, selector)
+			}
+			services, err = filterByTime(cmd, services)
+			if err != nil {
+				return nil, err
+			}
+			if err := sortItems(cmd, serviceSortColumns, services); err != nil {
+				return nil, err
+			}
+			return services, nil
 		}
 
-		// Parse response
-		var services []map[string]interface{}
-		if response["_is_array"] != nil {
-			if arr, ok := response["_array_data"].([]interface{}); ok {
-				for _, item := range arr {
-					if svc, ok := item.(map[string]interface{}); ok {
-						services = append(services, svc)
-					}
-				}
+		if watch {
+			notifiers, err := notifiersFromFlags(cmd)
+			if err != nil {
+				return err
 			}
+			cmd.SilenceUsage = true
+			return runWatch(watchInterval, "certfix services list", notifiers, func(prev map[string]string) (map[string]string, error) {
+				services, err := fetch()
+				if err != nil {
+					return nil, err
+				}
+				statuses := make(map[string]string)
+				for _, svc := range services {
+					statuses[serviceLabel(svc)] = serviceStatus(svc)
+				}
+				if len(services) == 0 {
+					fmt.Println("No services found.")
+				} else {
+					printServicesTable(services, statusChanges(prev, statuses))
+				}
+				return statuses, nil
+			})
 		}
 
-		if len(services) == 0 {
-			fmt.Println("No services found.")
+		// Make request
+		services, err := fetch()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		if tree && len(services) > 0 {
+			printServiceTree(buildServiceTree(apiClient, token, services), outputFormat)
 			return nil
 		}
 
@@ -123,62 +301,97 @@ var servicesListCmd = &cobra.Command{
 			return nil
 		}
 
-		// Table format
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "HASH\tNAME\tGROUP\tPOLICY\tSTATUS\tCREATED AT")
-		fmt.Fprintln(w, "----\t----\t-----\t------\t------\t----------")
-
-		for _, svc := range services {
-			   hash := fmt.Sprintf("%v", svc["service_hash"])
-			name := fmt.Sprintf("%v", svc["service_name"])
-			if len(name) > 30 {
-				name = name[:27] + "..."
-			}
-			
-			groupName := "N/A"
-			if svc["service_group_name"] != nil && svc["service_group_name"] != "<nil>" {
-				groupName = fmt.Sprintf("%v", svc["service_group_name"])
-				if len(groupName) > 20 {
-					groupName = groupName[:17] + "..."
-				}
-			}
-			
-			policyName := "N/A"
-			if svc["policy_name"] != nil && svc["policy_name"] != "<nil>" {
-				policyName = fmt.Sprintf("%v", svc["policy_name"])
-				if len(policyName) > 20 {
-					policyName = policyName[:17] + "..."
-				}
+		if len(services) == 0 {
+			fmt.Println("No services found.")
+			return nil
+		}
+
+		printServicesTable(services, nil)
+		return nil
+	},
+}
+
+// serviceLabel identifies a service in watch mode output
+func serviceLabel(svc map[string]interface{}) string {
+	return fmt.Sprintf("%v (%v)", svc["service_name"], svc["service_hash"])
+}
+
+func serviceStatus(svc map[string]interface{}) string {
+	if isDeleted(svc) {
+		return "Deleted"
+	}
+	if active, _ := svc["active"].(bool); active {
+		return "Active"
+	}
+	return "Inactive"
+}
+
+// printServicesTable renders the services list table. changes maps service
+// labels to a status transition to display instead of the plain status.
+func printServicesTable(services []map[string]interface{}, changes map[string]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "HASH\tNAME\tGROUP\tPOLICY\tSTATUS\tCREATED AT")
+	fmt.Fprintln(w, "----\t----\t-----\t------\t------\t----------")
+
+	for _, svc := range services {
+		hash := fmt.Sprintf("%v", svc["service_hash"])
+		name := fmt.Sprintf("%v", svc["service_name"])
+		if len(name) > 30 {
+			name = name[:27] + "..."
+		}
+
+		groupName := "N/A"
+		if svc["service_group_name"] != nil && svc["service_group_name"] != "<nil>" {
+			groupName = fmt.Sprintf("%v", svc["service_group_name"])
+			if len(groupName) > 20 {
+				groupName = groupName[:17] + "..."
 			}
-			
-			active := svc["active"].(bool)
-			status := "Inactive"
-			if active {
-				status = "Active"
-			}
-			
-			createdAt := ""
-			if svc["created_at"] != nil {
-				if t, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", svc["created_at"])); err == nil {
-					createdAt = t.Format("2006-01-02 15:04")
-				}
+		}
+
+		policyName := "N/A"
+		if svc["policy_name"] != nil && svc["policy_name"] != "<nil>" {
+			policyName = fmt.Sprintf("%v", svc["policy_name"])
+			if len(policyName) > 20 {
+				policyName = policyName[:17] + "..."
 			}
+		}
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", hash, name, groupName, policyName, status, createdAt)
+		status := serviceStatus(svc)
+		if change, ok := changes[serviceLabel(svc)]; ok {
+			status = change
 		}
-		w.Flush()
 
-		return nil
-	},
+		createdAt := ""
+		if svc["created_at"] != nil {
+			if t, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", svc["created_at"])); err == nil {
+				createdAt = t.Format("2006-01-02 15:04")
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", hash, name, groupName, policyName, status, createdAt)
+	}
+	w.Flush()
 }
 
 var servicesGetCmd = &cobra.Command{
 	Use:   "get <service-hash>",
 	Short: "Get details of a specific service",
-	Args:  cobra.ExactArgs(1),
+	Long: `Get the details of a service. --include adds its keys, relations,
+certificates or next rotation (or all of them), fetched concurrently, as
+sections of the table output or as fields of the JSON output.`,
+	Example: `  certfix services get payments-api
+  certfix services get payments-api --include keys,relations,certificates
+  certfix services get payments-api --include all -o json`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serviceHash := args[0]
 		outputFormat, _ := cmd.Flags().GetString("output")
+		includeFlag, _ := cmd.Flags().GetString("include")
+
+		includes, err := parseServiceIncludes(includeFlag)
+		if err != nil {
+			return err
+		}
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -188,8 +401,7 @@ var servicesGetCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Make request
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s", serviceHash), token)
@@ -198,8 +410,17 @@ var servicesGetCmd = &cobra.Command{
 			return fmt.Errorf("failed to get service: %w", err)
 		}
 
+		sections, err := fetchServiceIncludes(apiClient, token, response, includes)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
 		// Output format
 		if outputFormat == "json" {
+			for include, section := range sections {
+				response[include] = section
+			}
 			data, _ := json.MarshalIndent(response, "", "  ")
 			fmt.Println(string(data))
 			return nil
@@ -256,6 +477,10 @@ var servicesGetCmd = &cobra.Command{
 			fmt.Printf("DNS Names:    %s\n", strings.Join(parts, ", "))
 		}
 
+		if labels := serviceLabels(response); len(labels) > 0 {
+			fmt.Printf("Labels:       %s\n", formatLabels(labels))
+		}
+
 		if response["created_at"] != nil {
 			fmt.Printf("Created At:   %v\n", response["created_at"])
 		}
@@ -263,6 +488,7 @@ var servicesGetCmd = &cobra.Command{
 			fmt.Printf("Updated At:   %v\n", response["updated_at"])
 		}
 
+		printServiceIncludes(includes, sections)
 		return nil
 	},
 }
@@ -286,6 +512,7 @@ and will be validated before creating the service.`,
 		reloadService, _ := cmd.Flags().GetString("reload-service")
 		active, _ := cmd.Flags().GetBool("active")
 		dnsRaw, _ := cmd.Flags().GetString("dns")
+		labelValues, _ := cmd.Flags().GetStringArray("label")
 		outputFormat, _ := cmd.Flags().GetString("output")
 
 		// Validate required fields
@@ -293,6 +520,10 @@ and will be validated before creating the service.`,
 			cmd.SilenceUsage = true
 			return fmt.Errorf("name is required (use --name)")
 		}
+		labels, err := parseLabels(labelValues)
+		if err != nil {
+			return err
+		}
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -302,8 +533,7 @@ and will be validated before creating the service.`,
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// If hash is provided, check for duplicates
 		if serviceHash != "" {
@@ -353,6 +583,10 @@ and will be validated before creating the service.`,
 		}
 		payload["dns_names"] = dnsNames
 
+		if len(labels) > 0 {
+			payload["labels"] = labels
+		}
+
 		log.Infof("Creating service: %s", name)
 
 		// Make request
@@ -398,6 +632,10 @@ and will be validated before creating the service.`,
 			fmt.Printf("DNS Names:    %s\n", strings.Join(parts, ", "))
 		}
 
+		if labels := serviceLabels(response); len(labels) > 0 {
+			fmt.Printf("Labels:       %s\n", formatLabels(labels))
+		}
+
 		if response["reload_service"] != nil && response["reload_service"] != "<nil>" {
 			fmt.Printf("Reload:       %v\n", response["reload_service"])
 		}
@@ -428,8 +666,15 @@ var servicesUpdateCmd = &cobra.Command{
 		clearPolicy, _ := cmd.Flags().GetBool("clear-policy")
 		dnsRaw, _ := cmd.Flags().GetString("dns")
 		clearDNS, _ := cmd.Flags().GetBool("clear-dns")
+		labelValues, _ := cmd.Flags().GetStringArray("label")
+		removeLabels, _ := cmd.Flags().GetStringArray("remove-label")
 		outputFormat, _ := cmd.Flags().GetString("output")
 
+		setLabels, err := parseLabels(labelValues)
+		if err != nil {
+			return err
+		}
+
 		// Build update payload
 		payload := make(map[string]interface{})
 
@@ -478,9 +723,10 @@ var servicesUpdateCmd = &cobra.Command{
 			payload["dns_names"] = []string{}
 		}
 
-		if len(payload) == 0 {
+		updateLabels := len(setLabels) > 0 || len(removeLabels) > 0
+		if len(payload) == 0 && !updateLabels {
 			cmd.SilenceUsage = true
-			return fmt.Errorf("no fields to update (use --name, --webhook, --group, --policy, --reload-service, --active, --dns, or clear flags)")
+			return fmt.Errorf("no fields to update (use --name, --webhook, --group, --policy, --reload-service, --active, --dns, --label, or clear flags)")
 		}
 
 		// Get authentication token
@@ -491,8 +737,28 @@ var servicesUpdateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
+
+		// Read the service first so the update carries its ETag and fails
+		// rather than overwrite a change made meanwhile
+		current, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s", serviceHash), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get service: %w", err)
+		}
+
+		// Labels are replaced as a whole, so merge the changes into the
+		// current ones
+		if updateLabels {
+			labels := serviceLabels(current)
+			for key, value := range setLabels {
+				labels[key] = value
+			}
+			for _, key := range removeLabels {
+				delete(labels, key)
+			}
+			payload["labels"] = labels
+		}
 
 		log.Infof("Updating service: %s", serviceHash)
 
@@ -539,6 +805,10 @@ var servicesUpdateCmd = &cobra.Command{
 			fmt.Printf("DNS Names:    %s\n", strings.Join(parts, ", "))
 		}
 
+		if labels := serviceLabels(response); len(labels) > 0 {
+			fmt.Printf("Labels:       %s\n", formatLabels(labels))
+		}
+
 		if response["reload_service"] != nil && response["reload_service"] != "<nil>" {
 			fmt.Printf("Reload:       %v\n", response["reload_service"])
 		}
@@ -547,12 +817,143 @@ var servicesUpdateCmd = &cobra.Command{
 	},
 }
 
+var servicesBulkUpdateCmd = &cobra.Command{
+	Use:   "bulk-update",
+	Short: "Apply the same changes to every service in a group",
+	Long: `Apply the same field changes to every service in a service group.
+
+A preview of the affected services is shown before anything is changed.
+Services that already have the requested values are skipped.
+
+Example:
+  certfix services bulk-update --group <group-id> --policy <policy-id> --active=false`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		groupID, _ := cmd.Flags().GetString("group")
+		policyID, _ := cmd.Flags().GetString("policy")
+		webhookURL, _ := cmd.Flags().GetString("webhook")
+		reloadService, _ := cmd.Flags().GetString("reload-service")
+		clearPolicy, _ := cmd.Flags().GetBool("clear-policy")
+		clearWebhook, _ := cmd.Flags().GetBool("clear-webhook")
+		clearReload, _ := cmd.Flags().GetBool("clear-reload")
+		activeValue, _ := cmd.Flags().GetBool("active")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		// Build update payload
+		payload := make(map[string]interface{})
+		if policyID != "" {
+			payload["policy_id"] = policyID
+		} else if clearPolicy {
+			payload["policy_id"] = nil
+		}
+		if webhookURL != "" {
+			payload["webhook_url"] = webhookURL
+		} else if clearWebhook {
+			payload["webhook_url"] = nil
+		}
+		if reloadService != "" {
+			payload["reload_service"] = reloadService
+		} else if clearReload {
+			payload["reload_service"] = nil
+		}
+		if cmd.Flags().Changed("active") {
+			payload["active"] = activeValue
+		}
+
+		if len(payload) == 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("no fields to update (use --policy, --webhook, --reload-service, --active, or clear flags)")
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/group/%s", groupID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services in group: %w", err)
+		}
+
+		// Work out which services actually need changing
+		fields := make([]string, 0, len(payload))
+		for field := range payload {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		var hashes []string
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "HASH\tNAME\tCHANGES")
+		fmt.Fprintln(w, "----\t----\t-------")
+		for _, svc := range parseArrayResponse(response) {
+			var changes []string
+			for _, field := range fields {
+				current := stringOrNA(svc, field)
+				desired := "N/A"
+				if payload[field] != nil {
+					desired = fmt.Sprintf("%v", payload[field])
+				}
+				if current != desired {
+					changes = append(changes, fmt.Sprintf("%s: %s → %s", field, current, desired))
+				}
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			hash := fmt.Sprintf("%v", svc["service_hash"])
+			hashes = append(hashes, hash)
+			fmt.Fprintf(w, "%s\t%v\t%s\n", hash, svc["service_name"], strings.Join(changes, ", "))
+		}
+
+		if len(hashes) == 0 {
+			fmt.Println("No services need updating.")
+			return nil
+		}
+		w.Flush()
+		fmt.Printf("\n%d service(s) will be updated.\n", len(hashes))
+
+		if dryRun {
+			fmt.Println("Dry run: no changes applied.")
+			return nil
+		}
+
+		// Confirm update
+		confirmed, err := confirm(cmd, "Apply these changes?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Update cancelled.")
+			return nil
+		}
+
+		cmd.SilenceUsage = true
+		return runServiceBulk(hashes, "Updating service", func(hash string) error {
+			_, err := apiClient.PutWithAuth(fmt.Sprintf("/services/%s", hash), payload, token)
+			return err
+		})
+	},
+}
+
 var servicesActivateCmd = &cobra.Command{
-	Use:   "activate <service-hash>",
-	Short: "Activate a service",
-	Args:  cobra.ExactArgs(1),
+	Use:   "activate <service-hash[,service-hash,...]|->",
+	Short: "Activate one or more services",
+	Long: `Activate one or more services by hash.
+
+Hashes can be passed as a comma-separated list, read from stdin with '-', or
+read from a file with --from-file (one hash per line).`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		serviceHash := args[0]
+		hashes, err := collectServiceHashes(cmd, args)
+		if err != nil {
+			return err
+		}
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -562,32 +963,45 @@ var servicesActivateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
 			"active": true,
 		}
 
-		// Make request
-		_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s", serviceHash), payload, token)
-		if err != nil {
-			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to activate service: %w", err)
+		if len(hashes) == 1 {
+			_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s", hashes[0]), payload, token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to activate service: %w", err)
+			}
+
+			fmt.Printf("✓ Service activated successfully\n")
+			return nil
 		}
 
-		fmt.Printf("✓ Service activated successfully\n")
-		return nil
+		cmd.SilenceUsage = true
+		return runServiceBulk(hashes, "Activating service", func(hash string) error {
+			_, err := apiClient.PutWithAuth(fmt.Sprintf("/services/%s", hash), payload, token)
+			return err
+		})
 	},
 }
 
 var servicesDeactivateCmd = &cobra.Command{
-	Use:   "deactivate <service-hash>",
-	Short: "Deactivate a service",
-	Args:  cobra.ExactArgs(1),
+	Use:   "deactivate <service-hash[,service-hash,...]|->",
+	Short: "Deactivate one or more services",
+	Long: `Deactivate one or more services by hash.
+
+Hashes can be passed as a comma-separated list, read from stdin with '-', or
+read from a file with --from-file (one hash per line).`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		serviceHash := args[0]
+		hashes, err := collectServiceHashes(cmd, args)
+		if err != nil {
+			return err
+		}
 
 		// Get authentication token
 		token, err := auth.GetToken()
@@ -597,45 +1011,69 @@ var servicesDeactivateCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
 			"active": false,
 		}
 
-		// Make request
-		_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s", serviceHash), payload, token)
-		if err != nil {
-			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to deactivate service: %w", err)
+		if len(hashes) == 1 {
+			_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s", hashes[0]), payload, token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to deactivate service: %w", err)
+			}
+
+			fmt.Printf("✓ Service deactivated successfully\n")
+			return nil
 		}
 
-		fmt.Printf("✓ Service deactivated successfully\n")
-		return nil
+		cmd.SilenceUsage = true
+		return runServiceBulk(hashes, "Deactivating service", func(hash string) error {
+			_, err := apiClient.PutWithAuth(fmt.Sprintf("/services/%s", hash), payload, token)
+			return err
+		})
 	},
 }
 
 var servicesDeleteCmd = &cobra.Command{
-	Use:     "delete <service-hash>",
+	Use:     "delete <service-hash[,service-hash,...]|->",
 	Aliases: []string{"rm", "remove"},
-	Short:   "Delete a service",
-	Args:    cobra.ExactArgs(1),
+	Short:   "Delete one or more services",
+	Long: `Delete one or more services by hash.
+
+Hashes can be passed as a comma-separated list, read from stdin with '-', or
+read from a file with --from-file (one hash per line). Deleting asks you to
+type the service hash (or the number of services) to confirm; use --force when
+reading from stdin or running without a terminal.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log := logger.GetLogger()
-		serviceHash := args[0]
+
+		hashes, err := collectServiceHashes(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		if err := requirePermission(cmd, "service:delete"); err != nil {
+			return err
+		}
 
 		// Confirm deletion
-		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Are you sure you want to delete service %s? (y/N): ", serviceHash)
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
+		prompt := fmt.Sprintf("This permanently deletes service %s and its certificates and keys.", hashes[0])
+		expected := hashes[0]
+		if len(hashes) > 1 {
+			prompt = fmt.Sprintf("This permanently deletes %d services and their certificates and keys.", len(hashes))
+			expected = fmt.Sprintf("delete %d services", len(hashes))
+		}
+		confirmed, err := confirmTyped(cmd, prompt, expected)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
 		}
 
 		// Get authentication token
@@ -646,20 +1084,28 @@ var servicesDeleteCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
-		log.Infof("Deleting service: %s", serviceHash)
+		if len(hashes) == 1 {
+			log.Infof("Deleting service: %s", hashes[0])
 
-		// Make request
-		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/services/%s", serviceHash), token)
-		if err != nil {
-			cmd.SilenceUsage = true
-			return fmt.Errorf("failed to delete service: %w", err)
+			// Make request
+			response, err := apiClient.DeleteWithAuth(fmt.Sprintf("/services/%s", hashes[0]), token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to delete service: %w", err)
+			}
+
+			fmt.Printf("✓ Service deleted successfully\n")
+			restoreHint(response, "certfix services restore "+hashes[0])
+			return nil
 		}
 
-		fmt.Printf("✓ Service deleted successfully\n")
-		return nil
+		cmd.SilenceUsage = true
+		return runServiceBulk(hashes, "Deleting service", func(hash string) error {
+			_, err := apiClient.DeleteWithAuth(fmt.Sprintf("/services/%s", hash), token)
+			return err
+		})
 	},
 }
 
@@ -679,8 +1125,7 @@ var servicesGenerateHashCmd = &cobra.Command{
 		}
 
 		// Create API client
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		// Prepare payload
 		payload := map[string]interface{}{
@@ -708,6 +1153,278 @@ var servicesGenerateHashCmd = &cobra.Command{
 	},
 }
 
+var servicesCertificatesCmd = &cobra.Command{
+	Use:     "certificates <service-hash>",
+	Aliases: []string{"certs", "cert-history"},
+	Short:   "Show the certificate history of a service",
+	Long: `List every certificate ever issued or rotated for a service, newest first,
+including serial number, issue date, expiry, status and the policy or event
+that triggered the rotation. Useful for auditing rotation behaviour.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceHash := args[0]
+		outputFormat, _ := cmd.Flags().GetString("output")
+		statusFilter, _ := cmd.Flags().GetString("status")
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates?include_history=true", serviceHash), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get certificate history: %w", err)
+		}
+
+		var certs []map[string]interface{}
+		for _, cert := range parseArrayResponse(response) {
+			if statusFilter != "" && !strings.EqualFold(fmt.Sprintf("%v", cert["status"]), statusFilter) {
+				continue
+			}
+			certs = append(certs, cert)
+		}
+
+		// Newest first
+		sort.SliceStable(certs, func(i, j int) bool {
+			return fmt.Sprintf("%v", certs[i]["created_at"]) > fmt.Sprintf("%v", certs[j]["created_at"])
+		})
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(certs, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(certs) == 0 {
+			fmt.Println("No certificates found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "SERIAL\tISSUED AT\tEXPIRES AT\tSTATUS\tTRIGGERED BY")
+		fmt.Fprintln(w, "------\t---------\t----------\t------\t------------")
+
+		for _, cert := range certs {
+			serial := stringOrNA(cert, "serial_number")
+			issuedAt := formatTimestamp(cert["created_at"], "2006-01-02 15:04", "N/A")
+			expiresAt := formatTimestamp(cert["expires_at"], "2006-01-02 15:04", "N/A")
+			status := stringOrNA(cert, "status")
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", serial, issuedAt, expiresAt, status, certificateTrigger(cert))
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var servicesNextRotationCmd = &cobra.Command{
+	Use:   "next-rotation <service-hash>",
+	Short: "Show when a service's certificate is expected to rotate next",
+	Long: `Resolve the policy attached to a service and print when the next rotation is
+expected, together with the most recent rotations.
+
+For cron-based strategies (Gradual, Maintenance Window) the next run time is
+computed locally from the policy's cron configuration. For the Events strategy
+the current event counter is compared against the configured threshold.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceHash := args[0]
+		last, _ := cmd.Flags().GetInt("last")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		service, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s", serviceHash), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get service: %w", err)
+		}
+
+		result, err := nextRotation(apiClient, token, service)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		summary := fmt.Sprintf("%v", result["summary"])
+		policyID := stringOrNA(service, "policy_id")
+
+		// Most recent rotations
+		var recent []map[string]interface{}
+		if last > 0 {
+			response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/certificates?include_history=true", serviceHash), token)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to get certificate history: %w", err)
+			}
+			recent = parseArrayResponse(response)
+			sort.SliceStable(recent, func(i, j int) bool {
+				return fmt.Sprintf("%v", recent[i]["created_at"]) > fmt.Sprintf("%v", recent[j]["created_at"])
+			})
+			if len(recent) > last {
+				recent = recent[:last]
+			}
+		}
+		result["recent_rotations"] = recent
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Service:        %v (%v)\n", service["service_name"], service["service_hash"])
+		if policyID != "N/A" {
+			fmt.Printf("Policy:         %v (%v)\n", result["policy_name"], result["strategy"])
+		}
+		fmt.Printf("Next Rotation:  %s\n", summary)
+
+		if last > 0 {
+			fmt.Println("\nRecent Rotations:")
+			if len(recent) == 0 {
+				fmt.Println("  None")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "  ISSUED AT\tSERIAL\tSTATUS\tTRIGGERED BY")
+			for _, cert := range recent {
+				issuedAt := formatTimestamp(cert["created_at"], "2006-01-02 15:04", "N/A")
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", issuedAt, stringOrNA(cert, "serial_number"), stringOrNA(cert, "status"), certificateTrigger(cert))
+			}
+			w.Flush()
+		}
+
+		return nil
+	},
+}
+
+// nextRotation resolves the policy attached to a service and computes when its
+// certificate is expected to rotate next. The summary key holds a sentence
+// describing it.
+func nextRotation(apiClient client.APIClient, token string, service map[string]interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{
+		"service_hash": service["service_hash"],
+		"service_name": service["service_name"],
+	}
+
+	var summary string
+	policyID := stringOrNA(service, "policy_id")
+	if policyID == "N/A" {
+		summary = "No policy attached; certificates are only rotated manually"
+	} else {
+		policy, err := apiClient.GetWithAuth(fmt.Sprintf("/policies/%s", policyID), token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get policy: %w", err)
+		}
+		result["policy_id"] = policyID
+		result["policy_name"] = policy["name"]
+		result["strategy"] = policy["strategy"]
+
+		enabled, _ := policy["enabled"].(bool)
+		cronConfig, hasCron := policy["cron_config"].(map[string]interface{})
+		eventConfig, hasEvent := policy["event_config"].(map[string]interface{})
+
+		switch {
+		case !enabled:
+			summary = "Policy is disabled; no rotation is scheduled"
+		case fmt.Sprintf("%v", policy["strategy"]) == "events" && hasEvent:
+			eventID := fmt.Sprintf("%v", eventConfig["event_id"])
+			total := int(toFloat(eventConfig["total_events"]))
+			event, err := apiClient.GetWithAuth(fmt.Sprintf("/events/%s", eventID), token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get event: %w", err)
+			}
+			counter := int(toFloat(event["counter"]))
+			result["event_id"] = eventID
+			result["event_counter"] = counter
+			result["event_threshold"] = total
+			remaining := total - counter
+			if remaining < 0 {
+				remaining = 0
+			}
+			summary = fmt.Sprintf("After %d more occurrence(s) of event %v (counter %d/%d)", remaining, event["name"], counter, total)
+		case hasCron:
+			schedule, err := cronScheduleFromConfig(cronConfig)
+			if err != nil {
+				return nil, fmt.Errorf("policy has an invalid cron configuration: %w", err)
+			}
+			next := schedule.Next(time.Now())
+			if next.IsZero() {
+				summary = "Cron schedule never fires"
+			} else {
+				result["next_rotation"] = next.Format(time.RFC3339)
+				summary = fmt.Sprintf("%s (in %s)", next.Format("2006-01-02 15:04 MST"), time.Until(next).Round(time.Minute))
+			}
+		default:
+			summary = "Policy has no schedule or event configuration"
+		}
+	}
+	result["summary"] = summary
+	return result, nil
+}
+
+// serviceSortColumns are the --sort-by columns of 'services list'. "created"
+// is the name the deprecated --sort flag used.
+var serviceSortColumns = sortColumns{
+	"hash":       "service_hash",
+	"name":       "service_name",
+	"status":     "active",
+	"group":      "service_group_name",
+	"policy":     "policy_name",
+	"created":    "created_at",
+	"created_at": "created_at",
+}
+
+// filterServices applies the 'services list' filters locally so results are
+// correct even when the API does not support the corresponding query parameters.
+func filterServices(services []map[string]interface{}, name, policy string, webhookSet, inactiveOnly bool) []map[string]interface{} {
+	var filtered []map[string]interface{}
+	for _, svc := range services {
+		if name != "" && !strings.Contains(strings.ToLower(fmt.Sprintf("%v", svc["service_name"])), strings.ToLower(name)) {
+			continue
+		}
+		if policy != "" && stringOrNA(svc, "policy_id") != policy && !strings.EqualFold(stringOrNA(svc, "policy_name"), policy) {
+			continue
+		}
+		if webhookSet && (stringOrNA(svc, "webhook_url") == "N/A" || stringOrNA(svc, "webhook_url") == "") {
+			continue
+		}
+		if active, _ := svc["active"].(bool); inactiveOnly && active {
+			continue
+		}
+		filtered = append(filtered, svc)
+	}
+	return filtered
+}
+
+// certificateTrigger describes what caused a certificate to be issued, based on
+// the rotation metadata returned by the API.
+func certificateTrigger(cert map[string]interface{}) string {
+	if name := stringOrNA(cert, "policy_name"); name != "N/A" {
+		return "policy: " + name
+	}
+	if name := stringOrNA(cert, "event_name"); name != "N/A" {
+		return "event: " + name
+	}
+	if trigger := stringOrNA(cert, "rotation_trigger"); trigger != "N/A" {
+		return trigger
+	}
+	return "manual"
+}
+
 func init() {
 	rootCmd.AddCommand(servicesCmd)
 
@@ -716,21 +1433,39 @@ func init() {
 	servicesCmd.AddCommand(servicesGetCmd)
 	servicesCmd.AddCommand(servicesCreateCmd)
 	servicesCmd.AddCommand(servicesUpdateCmd)
+	servicesCmd.AddCommand(servicesBulkUpdateCmd)
 	servicesCmd.AddCommand(servicesActivateCmd)
 	servicesCmd.AddCommand(servicesDeactivateCmd)
 	servicesCmd.AddCommand(servicesDeleteCmd)
 	servicesCmd.AddCommand(servicesGenerateHashCmd)
+	servicesCmd.AddCommand(servicesCertificatesCmd)
+	servicesCmd.AddCommand(servicesNextRotationCmd)
 
 		// Add rotate command
 		servicesCmd.AddCommand(servicesRotateCmd)
 
 	// List command flags
 	servicesListCmd.Flags().BoolP("active", "a", false, "Show only active services")
+	servicesListCmd.Flags().BoolP("inactive", "i", false, "Show only inactive services")
 	servicesListCmd.Flags().StringP("group", "g", "", "Filter by service group ID")
+	servicesListCmd.Flags().StringP("name", "n", "", "Filter by service name (case-insensitive substring)")
+	servicesListCmd.Flags().StringP("policy", "p", "", "Filter by policy ID or name")
+	servicesListCmd.Flags().Bool("webhook-set", false, "Show only services with a webhook URL configured")
+	servicesListCmd.Flags().StringP("selector", "l", "", "Filter by labels, e.g. team=payments,env!=prod")
+	servicesListCmd.Flags().Bool("include-deleted", false, "Also list deleted services that can be restored")
+	servicesListCmd.Flags().String("sort", "", "Sort by field (name, created, group, policy); prefix with '-' for descending")
+	servicesListCmd.Flags().MarkDeprecated("sort", "use --sort-by and --reverse instead")
+	enableSorting(serviceSortColumns, servicesListCmd)
+	enableTimeFilters(false, servicesListCmd)
 	servicesListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	servicesListCmd.Flags().BoolP("watch", "w", false, "Refresh the list continuously, highlighting status changes")
+	servicesListCmd.Flags().Duration("interval", 5*time.Second, "Refresh interval for --watch")
+	servicesListCmd.Flags().Bool("tree", false, "Show services grouped by service group, with their keys and relations")
+	servicesListCmd.Flags().StringArray("notify", nil, "With --watch, send status changes to a slack://, teams://, mailto: or https:// target (repeatable)")
 
 	// Get command flags
 	servicesGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	servicesGetCmd.Flags().String("include", "", "Comma-separated sections to add: keys, relations, certificates, rotation, or all")
 
 	// Create command flags
 	servicesCreateCmd.Flags().StringP("name", "n", "", "Name of the service (required)")
@@ -740,6 +1475,7 @@ func init() {
 	servicesCreateCmd.Flags().StringP("policy", "p", "", "Policy ID")
 	servicesCreateCmd.Flags().String("reload-service", "", "Shell command to run after certificate rotation (e.g. 'systemctl reload nginx')")
 	servicesCreateCmd.Flags().BoolP("active", "a", true, "Activate the service immediately (default: true)")
+	servicesCreateCmd.Flags().StringArray("label", nil, "Label as key=value (repeatable)")
 	servicesCreateCmd.Flags().String("dns", "", "Comma-separated DNS names for the service certificate SAN (e.g. api.example.com,svc.internal)")
 	servicesCreateCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 	servicesCreateCmd.MarkFlagRequired("name")
@@ -757,11 +1493,50 @@ func init() {
 	servicesUpdateCmd.Flags().Bool("clear-policy", false, "Clear the policy")
 	servicesUpdateCmd.Flags().String("dns", "", "Comma-separated DNS names for the service certificate SAN")
 	servicesUpdateCmd.Flags().Bool("clear-dns", false, "Clear all DNS names")
+	servicesUpdateCmd.Flags().StringArray("label", nil, "Set a label as key=value (repeatable)")
+	servicesUpdateCmd.Flags().StringArray("remove-label", nil, "Remove the label with this key (repeatable)")
 	servicesUpdateCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 
+	// Bulk update command flags
+	servicesBulkUpdateCmd.Flags().StringP("group", "g", "", "Service group ID whose services are updated (required)")
+	servicesBulkUpdateCmd.Flags().StringP("policy", "p", "", "New policy ID")
+	servicesBulkUpdateCmd.Flags().StringP("webhook", "w", "", "New webhook URL")
+	servicesBulkUpdateCmd.Flags().String("reload-service", "", "New reload command")
+	servicesBulkUpdateCmd.Flags().BoolP("active", "a", false, "Activate or deactivate the services")
+	servicesBulkUpdateCmd.Flags().Bool("clear-policy", false, "Clear the policy")
+	servicesBulkUpdateCmd.Flags().Bool("clear-webhook", false, "Clear the webhook URL")
+	servicesBulkUpdateCmd.Flags().Bool("clear-reload", false, "Clear the reload service command")
+	servicesBulkUpdateCmd.Flags().Bool("dry-run", false, "Show the preview without applying changes")
+	servicesBulkUpdateCmd.Flags().BoolP("force", "f", false, "Apply without confirmation")
+	servicesBulkUpdateCmd.MarkFlagRequired("group")
+
 	// Delete command flags
 	servicesDeleteCmd.Flags().BoolP("force", "f", false, "Force deletion without confirmation")
 
+	// Bulk operation flags
+	for _, c := range []*cobra.Command{servicesRotateCmd, servicesActivateCmd, servicesDeactivateCmd, servicesDeleteCmd} {
+		c.Flags().String("from-file", "", "Read service hashes from a file (one per line)")
+		c.Flags().String("selector", "", "Select the services by labels, e.g. team=payments")
+		c.Flags().BoolP("interactive", "i", false, "Pick services interactively with a fuzzy-search list")
+	}
+
+	// Rotate command flags
+	servicesRotateCmd.Flags().Bool("wait", false, "Wait for each rotation to complete and show the new certificate")
+	servicesRotateCmd.Flags().Duration("wait-timeout", 5*time.Minute, "Maximum time to wait per service with --wait")
+
 	// Generate hash command flags
 	servicesGenerateHashCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Certificates command flags
+	servicesCertificatesCmd.Flags().StringP("status", "s", "", "Filter by certificate status (e.g. active, revoked, expired)")
+	servicesCertificatesCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Next rotation command flags
+	servicesNextRotationCmd.Flags().IntP("last", "l", 5, "Number of recent rotations to show (0 to skip)")
+	servicesNextRotationCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	// Allow selecting the service by name
+	enableIdentifierResolver(api.KindService, servicesGetCmd, servicesCertificatesCmd, servicesNextRotationCmd)
+	enableExactIdentifierResolver(api.KindService, servicesUpdateCmd)
+	enableServiceByName(servicesGetCmd, servicesUpdateCmd, servicesRotateCmd, servicesActivateCmd, servicesDeactivateCmd, servicesDeleteCmd, servicesCertificatesCmd, servicesNextRotationCmd)
 }
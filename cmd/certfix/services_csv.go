@@ -0,0 +1,402 @@
+package certfix
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+// serviceCSVColumns are the columns written by 'services export' and read by
+// 'services import', mapped to the API field each one corresponds to.
+var serviceCSVColumns = []struct {
+	name  string
+	field string
+}{
+	{"hash", "service_hash"},
+	{"name", "service_name"},
+	{"group", "service_group_id"},
+	{"policy", "policy_id"},
+	{"webhook", "webhook_url"},
+	{"active", "active"},
+}
+
+var servicesExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export services as CSV or JSON",
+	Long: `Export all services with the columns hash, name, group, policy, webhook and
+active. The CSV output can be edited and fed back into 'certfix services import'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		outFile, _ := cmd.Flags().GetString("out")
+
+		if format != "csv" && format != "json" {
+			return fmt.Errorf("invalid format '%s' (valid: csv, json)", format)
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth("/services", token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+		services := parseArrayResponse(response)
+
+		out := io.Writer(os.Stdout)
+		if outFile != "" {
+			f, err := os.Create(outFile)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		rows := make([]map[string]string, 0, len(services))
+		for _, svc := range services {
+			row := make(map[string]string)
+			for _, col := range serviceCSVColumns {
+				row[col.name] = serviceCSVValue(svc, col.field)
+			}
+			rows = append(rows, row)
+		}
+
+		if format == "json" {
+			data, _ := json.MarshalIndent(rows, "", "  ")
+			fmt.Fprintln(out, string(data))
+		} else {
+			w := csv.NewWriter(out)
+			header := make([]string, 0, len(serviceCSVColumns))
+			for _, col := range serviceCSVColumns {
+				header = append(header, col.name)
+			}
+			w.Write(header)
+			for _, row := range rows {
+				record := make([]string, 0, len(serviceCSVColumns))
+				for _, col := range serviceCSVColumns {
+					record = append(record, row[col.name])
+				}
+				w.Write(record)
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("failed to write CSV: %w", err)
+			}
+		}
+
+		if outFile != "" {
+			fmt.Printf("✓ Exported %d service(s) to %s\n", len(services), outFile)
+		}
+		return nil
+	},
+}
+
+// serviceImportRow is a validated row from an import file
+type serviceImportRow struct {
+	line   int
+	values map[string]string
+}
+
+var servicesImportCmd = &cobra.Command{
+	Use:   "import <file.csv>",
+	Short: "Create or update services from a CSV file",
+	Long: `Create or update services from a CSV file with a header row using the columns
+hash, name, group, policy, webhook and active (the format written by
+'certfix services export').
+
+Rows whose hash matches an existing service update it; other rows create a new
+service (with the given hash, or a generated one when the hash is empty). Empty
+group, policy and webhook cells clear the field. The planned changes are shown
+before anything is applied.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		rows, err := readServiceCSV(args[0])
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth("/services", token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+		existing := make(map[string]map[string]interface{})
+		for _, svc := range parseArrayResponse(response) {
+			existing[fmt.Sprintf("%v", svc["service_hash"])] = svc
+		}
+
+		// Build the plan
+		type plannedChange struct {
+			row     serviceImportRow
+			create  bool
+			payload map[string]interface{}
+			changes []string
+		}
+		var plan []plannedChange
+		unchanged := 0
+		for _, row := range rows {
+			hash := row.values["hash"]
+			svc, found := existing[hash]
+			change := plannedChange{row: row, create: !found, payload: make(map[string]interface{})}
+
+			for _, col := range serviceCSVColumns {
+				if col.name == "hash" {
+					continue
+				}
+				desired, present := row.values[col.name]
+				if !present || (col.name == "active" && desired == "") {
+					continue
+				}
+				current := ""
+				if found {
+					current = serviceCSVValue(svc, col.field)
+				}
+				if found && current == desired {
+					continue
+				}
+				if desired == "" && !found {
+					continue
+				}
+				change.payload[col.field] = serviceCSVPayloadValue(col.field, desired)
+				if found {
+					change.changes = append(change.changes, fmt.Sprintf("%s: %s → %s", col.name, orNone(current), orNone(desired)))
+				}
+			}
+
+			if change.create {
+				if hash != "" {
+					change.payload["service_hash"] = hash
+				}
+				if _, ok := change.payload["active"]; !ok {
+					change.payload["active"] = true
+				}
+				change.payload["dns_names"] = []string{}
+			} else if len(change.payload) == 0 {
+				unchanged++
+				continue
+			}
+			plan = append(plan, change)
+		}
+
+		if len(plan) == 0 {
+			fmt.Printf("Nothing to do: all %d service(s) are up to date.\n", unchanged)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "LINE\tACTION\tHASH\tNAME\tCHANGES")
+		fmt.Fprintln(w, "----\t------\t----\t----\t-------")
+		for _, change := range plan {
+			action, details := "update", strings.Join(change.changes, ", ")
+			if change.create {
+				action, details = "create", "new service"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", change.row.line, action, orNone(change.row.values["hash"]), change.row.values["name"], details)
+		}
+		w.Flush()
+		fmt.Printf("\n%d to create/update, %d unchanged.\n", len(plan), unchanged)
+
+		if dryRun {
+			fmt.Println("Dry run: no changes applied.")
+			return nil
+		}
+
+		// Confirm import
+		confirmed, err := confirm(cmd, "Apply these changes?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Import cancelled.")
+			return nil
+		}
+
+		var failed []string
+		for i, change := range plan {
+			label := change.row.values["name"]
+			fmt.Printf("[%d/%d] line %d: %s... ", i+1, len(plan), change.row.line, label)
+
+			var err error
+			if change.create {
+				_, err = apiClient.PostWithAuth("/services", change.payload, token)
+			} else {
+				_, err = apiClient.PutWithAuth(fmt.Sprintf("/services/%s", change.row.values["hash"]), change.payload, token)
+			}
+			if err != nil {
+				fmt.Printf("Failed: %v\n", err)
+				failed = append(failed, fmt.Sprintf("line %d", change.row.line))
+			} else {
+				fmt.Printf("OK\n")
+			}
+		}
+
+		fmt.Printf("\nSummary: %d succeeded, %d failed (total %d)\n", len(plan)-len(failed), len(failed), len(plan))
+		if len(failed) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("import failed for: %s", strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+// readServiceCSV parses and validates an import file, reporting every invalid
+// row at once.
+func readServiceCSV(path string) ([]serviceImportRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+
+	// Map header names to column positions
+	index := make(map[string]int)
+	for i, name := range records[0] {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["name"]; !ok {
+		return nil, fmt.Errorf("CSV header must include a 'name' column")
+	}
+	for name := range index {
+		known := false
+		for _, col := range serviceCSVColumns {
+			if col.name == name {
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown CSV column '%s' (valid: hash, name, group, policy, webhook, active)", name)
+		}
+	}
+
+	var rows []serviceImportRow
+	var problems []string
+	seen := make(map[string]int)
+	for i, record := range records[1:] {
+		line := i + 2
+		row := serviceImportRow{line: line, values: make(map[string]string)}
+		for _, col := range serviceCSVColumns {
+			if pos, ok := index[col.name]; ok {
+				row.values[col.name] = ""
+				if pos < len(record) {
+					row.values[col.name] = strings.TrimSpace(record[pos])
+				}
+			}
+		}
+
+		if row.values["name"] == "" {
+			problems = append(problems, fmt.Sprintf("line %d: name is required", line))
+		}
+		if hash := row.values["hash"]; hash != "" {
+			if prev, dup := seen[hash]; dup {
+				problems = append(problems, fmt.Sprintf("line %d: duplicate hash '%s' (also on line %d)", line, hash, prev))
+			}
+			seen[hash] = line
+		}
+		if active := row.values["active"]; active != "" {
+			if _, err := strconv.ParseBool(active); err != nil {
+				problems = append(problems, fmt.Sprintf("line %d: invalid active value '%s' (use true or false)", line, active))
+			}
+		}
+		if webhook := row.values["webhook"]; webhook != "" {
+			if u, err := url.Parse(webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				problems = append(problems, fmt.Sprintf("line %d: invalid webhook URL '%s'", line, webhook))
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid CSV file:\n  %s", strings.Join(problems, "\n  "))
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV file has no data rows")
+	}
+	return rows, nil
+}
+
+// serviceCSVValue returns the CSV representation of a service field
+func serviceCSVValue(svc map[string]interface{}, field string) string {
+	if field == "active" {
+		active, _ := svc["active"].(bool)
+		return strconv.FormatBool(active)
+	}
+	if v := stringOrNA(svc, field); v != "N/A" {
+		return v
+	}
+	return ""
+}
+
+// serviceCSVPayloadValue converts a CSV cell into the value sent to the API
+func serviceCSVPayloadValue(field, value string) interface{} {
+	switch {
+	case field == "active":
+		active, _ := strconv.ParseBool(value)
+		return active
+	case value == "":
+		return nil
+	}
+	return value
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func init() {
+	servicesCmd.AddCommand(servicesExportCmd)
+	servicesCmd.AddCommand(servicesImportCmd)
+
+	// Export command flags
+	servicesExportCmd.Flags().StringP("format", "f", "csv", "Export format (csv, json)")
+	servicesExportCmd.Flags().String("out", "", "Write to a file instead of stdout")
+
+	// Import command flags
+	servicesImportCmd.Flags().Bool("dry-run", false, "Show the planned changes without applying them")
+	servicesImportCmd.Flags().Bool("force", false, "Apply without confirmation")
+}
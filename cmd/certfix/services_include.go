@@ -0,0 +1,136 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/certfix/certfix-cli/pkg/client"
+)
+
+// serviceIncludes are the sections 'services get --include' can add, in the
+// order they are printed
+var serviceIncludes = []string{"keys", "relations", "certificates", "rotation"}
+
+// parseServiceIncludes splits the value of --include, accepting "all" for
+// every section
+func parseServiceIncludes(value string) ([]string, error) {
+	requested := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case part == "":
+		case part == "all":
+			for _, include := range serviceIncludes {
+				requested[include] = true
+			}
+		case slices.Contains(serviceIncludes, part):
+			requested[part] = true
+		default:
+			return nil, fmt.Errorf("invalid --include '%s' (valid: %s, all)", part, strings.Join(serviceIncludes, ", "))
+		}
+	}
+
+	var includes []string
+	for _, include := range serviceIncludes {
+		if requested[include] {
+			includes = append(includes, include)
+		}
+	}
+	return includes, nil
+}
+
+// fetchServiceIncludes fetches the requested sections of a service
+// concurrently. Keys, relations and certificates are lists; rotation is the
+// result of nextRotation.
+func fetchServiceIncludes(apiClient client.APIClient, token string, service map[string]interface{}, includes []string) (map[string]interface{}, error) {
+	hash := stringField(service, "service_hash")
+	sections := make([]interface{}, len(includes))
+	errs := make([]error, len(includes))
+
+	var wg sync.WaitGroup
+	for i, include := range includes {
+		wg.Add(1)
+		go func(i int, include string) {
+			defer wg.Done()
+
+			var endpoint string
+			switch include {
+			case "rotation":
+				sections[i], errs[i] = nextRotation(apiClient, token, service)
+				return
+			case "keys":
+				endpoint = fmt.Sprintf("/services/%s/keys/list", hash)
+			case "relations":
+				endpoint = fmt.Sprintf("/services/%s/matrix/relations", hash)
+			case "certificates":
+				endpoint = fmt.Sprintf("/services/%s/certificates", hash)
+			}
+			response, err := apiClient.GetWithAuth(endpoint, token)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to list %s: %w", include, err)
+				return
+			}
+			sections[i] = parseArrayResponse(response)
+		}(i, include)
+	}
+	wg.Wait()
+
+	result := make(map[string]interface{}, len(includes))
+	for i, include := range includes {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		result[include] = sections[i]
+	}
+	return result, nil
+}
+
+// printServiceIncludes prints the sections fetched by fetchServiceIncludes
+// below the service's details
+func printServiceIncludes(includes []string, sections map[string]interface{}) {
+	for _, include := range includes {
+		if include == "rotation" {
+			rotation := sections[include].(map[string]interface{})
+			fmt.Println("\nRotation:")
+			if stringField(rotation, "policy_id") != "" {
+				fmt.Printf("  Policy:         %v (%v)\n", rotation["policy_name"], rotation["strategy"])
+			}
+			fmt.Printf("  Next Rotation:  %v\n", rotation["summary"])
+			continue
+		}
+
+		items := sections[include].([]map[string]interface{})
+		fmt.Printf("\n%s (%d):\n", strings.ToUpper(include[:1])+include[1:], len(items))
+		if len(items) == 0 {
+			fmt.Println("  None")
+			continue
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		switch include {
+		case "keys":
+			fmt.Fprintln(w, "  KEY ID\tNAME\tENABLED\tEXPIRES AT")
+			for _, key := range items {
+				fmt.Fprintf(w, "  %s\t%s\t%v\t%s\n", stringOrNA(key, "key_id"), stringOrNA(key, "key_name"),
+					boolField(key, "enabled"), formatTimestamp(key["expires_at"], "2006-01-02 15:04", "Never"))
+			}
+		case "relations":
+			fmt.Fprintln(w, "  RELATION ID\tSERVICE\tNAME\tTYPE\tDIRECTION\tENABLED")
+			for _, rel := range items {
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%v\n", stringOrNA(rel, "relation_id"), relatedHash(rel),
+					stringOrNA(rel, "related_service_name"), stringOrNA(rel, "relation_type"), stringOrNA(rel, "direction"), boolField(rel, "enabled"))
+			}
+		case "certificates":
+			fmt.Fprintln(w, "  UNIQUE ID\tCOMMON NAME\tSTATUS\tEXPIRES AT")
+			for _, cert := range items {
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", stringOrNA(cert, "unique_id"), stringOrNA(cert, "common_name"),
+					stringOrNA(cert, "status"), formatTimestamp(cert["expires_at"], "2006-01-02 15:04", "N/A"))
+			}
+		}
+		w.Flush()
+	}
+}
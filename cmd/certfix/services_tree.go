@@ -0,0 +1,187 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/certfix/certfix-cli/pkg/client"
+)
+
+// serviceTreeConcurrency limits parallel per-service requests for --tree
+const serviceTreeConcurrency = 8
+
+// treeGroup is a service group in the services tree
+type treeGroup struct {
+	ID       string        `json:"service_group_id,omitempty"`
+	Name     string        `json:"name"`
+	Services []treeService `json:"services"`
+}
+
+// treeService is a service in the services tree with its keys and relations
+type treeService struct {
+	Hash      string                   `json:"service_hash"`
+	Name      string                   `json:"service_name"`
+	Status    string                   `json:"status"`
+	Keys      []map[string]interface{} `json:"keys"`
+	Relations []map[string]interface{} `json:"relations"`
+	Errors    []string                 `json:"errors,omitempty"`
+}
+
+// buildServiceTree groups services by service group and fetches the keys and
+// relations of each. Groups keep the order their first service appears in;
+// services without a group come last.
+func buildServiceTree(apiClient client.APIClient, token string, services []map[string]interface{}) []*treeGroup {
+	var (
+		groups   []*treeGroup
+		byID     = make(map[string]*treeGroup)
+		ungroup  *treeGroup
+		nodes    = make([]treeService, len(services))
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, serviceTreeConcurrency)
+		grouping = make([]*treeGroup, len(services))
+	)
+	for i, svc := range services {
+		groupID := stringOrNA(svc, "service_group_id")
+		var group *treeGroup
+		if groupID == "N/A" {
+			if ungroup == nil {
+				ungroup = &treeGroup{Name: "No group"}
+			}
+			group = ungroup
+		} else if group = byID[groupID]; group == nil {
+			group = &treeGroup{ID: groupID, Name: stringOrNA(svc, "service_group_name")}
+			byID[groupID] = group
+			groups = append(groups, group)
+		}
+		grouping[i] = group
+
+		wg.Add(1)
+		go func(i int, svc map[string]interface{}) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			hash := stringOrNA(svc, "service_hash")
+			node := treeService{Hash: hash, Name: stringOrNA(svc, "service_name"), Status: serviceStatus(svc)}
+			if response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/keys/list", hash), token); err != nil {
+				node.Errors = append(node.Errors, fmt.Sprintf("failed to list keys: %v", err))
+			} else {
+				node.Keys = parseArrayResponse(response)
+			}
+			if response, err := apiClient.GetWithAuth(fmt.Sprintf("/services/%s/matrix/relations", hash), token); err != nil {
+				node.Errors = append(node.Errors, fmt.Sprintf("failed to list relations: %v", err))
+			} else {
+				node.Relations = parseArrayResponse(response)
+			}
+			nodes[i] = node
+		}(i, svc)
+	}
+	wg.Wait()
+
+	for i, group := range grouping {
+		group.Services = append(group.Services, nodes[i])
+	}
+	if ungroup != nil {
+		groups = append(groups, ungroup)
+	}
+	return groups
+}
+
+// treeNode is a line of a rendered tree with the lines nested under it
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+// printTree renders nodes with box-drawing branches, like printImpactTree
+func printTree(nodes []*treeNode, prefix string) {
+	for i, n := range nodes {
+		branch, childPrefix := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, childPrefix = "└── ", "    "
+		}
+		fmt.Printf("%s%s%s\n", prefix, branch, n.label)
+		printTree(n.children, prefix+childPrefix)
+	}
+}
+
+// enabledLabel renders the enabled field of a key or relation
+func enabledLabel(item map[string]interface{}) string {
+	if enabled, _ := item["enabled"].(bool); enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// countEnabled counts the items whose enabled field is true
+func countEnabled(items []map[string]interface{}) int {
+	n := 0
+	for _, item := range items {
+		if enabled, _ := item["enabled"].(bool); enabled {
+			n++
+		}
+	}
+	return n
+}
+
+// printServiceTree renders services as groups → services → keys/relations,
+// or as nested JSON
+func printServiceTree(groups []*treeGroup, outputFormat string) {
+	if outputFormat == "json" {
+		data, _ := json.MarshalIndent(groups, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	totalGroups, totalServices, totalKeys, totalRelations := 0, 0, 0, 0
+	for gi, group := range groups {
+		if group.ID != "" {
+			totalGroups++
+		}
+		if gi > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%d service(s))\n", group.Name, len(group.Services))
+
+		var serviceNodes []*treeNode
+		for _, svc := range group.Services {
+			totalServices++
+			totalKeys += len(svc.Keys)
+			totalRelations += len(svc.Relations)
+
+			keys := &treeNode{label: fmt.Sprintf("Keys: %d (%d enabled)", len(svc.Keys), countEnabled(svc.Keys))}
+			sorted := append([]map[string]interface{}(nil), svc.Keys...)
+			sort.SliceStable(sorted, func(i, j int) bool {
+				return stringOrNA(sorted[i], "key_name") < stringOrNA(sorted[j], "key_name")
+			})
+			for _, key := range sorted {
+				keys.children = append(keys.children, &treeNode{label: fmt.Sprintf("%s (%s) %s, expires %s",
+					stringOrNA(key, "key_name"), stringOrNA(key, "key_id"), enabledLabel(key),
+					formatTimestamp(key["expires_at"], "2006-01-02", "never"))})
+			}
+
+			relations := &treeNode{label: fmt.Sprintf("Relations: %d (%d enabled)", len(svc.Relations), countEnabled(svc.Relations))}
+			for _, rel := range svc.Relations {
+				label := fmt.Sprintf("→ %s (%s)", stringOrNA(rel, "related_service_name"), stringOrNA(rel, "related_service_hash"))
+				if relType := stringOrNA(rel, "relation_type"); relType != "N/A" {
+					label += " " + relType
+				}
+				if enabled, _ := rel["enabled"].(bool); !enabled {
+					label += " [disabled]"
+				}
+				relations.children = append(relations.children, &treeNode{label: label})
+			}
+
+			node := &treeNode{label: fmt.Sprintf("%s (%s) %s", svc.Name, svc.Hash, svc.Status), children: []*treeNode{keys, relations}}
+			for _, e := range svc.Errors {
+				node.children = append(node.children, &treeNode{label: "Warning: " + e})
+			}
+			serviceNodes = append(serviceNodes, node)
+		}
+		printTree(serviceNodes, "")
+	}
+
+	fmt.Printf("\n%d service(s) in %d group(s), %d key(s), %d relation(s)\n", totalServices, totalGroups, totalKeys, totalRelations)
+}
@@ -0,0 +1,113 @@
+package certfix
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// sortColumns maps the --sort-by column names of a list command, taken from
+// its table headers, to the fields of the items it lists
+type sortColumns map[string]string
+
+// names returns the column names in alphabetical order
+func (c sortColumns) names() []string {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// enableSorting adds --sort-by and --reverse to list commands. RunE sorts
+// the items with sortItems before rendering them.
+func enableSorting(columns sortColumns, cmds ...*cobra.Command) {
+	names := columns.names()
+	for _, c := range cmds {
+		c.Flags().String("sort-by", "", fmt.Sprintf("Sort by column (%s) or any field of the JSON output", strings.Join(names, ", ")))
+		c.Flags().Bool("reverse", false, "Reverse the sort order")
+		c.RegisterFlagCompletionFunc("sort-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return names, cobra.ShellCompDirectiveNoFileComp
+		})
+	}
+}
+
+// sortItems orders items by the --sort-by and --reverse flags of cmd. A
+// column name may also be any field of the items. Items without the field
+// come last in either direction.
+func sortItems(cmd *cobra.Command, columns sortColumns, items []map[string]interface{}) error {
+	column, _ := cmd.Flags().GetString("sort-by")
+	reverse, _ := cmd.Flags().GetBool("reverse")
+	if column == "" {
+		if reverse {
+			return fmt.Errorf("--reverse requires --sort-by")
+		}
+		return nil
+	}
+
+	field, ok := columns[strings.ToLower(column)]
+	if !ok {
+		field = column
+		found := len(items) == 0
+		for _, item := range items {
+			if _, ok := item[field]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid sort column '%s' (valid: %s, or a field of the JSON output)", column, strings.Join(columns.names(), ", "))
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i][field], items[j][field]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if reverse {
+			return compareValues(b, a) < 0
+		}
+		return compareValues(a, b) < 0
+	})
+	return nil
+}
+
+// compareValues compares two JSON values by type: numbers numerically,
+// timestamps chronologically, booleans false before true and other values as
+// case-insensitive text
+func compareValues(a, b interface{}) int {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			return cmp.Compare(x, y)
+		}
+	case bool:
+		if y, ok := b.(bool); ok && x != y {
+			if x {
+				return 1
+			}
+			return -1
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			if tx, err := time.Parse(time.RFC3339, x); err == nil {
+				if ty, err := time.Parse(time.RFC3339, y); err == nil {
+					return tx.Compare(ty)
+				}
+			}
+			if nx, err := strconv.ParseFloat(x, 64); err == nil {
+				if ny, err := strconv.ParseFloat(y, 64); err == nil {
+					return cmp.Compare(nx, ny)
+				}
+			}
+		}
+	}
+	return strings.Compare(strings.ToLower(fmt.Sprintf("%v", a)), strings.ToLower(fmt.Sprintf("%v", b)))
+}
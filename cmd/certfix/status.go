@@ -0,0 +1,168 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// statusExpiryWindow is how far ahead status looks for expiring certificates
+const statusExpiryWindow = 30 * 24 * time.Hour
+
+// environmentStatus is what certfix status reports. Counts are nil when they
+// could not be fetched.
+type environmentStatus struct {
+	Profile              string     `json:"profile"`
+	Endpoint             string     `json:"endpoint"`
+	LoggedIn             bool       `json:"logged_in"`
+	TokenExpiresAt       *time.Time `json:"token_expires_at,omitempty"`
+	Services             *int       `json:"services,omitempty"`
+	Policies             *int       `json:"policies,omitempty"`
+	Events               *int       `json:"events,omitempty"`
+	ExpiringCertificates *int       `json:"expiring_certificates,omitempty"`
+	ExpiredCertificates  *int       `json:"expired_certificates,omitempty"`
+	LostInstances        *int       `json:"lost_instances,omitempty"`
+	Warnings             []string   `json:"warnings,omitempty"`
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show an overview of the current environment",
+	Long: `Show the active profile and endpoint, the login state and session expiry, how
+many services, policies and events exist, the certificates expiring within 30
+days and the instances that stopped checking in.
+
+Counts that cannot be fetched are reported as warnings; the rest of the
+overview is still shown. See 'certfix report' for the details behind them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		status := &environmentStatus{
+			Profile:  config.GetProfile(),
+			Endpoint: config.GetAPIEndpoint(),
+		}
+		if expiresAt, err := auth.GetTokenExpiry(); err == nil {
+			status.TokenExpiresAt = &expiresAt
+		}
+		token, err := auth.GetToken()
+		if err == nil {
+			status.LoggedIn = true
+			collectStatus(status, token)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(status, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Profile:    %s\n", status.Profile)
+		fmt.Printf("Endpoint:   %s\n", status.Endpoint)
+		switch {
+		case status.LoggedIn:
+			fmt.Printf("Session:    logged in, expires in %s (%s)\n",
+				formatAge(time.Until(*status.TokenExpiresAt)), status.TokenExpiresAt.Local().Format("2006-01-02 15:04"))
+		case status.TokenExpiresAt != nil:
+			fmt.Printf("Session:    expired %s ago; run 'certfix login'\n", formatAge(time.Since(*status.TokenExpiresAt)))
+		default:
+			fmt.Println("Session:    not logged in; run 'certfix login'")
+		}
+		if !status.LoggedIn {
+			return nil
+		}
+
+		fmt.Println()
+		printCount := func(label string, n *int) {
+			fmt.Printf("%-38s %s\n", label+":", countOrUnknown(n))
+		}
+		printCount("Services", status.Services)
+		printCount("Policies", status.Policies)
+		printCount("Events", status.Events)
+		printCount("Certificates expiring within 30 days", status.ExpiringCertificates)
+		printCount("Certificates expired", status.ExpiredCertificates)
+		printCount("Lost instances", status.LostInstances)
+
+		if len(status.Warnings) > 0 {
+			fmt.Println()
+			for _, warning := range status.Warnings {
+				fmt.Printf("Warning: %s\n", warning)
+			}
+		}
+		return nil
+	},
+}
+
+// collectStatus fills in the counts of status. Failures are recorded as
+// warnings.
+func collectStatus(status *environmentStatus, token string) {
+	warn := func(format string, args ...interface{}) {
+		status.Warnings = append(status.Warnings, fmt.Sprintf(format, args...))
+	}
+	apiClient := newAPIClient()
+
+	count := func(endpoint, what string) *int {
+		response, err := apiClient.GetWithAuth(endpoint, token)
+		if err != nil {
+			warn("failed to list %s: %v", what, err)
+			return nil
+		}
+		n := len(parseArrayResponse(response))
+		return &n
+	}
+	status.Services = count("/services", "services")
+	status.Policies = count("/policies", "policies")
+	status.Events = count("/events", "events")
+
+	certs, err := newAPI().ListValidCertificates()
+	if err != nil {
+		warn("failed to list certificates: %v", err)
+	} else {
+		expiring, expired := 0, 0
+		for _, cert := range certs {
+			expiresAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", cert["expires_at"]))
+			if err != nil {
+				continue
+			}
+			switch left := time.Until(expiresAt); {
+			case left <= 0:
+				expired++
+			case left <= statusExpiryWindow:
+				expiring++
+			}
+		}
+		status.ExpiringCertificates = &expiring
+		status.ExpiredCertificates = &expired
+	}
+
+	instances, err := newAPI().ListAllInstances()
+	if err != nil {
+		warn("failed to list instances: %v", err)
+	} else {
+		markLostInstances(instances)
+		lost := 0
+		for _, instance := range instances {
+			if instance["status"] == "Lost" {
+				lost++
+			}
+		}
+		status.LostInstances = &lost
+	}
+}
+
+// countOrUnknown renders a count that may not have been fetched
+func countOrUnknown(n *int) string {
+	if n == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d", *n)
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
@@ -0,0 +1,286 @@
+package certfix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/spf13/cobra"
+)
+
+var teamsCmd = &cobra.Command{
+	Use:               "teams",
+	Aliases:           []string{"team"},
+	Short:             "Manage teams",
+	Long:              `Manage teams and their members, so users can be onboarded into the team that owns their services.`,
+	PersistentPreRunE: requireSuperuser,
+}
+
+var teamsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all teams",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth("/teams", token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to list teams: %w", err)
+		}
+		teams := parseArrayResponse(response)
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(teams, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(teams) == 0 {
+			fmt.Println("No teams found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "TEAM ID\tNAME\tMEMBERS\tCREATED AT")
+		fmt.Fprintln(w, "-------\t----\t-------\t----------")
+
+		for _, team := range teams {
+			members := stringOrNA(team, "member_count")
+			if list, ok := team["members"].([]interface{}); ok {
+				members = fmt.Sprintf("%d", len(list))
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				stringOrNA(team, "team_id"),
+				stringOrNA(team, "name"),
+				members,
+				formatTimestamp(team["created_at"], "2006-01-02 15:04", "N/A"))
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var teamsGetCmd = &cobra.Command{
+	Use:   "get <team-id>",
+	Short: "Get details of a team and its members",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		response, err := apiClient.GetWithAuth(fmt.Sprintf("/teams/%s", teamID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get team: %w", err)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(response, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Team ID:     %s\n", stringOrNA(response, "team_id"))
+		fmt.Printf("Name:        %s\n", stringOrNA(response, "name"))
+		fmt.Printf("Description: %s\n", stringOrNA(response, "description"))
+
+		members, _ := response["members"].([]interface{})
+		fmt.Printf("\nMembers (%d):\n", len(members))
+		if len(members) == 0 {
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "USER ID\tEMAIL\tTEAM ROLE")
+		fmt.Fprintln(w, "-------\t-----\t---------")
+		for _, item := range members {
+			member, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", stringOrNA(member, "user_id"), stringOrNA(member, "email"), stringOrNA(member, "role"))
+		}
+		w.Flush()
+
+		return nil
+	},
+}
+
+var teamsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new team",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		description, _ := cmd.Flags().GetString("description")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		payload := map[string]interface{}{
+			"name": name,
+		}
+		if description != "" {
+			payload["description"] = description
+		}
+
+		response, err := apiClient.PostWithAuth("/teams", payload, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to create team: %w", err)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(response, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✓ Team created successfully\n")
+		fmt.Printf("Team ID: %s\n", stringOrNA(response, "team_id"))
+		return nil
+	},
+}
+
+var teamsDeleteCmd = &cobra.Command{
+	Use:     "delete <team-id>",
+	Aliases: []string{"rm", "remove"},
+	Short:   "Delete a team",
+	Long:    `Delete a team. Its members keep their accounts.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete team %s?", teamID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/teams/%s", teamID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to delete team: %w", err)
+		}
+
+		fmt.Printf("✓ Team deleted successfully\n")
+		return nil
+	},
+}
+
+var teamsAddMemberCmd = &cobra.Command{
+	Use:     "add-member <team-id> <user-id>",
+	Short:   "Add a user to a team",
+	Example: `  certfix teams add-member payments 42 --role maintainer`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+		userID := args[1]
+		role, _ := cmd.Flags().GetString("role")
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		payload := map[string]interface{}{
+			"user_id": userID,
+			"role":    role,
+		}
+		_, err = apiClient.PostWithAuth(fmt.Sprintf("/teams/%s/members", teamID), payload, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to add member: %w", err)
+		}
+
+		fmt.Printf("✓ User %s added to team %s as %s\n", userID, teamID, role)
+		return nil
+	},
+}
+
+var teamsRemoveMemberCmd = &cobra.Command{
+	Use:   "remove-member <team-id> <user-id>",
+	Short: "Remove a user from a team",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		teamID := args[0]
+		userID := args[1]
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/teams/%s/members/%s", teamID, userID), token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to remove member: %w", err)
+		}
+
+		fmt.Printf("✓ User %s removed from team %s\n", userID, teamID)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(teamsCmd)
+	teamsCmd.AddCommand(teamsListCmd)
+	teamsCmd.AddCommand(teamsGetCmd)
+	teamsCmd.AddCommand(teamsCreateCmd)
+	teamsCmd.AddCommand(teamsDeleteCmd)
+	teamsCmd.AddCommand(teamsAddMemberCmd)
+	teamsCmd.AddCommand(teamsRemoveMemberCmd)
+
+	teamsListCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	teamsGetCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	teamsCreateCmd.Flags().StringP("name", "n", "", "Name of the team (required)")
+	teamsCreateCmd.Flags().StringP("description", "d", "", "Description of the team")
+	teamsCreateCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+	teamsCreateCmd.MarkFlagRequired("name")
+
+	teamsDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+
+	teamsAddMemberCmd.Flags().StringP("role", "r", "member", "Role in the team (member, maintainer)")
+}
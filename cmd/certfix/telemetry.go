@@ -0,0 +1,79 @@
+package certfix
+
+import (
+	"fmt"
+
+	"github.com/certfix/certfix-cli/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Manage anonymous usage reporting",
+	Long: `Anonymous usage reporting is off unless you turn it on. When enabled, each
+command sends its name (e.g. "services list"), duration, exit status, the CLI
+version and the OS/architecture, together with a random installation ID.
+Arguments, flag values, resource names and credentials are never sent.
+
+CERTFIX_TELEMETRY=off or DO_NOT_TRACK=1 disable reporting regardless of this
+setting.`,
+}
+
+var telemetryOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Opt in to anonymous usage reporting",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := telemetry.Enable(); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to enable telemetry: %w", err)
+		}
+		fmt.Println("✓ Anonymous usage reporting enabled. Thank you!")
+		if telemetry.DisabledByEnv() {
+			fmt.Println("Note: reporting stays off while CERTFIX_TELEMETRY=off or DO_NOT_TRACK=1 is set.")
+		}
+		return nil
+	},
+}
+
+var telemetryOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Opt out of anonymous usage reporting",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := telemetry.Disable(); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to disable telemetry: %w", err)
+		}
+		fmt.Println("✓ Anonymous usage reporting disabled")
+		return nil
+	},
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether usage reporting is enabled",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		status := "disabled"
+		switch {
+		case telemetry.Enabled():
+			status = "enabled"
+		case telemetry.DisabledByEnv():
+			status = "disabled (by environment)"
+		}
+
+		fmt.Printf("Telemetry:        %s\n", status)
+		if telemetry.Enabled() {
+			fmt.Printf("Installation ID:  %s\n", telemetry.InstallID())
+			fmt.Printf("Endpoint:         %s\n", telemetry.Endpoint())
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(telemetryCmd)
+	telemetryCmd.AddCommand(telemetryOnCmd)
+	telemetryCmd.AddCommand(telemetryOffCmd)
+	telemetryCmd.AddCommand(telemetryStatusCmd)
+}
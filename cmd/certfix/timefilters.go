@@ -0,0 +1,79 @@
+package certfix
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// parseTimeBound parses a time filter value: a duration such as "24h" or "7d"
+// counted back from now (or forward, when ahead is set), or a date in the form
+// parseExpiryDate accepts
+func parseTimeBound(value string, ahead bool) (time.Time, error) {
+	if d, err := parseHumanDuration(value); err == nil {
+		if ahead {
+			return time.Now().Add(d), nil
+		}
+		return time.Now().Add(-d), nil
+	}
+	if t, err := parseExpiryDate(value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s' (use a duration such as 24h or 7d, or a date such as 2026-01-01)", value)
+}
+
+// timeFilter keeps the items whose field lies after or before a bound
+type timeFilter struct {
+	flag   string
+	field  string
+	before bool // the field must lie before the bound rather than after it
+}
+
+// timeFilters are the flags added by enableTimeFilters
+var timeFilters = []timeFilter{
+	{flag: "created-since", field: "created_at"},
+	{flag: "updated-since", field: "updated_at"},
+	{flag: "expires-within", field: "expires_at", before: true},
+}
+
+// enableTimeFilters adds --created-since and --updated-since to list
+// commands, and --expires-within when their items expire. RunE filters the
+// items with filterByTime before rendering them.
+func enableTimeFilters(expiring bool, cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().String("created-since", "", "Only show entries created within this duration or since this date (e.g. 7d, 2026-01-01)")
+		c.Flags().String("updated-since", "", "Only show entries updated within this duration or since this date (e.g. 24h)")
+		if expiring {
+			c.Flags().String("expires-within", "", "Only show entries expiring within this duration or before this date, expired ones included (e.g. 30d)")
+		}
+	}
+}
+
+// filterByTime applies the time filter flags of cmd. Items without the
+// filtered field, such as keys that never expire, are left out.
+func filterByTime(cmd *cobra.Command, items []map[string]interface{}) ([]map[string]interface{}, error) {
+	for _, f := range timeFilters {
+		value, err := cmd.Flags().GetString(f.flag)
+		if err != nil || value == "" {
+			continue
+		}
+		bound, err := parseTimeBound(value, f.before)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %w", f.flag, err)
+		}
+
+		var kept []map[string]interface{}
+		for _, item := range items {
+			t, err := time.Parse(time.RFC3339, stringField(item, f.field))
+			if err != nil {
+				continue
+			}
+			if f.before && !t.After(bound) || !f.before && !t.Before(bound) {
+				kept = append(kept, item)
+			}
+		}
+		items = kept
+	}
+	return items, nil
+}
@@ -0,0 +1,565 @@
+package certfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/internal/tui"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// uiRow is one line of a dashboard pane and the API object behind it
+type uiRow struct {
+	cells []string
+	item  map[string]interface{}
+}
+
+// uiAction is an operation on the selected row that runs after confirmation
+type uiAction struct {
+	prompt string
+	run    func() (string, error)
+}
+
+// uiPane is one tab of the dashboard
+type uiPane struct {
+	title   string
+	empty   string
+	columns []string
+	load    func() ([]uiRow, error)
+	// actions maps a key to the action it triggers on the selected row; nil
+	// means the key does nothing for that row
+	actions map[string]func(row uiRow) *uiAction
+
+	rows   []uiRow
+	err    error
+	loaded bool
+	cursor int
+	offset int
+}
+
+// uiLoadResult carries freshly loaded rows back to the event loop
+type uiLoadResult struct {
+	pane int
+	rows []uiRow
+	err  error
+}
+
+// uiModel is the state of the dashboard
+type uiModel struct {
+	screen   *tui.Screen
+	endpoint string
+	panes    []*uiPane
+	active   int
+	pending  int
+	updated  time.Time
+	status   string
+	confirm  *uiAction
+	detail   []string
+	scroll   int
+}
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Interactive terminal dashboard",
+	Long: `Open a full-screen operations console with panes for services, certificates
+expiring soon, recent events and lost instances. The data refreshes every
+--interval.
+
+Keys:
+  ←/→, Tab, 1-4   switch pane
+  ↑/↓, PgUp/PgDn  move the selection
+  Enter, i        inspect the selected item
+  r               rotate the certificate of the selected service
+  a               activate or deactivate the selected service
+  R               refresh now
+  q, Esc          close the inspector, or quit`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		days, _ := cmd.Flags().GetInt("days")
+
+		if interval < time.Second {
+			return fmt.Errorf("--interval must be at least 1s")
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		screen, err := tui.Open()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		defer screen.Close()
+
+		m := &uiModel{
+			screen:   screen,
+			endpoint: config.GetAPIEndpoint(),
+			panes:    uiPanes(apiClient, token, days),
+		}
+		return m.run(interval)
+	},
+}
+
+// uiPanes builds the dashboard panes
+func uiPanes(apiClient client.APIClient, token string, days int) []*uiPane {
+	rotate := func(row uiRow) *uiAction {
+		hash := stringOrNA(row.item, "service_hash")
+		if hash == "N/A" {
+			return nil
+		}
+		return &uiAction{
+			prompt: fmt.Sprintf("Rotate the certificate of %s?", hash),
+			run: func() (string, error) {
+				if _, err := apiClient.PostWithAuth("/services/"+hash+"/certificates/rotate", map[string]interface{}{}, token); err != nil {
+					return "", fmt.Errorf("failed to rotate %s: %w", hash, err)
+				}
+				return fmt.Sprintf("✓ Rotation started for %s", hash), nil
+			},
+		}
+	}
+
+	services := &uiPane{
+		title:   "Services",
+		empty:   "No services found.",
+		columns: []string{"HASH", "NAME", "GROUP", "POLICY", "STATUS"},
+		load: func() ([]uiRow, error) {
+			response, err := apiClient.GetWithAuth("/services", token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list services: %w", err)
+			}
+			var rows []uiRow
+			for _, svc := range parseArrayResponse(response) {
+				rows = append(rows, uiRow{
+					cells: []string{
+						stringOrNA(svc, "service_hash"),
+						stringOrNA(svc, "service_name"),
+						stringOrNA(svc, "service_group_name"),
+						stringOrNA(svc, "policy_name"),
+						serviceStatus(svc),
+					},
+					item: svc,
+				})
+			}
+			return rows, nil
+		},
+		actions: map[string]func(row uiRow) *uiAction{
+			"r": rotate,
+			"a": func(row uiRow) *uiAction {
+				hash := stringOrNA(row.item, "service_hash")
+				active, _ := row.item["active"].(bool)
+				verb := "Activate"
+				if active {
+					verb = "Deactivate"
+				}
+				return &uiAction{
+					prompt: fmt.Sprintf("%s service %s?", verb, hash),
+					run: func() (string, error) {
+						payload := map[string]interface{}{"active": !active}
+						if _, err := apiClient.PutWithAuth(fmt.Sprintf("/services/%s", hash), payload, token); err != nil {
+							return "", fmt.Errorf("failed to %s %s: %w", strings.ToLower(verb), hash, err)
+						}
+						return fmt.Sprintf("✓ Service %s %sd", hash, strings.ToLower(verb)), nil
+					},
+				}
+			},
+		},
+	}
+
+	certificates := &uiPane{
+		title:   "Expiring certificates",
+		empty:   fmt.Sprintf("No certificates expire within %d days.", days),
+		columns: []string{"COMMON NAME", "TYPE", "SERIAL", "EXPIRES AT", "DAYS LEFT"},
+		load: func() ([]uiRow, error) {
+			certs, err := newAPI().ListExpiringCertificates(days)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list expiring certificates: %w", err)
+			}
+			var rows []uiRow
+			for _, cert := range certs {
+				daysLeft := "N/A"
+				if expiresAt, err := time.Parse(time.RFC3339, fmt.Sprintf("%v", cert["expires_at"])); err == nil {
+					daysLeft = fmt.Sprint(int(time.Until(expiresAt).Hours() / 24))
+				}
+				rows = append(rows, uiRow{
+					cells: []string{
+						stringOrNA(cert, "common_name"),
+						stringOrNA(cert, "certificate_type"),
+						stringOrNA(cert, "serial_number"),
+						formatTimestamp(cert["expires_at"], "2006-01-02 15:04", "N/A"),
+						daysLeft,
+					},
+					item: cert,
+				})
+			}
+			return rows, nil
+		},
+		actions: map[string]func(row uiRow) *uiAction{"r": rotate},
+	}
+
+	events := &uiPane{
+		title:   "Recent events",
+		empty:   "No event occurrences in the last 24 hours.",
+		columns: []string{"TIME", "EVENT", "SOURCE", "COUNTER"},
+		load: func() ([]uiRow, error) {
+			occurrences, err := fetchOccurrences(apiClient, token, "", time.Now().Add(-24*time.Hour))
+			if err != nil {
+				return nil, err
+			}
+			// Newest first
+			var rows []uiRow
+			for i := len(occurrences) - 1; i >= 0; i-- {
+				occ := occurrences[i]
+				name := stringOrNA(occ, "event_name")
+				if name == "N/A" {
+					name = stringOrNA(occ, "event_id")
+				}
+				rows = append(rows, uiRow{
+					cells: []string{
+						formatTimestamp(occurrenceTime(occ), "2006-01-02 15:04:05", "N/A"),
+						name,
+						stringOrNA(occ, "source"),
+						occurrenceCounter(occ),
+					},
+					item: occ,
+				})
+			}
+			return rows, nil
+		},
+	}
+
+	instances := &uiPane{
+		title:   "Lost instances",
+		empty:   "No lost instances.",
+		columns: []string{"ID", "HOSTNAME", "SERVICE", "LAST SEEN", "SILENT FOR"},
+		load: func() ([]uiRow, error) {
+			all, err := newAPI().ListAllInstances()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list instances: %w", err)
+			}
+			markLostInstances(all)
+			var rows []uiRow
+			for _, instance := range all {
+				if instance["status"] != "Lost" {
+					continue
+				}
+				silentFor := "N/A"
+				if silence, ok := instanceSilence(instance); ok {
+					silentFor = formatAge(silence)
+				}
+				rows = append(rows, uiRow{
+					cells: []string{
+						stringOrNA(instance, "id"),
+						stringOrNA(instance, "hostname"),
+						stringOrNA(instance, "service_hash"),
+						formatTimestamp(instance["last_seen_at"], "2006-01-02 15:04", "N/A"),
+						silentFor,
+					},
+					item: instance,
+				})
+			}
+			return rows, nil
+		},
+	}
+
+	return []*uiPane{services, certificates, events, instances}
+}
+
+// run is the event loop: it redraws after every key press, load and action
+// result, and reloads all panes every interval
+func (m *uiModel) run(interval time.Duration) error {
+	loaded := make(chan uiLoadResult)
+	finished := make(chan string)
+
+	refresh := func() {
+		if m.pending > 0 {
+			return
+		}
+		m.pending = len(m.panes)
+		for i, pane := range m.panes {
+			go func(i int, load func() ([]uiRow, error)) {
+				rows, err := load()
+				loaded <- uiLoadResult{pane: i, rows: rows, err: err}
+			}(i, pane.load)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	refresh()
+	for {
+		m.draw()
+
+		select {
+		case key, ok := <-m.screen.Keys():
+			if !ok {
+				return nil
+			}
+			quit, action := m.handleKey(key)
+			if quit {
+				return nil
+			}
+			if key == "R" {
+				refresh()
+			}
+			if action != nil {
+				m.status = "Working..."
+				go func(run func() (string, error)) {
+					message, err := run()
+					if err != nil {
+						message = "Error: " + err.Error()
+					}
+					finished <- message
+				}(action.run)
+			}
+		case result := <-loaded:
+			pane := m.panes[result.pane]
+			pane.rows, pane.err, pane.loaded = result.rows, result.err, true
+			pane.clamp()
+			m.pending--
+			if m.pending == 0 {
+				m.updated = time.Now()
+			}
+		case message := <-finished:
+			m.status = message
+			refresh()
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// handleKey applies a key press. It reports whether the dashboard should close
+// and returns a confirmed action to run, if any.
+func (m *uiModel) handleKey(key string) (quit bool, run *uiAction) {
+	if key == tui.KeyCtrlC {
+		return true, nil
+	}
+
+	// A pending confirmation takes the next key
+	if m.confirm != nil {
+		action := m.confirm
+		m.confirm = nil
+		if key == "y" || key == "Y" {
+			return false, action
+		}
+		m.status = "Cancelled."
+		return false, nil
+	}
+
+	// Inspector
+	if m.detail != nil {
+		_, height := m.screen.Size()
+		page := height - 3
+		switch key {
+		case "q", tui.KeyEsc, tui.KeyEnter, "i":
+			m.detail = nil
+		case tui.KeyUp, "k":
+			m.scroll--
+		case tui.KeyDown, "j":
+			m.scroll++
+		case tui.KeyPageUp:
+			m.scroll -= page
+		case tui.KeyPageDown:
+			m.scroll += page
+		}
+		if max := len(m.detail) - page; m.scroll > max {
+			m.scroll = max
+		}
+		if m.scroll < 0 {
+			m.scroll = 0
+		}
+		return false, nil
+	}
+
+	pane := m.panes[m.active]
+	switch key {
+	case "q", tui.KeyEsc:
+		return true, nil
+	case tui.KeyRight, tui.KeyTab, "l":
+		m.active = (m.active + 1) % len(m.panes)
+	case tui.KeyLeft, "h":
+		m.active = (m.active + len(m.panes) - 1) % len(m.panes)
+	case "1", "2", "3", "4":
+		if i := int(key[0] - '1'); i < len(m.panes) {
+			m.active = i
+		}
+	case tui.KeyUp, "k":
+		pane.cursor--
+	case tui.KeyDown, "j":
+		pane.cursor++
+	case tui.KeyPageUp:
+		pane.cursor -= m.visibleRows()
+	case tui.KeyPageDown:
+		pane.cursor += m.visibleRows()
+	case tui.KeyEnter, "i":
+		if row, ok := pane.selected(); ok {
+			data, _ := json.MarshalIndent(row.item, "", "  ")
+			m.detail = strings.Split(string(data), "\n")
+			m.scroll = 0
+		}
+	default:
+		if build, ok := pane.actions[key]; ok {
+			if row, ok := pane.selected(); ok {
+				if action := build(row); action != nil {
+					m.confirm = action
+				}
+			}
+		}
+	}
+	pane.clamp()
+	return false, nil
+}
+
+// selected returns the highlighted row
+func (p *uiPane) selected() (uiRow, bool) {
+	if p.cursor < 0 || p.cursor >= len(p.rows) {
+		return uiRow{}, false
+	}
+	return p.rows[p.cursor], true
+}
+
+// clamp keeps the cursor within the rows
+func (p *uiPane) clamp() {
+	if p.cursor >= len(p.rows) {
+		p.cursor = len(p.rows) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+}
+
+// visibleRows is the number of table rows that fit below the header
+func (m *uiModel) visibleRows() int {
+	_, height := m.screen.Size()
+	if rows := height - 7; rows > 1 {
+		return rows
+	}
+	return 1
+}
+
+// draw renders the current state
+func (m *uiModel) draw() {
+	var lines []string
+
+	updated := "loading..."
+	if !m.updated.IsZero() {
+		updated = "updated " + m.updated.Format("15:04:05")
+	}
+	if m.pending > 0 && !m.updated.IsZero() {
+		updated += " (refreshing)"
+	}
+	lines = append(lines, fmt.Sprintf("\x1b[1mCertFix\x1b[0m  %s  %s", m.endpoint, updated))
+
+	var tabs []string
+	for i, pane := range m.panes {
+		tab := fmt.Sprintf(" %d %s (%d) ", i+1, pane.title, len(pane.rows))
+		if i == m.active {
+			tab = "\x1b[7m" + tab + "\x1b[0m"
+		}
+		tabs = append(tabs, tab)
+	}
+	lines = append(lines, strings.Join(tabs, "│"), "")
+
+	_, height := m.screen.Size()
+	if m.detail != nil {
+		end := m.scroll + height - 3
+		if end > len(m.detail) {
+			end = len(m.detail)
+		}
+		lines = append(lines, m.detail[m.scroll:end]...)
+		for len(lines) < height-1 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "\x1b[2m↑/↓ scroll  q/Esc back\x1b[0m")
+		m.screen.Draw(lines)
+		return
+	}
+
+	pane := m.panes[m.active]
+	switch {
+	case !pane.loaded:
+		lines = append(lines, "Loading...")
+	case pane.err != nil:
+		lines = append(lines, "Error: "+pane.err.Error())
+	case len(pane.rows) == 0:
+		lines = append(lines, pane.empty)
+	default:
+		visible := m.visibleRows()
+		if pane.cursor < pane.offset {
+			pane.offset = pane.cursor
+		}
+		if pane.cursor >= pane.offset+visible {
+			pane.offset = pane.cursor - visible + 1
+		}
+
+		table := pane.table()
+		lines = append(lines, "\x1b[1m"+table[0]+"\x1b[0m")
+		end := pane.offset + visible
+		if end > len(pane.rows) {
+			end = len(pane.rows)
+		}
+		for i := pane.offset; i < end; i++ {
+			line := table[i+1]
+			if i == pane.cursor {
+				line = "\x1b[7m" + line + "\x1b[0m"
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+
+	status := m.status
+	if m.confirm != nil {
+		status = "\x1b[1;33m" + m.confirm.prompt + " (y/N)\x1b[0m"
+	}
+	lines = append(lines, status)
+
+	help := "←/→ pane  ↑/↓ move  Enter inspect  R refresh  q quit"
+	if _, ok := pane.actions["a"]; ok {
+		help = "←/→ pane  ↑/↓ move  Enter inspect  r rotate  a (de)activate  R refresh  q quit"
+	} else if _, ok := pane.actions["r"]; ok {
+		help = "←/→ pane  ↑/↓ move  Enter inspect  r rotate  R refresh  q quit"
+	}
+	lines = append(lines, "\x1b[2m"+help+"\x1b[0m")
+
+	m.screen.Draw(lines)
+}
+
+// table lays out the header and all rows of a pane in aligned columns
+func (p *uiPane) table() []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(p.columns, "\t"))
+	for _, row := range p.rows {
+		fmt.Fprintln(w, strings.Join(row.cells, "\t"))
+	}
+	w.Flush()
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+
+	// UI command flags
+	uiCmd.Flags().Duration("interval", 30*time.Second, "Refresh interval")
+	uiCmd.Flags().IntP("days", "d", 30, "Show certificates expiring within this many days")
+}
@@ -4,13 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -35,8 +32,7 @@ var ugListCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/user-groups", token)
 		if err != nil {
@@ -108,8 +104,7 @@ var ugGetCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/user-groups/%s", groupID), token)
 		if err != nil {
@@ -157,8 +152,7 @@ var ugCreateCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		payload := map[string]interface{}{
 			"name":    name,
@@ -215,8 +209,7 @@ var ugUpdateCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.PutWithAuth(fmt.Sprintf("/user-groups/%s", groupID), payload, token)
 		if err != nil {
@@ -245,16 +238,14 @@ var ugDeleteCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		groupID := args[0]
-		force, _ := cmd.Flags().GetBool("force")
-
-		if !force {
-			fmt.Printf("Are you sure you want to delete user group %s? (y/N): ", groupID)
-			var ans string
-			fmt.Scanln(&ans)
-			if strings.ToLower(ans) != "y" && strings.ToLower(ans) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
+
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete user group %s?", groupID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
 		}
 
 		token, err := auth.GetToken()
@@ -263,8 +254,7 @@ var ugDeleteCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/user-groups/%s", groupID), token)
 		if err != nil {
@@ -290,8 +280,7 @@ var ugEnableCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		_, err = apiClient.PutWithAuth(fmt.Sprintf("/user-groups/%s", groupID), map[string]interface{}{"enabled": true}, token)
 		if err != nil {
@@ -317,8 +306,7 @@ var ugDisableCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		_, err = apiClient.PatchWithAuth(fmt.Sprintf("/user-groups/%s/disable", groupID), nil, token)
 		if err != nil {
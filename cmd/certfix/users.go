@@ -4,12 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
 	"text/tabwriter"
 
 	"github.com/certfix/certfix-cli/internal/auth"
-	"github.com/certfix/certfix-cli/internal/config"
-	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +14,7 @@ var usersCmd = &cobra.Command{
 	Use:               "users",
 	Aliases:           []string{"user"},
 	Short:             "Manage users",
-	Long:              `Manage users including listing, creating, updating, deleting, and managing super user status.`,
+	Long:              `Manage users including listing, inviting, creating, updating, deactivating, deleting, and managing roles and super user status.`,
 	PersistentPreRunE: requireSuperuser,
 }
 
@@ -34,8 +31,7 @@ var usersListCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth("/users", token)
 		if err != nil {
@@ -66,8 +62,8 @@ var usersListCmd = &cobra.Command{
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "USER ID\tEMAIL\tSUPER\tENABLED")
-		fmt.Fprintln(w, "-------\t-----\t-----\t-------")
+		fmt.Fprintln(w, "USER ID\tEMAIL\tROLE\tSUPER\tENABLED")
+		fmt.Fprintln(w, "-------\t-----\t----\t-----\t-------")
 
 		for _, u := range users {
 			id := fmt.Sprintf("%v", u["user_id"])
@@ -83,7 +79,7 @@ var usersListCmd = &cobra.Command{
 			if e, ok := u["enabled"].(bool); ok && e {
 				enabled = "Yes"
 			}
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", id, email, isSuper, enabled)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", id, email, stringOrNA(u, "role"), isSuper, enabled)
 		}
 		w.Flush()
 
@@ -105,8 +101,7 @@ var usersGetCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.GetWithAuth(fmt.Sprintf("/users/%s", userID), token)
 		if err != nil {
@@ -122,6 +117,7 @@ var usersGetCmd = &cobra.Command{
 
 		fmt.Printf("User ID:    %v\n", response["user_id"])
 		fmt.Printf("Email:      %v\n", response["email"])
+		fmt.Printf("Role:       %s\n", stringOrNA(response, "role"))
 		isSuper := "No"
 		if s, ok := response["is_super_user"].(bool); ok && s {
 			isSuper = "Yes"
@@ -166,8 +162,7 @@ var usersCreateCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		payload := map[string]interface{}{
 			"name":     name,
@@ -243,8 +238,7 @@ var usersUpdateCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		response, err := apiClient.PutWithAuth(fmt.Sprintf("/users/%s", userID), payload, token)
 		if err != nil {
@@ -274,16 +268,14 @@ var usersDeleteCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userID := args[0]
-		force, _ := cmd.Flags().GetBool("force")
-
-		if !force {
-			fmt.Printf("Are you sure you want to delete user %s? (y/N): ", userID)
-			var ans string
-			fmt.Scanln(&ans)
-			if strings.ToLower(ans) != "y" && strings.ToLower(ans) != "yes" {
-				fmt.Println("Deletion cancelled.")
-				return nil
-			}
+
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete user %s?", userID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
 		}
 
 		token, err := auth.GetToken()
@@ -292,8 +284,7 @@ var usersDeleteCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		_, err = apiClient.DeleteWithAuth(fmt.Sprintf("/users/%s", userID), token)
 		if err != nil {
@@ -306,6 +297,156 @@ var usersDeleteCmd = &cobra.Command{
 	},
 }
 
+var usersInviteCmd = &cobra.Command{
+	Use:   "invite <email>",
+	Short: "Invite a user by email",
+	Long: `Invite a teammate by email. The server sends them a link to set their own
+password, so no password has to be shared. --role and --team set what they
+can do once they accept.`,
+	Example: `  certfix users invite alice@example.com --role operator --team payments`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		email := args[0]
+		name, _ := cmd.Flags().GetString("name")
+		role, _ := cmd.Flags().GetString("role")
+		team, _ := cmd.Flags().GetString("team")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		payload := map[string]interface{}{
+			"email": email,
+		}
+		if name != "" {
+			payload["name"] = name
+		}
+		if role != "" {
+			payload["role"] = role
+		}
+		if team != "" {
+			payload["team_id"] = team
+		}
+
+		response, err := apiClient.PostWithAuth("/users/invite", payload, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to invite user: %w", err)
+		}
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(response, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("✓ Invitation sent to %s\n", email)
+		if expiresAt := formatTimestamp(response["expires_at"], "2006-01-02 15:04", ""); expiresAt != "" {
+			fmt.Printf("The invitation expires at %s.\n", expiresAt)
+		}
+		return nil
+	},
+}
+
+var usersDeactivateCmd = &cobra.Command{
+	Use:   "deactivate <user-id>",
+	Short: "Deactivate a user",
+	Long: `Deactivate a user: they can no longer log in and their sessions and personal
+access tokens stop working, but their account and history are kept. Use
+'certfix users activate' to undo it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID := args[0]
+
+		confirmed, err := confirm(cmd, fmt.Sprintf("Are you sure you want to deactivate user %s?", userID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Deactivation cancelled.")
+			return nil
+		}
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		_, err = apiClient.PatchWithAuth(fmt.Sprintf("/users/%s/disable", userID), nil, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to deactivate user: %w", err)
+		}
+
+		fmt.Printf("✓ User deactivated successfully\n")
+		return nil
+	},
+}
+
+var usersActivateCmd = &cobra.Command{
+	Use:   "activate <user-id>",
+	Short: "Reactivate a deactivated user",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID := args[0]
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		_, err = apiClient.PatchWithAuth(fmt.Sprintf("/users/%s/enable", userID), nil, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to activate user: %w", err)
+		}
+
+		fmt.Printf("✓ User activated successfully\n")
+		return nil
+	},
+}
+
+var usersSetRoleCmd = &cobra.Command{
+	Use:   "set-role <user-id> <role>",
+	Short: "Change the role of a user",
+	Long: `Change the role of a user, which decides what they may do. Run
+'certfix auth can-i --list' as that user to see what a role allows.`,
+	Example: `  certfix users set-role 42 operator`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userID := args[0]
+		role := args[1]
+
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		apiClient := newAPIClient()
+
+		_, err = apiClient.PutWithAuth(fmt.Sprintf("/users/%s/role", userID), map[string]interface{}{"role": role}, token)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to set role: %w", err)
+		}
+
+		fmt.Printf("✓ Role of user %s set to %s\n", userID, role)
+		return nil
+	},
+}
+
 var usersSetSuperCmd = &cobra.Command{
 	Use:   "set-super <email>",
 	Short: "Grant super user privileges to a user",
@@ -319,8 +460,7 @@ var usersSetSuperCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		payload := map[string]interface{}{
 			"email": email,
@@ -350,8 +490,7 @@ var usersRevokeSuperCmd = &cobra.Command{
 			return err
 		}
 
-		endpoint := config.GetAPIEndpoint()
-		apiClient := client.NewHTTPClient(endpoint)
+		apiClient := newAPIClient()
 
 		payload := map[string]interface{}{
 			"email": email,
@@ -375,6 +514,10 @@ func init() {
 	usersCmd.AddCommand(usersCreateCmd)
 	usersCmd.AddCommand(usersUpdateCmd)
 	usersCmd.AddCommand(usersDeleteCmd)
+	usersCmd.AddCommand(usersInviteCmd)
+	usersCmd.AddCommand(usersDeactivateCmd)
+	usersCmd.AddCommand(usersActivateCmd)
+	usersCmd.AddCommand(usersSetRoleCmd)
 	usersCmd.AddCommand(usersSetSuperCmd)
 	usersCmd.AddCommand(usersRevokeSuperCmd)
 
@@ -396,4 +539,11 @@ func init() {
 	usersUpdateCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 
 	usersDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+
+	usersInviteCmd.Flags().StringP("name", "n", "", "Full name of the user")
+	usersInviteCmd.Flags().StringP("role", "r", "", "Role of the user once they accept (default: the server's default role)")
+	usersInviteCmd.Flags().StringP("team", "t", "", "Team ID to add the user to once they accept")
+	usersInviteCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+
+	usersDeactivateCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 }
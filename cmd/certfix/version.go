@@ -1,9 +1,11 @@
 package certfix
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/certfix/certfix-cli/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -17,12 +19,66 @@ var (
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number",
-	Long:  `Display the current version of Certfix CLI.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Certfix CLI v%s (built %s)\n", strings.TrimPrefix(Version, "v"), BuildDate)
+	Long: `Display the current version of Certfix CLI.
+
+With --remote the server is asked for its version and features as well, and
+any incompatibility with this CLI is reported.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		remote, _ := cmd.Flags().GetBool("remote")
+		outputFormat, _ := cmd.Flags().GetString("output")
+
+		if !remote {
+			fmt.Printf("Certfix CLI v%s (built %s)\n", strings.TrimPrefix(Version, "v"), BuildDate)
+			return nil
+		}
+
+		endpoint := config.GetAPIEndpoint()
+		info, err := fetchServerInfo(endpoint)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to get server version: %w", err)
+		}
+		warnings := compatibilityWarnings(info, nil)
+
+		if outputFormat == "json" {
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"cli_version": strings.TrimPrefix(Version, "v"),
+				"build_date":  BuildDate,
+				"server":      info,
+				"warnings":    warnings,
+			}, "", "  ")
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Certfix CLI v%s (built %s)\n\n", strings.TrimPrefix(Version, "v"), BuildDate)
+		fmt.Printf("Server:          %s\n", endpoint)
+		if !info.Supported {
+			fmt.Println("\nThe server does not report its version; it predates version reporting.")
+			return nil
+		}
+		fmt.Printf("Server version:  %s\n", orNone(info.Version))
+		fmt.Printf("API version:     %s\n", orNone(info.APIVersion))
+		fmt.Printf("Minimum CLI:     %s\n", orNone(info.MinCLIVersion))
+		if len(info.Features) > 0 {
+			fmt.Printf("Features:        %s\n", strings.Join(info.Features, ", "))
+		}
+
+		fmt.Println()
+		if len(warnings) == 0 {
+			fmt.Println("✓ CLI and server are compatible")
+		}
+		for _, warning := range warnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+
+	// Version command flags
+	versionCmd.Flags().Bool("remote", false, "Also show the server version and check compatibility")
+	versionCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
 }
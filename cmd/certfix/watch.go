@@ -0,0 +1,83 @@
+package certfix
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/certfix/certfix-cli/pkg/notify"
+)
+
+// runWatch clears the screen and calls render every interval until the user
+// interrupts it. render prints the current view and returns the status of each
+// item keyed by a display label; statuses from the previous refresh are passed
+// back in so the view can mark transitions. Changes are also listed below the
+// view, highlighted, until the next refresh, and sent to notifiers.
+func runWatch(interval time.Duration, title string, notifiers []notify.Notifier, render func(prev map[string]string) (map[string]string, error)) error {
+	if interval < time.Second {
+		return fmt.Errorf("watch interval must be at least 1s")
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev map[string]string
+	for {
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("Every %s: %s    %s\n\n", interval, title, time.Now().Format("2006-01-02 15:04:05"))
+
+		current, err := render(prev)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		} else {
+			if changes := statusChanges(prev, current); len(changes) > 0 {
+				labels := make([]string, 0, len(changes))
+				for label := range changes {
+					labels = append(labels, label)
+				}
+				sort.Strings(labels)
+
+				fmt.Printf("\n\033[1;33mStatus changes:\033[0m\n")
+				msg := notify.Message{
+					Title:    fmt.Sprintf("%d status change(s) in %s", len(changes), title),
+					Severity: notify.SeverityWarning,
+				}
+				for _, label := range labels {
+					fmt.Printf("\033[33m  %s: %s\033[0m\n", label, changes[label])
+					msg.Lines = append(msg.Lines, fmt.Sprintf("%s: %s", label, changes[label]))
+				}
+				sendNotification(notifiers, msg)
+			}
+			prev = current
+		}
+
+		select {
+		case <-interrupt:
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// statusChanges returns a "Old → New" description for every label whose status
+// differs between two refreshes. Nothing is reported for the first refresh.
+func statusChanges(prev, current map[string]string) map[string]string {
+	changes := make(map[string]string)
+	if prev == nil {
+		return changes
+	}
+	for label, status := range current {
+		if old, ok := prev[label]; ok && old != status {
+			changes[label] = fmt.Sprintf("%s → %s", old, status)
+		}
+	}
+	return changes
+}
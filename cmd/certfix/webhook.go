@@ -0,0 +1,309 @@
+package certfix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/api"
+	"github.com/certfix/certfix-cli/internal/auth"
+	"github.com/certfix/certfix-cli/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// webhookForwardHeaders are the request headers copied to the forward target.
+// Headers starting with X- (signatures, delivery IDs) are always copied.
+var webhookForwardHeaders = []string{"Content-Type", "User-Agent"}
+
+// webhookEventName returns a short description of a delivery for the log line
+func webhookEventName(body []byte) string {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "non-JSON payload"
+	}
+	for _, key := range []string{"event", "event_type", "type", "action"} {
+		if value, ok := payload[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return "payload"
+}
+
+// relayWebhook copies an incoming delivery to target and returns the status
+// line of the response
+func relayWebhook(httpClient *http.Client, target string, r *http.Request, body []byte) (string, error) {
+	req, err := http.NewRequest(r.Method, target, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range r.Header {
+		if strings.HasPrefix(strings.ToLower(name), "x-") {
+			req.Header[name] = values
+		}
+	}
+	for _, name := range webhookForwardHeaders {
+		if value := r.Header.Get(name); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.Status, nil
+}
+
+var webhookCmd = &cobra.Command{
+	Use:     "webhook",
+	Aliases: []string{"webhooks"},
+	Short:   "Develop against service webhooks",
+	Long:    `Tools for testing webhook consumers locally.`,
+}
+
+// webhookTemporaryPath registers a temporary webhook next to a service's own,
+// which the server drops by itself once its TTL runs out. Servers without
+// temporary webhooks answer 404.
+const webhookTemporaryPath = "/services/%s/webhooks/temporary"
+
+// registerTemporaryWebhook registers publicURL as a temporary webhook of a
+// service for ttl and returns its ID. supported is false when the server has
+// no temporary webhooks.
+func registerTemporaryWebhook(apiClient client.APIClient, token, serviceHash, publicURL string, ttl time.Duration) (id string, supported bool, err error) {
+	body, _ := json.Marshal(map[string]interface{}{"url": publicURL, "ttl_seconds": int(ttl.Seconds())})
+	status, response, err := apiClient.RawWithAuth(http.MethodPost, fmt.Sprintf(webhookTemporaryPath, serviceHash), body, token)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to register temporary webhook: %w", err)
+	}
+	if status == http.StatusNotFound {
+		return "", false, nil
+	}
+	if status < 200 || status >= 300 {
+		return "", true, fmt.Errorf("failed to register temporary webhook: request failed with status %d: %s", status, string(response))
+	}
+	var registered map[string]interface{}
+	if err := json.Unmarshal(response, &registered); err != nil {
+		return "", true, fmt.Errorf("failed to parse temporary webhook: %w", err)
+	}
+	id = firstField(registered, "webhook_id", "id")
+	if id == "" {
+		return "", true, fmt.Errorf("the server returned no ID for the temporary webhook")
+	}
+	return id, true, nil
+}
+
+var webhookListenCmd = &cobra.Command{
+	Use:   "listen <service-hash>",
+	Short: "Relay a service's webhook deliveries to a local server",
+	Long: `Register a temporary webhook for a service pointing at this machine and relay
+every delivery (such as certificate rotation events) to --forward, printing a
+line per delivery. The service's own webhook keeps receiving its deliveries.
+The temporary webhook is removed when the command exits, and the server drops
+it by itself after --ttl, so a crash or a closed terminal leaves nothing
+behind.
+
+Servers without temporary webhooks can only divert the service's own webhook
+to this machine. Live deliveries then stop reaching the real consumer until
+the command exits, so this asks for confirmation (or --force). The original
+URL is restored on Ctrl+C, SIGTERM or SIGHUP, unless someone changed the
+webhook in the meantime; after a crash it has to be restored by hand with
+'certfix services update'.
+
+The CertFix API must be able to reach --public-url. When the API runs locally
+the default (derived from --listen) works as is; otherwise expose --listen
+through a tunnel and pass its address as --public-url.`,
+	Example: `  certfix webhook listen abc123 --forward http://localhost:8080/hook
+  certfix webhook listen abc123 --forward http://localhost:8080/hook \
+    --listen :8788 --public-url https://my-tunnel.example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceHash := args[0]
+		forward, _ := cmd.Flags().GetString("forward")
+		listen, _ := cmd.Flags().GetString("listen")
+		publicURL, _ := cmd.Flags().GetString("public-url")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+
+		if u, err := url.Parse(forward); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("--forward must be an http(s) URL")
+		}
+		if ttl < time.Minute {
+			return fmt.Errorf("--ttl must be at least 1m")
+		}
+		if publicURL == "" {
+			host := listen
+			if strings.HasPrefix(host, ":") {
+				host = "localhost" + host
+			}
+			publicURL = "http://" + host + "/"
+		}
+
+		// Get authentication token
+		token, err := auth.GetToken()
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		// Create API client
+		apiClient := newAPIClient()
+
+		httpClient := &http.Client{Timeout: 30 * time.Second}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "failed to read body", http.StatusBadRequest)
+				return
+			}
+			fmt.Printf("%s  %s %s (%d bytes)  → ", time.Now().Format("15:04:05"), r.Method, webhookEventName(body), len(body))
+			status, err := relayWebhook(httpClient, forward, r, body)
+			if err != nil {
+				fmt.Printf("Failed: %v\n", err)
+				http.Error(w, "forward failed", http.StatusBadGateway)
+				return
+			}
+			fmt.Println(status)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		serverErr := make(chan error, 1)
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
+
+		webhookID, supported, err := registerTemporaryWebhook(apiClient, token, serviceHash, publicURL, ttl)
+		if err != nil {
+			server.Close()
+			return err
+		}
+
+		var cleanup func() error
+		if supported {
+			fmt.Printf("✓ Temporary webhook %s of %s points at %s (expires in %s)\n", webhookID, serviceHash, publicURL, ttl)
+			cleanup = func() error {
+				if _, err := apiClient.DeleteWithAuth(fmt.Sprintf(webhookTemporaryPath+"/%s", serviceHash, webhookID), token); err != nil {
+					return fmt.Errorf("failed to remove temporary webhook %s (it expires by itself): %w", webhookID, err)
+				}
+				fmt.Printf("✓ Temporary webhook %s removed\n", webhookID)
+				return nil
+			}
+		} else {
+			cleanup, err = divertWebhook(cmd, apiClient, token, serviceHash, publicURL)
+			if err != nil {
+				server.Close()
+				return err
+			}
+		}
+		fmt.Printf("Forwarding deliveries to %s (Ctrl+C to stop)\n\n", forward)
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		defer signal.Stop(interrupt)
+
+		var serveErr error
+		select {
+		case <-interrupt:
+			fmt.Println()
+		case serveErr = <-serverErr:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+
+		if err := cleanup(); err != nil {
+			return err
+		}
+		if serveErr != nil {
+			return fmt.Errorf("failed to listen on %s: %w", listen, serveErr)
+		}
+		return nil
+	},
+}
+
+// divertWebhook points a service's own webhook at publicURL, for servers
+// without temporary webhooks, once the user confirmed that live deliveries are
+// diverted. The returned function restores the original URL, unless the
+// webhook was changed in the meantime.
+func divertWebhook(cmd *cobra.Command, apiClient client.APIClient, token, serviceHash, publicURL string) (func() error, error) {
+	servicePath := fmt.Sprintf("/services/%s", serviceHash)
+	service, err := apiClient.GetWithAuth(servicePath, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	var original interface{}
+	if value := stringField(service, "webhook_url"); value != "" {
+		original = value
+	}
+
+	current := "no webhook"
+	if original != nil {
+		current = fmt.Sprintf("%v", original)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: the server cannot register temporary webhooks. Live deliveries of %s will go to %s instead of %s until this command exits.\n", serviceHash, publicURL, current)
+	confirmed, err := confirm(cmd, "Divert the service's webhook?")
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, fmt.Errorf("cancelled")
+	}
+
+	if _, err := apiClient.PutWithAuth(servicePath, map[string]interface{}{"webhook_url": publicURL}, token); err != nil {
+		return nil, fmt.Errorf("failed to set webhook URL: %w", err)
+	}
+	fmt.Printf("✓ Webhook of %s now points at %s\n", serviceHash, publicURL)
+
+	return func() error {
+		// Read the service again: the ETag of the diverting update is stale
+		// if anyone edited the service since, and their webhook must win
+		service, err := apiClient.GetWithAuth(servicePath, token)
+		if err != nil {
+			return fmt.Errorf("failed to restore webhook URL (was %s): %w", current, err)
+		}
+		if now := stringField(service, "webhook_url"); now != publicURL {
+			fmt.Fprintf(os.Stderr, "Warning: the webhook of %s was changed to %s meanwhile; not restoring %s\n", serviceHash, orNone(now), current)
+			return nil
+		}
+		if _, err := apiClient.PutWithAuth(servicePath, map[string]interface{}{"webhook_url": original}, token); err != nil {
+			return fmt.Errorf("failed to restore webhook URL (was %s): %w", current, err)
+		}
+		if original == nil {
+			fmt.Printf("✓ Webhook of %s cleared\n", serviceHash)
+		} else {
+			fmt.Printf("✓ Webhook of %s restored to %s\n", serviceHash, original)
+		}
+		return nil
+	}, nil
+}
+
+func init() {
+	rootCmd.AddCommand(webhookCmd)
+	webhookCmd.AddCommand(webhookListenCmd)
+
+	// Listen command flags
+	webhookListenCmd.Flags().String("forward", "", "Local URL to relay deliveries to (required)")
+	webhookListenCmd.Flags().String("listen", "127.0.0.1:8788", "Address to receive deliveries on")
+	webhookListenCmd.Flags().String("public-url", "", "URL the API delivers to (default: derived from --listen)")
+	webhookListenCmd.Flags().Duration("ttl", time.Hour, "Lifetime of the temporary webhook on the server")
+	webhookListenCmd.Flags().BoolP("force", "f", false, "Divert the service's own webhook without asking when the server has no temporary webhooks")
+	webhookListenCmd.MarkFlagRequired("forward")
+
+	enableExactIdentifierResolver(api.KindService, webhookListenCmd)
+}
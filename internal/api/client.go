@@ -1,179 +1,96 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/certfix/certfix-cli/internal/auth"
 	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/internal/tracing"
+	"github.com/certfix/certfix-cli/pkg/certfix"
 	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/certfix/certfix-cli/pkg/models"
 )
 
 // Client represents an API client
 type Client struct {
-	httpClient *client.HTTPClient
+	httpClient client.APIClient
 }
 
 // NewClient creates a new API client
 func NewClient() *Client {
-	endpoint := config.GetAPIEndpoint()
-	return &Client{
-		httpClient: client.NewHTTPClient(endpoint),
-	}
+	return New(NewHTTPClient())
 }
 
-// CreateInstance creates a new instance
-func (c *Client) CreateInstance(name, instanceType, region string) (*models.Instance, error) {
-	token, err := auth.GetToken()
-	if err != nil {
-		return nil, err
-	}
-
-	payload := map[string]string{
-		"name":   name,
-		"type":   instanceType,
-		"region": region,
-	}
-
-	response, err := c.httpClient.PostWithAuth("/instances", payload, token)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse response into Instance model
-	instance := &models.Instance{
-		ID:     fmt.Sprintf("%v", response["id"]),
-		Name:   name,
-		Status: fmt.Sprintf("%v", response["status"]),
-	}
-
-	return instance, nil
+// NewHTTPClient creates an HTTP client for the configured endpoint, with the
+// configured request timeout, retries and change annotations
+func NewHTTPClient() *client.HTTPClient {
+	return NewProfileHTTPClient(config.GetProfile())
 }
 
-// ListInstances lists all instances
-func (c *Client) ListInstances() ([]*models.Instance, error) {
-	token, err := auth.GetToken()
-	if err != nil {
-		return nil, err
+// NewProfileHTTPClient creates an HTTP client for the endpoint of a profile
+func NewProfileHTTPClient(profile string) *client.HTTPClient {
+	opts := client.Options{
+		Timeout:      config.GetRequestTimeout(),
+		Retries:      config.GetRetryAttempts(),
+		FieldManager: config.GetFieldManager(),
+		Reason:       config.GetChangeReason(),
+		Context:      tracing.Context,
 	}
-
-	response, err := c.httpClient.GetWithAuth("/instances", token)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse response into Instance models
-	instances := []*models.Instance{}
-	if items, ok := response["instances"].([]interface{}); ok {
-		for _, item := range items {
-			if inst, ok := item.(map[string]interface{}); ok {
-				instance := &models.Instance{
-					ID:     fmt.Sprintf("%v", inst["id"]),
-					Name:   fmt.Sprintf("%v", inst["name"]),
-					Status: fmt.Sprintf("%v", inst["status"]),
-				}
-				instances = append(instances, instance)
-			}
-		}
+	if config.TraceRequests() {
+		opts.Trace = os.Stderr
 	}
-
-	return instances, nil
+	opts.SigningKeyID, opts.SigningSecret = config.GetProfileSigningKey(profile)
+	return client.NewHTTPClientWithOptions(config.GetProfileAPIEndpoint(profile), opts)
 }
 
-// ListInstancesByKey lists all instances for a specific key
-func (c *Client) ListInstancesByKey(keyId string) ([]map[string]interface{}, error) {
-	token, err := auth.GetToken()
-	if err != nil {
-		return nil, err
-	}
-
-	// Use the correct endpoint as found in server routes: /keys/:keyId/instances
-	// This endpoint is mounted at root /keys/:keyId/instances in backofficeApp.js
-	// Wait, backofficeApp.js says:
-	// app.use(
-	//     '/keys/:keyId/instances',
-	//     require('./controllers/serviceInstanceController').getInstancesByKey
-	// );
-	// So distinct from /instances or /services
-	// Let's verify the full path. backofficeApp.js is mounted at /api via app.js
-	// So it should be /api/keys/:keyId/instances relative to base URL?
-	// Client base URL usually includes /api or is configured to root.
-	// Looking at other methods: "/instances", "/certificates", etc.
-	// If client config URL ends with /api, then we just need /keys/...
-	response, err := c.httpClient.GetWithAuth(fmt.Sprintf("/keys/%s/instances", keyId), token)
-	if err != nil {
-		return nil, err
-	}
-
-	// The controller returns res.json(instances) which is an array
-	if items, ok := response["_array_data"].([]interface{}); ok {
-		return convertToMapArray(items), nil
-	}
+// New creates an API client that sends its requests through httpClient
+func New(httpClient client.APIClient) *Client {
+	return &Client{httpClient: httpClient}
+}
 
-	// Fallback if it returns wrapped object (though controller suggests direct array)
-	// The HTTPClient wrapper likely handles basic JSON parsing.
-	// If the response is a direct array, existing client might wrap it in _array_data or similar?
-	// Let's look at ListValidCertificates implementation in this file for pattern.
-	// It checks _is_array.
+// sdk returns the SDK client sending requests through c with token, so both
+// share the endpoint definitions of pkg/certfix
+func (c *Client) sdk(token string) *certfix.Client {
+	return certfix.NewWithAPIClient(c.httpClient, certfix.WithToken(token))
+}
 
-	if response["_is_array"] != nil {
-		if arr, ok := response["_array_data"].([]interface{}); ok {
-			return convertToMapArray(arr), nil
-		}
+// sdkError turns an error response returned by the SDK into the error the
+// request methods of c.httpClient return for it
+func sdkError(err error) error {
+	var apiErr *certfix.Error
+	if errors.As(err, &apiErr) {
+		return client.ResponseError(apiErr.StatusCode, apiErr.Body)
 	}
-
-	return []map[string]interface{}{}, nil
+	return err
 }
 
-// DeleteInstance deletes an instance
-func (c *Client) DeleteInstance(id string) error {
-	token, err := auth.GetToken()
-	if err != nil {
-		return err
+// certificateFields returns the certificates as the API returned them
+func certificateFields(certs []certfix.Certificate) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(certs))
+	for _, cert := range certs {
+		result = append(result, cert.Fields)
 	}
-
-	_, err = c.httpClient.DeleteWithAuth(fmt.Sprintf("/instances/%s", id), token)
-	return err
+	return result
 }
 
+// CertificateRequest describes a certificate to issue. Zero values are left
+// to the server's defaults.
+type CertificateRequest = certfix.CertificateCreate
+
 // CreateCertificate creates a new certificate
-func (c *Client) CreateCertificate(commonName, certType, description string, days, keySize int, san, clientId string) (map[string]interface{}, error) {
+func (c *Client) CreateCertificate(req CertificateRequest) (map[string]interface{}, error) {
 	token, err := auth.GetToken()
 	if err != nil {
 		return nil, err
 	}
 
-	// Build payload with required fields
-	payload := map[string]interface{}{
-		"commonName": commonName,
-		"type":       certType,
-	}
-
-	// Add clientId for client certificates
-	if certType == "client" && clientId != "" {
-		payload["clientId"] = clientId
-	}
-
-	// Add optional fields only if provided
-	if description != "" {
-		payload["description"] = description
-	}
-	if days > 0 {
-		payload["days"] = days
-	}
-	if keySize > 0 {
-		payload["keySize"] = keySize
-	}
-	if san != "" {
-		payload["san"] = san
-	}
-
-	response, err := c.httpClient.PostWithAuth("/certificates", payload, token)
+	cert, err := c.sdk(token).Certificates.Create(tracing.Context(), req)
 	if err != nil {
-		return nil, err
+		return nil, sdkError(err)
 	}
-
-	return response, nil
+	return cert.Fields, nil
 }
 
 // ListValidCertificates lists all valid certificates
@@ -183,24 +100,11 @@ func (c *Client) ListValidCertificates() ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
-	response, err := c.httpClient.GetWithAuth("/certificates", token)
+	certs, err := c.sdk(token).Certificates.List(tracing.Context())
 	if err != nil {
-		return nil, err
-	}
-
-	// Check if response is wrapped in a "certificates" key or is a direct array
-	if certs, ok := response["certificates"].([]interface{}); ok {
-		return convertToMapArray(certs), nil
-	}
-
-	// If response has an "array" marker indicating direct array response
-	if response["_is_array"] != nil {
-		if arr, ok := response["_array_data"].([]interface{}); ok {
-			return convertToMapArray(arr), nil
-		}
+		return nil, sdkError(err)
 	}
-
-	return []map[string]interface{}{}, nil
+	return certificateFields(certs), nil
 }
 
 // ListRevokedCertificates lists all revoked certificates
@@ -210,52 +114,25 @@ func (c *Client) ListRevokedCertificates() ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
-	response, err := c.httpClient.GetWithAuth("/certificates/revoked", token)
+	certs, err := c.sdk(token).Certificates.ListRevoked(tracing.Context())
 	if err != nil {
-		return nil, err
-	}
-
-	// Check if response is wrapped in a "certificates" key or is a direct array
-	if certs, ok := response["certificates"].([]interface{}); ok {
-		return convertToMapArray(certs), nil
+		return nil, sdkError(err)
 	}
-
-	// If response has an "array" marker indicating direct array response
-	if response["_is_array"] != nil {
-		if arr, ok := response["_array_data"].([]interface{}); ok {
-			return convertToMapArray(arr), nil
-		}
-	}
-
-	return []map[string]interface{}{}, nil
+	return certificateFields(certs), nil
 }
 
 // ListExpiringCertificates lists certificates expiring in the specified number of days
-func (c *Client) ListExpiringCertificates(days string) ([]map[string]interface{}, error) {
+func (c *Client) ListExpiringCertificates(days int) ([]map[string]interface{}, error) {
 	token, err := auth.GetToken()
 	if err != nil {
 		return nil, err
 	}
 
-	endpoint := fmt.Sprintf("/certificates?expiringInDays=%s", days)
-	response, err := c.httpClient.GetWithAuth(endpoint, token)
+	certs, err := c.sdk(token).Certificates.ListExpiring(tracing.Context(), days)
 	if err != nil {
-		return nil, err
-	}
-
-	// Check if response is wrapped in a "certificates" key or is a direct array
-	if certs, ok := response["certificates"].([]interface{}); ok {
-		return convertToMapArray(certs), nil
+		return nil, sdkError(err)
 	}
-
-	// If response has an "array" marker indicating direct array response
-	if response["_is_array"] != nil {
-		if arr, ok := response["_array_data"].([]interface{}); ok {
-			return convertToMapArray(arr), nil
-		}
-	}
-
-	return []map[string]interface{}{}, nil
+	return certificateFields(certs), nil
 }
 
 // convertToMapArray converts []interface{} to []map[string]interface{}
@@ -375,16 +252,11 @@ func (c *Client) CreateBackup() (map[string]interface{}, error) {
 }
 
 // SyncCertificates synchronizes certificates with the CA
-func (c *Client) SyncCertificates() (map[string]interface{}, error) {
+func (c *Client) SyncCertificates() error {
 	token, err := auth.GetToken()
 	if err != nil {
-		return nil, err
-	}
-
-	response, err := c.httpClient.PostWithAuth("/certificates/sync", nil, token)
-	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return response, nil
+	return sdkError(c.sdk(token).Certificates.Sync(tracing.Context()))
 }
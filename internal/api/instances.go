@@ -0,0 +1,257 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+)
+
+// arrayData returns the items of an array response, or nil when the response
+// is not an array
+func arrayData(response map[string]interface{}) []map[string]interface{} {
+	if arr, ok := response["_array_data"].([]interface{}); ok {
+		return convertToMapArray(arr)
+	}
+	return nil
+}
+
+// ListAllInstances lists every registered instance
+func (c *Client) ListAllInstances() ([]map[string]interface{}, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.GetWithAuth("/instances", token)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrayData(response), nil
+}
+
+// ListServiceInstances lists the instances the API associates with a service
+func (c *Client) ListServiceInstances(serviceHash string) ([]map[string]interface{}, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.GetWithAuth(fmt.Sprintf("/services/%s/instances", serviceHash), token)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrayData(response), nil
+}
+
+// GetInstance gets a single instance
+func (c *Client) GetInstance(id string) (map[string]interface{}, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.httpClient.GetWithAuth(fmt.Sprintf("/instances/%s", id), token)
+}
+
+// RenameInstance sets the display name of an instance. An empty name clears it.
+func (c *Client) RenameInstance(id, name string) (map[string]interface{}, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{"name": name}
+	if name == "" {
+		payload["name"] = nil
+	}
+
+	return c.httpClient.PutWithAuth(fmt.Sprintf("/instances/%s", id), payload, token)
+}
+
+// GetInstanceLogs gets the most recent logs of an instance. A limit of 0 uses
+// the server default.
+func (c *Client) GetInstanceLogs(id string, limit int) ([]map[string]interface{}, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/instances/%s/logs", id)
+	if limit > 0 {
+		endpoint += "?" + url.Values{"limit": {fmt.Sprint(limit)}}.Encode()
+	}
+
+	response, err := c.httpClient.GetWithAuth(endpoint, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrayData(response), nil
+}
+
+// ListInstancesByKey lists all instances for a specific key
+func (c *Client) ListInstancesByKey(keyId string) ([]map[string]interface{}, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.GetWithAuth(fmt.Sprintf("/keys/%s/instances", keyId), token)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrayData(response), nil
+}
+
+// instanceFanOutConcurrency limits parallel requests made when aggregating
+// instances across several keys
+const instanceFanOutConcurrency = 8
+
+// fanOut calls fn for every index in [0, n) with bounded concurrency and
+// returns the first error encountered
+func fanOut(n int, fn func(i int) error) error {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, instanceFanOutConcurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := fn(i); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// listServiceKeys lists the API keys of a service
+func (c *Client) listServiceKeys(serviceHash, token string) ([]map[string]interface{}, error) {
+	response, err := c.httpClient.GetWithAuth(fmt.Sprintf("/services/%s/keys/list", serviceHash), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys for service %s: %w", serviceHash, err)
+	}
+	return arrayData(response), nil
+}
+
+// listInstancesByServices lists the instances of every key of the given
+// services concurrently. Each instance is annotated with the service hash,
+// service name and key ID it was found under; instances reported under more
+// than one key are returned once.
+func (c *Client) listInstancesByServices(services []map[string]interface{}, token string) ([]map[string]interface{}, error) {
+	keysPerService := make([][]map[string]interface{}, len(services))
+	err := fanOut(len(services), func(i int) error {
+		keys, err := c.listServiceKeys(fmt.Sprintf("%v", services[i]["service_hash"]), token)
+		keysPerService[i] = keys
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type serviceKey struct {
+		service map[string]interface{}
+		keyID   string
+	}
+	var keys []serviceKey
+	for i, serviceKeys := range keysPerService {
+		for _, key := range serviceKeys {
+			keys = append(keys, serviceKey{service: services[i], keyID: fmt.Sprintf("%v", key["key_id"])})
+		}
+	}
+
+	instancesPerKey := make([][]map[string]interface{}, len(keys))
+	err = fanOut(len(keys), func(i int) error {
+		instances, err := c.ListInstancesByKey(keys[i].keyID)
+		if err != nil {
+			return fmt.Errorf("failed to list instances for key %s: %w", keys[i].keyID, err)
+		}
+		instancesPerKey[i] = instances
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	seen := make(map[string]bool)
+	for i, instances := range instancesPerKey {
+		for _, instance := range instances {
+			if id, ok := instance["id"]; ok && id != nil {
+				if seen[fmt.Sprintf("%v", id)] {
+					continue
+				}
+				seen[fmt.Sprintf("%v", id)] = true
+			}
+			if instance["service_hash"] == nil {
+				instance["service_hash"] = keys[i].service["service_hash"]
+			}
+			instance["service_name"] = keys[i].service["service_name"]
+			instance["key_id"] = keys[i].keyID
+			result = append(result, instance)
+		}
+	}
+
+	return result, nil
+}
+
+// ListInstancesByService lists the instances registered under any of a
+// service's keys
+func (c *Client) ListInstancesByService(serviceHash string) ([]map[string]interface{}, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := c.httpClient.GetWithAuth(fmt.Sprintf("/services/%s", serviceHash), token)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.listInstancesByServices([]map[string]interface{}{service}, token)
+}
+
+// ListInstancesByServiceGroup lists the instances of every service in a
+// service group
+func (c *Client) ListInstancesByServiceGroup(groupID string) ([]map[string]interface{}, error) {
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.GetWithAuth("/services", token)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []map[string]interface{}
+	for _, svc := range arrayData(response) {
+		if fmt.Sprintf("%v", svc["service_group_id"]) == groupID {
+			services = append(services, svc)
+		}
+	}
+
+	return c.listInstancesByServices(services, token)
+}
+
+// DeleteInstance deletes an instance
+func (c *Client) DeleteInstance(id string) error {
+	token, err := auth.GetToken()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.httpClient.DeleteWithAuth(fmt.Sprintf("/instances/%s", id), token)
+	return err
+}
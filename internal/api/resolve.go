@@ -0,0 +1,245 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/certfix/certfix-cli/internal/auth"
+)
+
+// Kind is a type of resource an identifier can refer to
+type Kind string
+
+const (
+	KindService      Kind = "service"
+	KindPolicy       Kind = "policy"
+	KindEvent        Kind = "event"
+	KindServiceGroup Kind = "service group"
+)
+
+// Ways an identifier can match a resource, in the order they are tried
+const (
+	MatchedByID         = "id"
+	MatchedByExternalID = "external id"
+	MatchedByName       = "name"
+	MatchedByPartial    = "partial name"
+)
+
+// resolvable describes where the resources of a kind are listed and which
+// fields identify them
+type resolvable struct {
+	listPath string
+	// itemPath, for kinds whose IDs have no recognisable shape, is where a
+	// single resource is fetched so an identifier can be tried as its ID
+	// before listing them all
+	itemPath        string
+	idField         string
+	nameField       string
+	externalIDField string
+	// uuidIDs marks kinds whose IDs are UUIDs assigned by the API, so an
+	// identifier shaped like one is taken as an ID without a lookup
+	uuidIDs bool
+}
+
+var resolvables = map[Kind]resolvable{
+	KindService:      {listPath: "/services", itemPath: "/services/%s", idField: "service_hash", nameField: "service_name"},
+	KindPolicy:       {listPath: "/policies", idField: "policy_id", nameField: "name", uuidIDs: true},
+	KindEvent:        {listPath: "/events", idField: "event_id", nameField: "name", externalIDField: "external_id", uuidIDs: true},
+	KindServiceGroup: {listPath: "/service-groups", idField: "service_group_id", nameField: "name", uuidIDs: true},
+}
+
+// uuidPattern matches the IDs the API assigns to service groups, policies and events
+var uuidPattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// LooksLikeID reports whether s has the shape of an API-assigned ID
+func LooksLikeID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// Match is the resource an identifier resolved to
+type Match struct {
+	Kind      Kind
+	ID        string // Service hash for services
+	Name      string
+	MatchedBy string
+}
+
+// NotFoundError is returned when no resource matches an identifier
+type NotFoundError struct {
+	Kind       Kind
+	Identifier string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no %s found matching '%s'", e.Kind, e.Identifier)
+}
+
+// AmbiguousError is returned when several resources match an identifier
+// equally well
+type AmbiguousError struct {
+	Kind       Kind
+	Identifier string
+	Candidates []Match
+}
+
+func (e *AmbiguousError) Error() string {
+	lines := make([]string, 0, len(e.Candidates))
+	for _, candidate := range e.Candidates {
+		lines = append(lines, fmt.Sprintf("  - %s (%s)", candidate.Name, candidate.ID))
+	}
+	idName := "ID"
+	if e.Kind == KindService {
+		idName = "hash"
+	}
+	return fmt.Sprintf("%s '%s' is ambiguous, %d match:\n%s\nUse the %s instead",
+		e.Kind, e.Identifier, len(e.Candidates), strings.Join(lines, "\n"), idName)
+}
+
+// Resolver turns identifiers typed by users (a hash, an ID, an external ID or
+// a name) into the resources they refer to. Each kind is listed at most once
+// per Resolver.
+type Resolver struct {
+	client *Client
+
+	mu    sync.Mutex
+	lists map[Kind][]map[string]interface{}
+}
+
+// NewResolver creates a resolver that looks resources up through c
+func (c *Client) NewResolver() *Resolver {
+	return &Resolver{client: c, lists: make(map[Kind][]map[string]interface{})}
+}
+
+// list returns the resources of a kind, fetching them on first use
+func (r *Resolver) list(kind Kind) ([]map[string]interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if items, ok := r.lists[kind]; ok {
+		return items, nil
+	}
+
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.client.httpClient.GetWithAuth(resolvables[kind].listPath, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", kind, err)
+	}
+	items := arrayData(response)
+	r.lists[kind] = items
+	return items, nil
+}
+
+// lookup fetches the resource whose hash or ID is identifier, for kinds with
+// an itemPath. It returns nil when there is none, or when the kind has already
+// been listed and the list can answer instead.
+func (r *Resolver) lookup(kind Kind, identifier string) *Match {
+	spec := resolvables[kind]
+	if spec.itemPath == "" || identifier == "" || url.PathEscape(identifier) != identifier {
+		return nil
+	}
+	r.mu.Lock()
+	_, listed := r.lists[kind]
+	r.mu.Unlock()
+	if listed {
+		return nil
+	}
+
+	token, err := auth.GetToken()
+	if err != nil {
+		return nil
+	}
+	item, err := r.client.httpClient.GetWithAuth(fmt.Sprintf(spec.itemPath, identifier), token)
+	if err != nil || fmt.Sprintf("%v", item[spec.idField]) != identifier {
+		return nil
+	}
+	name, _ := item[spec.nameField].(string)
+	return &Match{Kind: kind, ID: identifier, Name: name, MatchedBy: MatchedByID}
+}
+
+// Resolve finds the resource of a kind an identifier refers to, trying in
+// order its hash or ID, its external ID, its exact (case-insensitive) name and
+// finally a unique partial name
+func (r *Resolver) Resolve(kind Kind, identifier string) (*Match, error) {
+	return r.resolve(kind, identifier, true)
+}
+
+// ResolveExact is Resolve without partial names, for commands that change or
+// delete the resource
+func (r *Resolver) ResolveExact(kind Kind, identifier string) (*Match, error) {
+	return r.resolve(kind, identifier, false)
+}
+
+func (r *Resolver) resolve(kind Kind, identifier string, partial bool) (*Match, error) {
+	spec, ok := resolvables[kind]
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve %s identifiers", kind)
+	}
+	if spec.uuidIDs && LooksLikeID(identifier) {
+		return &Match{Kind: kind, ID: identifier, MatchedBy: MatchedByID}, nil
+	}
+
+	if match := r.lookup(kind, identifier); match != nil {
+		return match, nil
+	}
+
+	items, err := r.list(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	field := func(item map[string]interface{}, key string) string {
+		if key == "" || item[key] == nil {
+			return ""
+		}
+		return fmt.Sprintf("%v", item[key])
+	}
+	matches := func(by string, pred func(item map[string]interface{}) bool) []Match {
+		var result []Match
+		for _, item := range items {
+			if pred(item) {
+				result = append(result, Match{Kind: kind, ID: field(item, spec.idField), Name: field(item, spec.nameField), MatchedBy: by})
+			}
+		}
+		return result
+	}
+
+	lower := strings.ToLower(identifier)
+	steps := []struct {
+		by   string
+		pred func(item map[string]interface{}) bool
+	}{
+		{MatchedByID, func(item map[string]interface{}) bool { return field(item, spec.idField) == identifier }},
+		{MatchedByExternalID, func(item map[string]interface{}) bool {
+			return spec.externalIDField != "" && field(item, spec.externalIDField) == identifier
+		}},
+		{MatchedByName, func(item map[string]interface{}) bool {
+			return strings.EqualFold(field(item, spec.nameField), identifier)
+		}},
+		{MatchedByPartial, func(item map[string]interface{}) bool {
+			return strings.Contains(strings.ToLower(field(item, spec.nameField)), lower)
+		}},
+	}
+
+	if !partial {
+		steps = steps[:len(steps)-1]
+	}
+
+	for _, step := range steps {
+		found := matches(step.by, step.pred)
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return &found[0], nil
+		default:
+			return nil, &AmbiguousError{Kind: kind, Identifier: identifier, Candidates: found}
+		}
+	}
+	return nil, &NotFoundError{Kind: kind, Identifier: identifier}
+}
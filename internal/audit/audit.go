@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/config"
+)
+
+// Entry represents a single audit log record
+type Entry struct {
+	Time    time.Time         `json:"time"`
+	User    string            `json:"user,omitempty"`
+	Action  string            `json:"action"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// GetLogPath returns the path to the local audit log
+func GetLogPath() string {
+	return filepath.Join(config.GetStateDir(), "audit.log")
+}
+
+// Record appends an entry to the local audit log. Secrets must never be
+// passed in details.
+func Record(action string, details map[string]string) error {
+	entry := Entry{
+		Time:    time.Now().UTC(),
+		Action:  action,
+		Details: details,
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	path := GetLogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
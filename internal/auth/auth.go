@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/internal/tracing"
 	"github.com/certfix/certfix-cli/pkg/client"
 	"github.com/certfix/certfix-cli/pkg/logger"
 	"github.com/golang-jwt/jwt/v5"
@@ -30,7 +31,13 @@ func Login(email, personalToken, endpoint string) (string, error) {
 	log.Debugf("Authenticating with personal token at endpoint: %s", endpoint)
 
 	// Create API client
-	apiClient := client.NewHTTPClient(endpoint)
+	keyID, secret := config.GetProfileSigningKey(config.GetProfile())
+	apiClient := client.NewHTTPClientWithOptions(endpoint, client.Options{
+		Timeout:       config.GetRequestTimeout(),
+		SigningKeyID:  keyID,
+		SigningSecret: secret,
+		Context:       tracing.Context,
+	})
 
 	// Perform CLI auth request
 	payload := map[string]string{
@@ -89,8 +96,13 @@ func StoreToken(token string) error {
 		return fmt.Errorf("failed to marshal token data: %w", err)
 	}
 
-	// Write token to file
-	if err := os.WriteFile(tokenPath, data, 0600); err != nil {
+	// Write token to file, holding the lock against other certfix processes
+	unlock, err := config.Lock(tokenPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := config.WriteFileAtomic(tokenPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write token file: %w", err)
 	}
 
@@ -98,29 +110,61 @@ func StoreToken(token string) error {
 	return nil
 }
 
-// GetToken retrieves the stored authentication token
+// allowExpired makes GetToken accept an expired session, for offline mode
+// where the token is never sent to the API
+var allowExpired bool
+
+// SetAllowExpired controls whether GetToken returns a stored token that has
+// expired
+func SetAllowExpired(allow bool) {
+	allowExpired = allow
+}
+
+// GetToken retrieves the stored authentication token of the profile in use
 func GetToken() (string, error) {
-	tokenPath := getTokenPath()
+	return GetProfileToken(config.GetProfile())
+}
 
-	data, err := os.ReadFile(tokenPath)
+// GetProfileToken retrieves the stored authentication token of a profile
+func GetProfileToken(profile string) (string, error) {
+	tokenData, err := readTokenData(profile)
+	if err != nil {
+		return "", err
+	}
+
+	// Check if token is expired
+	if time.Now().After(tokenData.ExpiresAt) && !allowExpired {
+		return "", fmt.Errorf("token expired: please run 'certfix login%s'", profileFlag(profile))
+	}
+
+	return tokenData.Token, nil
+}
+
+// GetTokenExpiry returns when the stored session of the profile in use
+// expires, whether or not it already has
+func GetTokenExpiry() (time.Time, error) {
+	tokenData, err := readTokenData(config.GetProfile())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tokenData.ExpiresAt, nil
+}
+
+// readTokenData reads the stored token file of a profile
+func readTokenData(profile string) (*TokenData, error) {
+	data, err := os.ReadFile(profileTokenPath(profile))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", fmt.Errorf("not authenticated: please run 'certfix login'")
+			return nil, fmt.Errorf("not authenticated: please run 'certfix login%s'", profileFlag(profile))
 		}
-		return "", fmt.Errorf("failed to read token file: %w", err)
+		return nil, fmt.Errorf("failed to read token file: %w", err)
 	}
 
 	var tokenData TokenData
 	if err := json.Unmarshal(data, &tokenData); err != nil {
-		return "", fmt.Errorf("failed to parse token file: %w", err)
-	}
-
-	// Check if token is expired
-	if time.Now().After(tokenData.ExpiresAt) {
-		return "", fmt.Errorf("token expired: please run 'certfix login'")
+		return nil, fmt.Errorf("failed to parse token file: %w", err)
 	}
-
-	return tokenData.Token, nil
+	return &tokenData, nil
 }
 
 // IsAuthenticated checks if the user is currently authenticated
@@ -133,6 +177,11 @@ func IsAuthenticated() bool {
 func Logout() error {
 	tokenPath := getTokenPath()
 
+	unlock, err := config.Lock(tokenPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	if err := os.Remove(tokenPath); err != nil {
 		if os.IsNotExist(err) {
 			return nil // Already logged out
@@ -143,11 +192,24 @@ func Logout() error {
 	return nil
 }
 
-// getTokenPath returns the path to the token file
+// getTokenPath returns the path to the token file of the profile in use
 func getTokenPath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		homeDir = "."
+	return profileTokenPath(config.GetProfile())
+}
+
+// profileTokenPath returns the path to the token file of a profile. The
+// default profile keeps the original token.json.
+func profileTokenPath(profile string) string {
+	if profile == config.DefaultProfile {
+		return filepath.Join(config.GetStateDir(), "token.json")
+	}
+	return filepath.Join(config.GetStateDir(), "tokens", profile+".json")
+}
+
+// profileFlag returns the --profile flag selecting profile in hints
+func profileFlag(profile string) string {
+	if profile == config.DefaultProfile {
+		return ""
 	}
-	return filepath.Join(homeDir, ".certfix", "token.json")
+	return " --profile " + profile
 }
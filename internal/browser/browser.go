@@ -0,0 +1,42 @@
+package browser
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// candidates lists the commands that open a URL for each platform, in order
+func candidates() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"open"}}
+	case "windows":
+		return [][]string{{"rundll32", "url.dll,FileProtocolHandler"}}
+	default:
+		return [][]string{
+			{"xdg-open"},
+			// WSL
+			{"wslview"},
+		}
+	}
+}
+
+// Open opens url in the user's default browser using the first available
+// opener. It does not wait for the browser to exit.
+func Open(url string) error {
+	for _, args := range candidates() {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, append(args[1:], url)...)
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to open the browser with %s: %w", args[0], err)
+		}
+		go cmd.Wait()
+		return nil
+	}
+	return fmt.Errorf("no browser opener found (install xdg-utils)")
+}
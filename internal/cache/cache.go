@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/certfix/certfix-cli/pkg/client"
+)
+
+// ErrNoCache is returned when no snapshot has been synced yet
+var ErrNoCache = errors.New("no local cache: run 'certfix cache sync' while online")
+
+// Snapshot is a local copy of API responses, keyed by request path, that
+// read commands can be served from without reaching the API
+type Snapshot struct {
+	Endpoint  string                     `json:"endpoint"`
+	SyncedAt  time.Time                  `json:"synced_at"`
+	Counts    map[string]int             `json:"counts,omitempty"`
+	Responses map[string]json.RawMessage `json:"responses"`
+}
+
+// New creates an empty snapshot of endpoint
+func New(endpoint string) *Snapshot {
+	return &Snapshot{
+		Endpoint:  endpoint,
+		Counts:    make(map[string]int),
+		Responses: make(map[string]json.RawMessage),
+	}
+}
+
+// GetPath returns the path to the local cache file
+func GetPath() string {
+	return filepath.Join(config.GetStateDir(), "cache.json")
+}
+
+// Load reads the snapshot from disk, returning ErrNoCache when there is none
+func Load() (*Snapshot, error) {
+	data, err := os.ReadFile(GetPath())
+	if os.IsNotExist(err) {
+		return nil, ErrNoCache
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse cache: %w", err)
+	}
+	if snapshot.Counts == nil {
+		snapshot.Counts = make(map[string]int)
+	}
+	if snapshot.Responses == nil {
+		snapshot.Responses = make(map[string]json.RawMessage)
+	}
+	return &snapshot, nil
+}
+
+// Clear deletes the snapshot from disk. It reports whether there was one.
+func Clear() (bool, error) {
+	err := os.Remove(GetPath())
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to remove cache: %w", err)
+	}
+	return true, nil
+}
+
+// Save writes the snapshot to disk. The file is only readable by the user
+// since it holds the same data the API would return to them.
+func (s *Snapshot) Save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	path := GetPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// Put stores the response body of a GET request to path
+func (s *Snapshot) Put(path string, body []byte) {
+	s.Responses[path] = json.RawMessage(body)
+}
+
+// Lookup returns the stored response body of a GET request to path
+func (s *Snapshot) Lookup(path string) ([]byte, bool) {
+	body, ok := s.Responses[path]
+	return body, ok
+}
+
+// Age returns how long ago the snapshot was synced
+func (s *Snapshot) Age() time.Duration {
+	return time.Since(s.SyncedAt)
+}
+
+// Client is a client.APIClient that serves GET requests from a snapshot and
+// rejects everything else. A Client created with an error fails every request
+// with it.
+type Client struct {
+	snapshot *Snapshot
+	err      error
+
+	// onRead is called before the first request served from the snapshot
+	onRead func(*Snapshot)
+	once   sync.Once
+}
+
+var _ client.APIClient = (*Client)(nil)
+
+// NewClient creates a client serving from snapshot. onRead, when not nil, is
+// called once before the first cached response is returned, so callers can
+// tell users how old the data is.
+func NewClient(snapshot *Snapshot, onRead func(*Snapshot)) *Client {
+	return &Client{snapshot: snapshot, onRead: onRead}
+}
+
+// NewFailingClient creates a client that fails every request with err
+func NewFailingClient(err error) *Client {
+	return &Client{err: err}
+}
+
+// get returns the cached response to a GET request
+func (c *Client) get(endpoint string) (map[string]interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	body, ok := c.snapshot.Responses[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("%s is not in the local cache (offline mode serves the paths 'certfix cache sync' stores)", endpoint)
+	}
+	if c.onRead != nil {
+		c.once.Do(func() { c.onRead(c.snapshot) })
+	}
+	return client.ParseResponse(http.StatusOK, body)
+}
+
+// offline is the error returned for requests that would reach the API
+func (c *Client) offline(method, endpoint string) error {
+	if c.err != nil {
+		return c.err
+	}
+	return fmt.Errorf("cannot %s %s in offline mode: only cached reads are available", method, endpoint)
+}
+
+// Get serves a GET request from the cache
+func (c *Client) Get(endpoint string) (map[string]interface{}, error) {
+	return c.get(endpoint)
+}
+
+// GetWithAuth serves an authenticated GET request from the cache
+func (c *Client) GetWithAuth(endpoint string, token string) (map[string]interface{}, error) {
+	return c.get(endpoint)
+}
+
+// Post fails in offline mode
+func (c *Client) Post(endpoint string, payload interface{}) (map[string]interface{}, error) {
+	return nil, c.offline("POST", endpoint)
+}
+
+// PostWithAuth fails in offline mode
+func (c *Client) PostWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return nil, c.offline("POST", endpoint)
+}
+
+// PutWithAuth fails in offline mode
+func (c *Client) PutWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return nil, c.offline("PUT", endpoint)
+}
+
+// PatchWithAuth fails in offline mode
+func (c *Client) PatchWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return nil, c.offline("PATCH", endpoint)
+}
+
+// DeleteWithAuth fails in offline mode
+func (c *Client) DeleteWithAuth(endpoint string, token string) (map[string]interface{}, error) {
+	return nil, c.offline("DELETE", endpoint)
+}
+
+// DeleteWithAuthAndPayload fails in offline mode
+func (c *Client) DeleteWithAuthAndPayload(endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return nil, c.offline("DELETE", endpoint)
+}
+
+// PostWithHeaders fails in offline mode
+func (c *Client) PostWithHeaders(endpoint string, payload interface{}, headers map[string]string) (map[string]interface{}, error) {
+	return nil, c.offline("POST", endpoint)
+}
+
+// RawWithAuth serves GET requests from the cache and fails for other methods
+func (c *Client) RawWithAuth(method, endpoint string, body []byte, token string) (int, []byte, error) {
+	if method != http.MethodGet {
+		return 0, nil, c.offline(method, endpoint)
+	}
+	if _, err := c.get(endpoint); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, c.snapshot.Responses[endpoint], nil
+}
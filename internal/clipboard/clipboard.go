@@ -0,0 +1,45 @@
+package clipboard
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// candidates lists the clipboard commands to try for each platform, in order
+func candidates() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	default:
+		return [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+			// WSL
+			{"clip.exe"},
+		}
+	}
+}
+
+// Copy places text on the system clipboard using the first available
+// clipboard utility.
+func Copy(text string) error {
+	for _, args := range candidates() {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to copy to clipboard with %s: %w", args[0], err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard utility found (install pbcopy, wl-copy, xclip or xsel)")
+}
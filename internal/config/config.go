@@ -1,34 +1,38 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // InitConfig initializes the configuration
 func InitConfig(cfgFile string) {
+	if message, err := MigrateLegacyDir(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; keeping ~/.certfix\n", err)
+	} else if message != "" {
+		fmt.Fprintln(os.Stderr, message)
+	}
+
 	if cfgFile != "" {
 		// Use config file from the flag
 		viper.SetConfigFile(cfgFile)
 	} else {
-		// Find home directory
-		home, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting home directory: %v\n", err)
-			os.Exit(1)
-		}
-
-		// Create .certfix directory if it doesn't exist
-		configDir := filepath.Join(home, ".certfix")
+		// Create the config directory if it doesn't exist
+		configDir := GetConfigDir()
 		if err := os.MkdirAll(configDir, 0700); err != nil {
 			fmt.Fprintf(os.Stderr, "Error creating config directory: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Search config in home directory with name ".certfix" (without extension)
+		// Search config in the config directory with name "config" (without extension)
 		viper.AddConfigPath(configDir)
 		viper.SetConfigType("yaml")
 		viper.SetConfigName("config")
@@ -39,7 +43,8 @@ func InitConfig(cfgFile string) {
 	// Set defaults
 	viper.SetDefault("endpoint", "https://certfix.io")
 	viper.SetDefault("timeout", 30)
-	viper.SetDefault("retry_attempts", 3)
+	viper.SetDefault("retry_attempts", 0)
+	viper.SetDefault("cache_ttl", "1h")
 
 	// If a config file is found, read it in
 	if err := viper.ReadInConfig(); err == nil {
@@ -47,31 +52,64 @@ func InitConfig(cfgFile string) {
 	}
 }
 
+// runOverrides holds settings given with global flags, such as --timeout. They
+// apply to the current run only and are never written to the config file.
+var runOverrides = map[string]string{}
+
+// SetRunOverrides replaces the settings given for the current run
+func SetRunOverrides(overrides map[string]string) {
+	runOverrides = map[string]string{}
+	for key, value := range overrides {
+		runOverrides[key] = value
+	}
+}
+
+// setting returns key from the run overrides, falling back to the config
+func setting(key string) string {
+	if value, ok := runOverrides[key]; ok {
+		return value
+	}
+	return viper.GetString(key)
+}
+
 // Set sets a configuration value
 func Set(key, value string) error {
-	viper.Set(key, value)
-
 	// Save to config file
 	configFile := viper.ConfigFileUsed()
 	if configFile == "" {
 		// If no config file is in use, create one
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
-		}
-		configFile = filepath.Join(home, ".certfix", "config.yaml")
+		configFile = filepath.Join(GetConfigDir(), "config.yaml")
 	}
 
-	// Create directory if it doesn't exist
-	configDir := filepath.Dir(configFile)
-	if err := os.MkdirAll(configDir, 0700); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+	// Another certfix process may be writing the config too: hold the lock
+	// and re-read the file, so its changes are kept rather than overwritten
+	unlock, err := Lock(configFile)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	if err := viper.WriteConfigAs(configFile); err != nil {
+	// Only the file is written back: defaults, environment variables and
+	// run overrides held by viper must not end up in it
+	file := viper.New()
+	file.SetConfigFile(configFile)
+	if filepath.Ext(configFile) == "" {
+		file.SetConfigType("yaml")
+	}
+	if err := file.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	file.Set(key, value)
+
+	var buf bytes.Buffer
+	if err := file.WriteConfigTo(&buf); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := WriteFileAtomic(configFile, buf.Bytes(), 0600); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
+	viper.Set(key, value)
 	return nil
 }
 
@@ -89,14 +127,144 @@ func List() (map[string]interface{}, error) {
 	return viper.AllSettings(), nil
 }
 
-// GetDefaultEndpoint returns the default API endpoint
+// DefaultProfile is the profile made of the top-level settings of the config
+// file. Other profiles are stored under profiles.<name>.
+const DefaultProfile = "default"
+
+// profileOverride is the profile selected with --profile for this run
+var profileOverride string
+
+// SetProfile selects the profile used for this run instead of the active one.
+// An empty name selects the active profile again.
+func SetProfile(name string) error {
+	name = strings.ToLower(name)
+	if name == "" {
+		profileOverride = ""
+		return nil
+	}
+	if !HasProfile(name) {
+		return fmt.Errorf("profile '%s' not found: add it with 'certfix profile add %s --api-url <url>'", name, name)
+	}
+	profileOverride = name
+	return nil
+}
+
+// GetProfile returns the profile in use: the one given with --profile, else
+// the active profile set with 'certfix profile use'
+func GetProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	if name := strings.ToLower(viper.GetString("profile")); name != "" && HasProfile(name) {
+		return name
+	}
+	return DefaultProfile
+}
+
+// ProfileNames returns the configured profiles, the default one first
+func ProfileNames() []string {
+	var names []string
+	for name := range viper.GetStringMap("profiles") {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...)
+}
+
+// HasProfile reports whether a profile is configured
+func HasProfile(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	_, ok := viper.GetStringMap("profiles")[strings.ToLower(name)]
+	return ok
+}
+
+// GetProfileEndpoint returns the base URL of a profile
+func GetProfileEndpoint(name string) string {
+	if name == DefaultProfile {
+		return viper.GetString("endpoint")
+	}
+	return viper.GetString("profiles." + strings.ToLower(name) + ".endpoint")
+}
+
+// GetProfileSigningKey returns the key ID and secret a profile signs its API
+// requests with. An empty secret means requests are not signed.
+func GetProfileSigningKey(name string) (keyID, secret string) {
+	prefix := signingPrefix(name)
+	keyID = viper.GetString(prefix + "key_id")
+	if data, err := os.ReadFile(signingSecretPath(name)); err == nil {
+		return keyID, string(data)
+	}
+	// Earlier versions kept the secret in the config file
+	return keyID, viper.GetString(prefix + "secret")
+}
+
+// SetProfileSigningKey saves the signing key of a profile; an empty secret
+// turns signing off. The key ID goes to the config file and the secret to the
+// state directory, readable only by the user like the session tokens.
+func SetProfileSigningKey(name, keyID, secret string) error {
+	prefix := signingPrefix(name)
+	if err := Set(prefix+"key_id", keyID); err != nil {
+		return err
+	}
+	if viper.GetString(prefix+"secret") != "" {
+		if err := Set(prefix+"secret", ""); err != nil {
+			return err
+		}
+	}
+
+	path := signingSecretPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create signing directory: %w", err)
+	}
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if secret == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove signing secret: %w", err)
+		}
+		return nil
+	}
+	if err := WriteFileAtomic(path, []byte(secret), 0600); err != nil {
+		return fmt.Errorf("failed to write signing secret: %w", err)
+	}
+	return nil
+}
+
+// signingPrefix returns the prefix of the signing settings of a profile
+func signingPrefix(name string) string {
+	if name == DefaultProfile {
+		return "signing."
+	}
+	return "profiles." + strings.ToLower(name) + ".signing."
+}
+
+// signingSecretPath returns the file holding the signing secret of a profile
+func signingSecretPath(name string) string {
+	return filepath.Join(GetStateDir(), "signing", strings.ToLower(name))
+}
+
+// GetDefaultEndpoint returns the API endpoint of the profile in use
 func GetDefaultEndpoint() string {
-	return viper.GetString("endpoint")
+	return GetProfileEndpoint(GetProfile())
 }
 
 // GetAPIEndpoint returns the API endpoint with /api/v0.0.1 appended
 func GetAPIEndpoint() string {
-	baseURL := viper.GetString("endpoint")
+	return GetProfileAPIEndpoint(GetProfile())
+}
+
+// GetProfileAPIEndpoint returns the API endpoint of a profile with
+// /api/v0.0.1 appended
+func GetProfileAPIEndpoint(name string) string {
+	baseURL := GetProfileEndpoint(name)
 	if baseURL == "" {
 		baseURL = "https://api.certfix.io"
 	}
@@ -112,9 +280,57 @@ func GetTimeout() int {
 	return viper.GetInt("timeout")
 }
 
+// GetRequestTimeout returns the timeout for API requests. The timeout setting
+// is a number of seconds in the config file, or a duration such as "2m" when
+// given with --timeout.
+func GetRequestTimeout() time.Duration {
+	value := setting("timeout")
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return duration
+	}
+	return 0
+}
+
+// GetCacheTTL returns how old the local cache may be for lookups, such as
+// resolving names, to read it instead of the API. Zero disables such reads.
+func GetCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(viper.GetString("cache_ttl"))
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
 // GetRetryAttempts returns the configured retry attempts
 func GetRetryAttempts() int {
-	return viper.GetInt("retry_attempts")
+	attempts, _ := strconv.Atoi(setting("retry_attempts"))
+	return attempts
+}
+
+// GetFieldManager returns the name sent with changing requests to identify who
+// or what made them
+func GetFieldManager() string {
+	return setting("field_manager")
+}
+
+// GetChangeReason returns the reason sent with changing requests
+func GetChangeReason() string {
+	return setting("change_reason")
+}
+
+// TraceRequests reports whether each API request is traced on stderr
+func TraceRequests() bool {
+	trace, _ := strconv.ParseBool(setting("trace"))
+	return trace
+}
+
+// GetCertTemplates returns the certificate templates defined in the config
+// file under cert_templates, by name
+func GetCertTemplates() map[string]interface{} {
+	return viper.GetStringMap("cert_templates")
 }
 
 // GetAPIToken returns the configured API token
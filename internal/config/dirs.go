@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// HomeEnv relocates the directory holding the CLI configuration and state,
+// e.g. to give each CI job on a shared runner its own
+const HomeEnv = "CERTFIX_HOME"
+
+// configFileName is the one file of the legacy directory that belongs in the
+// config directory; everything else there is state
+const configFileName = "config.yaml"
+
+// legacyDir returns ~/.certfix, where every file was kept before the CLI
+// followed the platform's conventions
+func legacyDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".certfix")
+}
+
+// platformDirs returns the config and state directories the platform's
+// conventions call for: $XDG_CONFIG_HOME/certfix and $XDG_STATE_HOME/certfix
+// on Linux and other Unix systems, %APPDATA%\certfix for both on Windows.
+// macOS keeps ~/.certfix.
+func platformDirs() (configDir, stateDir string) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	switch runtime.GOOS {
+	case "windows":
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			appData = filepath.Join(home, "AppData", "Roaming")
+		}
+		return filepath.Join(appData, "certfix"), filepath.Join(appData, "certfix")
+	case "darwin":
+		return legacyDir(), legacyDir()
+	}
+
+	// The XDG spec says relative paths are invalid and must be ignored
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(configHome) {
+		configHome = filepath.Join(home, ".config")
+	}
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if !filepath.IsAbs(stateHome) {
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(configHome, "certfix"), filepath.Join(stateHome, "certfix")
+}
+
+// dirs returns the config and state directories in use: $CERTFIX_HOME for
+// both when it is set, ~/.certfix while it was not migrated, else the
+// platform's directories
+func dirs() (configDir, stateDir string) {
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		return dir, dir
+	}
+	configDir, stateDir = platformDirs()
+	if !exists(configDir) && exists(legacyDir()) {
+		return legacyDir(), legacyDir()
+	}
+	return configDir, stateDir
+}
+
+// GetConfigDir returns the directory holding the CLI configuration
+func GetConfigDir() string {
+	configDir, _ := dirs()
+	return configDir
+}
+
+// GetStateDir returns the directory holding the session tokens, the cache,
+// the history and the other files the CLI keeps between runs
+func GetStateDir() string {
+	_, stateDir := dirs()
+	return stateDir
+}
+
+// MigrateLegacyDir moves the files of ~/.certfix to the platform's config and
+// state directories, when they do not exist yet. It does nothing while a
+// certfix process holds a lock in ~/.certfix. When a move fails, the moved
+// files are put back so ~/.certfix stays in use, and an error is returned.
+// The returned message says what was moved.
+func MigrateLegacyDir() (string, error) {
+	if os.Getenv(HomeEnv) != "" {
+		return "", nil
+	}
+	legacy := legacyDir()
+	configDir, stateDir := platformDirs()
+	if configDir == legacy || !exists(legacy) || exists(configDir) || exists(stateDir) {
+		return "", nil
+	}
+
+	entries, err := os.ReadDir(legacy)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", legacy, err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".lock") {
+			return "", nil
+		}
+	}
+
+	type move struct{ from, to string }
+	var moved []move
+	undo := func() {
+		for i := len(moved) - 1; i >= 0; i-- {
+			os.Rename(moved[i].to, moved[i].from)
+		}
+		os.Remove(stateDir)
+		os.Remove(configDir)
+	}
+
+	for _, dir := range []string{configDir, stateDir} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			undo()
+			return "", fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	for _, entry := range entries {
+		to := filepath.Join(stateDir, entry.Name())
+		if entry.Name() == configFileName {
+			to = filepath.Join(configDir, entry.Name())
+		}
+		from := filepath.Join(legacy, entry.Name())
+		if err := os.Rename(from, to); err != nil {
+			undo()
+			return "", fmt.Errorf("failed to move %s to %s: %w", from, to, err)
+		}
+		moved = append(moved, move{from, to})
+	}
+	os.Remove(legacy)
+
+	if configDir == stateDir {
+		return fmt.Sprintf("Moved %s to %s", legacy, configDir), nil
+	}
+	return fmt.Sprintf("Moved %s to %s (configuration) and %s (sessions, cache and history)", legacy, configDir, stateDir), nil
+}
+
+// exists reports whether path exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
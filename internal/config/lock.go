@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// lockTimeout is how long Lock waits for another process to release a
+	// lock before giving up
+	lockTimeout = 10 * time.Second
+	// staleLockAge is when a lock is considered left behind by a process
+	// that died while holding it. Writes under a lock take milliseconds.
+	staleLockAge = 30 * time.Second
+	// lockPollInterval is how often Lock retries while waiting
+	lockPollInterval = 25 * time.Millisecond
+)
+
+// Lock takes the lock guarding writes to path, a <path>.lock file created
+// exclusively, waiting while another certfix process holds it. Locks older
+// than staleLockAge are taken over. The returned function releases the lock.
+func Lock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintln(f, strconv.Itoa(os.Getpid()))
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			owner := "unknown"
+			if data, err := os.ReadFile(lockPath); err == nil {
+				owner = strings.TrimSpace(string(data))
+			}
+			return nil, fmt.Errorf("timed out waiting for %s (held by pid %s); if no certfix process is running, remove it", lockPath, owner)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// WriteFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never see a partially written file
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
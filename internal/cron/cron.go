@@ -0,0 +1,213 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule represents a parsed five-field cron schedule
+type Schedule struct {
+	minute  uint64
+	hour    uint64
+	day     uint64
+	month   uint64
+	weekday uint64
+
+	dayStar     bool
+	weekdayStar bool
+}
+
+type bounds struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds  = bounds{name: "minute", min: 0, max: 59}
+	hourBounds    = bounds{name: "hour", min: 0, max: 23}
+	dayBounds     = bounds{name: "day", min: 1, max: 31}
+	monthBounds   = bounds{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	weekdayBounds = bounds{name: "weekday", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+// Parse parses the individual cron fields. Empty fields are treated as "*".
+func Parse(minute, hour, day, month, weekday string) (*Schedule, error) {
+	s := &Schedule{}
+	var err error
+
+	if s.minute, _, err = parseField(minute, minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, _, err = parseField(hour, hourBounds); err != nil {
+		return nil, err
+	}
+	if s.day, s.dayStar, err = parseField(day, dayBounds); err != nil {
+		return nil, err
+	}
+	if s.month, _, err = parseField(month, monthBounds); err != nil {
+		return nil, err
+	}
+	if s.weekday, s.weekdayStar, err = parseField(weekday, weekdayBounds); err != nil {
+		return nil, err
+	}
+
+	// 7 is an alias for Sunday
+	if s.weekday&(1<<7) != 0 {
+		s.weekday |= 1
+	}
+
+	return s, nil
+}
+
+// Split splits a standard five-field crontab expression such as "0 3 * * 6"
+// into its minute, hour, day, month and weekday fields. The fields are
+// validated with Parse.
+func Split(expr string) ([]string, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day month weekday), got %d", expr, len(fields))
+	}
+	if _, err := Parse(fields[0], fields[1], fields[2], fields[3], fields[4]); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Next returns the first activation time strictly after t, in t's location.
+// A zero time is returned if the schedule never fires within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+// NextN returns up to n consecutive activation times after t
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	var times []time.Time
+	for i := 0; i < n; i++ {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// dayMatches applies the standard cron rule: when both day-of-month and
+// day-of-week are restricted, a day matches if either field matches.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dayMatch := s.day&(1<<uint(t.Day())) != 0
+	weekdayMatch := s.weekday&(1<<uint(t.Weekday())) != 0
+	if s.dayStar || s.weekdayStar {
+		return dayMatch && weekdayMatch
+	}
+	return dayMatch || weekdayMatch
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into a
+// bitset. The returned bool reports whether the field was an unrestricted "*".
+func parseField(field string, b bounds) (uint64, bool, error) {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		field = "*"
+	}
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return 0, false, fmt.Errorf("invalid %s step in %q", b.name, part)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = b.min, b.max
+		case strings.Contains(rangePart, "-"):
+			ends := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = parseValue(ends[0], b); err != nil {
+				return 0, false, err
+			}
+			if hi, err = parseValue(ends[1], b); err != nil {
+				return 0, false, err
+			}
+			if lo > hi {
+				return 0, false, fmt.Errorf("invalid %s range %q: start is after end", b.name, rangePart)
+			}
+		default:
+			v, err := parseValue(rangePart, b)
+			if err != nil {
+				return 0, false, err
+			}
+			lo, hi = v, v
+			if step > 1 {
+				hi = b.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, field == "*", nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", b.name, value)
+	}
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("%s value %d out of range (%d-%d)", b.name, v, b.min, b.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,89 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/config"
+)
+
+// Entry is one state-changing CLI invocation
+type Entry struct {
+	Time     time.Time         `json:"time"`
+	Command  string            `json:"command"`
+	Args     []string          `json:"args,omitempty"`
+	Flags    map[string]string `json:"flags,omitempty"`
+	Target   string            `json:"target,omitempty"`
+	Result   string            `json:"result"`
+	Error    string            `json:"error,omitempty"`
+	Endpoint string            `json:"endpoint"`
+	Duration string            `json:"duration,omitempty"`
+}
+
+// Results recorded in Entry.Result
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+// GetPath returns the path to the local history file
+func GetPath() string {
+	return filepath.Join(config.GetStateDir(), "history.jsonl")
+}
+
+// Append adds an entry to the history file. Secrets must never be passed in
+// the entry.
+func Append(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	path := GetPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// Load returns all entries, oldest first. A missing file is an empty history;
+// lines that cannot be parsed are skipped.
+func Load() ([]Entry, error) {
+	f, err := os.Open(GetPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
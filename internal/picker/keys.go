@@ -0,0 +1,121 @@
+package picker
+
+import (
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Key names reported for special keys. Printable keys are reported as the
+// character itself.
+const (
+	KeyUp        = "up"
+	KeyDown      = "down"
+	KeyLeft      = "left"
+	KeyRight     = "right"
+	KeyPageUp    = "pgup"
+	KeyPageDown  = "pgdown"
+	KeyEnter     = "enter"
+	KeyEsc       = "esc"
+	KeyTab       = "tab"
+	KeyBackspace = "backspace"
+	KeyCtrlC     = "ctrl-c"
+	KeyCtrlN     = "ctrl-n"
+	KeyCtrlP     = "ctrl-p"
+)
+
+// MakeRaw switches stdin to raw mode and returns a function restoring it
+func MakeRaw() (restore func(), err error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("interactive mode requires a terminal")
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise terminal: %w", err)
+	}
+	return func() { term.Restore(fd, oldState) }, nil
+}
+
+// escapeKeys maps the escape sequences of special keys to their names
+var escapeKeys = map[string]string{
+	"\x1b[A":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1b[C":  KeyRight,
+	"\x1b[D":  KeyLeft,
+	"\x1bOA":  KeyUp,
+	"\x1bOB":  KeyDown,
+	"\x1bOC":  KeyRight,
+	"\x1bOD":  KeyLeft,
+	"\x1b[5~": KeyPageUp,
+	"\x1b[6~": KeyPageDown,
+}
+
+// DecodeKeys splits one read from the terminal into key names. Escape
+// sequences of keys without a name are skipped.
+func DecodeKeys(input []byte) []string {
+	var keys []string
+	for i := 0; i < len(input); {
+		if input[i] == 0x1b {
+			end := escapeEnd(input, i)
+			if end == i+1 {
+				keys = append(keys, KeyEsc)
+			} else if name, ok := escapeKeys[string(input[i:end])]; ok {
+				keys = append(keys, name)
+			}
+			i = end
+			continue
+		}
+
+		switch c := input[i]; {
+		case c == 3:
+			keys = append(keys, KeyCtrlC)
+		case c == 14:
+			keys = append(keys, KeyCtrlN)
+		case c == 16:
+			keys = append(keys, KeyCtrlP)
+		case c == '\r' || c == '\n':
+			keys = append(keys, KeyEnter)
+		case c == '\t':
+			keys = append(keys, KeyTab)
+		case c == 127 || c == 8:
+			keys = append(keys, KeyBackspace)
+		case c >= 32:
+			r, size := utf8.DecodeRune(input[i:])
+			keys = append(keys, string(r))
+			i += size
+			continue
+		}
+		i++
+	}
+	return keys
+}
+
+// escapeEnd returns the end of the escape sequence starting at input[i]: a
+// CSI sequence runs up to its final byte and an SS3 sequence is three bytes.
+// A lone Esc ends right after itself.
+func escapeEnd(input []byte, i int) int {
+	if i+1 >= len(input) {
+		return i + 1
+	}
+	switch input[i+1] {
+	case '[':
+		j := i + 2
+		for j < len(input) && (input[j] < 0x40 || input[j] > 0x7e) {
+			j++
+		}
+		if j < len(input) {
+			j++
+		}
+		return j
+	case 'O':
+		if i+2 < len(input) {
+			return i + 3
+		}
+		return len(input)
+	}
+	return i + 1
+}
@@ -0,0 +1,23 @@
+package picker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	tests := map[string][]string{
+		"\x1b[Aq":          {KeyUp, "q"},
+		"\x1bOB\r":         {KeyDown, KeyEnter},
+		"\x1b":             {KeyEsc},
+		"\x1b[1;5Cab":      {"a", "b"},
+		"\x1b[15~\x1b[6~x": {KeyPageDown, "x"},
+		"\x1bOPé\x7f\x03":  {"é", KeyBackspace, KeyCtrlC},
+		"\x0e\x10\t":       {KeyCtrlN, KeyCtrlP, KeyTab},
+	}
+	for input, want := range tests {
+		if got := DecodeKeys([]byte(input)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: expected %q, got %q", input, want, got)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrCancelled is returned when the user aborts the picker with Esc or Ctrl-C
+var ErrCancelled = errors.New("selection cancelled")
+
+// Item is a single selectable entry
+type Item struct {
+	Label string
+	Value string
+}
+
+const maxVisible = 10
+
+type state struct {
+	items    []Item
+	prompt   string
+	multi    bool
+	query    string
+	matches  []int
+	cursor   int
+	offset   int
+	selected map[int]bool
+	lines    int
+}
+
+// Pick shows an interactive fuzzy-search list on the terminal and returns the
+// values of the chosen items. With multi set, Tab toggles items and Enter
+// confirms the selection (or the highlighted item if nothing is toggled).
+func Pick(items []Item, prompt string, multi bool) ([]string, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("nothing to select")
+	}
+
+	restore, err := MakeRaw()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	s := &state{items: items, prompt: prompt, multi: multi, selected: make(map[int]bool)}
+	s.filter()
+
+	out := os.Stderr
+	defer s.clear(out)
+
+	buf := make([]byte, 64)
+	for {
+		s.render(out)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range DecodeKeys(buf[:n]) {
+			switch key {
+			case KeyCtrlC, KeyEsc:
+				return nil, ErrCancelled
+			case KeyEnter:
+				return s.result(), nil
+			case KeyTab:
+				if s.multi && len(s.matches) > 0 {
+					idx := s.matches[s.cursor]
+					s.selected[idx] = !s.selected[idx]
+					s.move(1)
+				}
+			case KeyBackspace:
+				if len(s.query) > 0 {
+					_, size := utf8.DecodeLastRuneInString(s.query)
+					s.query = s.query[:len(s.query)-size]
+					s.filter()
+				}
+			case KeyCtrlP, KeyUp:
+				s.move(-1)
+			case KeyCtrlN, KeyDown:
+				s.move(1)
+			default:
+				if utf8.RuneCountInString(key) == 1 {
+					s.query += key
+					s.filter()
+				}
+			}
+		}
+	}
+}
+
+func (s *state) result() []string {
+	var values []string
+	for i, item := range s.items {
+		if s.selected[i] {
+			values = append(values, item.Value)
+		}
+	}
+	if len(values) == 0 && len(s.matches) > 0 {
+		values = append(values, s.items[s.matches[s.cursor]].Value)
+	}
+	return values
+}
+
+func (s *state) move(delta int) {
+	if len(s.matches) == 0 {
+		return
+	}
+	s.cursor = (s.cursor + delta + len(s.matches)) % len(s.matches)
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+maxVisible {
+		s.offset = s.cursor - maxVisible + 1
+	}
+}
+
+// filter recomputes the matching items for the current query, best matches first
+func (s *state) filter() {
+	type scored struct {
+		index int
+		score int
+	}
+	var results []scored
+	for i, item := range s.items {
+		if score, ok := fuzzyScore(strings.ToLower(item.Label), strings.ToLower(s.query)); ok {
+			results = append(results, scored{i, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score < results[j].score })
+
+	s.matches = s.matches[:0]
+	for _, r := range results {
+		s.matches = append(s.matches, r.index)
+	}
+	s.cursor, s.offset = 0, 0
+}
+
+// fuzzyScore reports whether all characters of query appear in text in order.
+// Lower scores are better: gaps between matched characters are penalised.
+func fuzzyScore(text, query string) (int, bool) {
+	score, pos, last := 0, 0, -1
+	for _, qc := range query {
+		idx := strings.IndexRune(text[pos:], qc)
+		if idx < 0 {
+			return 0, false
+		}
+		if last >= 0 {
+			score += idx
+		} else {
+			score += pos + idx
+		}
+		last = pos + idx
+		pos = last + len(string(qc))
+	}
+	return score, true
+}
+
+func (s *state) render(out io.Writer) {
+	s.clear(out)
+
+	var b strings.Builder
+	help := "↑/↓ move, Enter select, Esc cancel"
+	if s.multi {
+		help = "↑/↓ move, Tab toggle, Enter confirm, Esc cancel"
+	}
+	fmt.Fprintf(&b, "%s (%d/%d) %s\r\n", s.prompt, len(s.matches), len(s.items), help)
+
+	lines := 1
+	end := s.offset + maxVisible
+	if end > len(s.matches) {
+		end = len(s.matches)
+	}
+	for i := s.offset; i < end; i++ {
+		idx := s.matches[i]
+		pointer := "  "
+		if i == s.cursor {
+			pointer = "> "
+		}
+		mark := ""
+		if s.multi {
+			mark = "[ ] "
+			if s.selected[idx] {
+				mark = "[x] "
+			}
+		}
+		line := pointer + mark + s.items[idx].Label
+		if i == s.cursor {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		fmt.Fprintf(&b, "%s\r\n", line)
+		lines++
+	}
+
+	fmt.Fprintf(&b, "> %s", s.query)
+	fmt.Fprint(out, b.String())
+	s.lines = lines
+}
+
+// clear erases the previously rendered frame
+func (s *state) clear(out io.Writer) {
+	if s.lines > 0 {
+		fmt.Fprintf(out, "\x1b[%dA", s.lines)
+	}
+	fmt.Fprint(out, "\r\x1b[J")
+	s.lines = 0
+}
@@ -0,0 +1,121 @@
+// Package telemetry sends anonymous, opt-in usage reports. Nothing is sent
+// unless the user has run "certfix telemetry on".
+package telemetry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/certfix/certfix-cli/internal/config"
+	"github.com/spf13/viper"
+)
+
+const (
+	// enabledKey and idKey are the configuration keys holding the consent and
+	// the anonymous installation ID
+	enabledKey = "telemetry"
+	idKey      = "telemetry_id"
+
+	// endpointKey overrides where reports are sent
+	endpointKey = "telemetry_endpoint"
+
+	// sendTimeout bounds how long a command may wait on reporting at exit
+	sendTimeout = 2 * time.Second
+)
+
+// Event is one usage report. It never contains arguments, flag values,
+// resource names or anything identifying the user.
+type Event struct {
+	InstallID  string `json:"install_id"`
+	Command    string `json:"command"`
+	DurationMs int64  `json:"duration_ms"`
+	ExitStatus int    `json:"exit_status"`
+	CLIVersion string `json:"cli_version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+}
+
+// DisabledByEnv reports whether the environment forbids telemetry regardless
+// of the configuration (CERTFIX_TELEMETRY=off or DO_NOT_TRACK=1)
+func DisabledByEnv() bool {
+	return os.Getenv("CERTFIX_TELEMETRY") == "off" || os.Getenv("DO_NOT_TRACK") == "1"
+}
+
+// Enabled reports whether the user has opted in
+func Enabled() bool {
+	return !DisabledByEnv() && viper.GetString(enabledKey) == "on"
+}
+
+// Enable records consent and creates the anonymous installation ID
+func Enable() error {
+	if viper.GetString(idKey) == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("failed to generate installation ID: %w", err)
+		}
+		if err := config.Set(idKey, hex.EncodeToString(b)); err != nil {
+			return err
+		}
+	}
+	return config.Set(enabledKey, "on")
+}
+
+// Disable withdraws consent and forgets the installation ID
+func Disable() error {
+	if err := config.Set(idKey, ""); err != nil {
+		return err
+	}
+	return config.Set(enabledKey, "off")
+}
+
+// InstallID returns the anonymous installation ID, empty when disabled
+func InstallID() string {
+	return viper.GetString(idKey)
+}
+
+// Endpoint returns where reports are sent
+func Endpoint() string {
+	if endpoint := viper.GetString(endpointKey); endpoint != "" {
+		return endpoint
+	}
+	return config.GetAPIEndpoint() + "/telemetry"
+}
+
+// NewEvent builds a report for a finished command
+func NewEvent(command string, duration time.Duration, exitStatus int, cliVersion string) Event {
+	return Event{
+		InstallID:  InstallID(),
+		Command:    command,
+		DurationMs: duration.Milliseconds(),
+		ExitStatus: exitStatus,
+		CLIVersion: cliVersion,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// Send reports an event if the user has opted in. Failures are ignored: usage
+// reporting must never affect the command.
+func Send(event Event) {
+	if !Enabled() {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	httpClient := &http.Client{Timeout: sendTimeout}
+	resp, err := httpClient.Post(Endpoint(), "application/json", bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
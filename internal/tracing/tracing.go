@@ -0,0 +1,107 @@
+// Package tracing exports OpenTelemetry spans of commands, API requests and
+// apply runs over OTLP. Nothing is recorded unless OTEL_EXPORTER_OTLP_ENDPOINT
+// or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set; the exporter reads the rest
+// of its settings, such as OTEL_EXPORTER_OTLP_HEADERS, from the environment.
+package tracing
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/certfix/certfix-cli/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of the spans started here
+const instrumentationName = "github.com/certfix/certfix-cli/internal/tracing"
+
+// shutdownTimeout bounds how long a command may wait at exit for its spans to
+// be exported
+const shutdownTimeout = 5 * time.Second
+
+var (
+	mu      sync.Mutex
+	current = context.Background()
+)
+
+// Enabled reports whether the environment configures an OTLP endpoint
+func Enabled() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Start installs an OTLP exporter when Enabled and returns a function that
+// flushes the recorded spans. Failing to set up the exporter never fails the
+// command; spans are then simply not recorded.
+func Start(version string) (shutdown func()) {
+	if !Enabled() {
+		return func() {}
+	}
+
+	exporter, err := otlptracehttp.New(context.Background())
+	if err != nil {
+		logger.GetLogger().Debugf("Failed to create the OTLP exporter: %v", err)
+		return func() {}
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName("certfix-cli"),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		res = resource.Default()
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			logger.GetLogger().Debugf("Failed to export traces: %v", err)
+		}
+	}
+}
+
+// Context returns the context of the span in progress, which API requests
+// are made in so their spans become its children
+func Context() context.Context {
+	mu.Lock()
+	defer mu.Unlock()
+	return current
+}
+
+// Span starts a span as a child of the span in progress and makes it the span
+// in progress until end is called. end records err, if any, as the outcome.
+func Span(name string, attrs ...attribute.KeyValue) (end func(err error)) {
+	mu.Lock()
+	parent := current
+	ctx, span := otel.Tracer(instrumentationName).Start(parent, name, trace.WithAttributes(attrs...))
+	current = ctx
+	mu.Unlock()
+
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+
+		mu.Lock()
+		current = parent
+		mu.Unlock()
+	}
+}
@@ -0,0 +1,143 @@
+// Package tui provides the minimal full-screen terminal handling used by the
+// interactive dashboard: raw keyboard input, an alternate screen buffer and
+// frame drawing.
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/certfix/certfix-cli/internal/picker"
+	"golang.org/x/term"
+)
+
+// Key names reported for special keys, shared with the picker. Printable
+// keys are reported as the character itself.
+const (
+	KeyUp        = picker.KeyUp
+	KeyDown      = picker.KeyDown
+	KeyLeft      = picker.KeyLeft
+	KeyRight     = picker.KeyRight
+	KeyPageUp    = picker.KeyPageUp
+	KeyPageDown  = picker.KeyPageDown
+	KeyEnter     = picker.KeyEnter
+	KeyEsc       = picker.KeyEsc
+	KeyTab       = picker.KeyTab
+	KeyBackspace = picker.KeyBackspace
+	KeyCtrlC     = picker.KeyCtrlC
+)
+
+// Screen is a terminal switched to raw mode and the alternate screen buffer
+type Screen struct {
+	restore func()
+	keys    chan string
+}
+
+// Open takes over the terminal. Close must be called to restore it.
+func Open() (*Screen, error) {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil, fmt.Errorf("interactive mode requires a terminal")
+	}
+
+	restore, err := picker.MakeRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Screen{restore: restore, keys: make(chan string, 16)}
+	// Alternate screen, hidden cursor
+	fmt.Fprint(os.Stdout, "\x1b[?1049h\x1b[?25l")
+	go s.readKeys()
+	return s, nil
+}
+
+// Close restores the terminal to its previous state
+func (s *Screen) Close() {
+	fmt.Fprint(os.Stdout, "\x1b[?25h\x1b[?1049l")
+	s.restore()
+}
+
+// Keys delivers key presses as they are typed
+func (s *Screen) Keys() <-chan string {
+	return s.keys
+}
+
+// Size returns the terminal width and height, falling back to 80x24
+func (s *Screen) Size() (width, height int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// Draw replaces the screen contents with lines, cutting each line to the
+// terminal width and dropping lines that do not fit
+func (s *Screen) Draw(lines []string) {
+	width, height := s.Size()
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		b.WriteString(Truncate(line, width))
+		b.WriteString("\x1b[0m\x1b[K")
+		if i < len(lines)-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	b.WriteString("\x1b[J")
+	fmt.Fprint(os.Stdout, b.String())
+}
+
+// Truncate cuts s to at most width visible characters. ANSI escape sequences
+// are kept and do not count towards the width.
+func Truncate(s string, width int) string {
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			// Copy the escape sequence up to and including its final byte
+			j := i + 1
+			if j < len(s) && s[j] == '[' {
+				j++
+				for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+					j++
+				}
+			}
+			if j < len(s) {
+				j++
+			}
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if visible == width {
+			break
+		}
+		b.WriteRune(r)
+		visible++
+		i += size
+	}
+	return b.String()
+}
+
+// readKeys decodes raw input into key names until stdin is closed
+func (s *Screen) readKeys() {
+	buf := make([]byte, 64)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(s.keys)
+			return
+		}
+		for _, key := range picker.DecodeKeys(buf[:n]) {
+			s.keys <- key
+		}
+	}
+}
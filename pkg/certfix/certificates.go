@@ -0,0 +1,124 @@
+package certfix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Certificate is a certificate issued by the CertFix CA
+type Certificate struct {
+	UniqueID         string    `json:"unique_id"`
+	CommonName       string    `json:"common_name"`
+	Type             string    `json:"certificate_type,omitempty"`
+	Status           string    `json:"status"`
+	SerialNumber     string    `json:"serial_number,omitempty"`
+	SAN              string    `json:"san,omitempty"`
+	ExpiresAt        Timestamp `json:"expires_at,omitempty"`
+	CreatedAt        Timestamp `json:"created_at,omitempty"`
+	RevokedAt        Timestamp `json:"revoked_at,omitempty"`
+	RevocationReason string    `json:"revocation_reason,omitempty"`
+
+	Fields `json:"-"`
+}
+
+// CertificateCreate describes a certificate issued directly by the CA. Type is
+// "server" or "client"; ClientID is only used for client certificates.
+type CertificateCreate struct {
+	CommonName  string `json:"commonName"`
+	Type        string `json:"type"`
+	ClientID    string `json:"clientId,omitempty"`
+	Description string `json:"description,omitempty"`
+	Days        int    `json:"days,omitempty"`
+
+	// KeyType is "rsa", "ecdsa" or "ed25519" (default rsa). KeySize applies
+	// to RSA keys and Curve ("P-256" or "P-384") to ECDSA keys.
+	KeyType string `json:"keyType,omitempty"`
+	KeySize int    `json:"keySize,omitempty"`
+	Curve   string `json:"curve,omitempty"`
+
+	// KeyUsage and ExtKeyUsage list key usages such as "digitalSignature"
+	// and "serverAuth"; empty means the CA's defaults
+	KeyUsage    []string `json:"keyUsage,omitempty"`
+	ExtKeyUsage []string `json:"extKeyUsage,omitempty"`
+
+	SAN string `json:"san,omitempty"`
+}
+
+// CertificatesService manages certificates
+type CertificatesService struct {
+	client *Client
+}
+
+// List returns every valid certificate
+func (s *CertificatesService) List(ctx context.Context) ([]Certificate, error) {
+	return listOf[Certificate](ctx, s.client, "/certificates", "certificates")
+}
+
+// ListRevoked returns every revoked certificate
+func (s *CertificatesService) ListRevoked(ctx context.Context) ([]Certificate, error) {
+	return listOf[Certificate](ctx, s.client, "/certificates/revoked", "certificates")
+}
+
+// ListExpiring returns the certificates that expire within days
+func (s *CertificatesService) ListExpiring(ctx context.Context, days int) ([]Certificate, error) {
+	return listOf[Certificate](ctx, s.client, "/certificates?expiringInDays="+strconv.Itoa(days), "certificates")
+}
+
+// ListByService returns the certificates of a service, including superseded
+// ones when history is true
+func (s *CertificatesService) ListByService(ctx context.Context, serviceHash string, history bool) ([]Certificate, error) {
+	path := fmt.Sprintf("/services/%s/certificates", url.PathEscape(serviceHash))
+	if history {
+		path += "?include_history=true"
+	}
+	return listOf[Certificate](ctx, s.client, path, "certificates")
+}
+
+// Get returns a certificate by unique ID
+func (s *CertificatesService) Get(ctx context.Context, uniqueID string) (*Certificate, error) {
+	var cert Certificate
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/services/certificates/%s/details", url.PathEscape(uniqueID)), nil, &cert); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// Create issues a certificate directly from the CA
+func (s *CertificatesService) Create(ctx context.Context, create CertificateCreate) (*Certificate, error) {
+	if create.Type != "client" {
+		create.ClientID = ""
+	}
+	var cert Certificate
+	if err := s.client.Do(ctx, http.MethodPost, "/certificates", create, &cert); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// Rotate starts the rotation of a service's certificate. The returned map is
+// the rotation job as reported by the API.
+func (s *CertificatesService) Rotate(ctx context.Context, serviceHash string) (map[string]interface{}, error) {
+	job := map[string]interface{}{}
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/services/%s/certificates/rotate", url.PathEscape(serviceHash)), map[string]interface{}{}, &job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Revoke revokes a service certificate. An empty reason uses the server
+// default.
+func (s *CertificatesService) Revoke(ctx context.Context, uniqueID, reason string) error {
+	payload := map[string]interface{}{}
+	if reason != "" {
+		payload["reason"] = reason
+	}
+	return s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/services/certificates/%s/revoke", url.PathEscape(uniqueID)), payload, nil)
+}
+
+// Sync synchronizes the certificate database with the CA
+func (s *CertificatesService) Sync(ctx context.Context) error {
+	return s.client.Do(ctx, http.MethodPost, "/certificates/sync", nil, nil)
+}
@@ -0,0 +1,322 @@
+// Package certfix is a Go client for the CertFix API. It covers the resources
+// managed by the certfix CLI so other programs can automate CertFix without
+// shelling out to it:
+//
+//	c := certfix.New("https://api.certfix.io/api/v0.0.1", certfix.WithToken(token))
+//	services, err := c.Services.List(ctx)
+//
+// Every method takes a context that bounds the request. Requests go through
+// the CLI's HTTP client, so they are retried, signed and traced the same way.
+package certfix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/certfix/certfix-cli/pkg/client"
+)
+
+const (
+	// DefaultTimeout is the request timeout used unless WithTimeout is given
+	DefaultTimeout = client.DefaultTimeout
+
+	defaultUserAgent = "certfix-go"
+)
+
+// Client talks to one CertFix API endpoint
+type Client struct {
+	token string
+	opts  client.Options
+	api   client.APIClient
+
+	Services     *ServicesService
+	Policies     *PoliciesService
+	Events       *EventsService
+	Keys         *KeysService
+	Matrix       *MatrixService
+	Certificates *CertificatesService
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithToken authenticates requests with a bearer token, such as a login
+// session token or a personal access token
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithTimeout sets the timeout of each attempt of a request
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.opts.Timeout = timeout }
+}
+
+// WithRetries sets how many times a request is retried after a network error
+// or a 429, 502, 503 or 504 response
+func WithRetries(retries int) Option {
+	return func(c *Client) { c.opts.Retries = retries }
+}
+
+// WithUserAgent sets the User-Agent header sent with each request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.opts.UserAgent = userAgent }
+}
+
+// WithSigningKey signs every request with the HMAC secret of keyID
+func WithSigningKey(keyID, secret string) Option {
+	return func(c *Client) { c.opts.SigningKeyID, c.opts.SigningSecret = keyID, secret }
+}
+
+// New creates a client for baseURL, the API root including its version prefix
+// (e.g. https://api.certfix.io/api/v0.0.1)
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{opts: client.Options{UserAgent: defaultUserAgent}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.init(client.NewHTTPClientWithOptions(strings.TrimRight(baseURL, "/"), c.opts))
+}
+
+// NewWithAPIClient creates a client sending its requests through api, such as
+// the HTTP client of the CLI or a client.FakeClient in tests. Options other
+// than WithToken are left to api.
+func NewWithAPIClient(api client.APIClient, opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c.init(api)
+}
+
+// init sets the API client and the resource services of c
+func (c *Client) init(api client.APIClient) *Client {
+	c.api = api
+	c.Services = &ServicesService{c}
+	c.Policies = &PoliciesService{c}
+	c.Events = &EventsService{c}
+	c.Keys = &KeysService{c}
+	c.Matrix = &MatrixService{c}
+	c.Certificates = &CertificatesService{c}
+	return c
+}
+
+// Error is returned for responses with a non-2xx status
+type Error struct {
+	StatusCode int
+	Message    string
+
+	// Body is the response body as received
+	Body []byte
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("request failed with status %d", e.StatusCode)
+	}
+	return e.Message
+}
+
+// Unwrap makes a 412 response match client.ErrResourceChanged: the resource
+// changed after it was read by this client
+func (e *Error) Unwrap() error {
+	if e.StatusCode == http.StatusPreconditionFailed {
+		return client.ErrResourceChanged
+	}
+	return nil
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is a 401 or 403 response
+func IsUnauthorized(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// ID is a resource identifier. The API returns some identifiers as numbers and
+// others as strings; ID accepts both.
+type ID string
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*id = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = ID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid identifier %s", data)
+	}
+	*id = ID(n.String())
+	return nil
+}
+
+func (id ID) String() string {
+	return string(id)
+}
+
+// Timestamp is a time returned by the API. Missing, null and unparseable
+// values decode to the zero time.
+type Timestamp struct {
+	time.Time
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil || s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Time = time.Time{}
+		return nil
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Time)
+}
+
+// contextClient is implemented by API clients that can bound a request by a
+// context, as client.HTTPClient does
+type contextClient interface {
+	RawWithContext(ctx context.Context, method, endpoint string, body []byte, token string) (int, []byte, error)
+}
+
+// Do sends a request and decodes a JSON response into out, which may be nil.
+// It is exported for endpoints this package does not wrap yet.
+func (c *Client) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+	}
+
+	var (
+		status   int
+		response []byte
+		err      error
+	)
+	if api, ok := c.api.(contextClient); ok {
+		status, response, err = api.RawWithContext(ctx, method, path, data, c.token)
+	} else if err = ctx.Err(); err == nil {
+		status, response, err = c.api.RawWithAuth(method, path, data, c.token)
+	}
+	if err != nil {
+		return err
+	}
+
+	if status < 200 || status >= 300 {
+		return &Error{StatusCode: status, Message: errorMessage(response), Body: response}
+	}
+
+	response = bytes.TrimSpace(response)
+	if out == nil || len(response) == 0 || (response[0] != '{' && response[0] != '[') {
+		return nil
+	}
+	return decode(response, out)
+}
+
+// Fields holds every field of a resource as the API returned it, including
+// the ones its type does not model
+type Fields map[string]interface{}
+
+func (f *Fields) setFields(fields map[string]interface{}) {
+	*f = fields
+}
+
+// decode unmarshals a JSON value into out, keeping the fields of resources
+// that embed Fields
+func decode(data []byte, out interface{}) error {
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if resource, ok := out.(interface{ setFields(map[string]interface{}) }); ok {
+		var fields map[string]interface{}
+		if json.Unmarshal(data, &fields) == nil {
+			resource.setFields(fields)
+		}
+	}
+	return nil
+}
+
+// errorMessage extracts the message of a standardized error response
+func errorMessage(body []byte) string {
+	var response struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+		Details struct {
+			Message string `json:"message"`
+		} `json:"details"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		return strings.TrimSpace(string(body))
+	}
+	switch {
+	case response.Details.Message != "":
+		return response.Details.Message
+	case response.Message != "":
+		return response.Message
+	default:
+		return response.Error
+	}
+}
+
+// listOf decodes list responses, which the API returns either as a bare array
+// or wrapped in an object under key
+func listOf[T any](ctx context.Context, c *Client, path, key string) ([]T, error) {
+	var raw json.RawMessage
+	if err := c.Do(ctx, http.MethodGet, path, nil, &raw); err != nil {
+		return nil, err
+	}
+
+	var elements []json.RawMessage
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0:
+	case raw[0] == '[':
+		if err := json.Unmarshal(raw, &elements); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+	default:
+		var wrapped map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if inner, ok := wrapped[key]; ok {
+			if err := json.Unmarshal(inner, &elements); err != nil {
+				return nil, fmt.Errorf("failed to parse response: %w", err)
+			}
+		}
+	}
+
+	items := make([]T, len(elements))
+	for i, element := range elements {
+		if err := decode(element, &items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,167 @@
+package certfix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/certfix/certfix-cli/pkg/client"
+)
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("expected the bearer token, got %q", got)
+		}
+		if got := r.Header.Get("User-Agent"); got != "test-agent" {
+			t.Errorf("expected the user agent, got %q", got)
+		}
+		switch r.URL.Path {
+		case "/api/services":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || string(body) != `{"service_name":"payments-api","active":true}` {
+				t.Errorf("unexpected request %s %s", r.Method, body)
+			}
+			w.Write([]byte(`{"service_hash":"a1b2c3","service_name":"payments-api","active":true}`))
+		case "/api/services/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Service not found"}`))
+		case "/api/services/empty":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL+"/api/", WithToken("tok"), WithUserAgent("test-agent"))
+	ctx := context.Background()
+
+	service, err := c.Services.Create(ctx, ServiceCreate{Name: "payments-api", Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.Hash != "a1b2c3" || service.Name != "payments-api" {
+		t.Errorf("unexpected service %+v", service)
+	}
+
+	_, err = c.Services.Get(ctx, "missing")
+	if !IsNotFound(err) || err.Error() != "Service not found" {
+		t.Errorf("expected a not found error with the server message, got %v", err)
+	}
+
+	var out map[string]interface{}
+	if err := c.Do(ctx, http.MethodDelete, "/services/empty", nil, &out); err != nil || out != nil {
+		t.Errorf("expected an empty response to leave out alone, got %v (%v)", out, err)
+	}
+}
+
+func TestListOf(t *testing.T) {
+	bodies := map[string]string{
+		"/bare":    `[{"service_hash":"a1b2c3"},{"service_hash":"d4e5f6"}]`,
+		"/wrapped": `{"services":[{"service_hash":"a1b2c3"},{"service_hash":"d4e5f6"}],"total":2}`,
+		"/other":   `{"total":0}`,
+		"/empty":   ``,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(bodies[r.URL.Path]))
+	}))
+	defer server.Close()
+	c := New(server.URL)
+
+	for path, want := range map[string]int{"/bare": 2, "/wrapped": 2, "/other": 0, "/empty": 0} {
+		services, err := listOf[Service](context.Background(), c, path, "services")
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if services == nil || len(services) != want {
+			t.Errorf("%s: expected %d services, got %+v", path, want, services)
+		}
+		if want > 0 && services[1].Hash != "d4e5f6" {
+			t.Errorf("%s: unexpected services %+v", path, services)
+		}
+	}
+}
+
+func TestDecoding(t *testing.T) {
+	var decoded struct {
+		Numeric ID        `json:"numeric"`
+		Text    ID        `json:"text"`
+		Null    ID        `json:"null"`
+		Created Timestamp `json:"created"`
+		Invalid Timestamp `json:"invalid"`
+		Missing Timestamp `json:"missing"`
+	}
+	data := `{"numeric":42,"text":"0b7e3c1a","null":null,"created":"2026-01-15T10:30:00Z","invalid":"yesterday"}`
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Numeric != "42" || decoded.Text != "0b7e3c1a" || decoded.Null != "" {
+		t.Errorf("unexpected IDs %+v", decoded)
+	}
+	if !decoded.Created.Equal(time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected timestamp %v", decoded.Created)
+	}
+	if !decoded.Invalid.IsZero() || !decoded.Missing.IsZero() {
+		t.Errorf("expected invalid and missing timestamps to be zero, got %v and %v", decoded.Invalid, decoded.Missing)
+	}
+
+	var id ID
+	if err := json.Unmarshal([]byte(`{}`), &id); err == nil {
+		t.Errorf("expected an object to be refused as an ID, got %q", id)
+	}
+}
+
+func TestTimeoutOption(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithTimeout(20*time.Millisecond))
+	if _, err := c.Services.List(context.Background()); err == nil {
+		t.Errorf("expected the request to time out")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := New(server.URL).Services.List(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to be bounded by its context, got %v", err)
+	}
+}
+
+func TestAPIClient(t *testing.T) {
+	fake := client.NewFakeClient()
+	fake.HandleJSON("GET", "/certificates", map[string]interface{}{
+		"certificates": []map[string]interface{}{{"unique_id": "c1", "common_name": "api.example.com", "issuer": "CertFix CA"}},
+	})
+	fake.Handle("PUT", "/services/a1b2c3", http.StatusPreconditionFailed, `{"message":"Service changed"}`)
+	c := NewWithAPIClient(fake, WithToken("tok"))
+
+	certs, err := c.Certificates.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 1 || certs[0].UniqueID != "c1" || certs[0].Fields["issuer"] != "CertFix CA" {
+		t.Errorf("expected the certificate with all its fields, got %+v", certs)
+	}
+	if requests := fake.Requests(); requests[0].Token != "tok" {
+		t.Errorf("expected the token to be sent, got %+v", requests)
+	}
+
+	active := false
+	_, err = c.Services.Update(context.Background(), "a1b2c3", ServiceUpdate{Active: &active})
+	if !errors.Is(err, client.ErrResourceChanged) || err.Error() != "Service changed" {
+		t.Errorf("expected a 412 to match ErrResourceChanged, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Services.List(ctx); !errors.Is(err, context.Canceled) || len(fake.Requests()) != 2 {
+		t.Errorf("expected a cancelled context to stop the request, got %v", err)
+	}
+}
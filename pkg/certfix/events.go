@@ -0,0 +1,97 @@
+package certfix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Event is a named signal that services report and event policies count
+type Event struct {
+	ID             ID        `json:"event_id"`
+	ExternalID     string    `json:"external_id,omitempty"`
+	Name           string    `json:"name"`
+	Severity       string    `json:"severity"`
+	Enabled        bool      `json:"enabled"`
+	Counter        int       `json:"counter"`
+	ResetTimeUnit  string    `json:"reset_time_unit,omitempty"`
+	ResetTimeValue int       `json:"reset_time_value,omitempty"`
+	LastEventAt    Timestamp `json:"last_event_at,omitempty"`
+	CreatedAt      Timestamp `json:"created_at,omitempty"`
+	UpdatedAt      Timestamp `json:"updated_at,omitempty"`
+
+	Fields `json:"-"`
+}
+
+// EventCreate describes a new event. Severity is one of low, medium, high and
+// critical.
+type EventCreate struct {
+	ExternalID     string `json:"external_id,omitempty"`
+	Name           string `json:"name"`
+	Severity       string `json:"severity"`
+	Enabled        bool   `json:"enabled"`
+	ResetTimeUnit  string `json:"reset_time_unit,omitempty"`
+	ResetTimeValue int    `json:"reset_time_value,omitempty"`
+}
+
+// EventUpdate changes an event. Nil fields are left unchanged.
+type EventUpdate struct {
+	Name           *string `json:"name,omitempty"`
+	Severity       *string `json:"severity,omitempty"`
+	Enabled        *bool   `json:"enabled,omitempty"`
+	ResetTimeUnit  *string `json:"reset_time_unit,omitempty"`
+	ResetTimeValue *int    `json:"reset_time_value,omitempty"`
+}
+
+// EventsService manages events
+type EventsService struct {
+	client *Client
+}
+
+// List returns every event
+func (s *EventsService) List(ctx context.Context) ([]Event, error) {
+	return listOf[Event](ctx, s.client, "/events", "events")
+}
+
+// Get returns an event by ID
+func (s *EventsService) Get(ctx context.Context, id string) (*Event, error) {
+	var event Event
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/events/%s", url.PathEscape(id)), nil, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Create creates an event
+func (s *EventsService) Create(ctx context.Context, create EventCreate) (*Event, error) {
+	var event Event
+	if err := s.client.Do(ctx, http.MethodPost, "/events", create, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Update changes an event
+func (s *EventsService) Update(ctx context.Context, id string, update EventUpdate) (*Event, error) {
+	var event Event
+	if err := s.client.Do(ctx, http.MethodPut, fmt.Sprintf("/events/%s", url.PathEscape(id)), update, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// SetEnabled enables or disables an event
+func (s *EventsService) SetEnabled(ctx context.Context, id string, enabled bool) (*Event, error) {
+	return s.Update(ctx, id, EventUpdate{Enabled: &enabled})
+}
+
+// ResetCounter sets the occurrence counter of an event back to zero
+func (s *EventsService) ResetCounter(ctx context.Context, id string) error {
+	return s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/events/%s/reset-counter", url.PathEscape(id)), nil, nil)
+}
+
+// Delete deletes an event
+func (s *EventsService) Delete(ctx context.Context, id string) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/events/%s", url.PathEscape(id)), nil, nil)
+}
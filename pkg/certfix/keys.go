@@ -0,0 +1,70 @@
+package certfix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Key is an API key a service's instances use to fetch certificates
+type Key struct {
+	ID         ID        `json:"key_id"`
+	Name       string    `json:"key_name"`
+	APIKey     string    `json:"api_key,omitempty"`
+	Enabled    bool      `json:"enabled"`
+	UsageCount int       `json:"usage_count"`
+	LastUsedAt Timestamp `json:"last_used_at,omitempty"`
+	ExpiresAt  Timestamp `json:"expires_at,omitempty"`
+	CreatedAt  Timestamp `json:"created_at,omitempty"`
+
+	Fields `json:"-"`
+}
+
+// KeyCreate describes a new key. The API takes the expiry as a number of
+// days; NoExpiryDays stands in for a key that never expires.
+type KeyCreate struct {
+	Name           string `json:"key_name"`
+	Enabled        bool   `json:"enabled"`
+	ExpirationDays int    `json:"expiration_days"`
+}
+
+// NoExpiryDays is the expiry the certfix CLI gives keys created with
+// --no-expiry, since the API cannot create a key that never expires
+const NoExpiryDays = 36500
+
+// KeysService manages service API keys
+type KeysService struct {
+	client *Client
+}
+
+// List returns the keys of a service
+func (s *KeysService) List(ctx context.Context, serviceHash string) ([]Key, error) {
+	return listOf[Key](ctx, s.client, fmt.Sprintf("/services/%s/keys/list", url.PathEscape(serviceHash)), "keys")
+}
+
+// Create adds a key to a service. The returned key carries the secret, which
+// the API does not show again.
+func (s *KeysService) Create(ctx context.Context, serviceHash string, create KeyCreate) (*Key, error) {
+	var key Key
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/services/%s/keys", url.PathEscape(serviceHash)), create, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Toggle enables a disabled key or disables an enabled one
+func (s *KeysService) Toggle(ctx context.Context, serviceHash, keyID string) (*Key, error) {
+	var key Key
+	path := fmt.Sprintf("/services/%s/keys/%s/toggle", url.PathEscape(serviceHash), url.PathEscape(keyID))
+	if err := s.client.Do(ctx, http.MethodPut, path, nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Delete removes a key from a service
+func (s *KeysService) Delete(ctx context.Context, serviceHash, keyID string) error {
+	path := fmt.Sprintf("/services/%s/keys/%s", url.PathEscape(serviceHash), url.PathEscape(keyID))
+	return s.client.Do(ctx, http.MethodDelete, path, nil, nil)
+}
@@ -0,0 +1,74 @@
+package certfix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Relation directions accepted by the API
+const (
+	DirectionOutbound      = "outbound"
+	DirectionInbound       = "inbound"
+	DirectionBidirectional = "bidirectional"
+)
+
+// Relation is an edge of the service matrix: a service that talks to another
+type Relation struct {
+	ID                 ID        `json:"relation_id"`
+	SourceServiceHash  string    `json:"source_service_hash"`
+	SourceServiceName  string    `json:"source_service_name,omitempty"`
+	RelatedServiceHash string    `json:"related_service_hash"`
+	RelatedServiceName string    `json:"related_service_name,omitempty"`
+	Type               string    `json:"relation_type,omitempty"`
+	Description        string    `json:"description,omitempty"`
+	Direction          string    `json:"direction,omitempty"`
+	Enabled            bool      `json:"enabled"`
+	CreatedAt          Timestamp `json:"created_at,omitempty"`
+
+	Fields `json:"-"`
+}
+
+// RelationCreate describes a new relation from a service to RelatedServiceHash
+type RelationCreate struct {
+	RelatedServiceHash string `json:"related_service_hash"`
+	Type               string `json:"relation_type,omitempty"`
+	Description        string `json:"description,omitempty"`
+	Direction          string `json:"direction,omitempty"`
+}
+
+// MatrixService manages the relations between services
+type MatrixService struct {
+	client *Client
+}
+
+// List returns the relations of a service
+func (s *MatrixService) List(ctx context.Context, serviceHash string) ([]Relation, error) {
+	return listOf[Relation](ctx, s.client, fmt.Sprintf("/services/%s/matrix/relations", url.PathEscape(serviceHash)), "relations")
+}
+
+// Create adds a relation from serviceHash to another service
+func (s *MatrixService) Create(ctx context.Context, serviceHash string, create RelationCreate) (*Relation, error) {
+	var relation Relation
+	if err := s.client.Do(ctx, http.MethodPost, fmt.Sprintf("/services/%s/matrix", url.PathEscape(serviceHash)), create, &relation); err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// Toggle enables a disabled relation or disables an enabled one
+func (s *MatrixService) Toggle(ctx context.Context, serviceHash, relationID string) (*Relation, error) {
+	var relation Relation
+	path := fmt.Sprintf("/services/%s/matrix/relations/%s/toggle", url.PathEscape(serviceHash), url.PathEscape(relationID))
+	if err := s.client.Do(ctx, http.MethodPut, path, nil, &relation); err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// Delete removes a relation
+func (s *MatrixService) Delete(ctx context.Context, serviceHash, relationID string) error {
+	path := fmt.Sprintf("/services/%s/matrix/relations/%s", url.PathEscape(serviceHash), url.PathEscape(relationID))
+	return s.client.Do(ctx, http.MethodDelete, path, nil, nil)
+}
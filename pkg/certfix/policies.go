@@ -0,0 +1,119 @@
+package certfix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Policy strategies accepted by the API
+const (
+	StrategyEvents            = "events"
+	StrategyGradual           = "gradual"
+	StrategyMaintenanceWindow = "maintenance_window"
+)
+
+// CronConfig is the schedule of a policy, one crontab field each
+type CronConfig struct {
+	Minute  string `json:"minute"`
+	Hour    string `json:"hour"`
+	Day     string `json:"day"`
+	Month   string `json:"month"`
+	Weekday string `json:"weekday"`
+}
+
+// EventConfig triggers a rotation after an event occurred TotalEvents times
+type EventConfig struct {
+	EventID     ID  `json:"event_id"`
+	TotalEvents int `json:"total_events"`
+}
+
+// GradualConfig rotates services in batches, waiting IntervalSeconds between
+// batches. Zero values use the server defaults.
+type GradualConfig struct {
+	BatchSize       int `json:"batch_size,omitempty"`
+	IntervalSeconds int `json:"interval_seconds,omitempty"`
+}
+
+// Policy decides when the certificates of its services are rotated
+type Policy struct {
+	ID            ID             `json:"policy_id"`
+	Name          string         `json:"name"`
+	Strategy      string         `json:"strategy"`
+	Enabled       bool           `json:"enabled"`
+	CronConfig    *CronConfig    `json:"cron_config,omitempty"`
+	EventConfig   *EventConfig   `json:"event_config,omitempty"`
+	GradualConfig *GradualConfig `json:"gradual_config,omitempty"`
+	CreatedAt     Timestamp      `json:"created_at,omitempty"`
+	UpdatedAt     Timestamp      `json:"updated_at,omitempty"`
+
+	Fields `json:"-"`
+}
+
+// PolicyCreate describes a new policy
+type PolicyCreate struct {
+	Name          string         `json:"name"`
+	Strategy      string         `json:"strategy"`
+	Enabled       bool           `json:"enabled"`
+	CronConfig    *CronConfig    `json:"cron_config,omitempty"`
+	EventConfig   *EventConfig   `json:"event_config,omitempty"`
+	GradualConfig *GradualConfig `json:"gradual_config,omitempty"`
+}
+
+// PolicyUpdate changes a policy. Nil fields are left unchanged.
+type PolicyUpdate struct {
+	Name          *string        `json:"name,omitempty"`
+	Strategy      *string        `json:"strategy,omitempty"`
+	Enabled       *bool          `json:"enabled,omitempty"`
+	CronConfig    *CronConfig    `json:"cron_config,omitempty"`
+	EventConfig   *EventConfig   `json:"event_config,omitempty"`
+	GradualConfig *GradualConfig `json:"gradual_config,omitempty"`
+}
+
+// PoliciesService manages rotation policies
+type PoliciesService struct {
+	client *Client
+}
+
+// List returns every policy
+func (s *PoliciesService) List(ctx context.Context) ([]Policy, error) {
+	return listOf[Policy](ctx, s.client, "/policies", "policies")
+}
+
+// Get returns a policy by ID
+func (s *PoliciesService) Get(ctx context.Context, id string) (*Policy, error) {
+	var policy Policy
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/policies/%s", url.PathEscape(id)), nil, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Create creates a policy
+func (s *PoliciesService) Create(ctx context.Context, create PolicyCreate) (*Policy, error) {
+	var policy Policy
+	if err := s.client.Do(ctx, http.MethodPost, "/policies", create, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Update changes a policy
+func (s *PoliciesService) Update(ctx context.Context, id string, update PolicyUpdate) (*Policy, error) {
+	var policy Policy
+	if err := s.client.Do(ctx, http.MethodPut, fmt.Sprintf("/policies/%s", url.PathEscape(id)), update, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SetEnabled enables or disables a policy
+func (s *PoliciesService) SetEnabled(ctx context.Context, id string, enabled bool) (*Policy, error) {
+	return s.Update(ctx, id, PolicyUpdate{Enabled: &enabled})
+}
+
+// Delete deletes a policy
+func (s *PoliciesService) Delete(ctx context.Context, id string) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/policies/%s", url.PathEscape(id)), nil, nil)
+}
@@ -0,0 +1,133 @@
+package certfix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Service is a service that receives certificates
+type Service struct {
+	Hash             string    `json:"service_hash"`
+	Name             string    `json:"service_name"`
+	Active           bool      `json:"active"`
+	WebhookURL       string    `json:"webhook_url,omitempty"`
+	ReloadService    string    `json:"reload_service,omitempty"`
+	DNSNames         []string  `json:"dns_names,omitempty"`
+	ServiceGroupID   ID        `json:"service_group_id,omitempty"`
+	ServiceGroupName string    `json:"service_group_name,omitempty"`
+	PolicyID         ID        `json:"policy_id,omitempty"`
+	PolicyName       string    `json:"policy_name,omitempty"`
+	CreatedAt        Timestamp `json:"created_at,omitempty"`
+	UpdatedAt        Timestamp `json:"updated_at,omitempty"`
+
+	Fields `json:"-"`
+}
+
+// ServiceCreate describes a new service. An empty Hash lets the server
+// generate one.
+type ServiceCreate struct {
+	Hash           string   `json:"service_hash,omitempty"`
+	Name           string   `json:"service_name"`
+	Active         bool     `json:"active"`
+	WebhookURL     string   `json:"webhook_url,omitempty"`
+	ReloadService  string   `json:"reload_service,omitempty"`
+	DNSNames       []string `json:"dns_names,omitempty"`
+	ServiceGroupID string   `json:"service_group_id,omitempty"`
+	PolicyID       string   `json:"policy_id,omitempty"`
+}
+
+// ServiceUpdate changes a service. Nil fields are left unchanged; pointers to
+// empty strings clear optional fields.
+type ServiceUpdate struct {
+	Name           *string
+	Active         *bool
+	WebhookURL     *string
+	ReloadService  *string
+	DNSNames       *[]string
+	ServiceGroupID *string
+	PolicyID       *string
+}
+
+// payload converts the update to a request body, sending JSON null for
+// optional fields set to an empty string
+func (u ServiceUpdate) payload() map[string]interface{} {
+	payload := map[string]interface{}{}
+	if u.Name != nil {
+		payload["service_name"] = *u.Name
+	}
+	if u.Active != nil {
+		payload["active"] = *u.Active
+	}
+	if u.DNSNames != nil {
+		payload["dns_names"] = *u.DNSNames
+	}
+	for field, value := range map[string]*string{
+		"webhook_url":      u.WebhookURL,
+		"reload_service":   u.ReloadService,
+		"service_group_id": u.ServiceGroupID,
+		"policy_id":        u.PolicyID,
+	} {
+		switch {
+		case value == nil:
+		case *value == "":
+			payload[field] = nil
+		default:
+			payload[field] = *value
+		}
+	}
+	return payload
+}
+
+// ServicesService manages services
+type ServicesService struct {
+	client *Client
+}
+
+// List returns every service
+func (s *ServicesService) List(ctx context.Context) ([]Service, error) {
+	return listOf[Service](ctx, s.client, "/services", "services")
+}
+
+// FindByName returns the services whose name matches name
+func (s *ServicesService) FindByName(ctx context.Context, name string) ([]Service, error) {
+	return listOf[Service](ctx, s.client, "/services?name="+url.QueryEscape(name), "services")
+}
+
+// Get returns a service by hash
+func (s *ServicesService) Get(ctx context.Context, hash string) (*Service, error) {
+	var service Service
+	if err := s.client.Do(ctx, http.MethodGet, fmt.Sprintf("/services/%s", url.PathEscape(hash)), nil, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// Create creates a service
+func (s *ServicesService) Create(ctx context.Context, create ServiceCreate) (*Service, error) {
+	var service Service
+	if err := s.client.Do(ctx, http.MethodPost, "/services", create, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// Update changes a service
+func (s *ServicesService) Update(ctx context.Context, hash string, update ServiceUpdate) (*Service, error) {
+	var service Service
+	if err := s.client.Do(ctx, http.MethodPut, fmt.Sprintf("/services/%s", url.PathEscape(hash)), update.payload(), &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// SetActive activates or deactivates a service
+func (s *ServicesService) SetActive(ctx context.Context, hash string, active bool) (*Service, error) {
+	return s.Update(ctx, hash, ServiceUpdate{Active: &active})
+}
+
+// Delete deletes a service
+func (s *ServicesService) Delete(ctx context.Context, hash string) error {
+	return s.client.Do(ctx, http.MethodDelete, fmt.Sprintf("/services/%s", url.PathEscape(hash)), nil, nil)
+}
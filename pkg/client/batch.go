@@ -0,0 +1,165 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// BatchEndpoint is where servers that support batches accept them
+const BatchEndpoint = "/batch"
+
+// MaxBatchSize is the most operations sent in one batch request; longer lists
+// are split
+const MaxBatchSize = 100
+
+// ErrSkipped is the error of operations not attempted because an earlier
+// operation of the batch failed
+var ErrSkipped = errors.New("skipped after an earlier operation failed")
+
+// Operation is one request of a batch
+type Operation struct {
+	Method   string      `json:"method"`
+	Endpoint string      `json:"path"`
+	Payload  interface{} `json:"body,omitempty"`
+}
+
+// Result is the outcome of an Operation: the parsed response, as the request
+// methods of an APIClient return it, or an error
+type Result struct {
+	Response map[string]interface{}
+	Err      error
+}
+
+// batchResult is one entry of the "results" of a batch response
+type batchResult struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// Batcher sends operations through a client, grouped into one request per
+// MaxBatchSize operations when the server has a batch endpoint. Servers
+// without one get the operations one at a time; the Batcher remembers that
+// and does not ask again.
+type Batcher struct {
+	client      APIClient
+	token       string
+	unsupported bool
+}
+
+// NewBatcher creates a Batcher that authenticates with token
+func NewBatcher(c APIClient, token string) *Batcher {
+	return &Batcher{client: c, token: token}
+}
+
+// Do runs the operations in order and returns a result for each. Operations
+// after the first failure are not attempted and fail with ErrSkipped.
+func (b *Batcher) Do(ops []Operation) []Result {
+	results := make([]Result, len(ops))
+	for start := 0; start < len(ops); start += MaxBatchSize {
+		end := min(start+MaxBatchSize, len(ops))
+		b.run(ops[start:end], results[start:end])
+		for i := start; i < end; i++ {
+			if results[i].Err != nil {
+				for j := end; j < len(ops); j++ {
+					results[j].Err = ErrSkipped
+				}
+				return results
+			}
+		}
+	}
+	return results
+}
+
+// run fills results for one chunk of operations
+func (b *Batcher) run(ops []Operation, results []Result) {
+	if len(ops) > 1 && !b.unsupported {
+		if b.batch(ops, results) {
+			return
+		}
+		b.unsupported = true
+	}
+	b.sequential(ops, results)
+}
+
+// batch sends ops as one request. It returns false, without filling results,
+// when the server does not support batches.
+func (b *Batcher) batch(ops []Operation, results []Result) bool {
+	body, err := json.Marshal(map[string]interface{}{
+		"operations":    ops,
+		"stop_on_error": true,
+	})
+	if err != nil {
+		for i := range results {
+			results[i].Err = fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		return true
+	}
+
+	status, responseBody, err := b.client.RawWithAuth(http.MethodPost, BatchEndpoint, body, b.token)
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return true
+	}
+	switch status {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return false
+	}
+	if _, err := parseResponse(status, responseBody, false); err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return true
+	}
+
+	var response struct {
+		Results []batchResult `json:"results"`
+	}
+	if err := json.Unmarshal(responseBody, &response); err != nil || len(response.Results) > len(ops) {
+		for i := range results {
+			results[i].Err = fmt.Errorf("invalid batch response")
+		}
+		return true
+	}
+	for i := range results {
+		if i >= len(response.Results) {
+			results[i].Err = ErrSkipped
+			continue
+		}
+		r := response.Results[i]
+		results[i].Response, results[i].Err = parseResponse(r.Status, r.Body, false)
+	}
+	return true
+}
+
+// sequential sends ops one at a time, stopping at the first failure
+func (b *Batcher) sequential(ops []Operation, results []Result) {
+	for i, op := range ops {
+		var body []byte
+		if op.Payload != nil {
+			data, err := json.Marshal(op.Payload)
+			if err != nil {
+				results[i].Err = fmt.Errorf("failed to marshal payload: %w", err)
+			} else {
+				body = data
+			}
+		}
+		if results[i].Err == nil {
+			status, responseBody, err := b.client.RawWithAuth(op.Method, op.Endpoint, body, b.token)
+			if err != nil {
+				results[i].Err = err
+			} else {
+				results[i].Response, results[i].Err = parseResponse(status, responseBody, false)
+			}
+		}
+		if results[i].Err != nil {
+			for j := i + 1; j < len(ops); j++ {
+				results[j].Err = ErrSkipped
+			}
+			return
+		}
+	}
+}
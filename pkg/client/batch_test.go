@@ -0,0 +1,190 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// endpoints returns the method and endpoint of each request received by fake
+func endpoints(fake *FakeClient) []string {
+	var sent []string
+	for _, request := range fake.Requests() {
+		sent = append(sent, request.Method+" "+request.Endpoint)
+	}
+	return sent
+}
+
+// errorsOf returns the error message of each result, "" for successes
+func errorsOf(results []Result) []string {
+	messages := make([]string, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			messages[i] = result.Err.Error()
+		}
+	}
+	return messages
+}
+
+func TestBatcher(t *testing.T) {
+	ops := []Operation{
+		{Method: "POST", Endpoint: "/services", Payload: map[string]string{"service_name": "payments-api"}},
+		{Method: "POST", Endpoint: "/services/a1b2c3/keys", Payload: map[string]string{"key_name": "ci"}},
+		{Method: "DELETE", Endpoint: "/services/d4e5f6"},
+	}
+	skipped := ErrSkipped.Error()
+
+	tests := []struct {
+		name   string
+		batch  *fakeResponse
+		fail   string
+		sent   []string
+		errors []string
+	}{
+		{
+			name:   "batch",
+			batch:  &fakeResponse{http.StatusOK, []byte(`{"results":[{"status":201,"body":{}},{"status":201,"body":{}},{"status":204}]}`)},
+			sent:   []string{"POST /batch"},
+			errors: []string{"", "", ""},
+		},
+		{
+			name:   "batch stopped at a failure",
+			batch:  &fakeResponse{http.StatusOK, []byte(`{"results":[{"status":201,"body":{}},{"status":409,"body":{"message":"Key already exists"}}]}`)},
+			sent:   []string{"POST /batch"},
+			errors: []string{"", "Key already exists", skipped},
+		},
+		{
+			name:   "batch failed as a whole",
+			batch:  &fakeResponse{http.StatusBadRequest, []byte(`{"message":"Invalid batch"}`)},
+			sent:   []string{"POST /batch"},
+			errors: []string{"Invalid batch", "Invalid batch", "Invalid batch"},
+		},
+		{
+			name:   "fallback on 404",
+			batch:  &fakeResponse{http.StatusNotFound, nil},
+			sent:   []string{"POST /batch", "POST /services", "POST /services/a1b2c3/keys", "DELETE /services/d4e5f6"},
+			errors: []string{"", "", ""},
+		},
+		{
+			name:   "fallback on 405",
+			batch:  &fakeResponse{http.StatusMethodNotAllowed, nil},
+			sent:   []string{"POST /batch", "POST /services", "POST /services/a1b2c3/keys", "DELETE /services/d4e5f6"},
+			errors: []string{"", "", ""},
+		},
+		{
+			name:   "fallback on 501",
+			batch:  &fakeResponse{http.StatusNotImplemented, nil},
+			sent:   []string{"POST /batch", "POST /services", "POST /services/a1b2c3/keys", "DELETE /services/d4e5f6"},
+			errors: []string{"", "", ""},
+		},
+		{
+			name:   "fallback stopped at a failure",
+			batch:  &fakeResponse{http.StatusNotFound, nil},
+			fail:   "/services/a1b2c3/keys",
+			sent:   []string{"POST /batch", "POST /services", "POST /services/a1b2c3/keys"},
+			errors: []string{"", "Key already exists", skipped},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeClient()
+			fake.Handle("POST", BatchEndpoint, tt.batch.status, string(tt.batch.body))
+			for _, op := range ops {
+				if op.Endpoint == tt.fail {
+					fake.Handle(op.Method, op.Endpoint, http.StatusConflict, `{"message":"Key already exists"}`)
+				} else {
+					fake.HandleJSON(op.Method, op.Endpoint, map[string]interface{}{})
+				}
+			}
+
+			results := NewBatcher(fake, "tok").Do(ops)
+			if got := errorsOf(results); !reflect.DeepEqual(got, tt.errors) {
+				t.Errorf("expected errors %q, got %q", tt.errors, got)
+			}
+			if got := endpoints(fake); !reflect.DeepEqual(got, tt.sent) {
+				t.Errorf("expected requests %q, got %q", tt.sent, got)
+			}
+			for i, result := range results {
+				if tt.errors[i] == skipped && !errors.Is(result.Err, ErrSkipped) {
+					t.Errorf("expected operation %d to fail with ErrSkipped, got %v", i, result.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestBatcherRemembersUnsupported(t *testing.T) {
+	fake := NewFakeClient()
+	fake.Handle("POST", BatchEndpoint, http.StatusNotFound, "")
+	fake.HandleJSON("POST", "/services", map[string]interface{}{})
+	ops := []Operation{{Method: "POST", Endpoint: "/services"}, {Method: "POST", Endpoint: "/services"}}
+
+	b := NewBatcher(fake, "tok")
+	b.Do(ops)
+	b.Do(ops)
+	want := []string{"POST /batch", "POST /services", "POST /services", "POST /services", "POST /services"}
+	if got := endpoints(fake); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the batch endpoint to be tried once, got %q", got)
+	}
+}
+
+func TestBatcherSplit(t *testing.T) {
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Operations  []Operation `json:"operations"`
+			StopOnError bool        `json:"stop_on_error"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || !request.StopOnError {
+			t.Errorf("unexpected batch request %+v (%v)", request, err)
+		}
+		sizes = append(sizes, len(request.Operations))
+
+		var results []string
+		for _, op := range request.Operations {
+			if op.Endpoint == "/services/fail" {
+				results = append(results, `{"status":409,"body":{"message":"Service already exists"}}`)
+				break
+			}
+			results = append(results, `{"status":201,"body":{}}`)
+		}
+		fmt.Fprintf(w, `{"results":[%s]}`, strings.Join(results, ","))
+	}))
+	defer server.Close()
+	c := NewHTTPClient(server.URL)
+
+	ops := make([]Operation, 2*MaxBatchSize+50)
+	for i := range ops {
+		ops[i] = Operation{Method: "POST", Endpoint: fmt.Sprintf("/services/s%d", i)}
+	}
+	for i, result := range NewBatcher(c, "tok").Do(ops) {
+		if result.Err != nil {
+			t.Errorf("operation %d: %v", i, result.Err)
+		}
+	}
+	if want := []int{MaxBatchSize, MaxBatchSize, 50}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("expected batches of %v operations, got %v", want, sizes)
+	}
+
+	// A failure in the first batch leaves the later ones unsent
+	sizes = nil
+	ops[10].Endpoint = "/services/fail"
+	results := NewBatcher(c, "tok").Do(ops)
+	if !reflect.DeepEqual(sizes, []int{MaxBatchSize}) {
+		t.Errorf("expected only the first batch to be sent, got %v", sizes)
+	}
+	if results[9].Err != nil || results[10].Err == nil || results[10].Err.Error() != "Service already exists" {
+		t.Errorf("expected operation 10 to fail, got %v and %v", results[9].Err, results[10].Err)
+	}
+	for i := 11; i < len(results); i++ {
+		if !errors.Is(results[i].Err, ErrSkipped) {
+			t.Fatalf("expected operation %d to be skipped, got %v", i, results[i].Err)
+		}
+	}
+}
@@ -2,31 +2,199 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/certfix/certfix-cli/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// instrumentationName names the tracer of request spans
+const instrumentationName = "github.com/certfix/certfix-cli/pkg/client"
+
+// APIClient is the set of requests commands make against the API. HTTPClient
+// implements it; tests substitute a fake.
+type APIClient interface {
+	Get(endpoint string) (map[string]interface{}, error)
+	Post(endpoint string, payload interface{}) (map[string]interface{}, error)
+	GetWithAuth(endpoint string, token string) (map[string]interface{}, error)
+	PostWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error)
+	PutWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error)
+	PatchWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error)
+	DeleteWithAuth(endpoint string, token string) (map[string]interface{}, error)
+	DeleteWithAuthAndPayload(endpoint string, payload interface{}, token string) (map[string]interface{}, error)
+	PostWithHeaders(endpoint string, payload interface{}, headers map[string]string) (map[string]interface{}, error)
+	RawWithAuth(method, endpoint string, body []byte, token string) (int, []byte, error)
+}
+
 // HTTPClient represents an HTTP client for API requests
 type HTTPClient struct {
 	baseURL    string
 	httpClient *http.Client
+	retries    int
+	userAgent  string
+
+	// annotations are headers added to every request other than a GET
+	annotations map[string]string
+
+	trace   io.Writer
+	context func() context.Context
+
+	// etags are the ETags of resources read by GET requests, by URL
+	etagMu sync.Mutex
+	etags  map[string]string
+}
+
+// DefaultTimeout is the request timeout used when Options.Timeout is zero
+const DefaultTimeout = 30 * time.Second
+
+// DefaultUserAgent is the User-Agent header used when Options.UserAgent is
+// empty
+const DefaultUserAgent = "certfix-cli/1.0"
+
+// Options configures an HTTPClient
+type Options struct {
+	// Timeout bounds each attempt of a request, including reading the
+	// response. Zero means DefaultTimeout.
+	Timeout time.Duration
+
+	// UserAgent is sent in the User-Agent header. Empty means
+	// DefaultUserAgent.
+	UserAgent string
+
+	// Retries is how many times a request is retried after a network error or
+	// a 429, 502, 503 or 504 response. POST and PATCH requests carry an
+	// IdempotencyKeyHeader so the server applies a retried one only once.
+	Retries int
+
+	// FieldManager names the operator, team or automation making changes. It
+	// is sent in FieldManagerHeader with every request other than a GET.
+	FieldManager string
+
+	// Reason explains why changes are made, e.g. a ticket number. It is sent
+	// in ReasonHeader with every request other than a GET.
+	Reason string
+
+	// Trace, when set, receives a line per attempt of each request with its
+	// status, duration and idempotency key
+	Trace io.Writer
+
+	// SigningSecret, when set, signs every request with a SigningTransport
+	// identified by SigningKeyID
+	SigningKeyID  string
+	SigningSecret string
+
+	// Context, when set, returns the context each request is made in. Every
+	// request is recorded as an OpenTelemetry span, a child of the span the
+	// context carries, and propagates it to the server in a traceparent
+	// header. Nil means context.Background.
+	Context func() context.Context
 }
 
-// NewHTTPClient creates a new HTTP client
+// Headers that annotate changing requests for the server's audit log
+const (
+	FieldManagerHeader = "X-Certfix-Field-Manager"
+	ReasonHeader       = "X-Certfix-Change-Reason"
+)
+
+// IdempotencyKeyHeader carries a key identifying one logical POST or PATCH
+// request. Every attempt of the request sends the same key, so a server that
+// already handled it returns the first result instead of creating a duplicate.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ErrResourceChanged is returned when an update is rejected because the
+// resource changed after it was read. HTTPClient remembers the ETag of every
+// resource it reads and sends it in If-Match when updating the same URL with
+// PUT or PATCH, so the server refuses to overwrite a concurrent edit.
+var ErrResourceChanged = errors.New("resource changed since it was read")
+
+// maxRetryDelay caps the exponential backoff between retries
+const maxRetryDelay = 8 * time.Second
+
+// retryDelay is the wait before retry number attempt+1
+func retryDelay(attempt int) time.Duration {
+	delay := 500 * time.Millisecond << attempt
+	if delay > maxRetryDelay || delay <= 0 {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// changed is set once a request that may change server state has been sent
+var changed atomic.Bool
+
+// StateChanged reports whether this process has sent any request other than a
+// GET, regardless of whether it succeeded
+func StateChanged() bool {
+	return changed.Load()
+}
+
+var _ APIClient = (*HTTPClient)(nil)
+
+// NewHTTPClient creates a new HTTP client with the default options
 func NewHTTPClient(baseURL string) *HTTPClient {
+	return NewHTTPClientWithOptions(baseURL, Options{})
+}
+
+// NewHTTPClientWithOptions creates a new HTTP client configured by opts
+func NewHTTPClientWithOptions(baseURL string, opts Options) *HTTPClient {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	annotations := make(map[string]string)
+	if value := headerValue(opts.FieldManager); value != "" {
+		annotations[FieldManagerHeader] = value
+	}
+	if value := headerValue(opts.Reason); value != "" {
+		annotations[ReasonHeader] = value
+	}
+	httpClient := &http.Client{Timeout: opts.Timeout}
+	if opts.SigningSecret != "" {
+		httpClient.Transport = &SigningTransport{KeyID: opts.SigningKeyID, Secret: []byte(opts.SigningSecret)}
+	}
 	return &HTTPClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:     baseURL,
+		httpClient:  httpClient,
+		retries:     opts.Retries,
+		userAgent:   opts.UserAgent,
+		annotations: annotations,
+		trace:       opts.Trace,
+		context:     opts.Context,
+		etags:       make(map[string]string),
 	}
 }
 
+// headerValue makes free text safe to send as a header value by replacing
+// control characters such as newlines with spaces
+func headerValue(s string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, s))
+}
+
 // Post makes a POST request
 func (c *HTTPClient) Post(endpoint string, payload interface{}) (map[string]interface{}, error) {
 	return c.request("POST", endpoint, payload, "")
@@ -67,74 +235,274 @@ func (c *HTTPClient) PatchWithAuth(endpoint string, payload interface{}, token s
 	return c.request("PATCH", endpoint, payload, token)
 }
 
+// PostWithHeaders makes a POST request authenticated by custom headers (such as
+// an integration key) instead of a bearer token
+func (c *HTTPClient) PostWithHeaders(endpoint string, payload interface{}, headers map[string]string) (map[string]interface{}, error) {
+	return c.requestWithHeaders("POST", endpoint, payload, "", headers)
+}
+
+// RawWithAuth makes an authenticated request with a pre-encoded JSON body and
+// returns the status code and response body as received, without interpreting
+// either
+func (c *HTTPClient) RawWithAuth(method, endpoint string, body []byte, token string) (int, []byte, error) {
+	return c.RawWithContext(c.requestContext(), method, endpoint, body, token)
+}
+
+// RawWithContext is RawWithAuth bounded by ctx, which also carries the span
+// the request is recorded under instead of Options.Context
+func (c *HTTPClient) RawWithContext(ctx context.Context, method, endpoint string, body []byte, token string) (int, []byte, error) {
+	log := logger.GetLogger()
+
+	url := c.baseURL + endpoint
+	log.Debugf("%s %s", method, url)
+
+	status, responseBody, err := c.do(ctx, method, url, body, token, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	log.Debugf("Response status: %d", status)
+
+	return status, responseBody, nil
+}
+
 // request performs an HTTP request
 func (c *HTTPClient) request(method, endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return c.requestWithHeaders(method, endpoint, payload, token, nil)
+}
+
+// requestWithHeaders performs an HTTP request with additional headers
+func (c *HTTPClient) requestWithHeaders(method, endpoint string, payload interface{}, token string, headers map[string]string) (map[string]interface{}, error) {
 	log := logger.GetLogger()
 
 	url := c.baseURL + endpoint
 	log.Debugf("%s %s", method, url)
 
-	var body io.Reader
+	var body []byte
 	if payload != nil {
 		jsonData, err := json.Marshal(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal payload: %w", err)
 		}
-		body = bytes.NewBuffer(jsonData)
+		body = jsonData
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	status, responseBody, err := c.do(c.requestContext(), method, url, body, token, headers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "certfix-cli/1.0")
+	if status < 200 || status >= 300 {
+		log.Debugf("Response status: %d, body: %s", status, string(responseBody))
+	}
+	return parseResponse(status, responseBody, len(headers) > 0)
+}
+
+// do sends a request and returns the status and body of the response,
+// retrying transient failures up to c.retries times
+func (c *HTTPClient) do(ctx context.Context, method, url string, body []byte, token string, headers map[string]string) (int, []byte, error) {
+	if method != http.MethodGet {
+		changed.Store(true)
+	}
+
+	idempotencyKey := ""
+	if method == http.MethodPost || method == http.MethodPatch {
+		idempotencyKey = newIdempotencyKey()
+		withKey := map[string]string{IdempotencyKeyHeader: idempotencyKey}
+		for name, value := range headers {
+			withKey[name] = value
+		}
+		headers = withKey
+	}
+
+	ctx, span := c.startSpan(ctx, method, url)
+	defer span.End()
+
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		status, responseBody, err := c.send(ctx, method, url, body, token, headers)
+		c.traceAttempt(method, url, attempt, idempotencyKey, status, err, time.Since(start))
+		if attempt >= c.retries || !retryable(status, err) {
+			endSpan(span, attempt, status, err)
+			return status, responseBody, err
+		}
+		delay := retryDelay(attempt)
+		logger.GetLogger().Debugf("Retrying %s %s in %s (attempt %d of %d)", method, url, delay, attempt+2, c.retries+1)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			endSpan(span, attempt, status, ctx.Err())
+			return 0, nil, fmt.Errorf("request failed: %w", ctx.Err())
+		}
+	}
+}
+
+// send makes a single attempt of a request
+func (c *HTTPClient) send(ctx context.Context, method, url string, body []byte, token string, headers map[string]string) (int, []byte, error) {
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
 	if token != "" {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
+	if method != http.MethodGet {
+		for name, value := range c.annotations {
+			req.Header.Set(name, value)
+		}
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	conditional := method == http.MethodPut || method == http.MethodPatch
+	if conditional {
+		if etag := c.etag(url); etag != "" {
+			req.Header.Set("If-Match", etag)
+		}
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return 0, nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if (method == http.MethodGet || conditional) && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		c.setETag(url, resp.Header.Get("ETag"))
+	}
+
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return 0, nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	return resp.StatusCode, responseBody, nil
+}
 
-	// Check status code
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		log.Debugf("Response status: %d, body: %s", resp.StatusCode, string(responseBody))
+// requestContext is the context of requests made without one
+func (c *HTTPClient) requestContext() context.Context {
+	if c.context != nil {
+		return c.context()
+	}
+	return context.Background()
+}
 
-		if resp.StatusCode == 401 || resp.StatusCode == 403 {
-			return nil, fmt.Errorf("session expired or unauthorized: please run 'certfix login'")
-		}
+// startSpan starts the span of a request, named after its method and path
+func (c *HTTPClient) startSpan(ctx context.Context, method, url string) (context.Context, trace.Span) {
+	path, _, _ := strings.Cut(strings.TrimPrefix(url, c.baseURL), "?")
+	return otel.Tracer(instrumentationName).Start(ctx, method+" "+path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.URLFull(url),
+		))
+}
 
-		// Extract message from standardized error response format
-		var errorResponse map[string]interface{}
-		if err := json.Unmarshal(responseBody, &errorResponse); err == nil {
-			// Check for details.message pattern (nested map)
-			if details, ok := errorResponse["details"].(map[string]interface{}); ok {
-				if message, ok := details["message"].(string); ok {
-					return nil, fmt.Errorf("%s", message)
-				}
-			}
-			// Check for top-level message field
-			if message, ok := errorResponse["message"].(string); ok {
-				return nil, fmt.Errorf("%s", message)
-			}
-			// Check for top-level error field
-			if errMsg, ok := errorResponse["error"].(string); ok {
-				return nil, fmt.Errorf("%s", errMsg)
-			}
-		}
+// endSpan records the outcome of the last attempt of a request on its span
+func endSpan(span trace.Span, attempt int, status int, err error) {
+	if attempt > 0 {
+		span.SetAttributes(semconv.HTTPRequestResendCount(attempt))
+	}
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case status >= 400:
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		span.SetStatus(codes.Error, http.StatusText(status))
+	default:
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	}
+}
+
+// etag returns the ETag last seen for url
+func (c *HTTPClient) etag(url string) string {
+	c.etagMu.Lock()
+	defer c.etagMu.Unlock()
+	return c.etags[url]
+}
+
+// setETag remembers the ETag of url, or forgets it when the server sent none
+func (c *HTTPClient) setETag(url, etag string) {
+	c.etagMu.Lock()
+	defer c.etagMu.Unlock()
+	if etag == "" {
+		delete(c.etags, url)
+	} else {
+		c.etags[url] = etag
+	}
+}
 
-		// Fallback to full error message
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseBody))
+// newIdempotencyKey returns a random key in the form of a version 4 UUID
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// traceAttempt writes a line about one attempt of a request to c.trace
+func (c *HTTPClient) traceAttempt(method, url string, attempt int, idempotencyKey string, status int, err error, elapsed time.Duration) {
+	if c.trace == nil {
+		return
+	}
+	line := fmt.Sprintf("[trace] %s %s", method, url)
+	if attempt > 0 {
+		line += fmt.Sprintf(" (attempt %d)", attempt+1)
+	}
+	if idempotencyKey != "" {
+		line += " idempotency-key=" + idempotencyKey
+	}
+	if err != nil {
+		line += fmt.Sprintf(" → error: %v", err)
+	} else {
+		line += fmt.Sprintf(" → %d", status)
+	}
+	fmt.Fprintf(c.trace, "%s (%s)\n", line, elapsed.Round(time.Millisecond))
+}
+
+// retryable reports whether a failed attempt of a request may be retried
+func retryable(status int, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr)
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// ParseResponse turns a response received outside an HTTPClient, such as one
+// replayed from a cache, into the map the request methods return
+func ParseResponse(statusCode int, responseBody []byte) (map[string]interface{}, error) {
+	return parseResponse(statusCode, responseBody, false)
+}
+
+// ResponseError returns the error the request methods return for a non-2xx
+// response received outside an HTTPClient, or nil for a 2xx response
+func ResponseError(statusCode int, responseBody []byte) error {
+	return responseError(statusCode, responseBody, false)
+}
+
+// parseResponse turns a response into the map returned by the request
+// methods. Array bodies are wrapped under "_array_data" with "_is_array" set;
+// non-2xx statuses become errors carrying the server's message.
+// headerAuth marks requests authenticated by custom headers rather than the
+// login session.
+func parseResponse(statusCode int, responseBody []byte, headerAuth bool) (map[string]interface{}, error) {
+	if err := responseError(statusCode, responseBody, headerAuth); err != nil {
+		return nil, err
 	}
 
 	// Parse response - handle objects, arrays, and non-JSON bodies
@@ -163,3 +531,41 @@ func (c *HTTPClient) request(method, endpoint string, payload interface{}, token
 
 	return result, nil
 }
+
+// responseError returns the error for a non-2xx response, carrying the
+// server's message, or nil for a 2xx response
+func responseError(statusCode int, responseBody []byte, headerAuth bool) error {
+	if statusCode >= 200 && statusCode < 300 {
+		return nil
+	}
+
+	// Requests authenticated by custom headers are not tied to the login session
+	if (statusCode == 401 || statusCode == 403) && !headerAuth {
+		return fmt.Errorf("session expired or unauthorized: please run 'certfix login'")
+	}
+	if statusCode == http.StatusPreconditionFailed {
+		return fmt.Errorf("%w: someone else updated it meanwhile; run the command again to apply the change to the current version", ErrResourceChanged)
+	}
+
+	// Extract message from standardized error response format
+	var errorResponse map[string]interface{}
+	if err := json.Unmarshal(responseBody, &errorResponse); err == nil {
+		// Check for details.message pattern (nested map)
+		if details, ok := errorResponse["details"].(map[string]interface{}); ok {
+			if message, ok := details["message"].(string); ok {
+				return fmt.Errorf("%s", message)
+			}
+		}
+		// Check for top-level message field
+		if message, ok := errorResponse["message"].(string); ok {
+			return fmt.Errorf("%s", message)
+		}
+		// Check for top-level error field
+		if errMsg, ok := errorResponse["error"].(string); ok {
+			return fmt.Errorf("%s", errMsg)
+		}
+	}
+
+	// Fallback to full error message
+	return fmt.Errorf("request failed with status %d: %s", statusCode, string(responseBody))
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if r.Method == http.MethodPost && len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	c := NewHTTPClientWithOptions(server.URL, Options{Retries: 2})
+
+	if _, err := c.PostWithAuth("/services", map[string]string{"service_name": "payments-api"}, "tok"); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 || keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("expected every attempt to send the same idempotency key, got %q", keys)
+	}
+
+	first := keys[0]
+	keys = nil
+	if _, err := c.PatchWithAuth("/services/a1b2c3", map[string]bool{"active": false}, "tok"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetWithAuth("/services/a1b2c3", "tok"); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] == first || keys[1] != "" {
+		t.Errorf("expected a new key for another request and none for a GET, got %q", keys)
+	}
+}
+
+func TestConditionalUpdates(t *testing.T) {
+	etag := `"v1"`
+	var ifMatch []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Path == "/services/a1b2c3" {
+				w.Header().Set("ETag", etag)
+			}
+		case http.MethodPut, http.MethodPatch:
+			ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+			if match := r.Header.Get("If-Match"); match != "" && match != etag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			etag = `"v2"`
+			w.Header().Set("ETag", etag)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	c := NewHTTPClient(server.URL)
+
+	// Updates send the ETag of the last read of the same URL
+	if _, err := c.GetWithAuth("/services/a1b2c3", "tok"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.PutWithAuth("/services/a1b2c3", map[string]bool{"active": false}, "tok"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.PatchWithAuth("/services/a1b2c3", map[string]bool{"active": true}, "tok"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.PutWithAuth("/services/d4e5f6", map[string]bool{"active": false}, "tok"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{`"v1"`, `"v2"`, ""}; len(ifMatch) != 3 || ifMatch[0] != want[0] || ifMatch[1] != want[1] || ifMatch[2] != want[2] {
+		t.Errorf("expected If-Match %q, got %q", want, ifMatch)
+	}
+
+	// Someone else changes the service after it was read
+	if _, err := c.GetWithAuth("/services/a1b2c3", "tok"); err != nil {
+		t.Fatal(err)
+	}
+	etag = `"v3"`
+	_, err := c.PutWithAuth("/services/a1b2c3", map[string]bool{"active": false}, "tok")
+	if !errors.Is(err, ErrResourceChanged) {
+		t.Errorf("expected a 412 to be reported as ErrResourceChanged, got %v", err)
+	}
+}
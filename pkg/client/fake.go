@@ -0,0 +1,139 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// FakeRequest is a request received by a FakeClient
+type FakeRequest struct {
+	Method   string
+	Endpoint string
+	Payload  interface{}
+	Token    string
+}
+
+// fakeResponse is a canned response of a FakeClient
+type fakeResponse struct {
+	status int
+	body   []byte
+}
+
+// FakeClient is an in-memory APIClient for tests. Responses are registered per
+// method and endpoint; requests without one fail with a 404 error. Responses
+// go through the same parsing as HTTPClient, so array bodies and error
+// messages behave as they do against a real server.
+type FakeClient struct {
+	mu        sync.Mutex
+	responses map[string]fakeResponse
+	requests  []FakeRequest
+}
+
+var _ APIClient = (*FakeClient)(nil)
+
+// NewFakeClient creates a FakeClient without any responses
+func NewFakeClient() *FakeClient {
+	return &FakeClient{responses: make(map[string]fakeResponse)}
+}
+
+// Handle registers the raw response for method and endpoint. The endpoint
+// must match exactly, including any query string.
+func (f *FakeClient) Handle(method, endpoint string, status int, body string) *FakeClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[method+" "+endpoint] = fakeResponse{status: status, body: []byte(body)}
+	return f
+}
+
+// HandleJSON registers a 200 response with v encoded as JSON
+func (f *FakeClient) HandleJSON(method, endpoint string, v interface{}) *FakeClient {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("fake response for %s %s: %v", method, endpoint, err))
+	}
+	return f.Handle(method, endpoint, http.StatusOK, string(data))
+}
+
+// Requests returns the requests received so far, in order
+func (f *FakeClient) Requests() []FakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeRequest(nil), f.requests...)
+}
+
+// respond records a request and returns its canned response
+func (f *FakeClient) respond(method, endpoint string, payload interface{}, token string) fakeResponse {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, FakeRequest{Method: method, Endpoint: endpoint, Payload: payload, Token: token})
+	if response, ok := f.responses[method+" "+endpoint]; ok {
+		return response
+	}
+	return fakeResponse{
+		status: http.StatusNotFound,
+		body:   []byte(fmt.Sprintf(`{"message":"no fake response for %s %s"}`, method, endpoint)),
+	}
+}
+
+func (f *FakeClient) request(method, endpoint string, payload interface{}, token string, headerAuth bool) (map[string]interface{}, error) {
+	response := f.respond(method, endpoint, payload, token)
+	return parseResponse(response.status, response.body, headerAuth)
+}
+
+// Get makes a fake GET request
+func (f *FakeClient) Get(endpoint string) (map[string]interface{}, error) {
+	return f.request("GET", endpoint, nil, "", false)
+}
+
+// Post makes a fake POST request
+func (f *FakeClient) Post(endpoint string, payload interface{}) (map[string]interface{}, error) {
+	return f.request("POST", endpoint, payload, "", false)
+}
+
+// GetWithAuth makes a fake authenticated GET request
+func (f *FakeClient) GetWithAuth(endpoint string, token string) (map[string]interface{}, error) {
+	return f.request("GET", endpoint, nil, token, false)
+}
+
+// PostWithAuth makes a fake authenticated POST request
+func (f *FakeClient) PostWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return f.request("POST", endpoint, payload, token, false)
+}
+
+// PutWithAuth makes a fake authenticated PUT request
+func (f *FakeClient) PutWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return f.request("PUT", endpoint, payload, token, false)
+}
+
+// PatchWithAuth makes a fake authenticated PATCH request
+func (f *FakeClient) PatchWithAuth(endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return f.request("PATCH", endpoint, payload, token, false)
+}
+
+// DeleteWithAuth makes a fake authenticated DELETE request
+func (f *FakeClient) DeleteWithAuth(endpoint string, token string) (map[string]interface{}, error) {
+	return f.request("DELETE", endpoint, nil, token, false)
+}
+
+// DeleteWithAuthAndPayload makes a fake authenticated DELETE request with a payload
+func (f *FakeClient) DeleteWithAuthAndPayload(endpoint string, payload interface{}, token string) (map[string]interface{}, error) {
+	return f.request("DELETE", endpoint, payload, token, false)
+}
+
+// PostWithHeaders makes a fake POST request authenticated by custom headers
+func (f *FakeClient) PostWithHeaders(endpoint string, payload interface{}, headers map[string]string) (map[string]interface{}, error) {
+	return f.request("POST", endpoint, payload, "", true)
+}
+
+// RawWithAuth makes a fake request and returns the canned status and body.
+// A JSON body is recorded decoded, like the payload of the other requests.
+func (f *FakeClient) RawWithAuth(method, endpoint string, body []byte, token string) (int, []byte, error) {
+	var payload interface{}
+	if len(body) > 0 && json.Unmarshal(body, &payload) != nil {
+		payload = json.RawMessage(body)
+	}
+	response := f.respond(method, endpoint, payload, token)
+	return response.status, response.body, nil
+}
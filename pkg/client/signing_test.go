@@ -0,0 +1,61 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSigningTransport(t *testing.T) {
+	// Each attempt is signed a few seconds after the previous one
+	signedAt := time.Unix(1760000000, 0)
+	signingClock = func() time.Time {
+		now := signedAt
+		signedAt = signedAt.Add(5 * time.Second)
+		return now
+	}
+	defer func() { signingClock = time.Now }()
+
+	type attempt struct {
+		body, keyID, timestamp, contentHash, signature string
+	}
+	var attempts []attempt
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		attempts = append(attempts, attempt{
+			body:        string(body),
+			keyID:       r.Header.Get(SigningKeyIDHeader),
+			timestamp:   r.Header.Get(TimestampHeader),
+			contentHash: r.Header.Get(ContentSHA256Header),
+			signature:   r.Header.Get(SignatureHeader),
+		})
+		if len(attempts) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewHTTPClientWithOptions(server.URL+"/api", Options{Retries: 1, SigningKeyID: "cli-ops", SigningSecret: "s3cret"})
+	if _, err := c.PostWithAuth("/services?dry_run=true", map[string]string{"service_name": "payments-api"}, "tok"); err != nil {
+		t.Fatal(err)
+	}
+
+	// HMAC-SHA256 of "POST\n/api/services?dry_run=true\n<timestamp>\n<content hash>"
+	const contentHash = "c4ba5528c9211dc10b5bfe3b6afafd94d595f8f0a3739f3e8c21c8d11e5b5fa3"
+	want := []attempt{
+		{`{"service_name":"payments-api"}`, "cli-ops", "1760000000", contentHash, "ec33c372a3c6fb04b08758c7c7ac034b3e30129e1df386b98a0eb282da6805ad"},
+		{`{"service_name":"payments-api"}`, "cli-ops", "1760000005", contentHash, "bf0474d2668b10161eef0655f13a2571e6f37bb654a91dd41cfd52c2995ffa8a"},
+	}
+	if len(attempts) != len(want) {
+		t.Fatalf("expected %d attempts, got %+v", len(want), attempts)
+	}
+	for i := range want {
+		if attempts[i] != want[i] {
+			t.Errorf("attempt %d: expected %+v, got %+v", i+1, want[i], attempts[i])
+		}
+	}
+}
@@ -12,8 +12,8 @@ var log *logrus.Logger
 func InitLogger(verbose bool) {
 	log = logrus.New()
 
-	// Set output to stdout
-	log.SetOutput(os.Stdout)
+	// Log to stderr so stdout only carries command results and stays pipeable
+	log.SetOutput(os.Stderr)
 
 	// Set log level
 	if verbose {
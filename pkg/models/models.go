@@ -1,14 +1,5 @@
 package models
 
-// Instance represents a Certfix instance
-type Instance struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Type   string `json:"type"`
-	Region string `json:"region"`
-	Status string `json:"status"`
-}
-
 // Certificate represents an SSL/TLS certificate
 type Certificate struct {
 	ID        string `json:"id"`
@@ -49,11 +40,19 @@ type EventConfig struct {
 
 // PolicyConfig represents a policy configuration
 type PolicyConfig struct {
-	Name        string                 `yaml:"name"`
-	Strategy    string                 `yaml:"strategy"`
-	Enabled     bool                   `yaml:"enabled"`
-	CronConfig  map[string]string      `yaml:"cron_config,omitempty"`
-	EventConfig map[string]interface{} `yaml:"event_config,omitempty"`
+	Name          string                 `yaml:"name"`
+	Strategy      string                 `yaml:"strategy"`
+	Enabled       bool                   `yaml:"enabled"`
+	Cron          string                 `yaml:"cron,omitempty"` // Five-field crontab expression, alternative to cron_config
+	CronConfig    map[string]string      `yaml:"cron_config,omitempty"`
+	EventConfig   map[string]interface{} `yaml:"event_config,omitempty"`
+	GradualConfig *GradualConfig         `yaml:"gradual_config,omitempty"`
+}
+
+// GradualConfig represents the batch rollout settings of a Gradual policy
+type GradualConfig struct {
+	BatchSize int    `yaml:"batch_size,omitempty"`
+	Interval  string `yaml:"interval,omitempty"` // Wait between batches, e.g. "10m"
 }
 
 // ServiceGroupConfig represents a service group configuration
@@ -73,6 +72,7 @@ type ServiceConfig struct {
 	PolicyName    string                  `yaml:"policy_name,omitempty"`    // Reference by name
 	ReloadService string                  `yaml:"reload_service,omitempty"` // Shell command to run after cert rotation
 	DNSNames      []string                `yaml:"dns_names,omitempty"`
+	Labels        map[string]string       `yaml:"labels,omitempty"` // Ownership labels, e.g. team: payments
 	Keys          []ServiceKeyConfig      `yaml:"keys,omitempty"`
 	Relations     []ServiceRelationConfig `yaml:"relations,omitempty"`
 }
@@ -86,8 +86,32 @@ type ServiceKeyConfig struct {
 
 // ServiceRelationConfig represents a service relation (matriz)
 type ServiceRelationConfig struct {
+	TargetHash  string `yaml:"target_hash"`
+	Type        string `yaml:"type,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Direction   string `yaml:"direction,omitempty"`
+}
+
+// MatrixImportConfig represents a relations file for 'certfix matrix import'
+type MatrixImportConfig struct {
+	Relations []MatrixRelationConfig `yaml:"relations"`
+}
+
+// MatrixRelationConfig represents a single relation in a matrix import file
+type MatrixRelationConfig struct {
+	SourceHash string `yaml:"source_hash"`
 	TargetHash string `yaml:"target_hash"`
 	Type       string `yaml:"type,omitempty"`
+	Enabled    *bool  `yaml:"enabled,omitempty"`
+}
+
+// AgentConfig represents the bootstrap configuration file read by the certfix agent
+type AgentConfig struct {
+	Endpoint    string `yaml:"endpoint"`
+	ServiceHash string `yaml:"service_hash"`
+	ServiceName string `yaml:"service_name,omitempty"`
+	KeyID       string `yaml:"key_id,omitempty"`
+	APIKey      string `yaml:"api_key"`
 }
 
 // CreatedResource tracks resources created during apply for rollback
@@ -0,0 +1,264 @@
+// Package notify pushes the results of CLI checks to chat channels and email.
+//
+// Targets are URLs:
+//
+//	slack://hooks.slack.com/services/T000/B000/XXXX   Slack incoming webhook
+//	teams://example.webhook.office.com/webhookb2/...  Microsoft Teams incoming webhook
+//	mailto:ops@example.com?smtp=smtp.example.com:587  email through an SMTP relay
+//	https://example.com/hook                          generic JSON webhook
+//
+// Email uses CERTFIX_SMTP_USERNAME and CERTFIX_SMTP_PASSWORD for
+// authentication when set, and CERTFIX_SMTP_FROM (or the from= parameter) as
+// the sender.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Severity levels of a message
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Message is a notification about the outcome of a check
+type Message struct {
+	Title    string
+	Severity string
+	Lines    []string
+}
+
+// Text renders the message body as plain text
+func (m Message) Text() string {
+	return strings.Join(m.Lines, "\n")
+}
+
+// Notifier delivers messages to one destination
+type Notifier interface {
+	Notify(msg Message) error
+	// String describes the destination without secrets, for logs and errors
+	String() string
+}
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// Parse returns the notifier for a target URL
+func Parse(target string) (Notifier, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification target %q: %w", target, err)
+	}
+
+	switch u.Scheme {
+	case "slack":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid Slack target %q (use slack://hooks.slack.com/services/...)", target)
+		}
+		u.Scheme = "https"
+		return &slackNotifier{url: u.String(), host: u.Host}, nil
+	case "teams":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid Teams target %q (use teams://<host>/<webhook-path>)", target)
+		}
+		u.Scheme = "https"
+		return &teamsNotifier{url: u.String(), host: u.Host}, nil
+	case "mailto":
+		return parseMail(u)
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook target %q", target)
+		}
+		return &webhookNotifier{url: u.String(), host: u.Host}, nil
+	default:
+		return nil, fmt.Errorf("unsupported notification target %q (use slack://, teams://, mailto: or https://)", target)
+	}
+}
+
+// ParseAll parses every target, failing on the first invalid one
+func ParseAll(targets []string) ([]Notifier, error) {
+	notifiers := make([]Notifier, 0, len(targets))
+	for _, target := range targets {
+		n, err := Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers, nil
+}
+
+// Send delivers msg to every notifier and returns the combined errors of the
+// ones that failed
+func Send(notifiers []Notifier, msg Message) error {
+	var errs []error
+	for _, n := range notifiers {
+		if err := n.Notify(msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// postJSON sends payload to a webhook URL and checks for a 2xx response
+func postJSON(target string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	resp, err := httpClient.Post(target, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// severityEmoji prefixes chat titles so severity stands out in a channel
+var severityEmoji = map[string]string{
+	SeverityInfo:    "✅",
+	SeverityWarning: "⚠️",
+	SeverityError:   "❌",
+}
+
+type slackNotifier struct {
+	url  string
+	host string
+}
+
+func (n *slackNotifier) Notify(msg Message) error {
+	text := fmt.Sprintf("%s *%s*", severityEmoji[msg.Severity], msg.Title)
+	if body := msg.Text(); body != "" {
+		text += "\n```\n" + body + "\n```"
+	}
+	return postJSON(n.url, map[string]string{"text": strings.TrimSpace(text)})
+}
+
+func (n *slackNotifier) String() string { return "slack://" + n.host }
+
+type teamsNotifier struct {
+	url  string
+	host string
+}
+
+// teamsColors are the MessageCard theme colors per severity
+var teamsColors = map[string]string{
+	SeverityInfo:    "2EB886",
+	SeverityWarning: "DAA038",
+	SeverityError:   "A30200",
+}
+
+func (n *teamsNotifier) Notify(msg Message) error {
+	return postJSON(n.url, map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title,
+		"themeColor": teamsColors[msg.Severity],
+		"title":      strings.TrimSpace(severityEmoji[msg.Severity] + " " + msg.Title),
+		"text":       "<pre>" + msg.Text() + "</pre>",
+	})
+}
+
+func (n *teamsNotifier) String() string { return "teams://" + n.host }
+
+type webhookNotifier struct {
+	url  string
+	host string
+}
+
+func (n *webhookNotifier) Notify(msg Message) error {
+	return postJSON(n.url, map[string]interface{}{
+		"title":    msg.Title,
+		"severity": msg.Severity,
+		"text":     msg.Text(),
+		"lines":    msg.Lines,
+		"time":     time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (n *webhookNotifier) String() string { return "webhook " + n.host }
+
+type mailNotifier struct {
+	to       []string
+	from     string
+	server   string
+	username string
+	password string
+}
+
+func parseMail(u *url.URL) (Notifier, error) {
+	to := u.Opaque
+	if to == "" {
+		to = u.Path
+	}
+	if to == "" {
+		return nil, fmt.Errorf("invalid email target %q (use mailto:ops@example.com?smtp=host:port)", u.String())
+	}
+
+	query := u.Query()
+	server := query.Get("smtp")
+	if server == "" {
+		return nil, fmt.Errorf("email target %q needs an SMTP server (add ?smtp=host:port)", u.String())
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "25")
+	}
+
+	from := query.Get("from")
+	if from == "" {
+		from = os.Getenv("CERTFIX_SMTP_FROM")
+	}
+	if from == "" {
+		from = "certfix@localhost"
+	}
+
+	return &mailNotifier{
+		to:       strings.Split(to, ","),
+		from:     from,
+		server:   server,
+		username: os.Getenv("CERTFIX_SMTP_USERNAME"),
+		password: os.Getenv("CERTFIX_SMTP_PASSWORD"),
+	}, nil
+}
+
+func (n *mailNotifier) Notify(msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&b, "Subject: [CertFix] %s\r\n", msg.Title)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Text(), "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if n.username != "" {
+		host, _, _ := net.SplitHostPort(n.server)
+		auth = smtp.PlainAuth("", n.username, n.password, host)
+	}
+	if err := smtp.SendMail(n.server, auth, n.from, n.to, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func (n *mailNotifier) String() string { return "mailto:" + strings.Join(n.to, ",") }